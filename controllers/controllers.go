@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"encoding/json"
 	"html/template"
 	"net/http"
+	"strings"
 
 	"gitea.com/go-chi/session"
+	"github.com/blogem/eod-scheduler/models"
 	"github.com/blogem/eod-scheduler/services"
+	"github.com/blogem/eod-scheduler/teamctx"
 )
 
 // getUserNickname retrieves the user's nickname from the session
@@ -17,19 +21,31 @@ func getUserNickname(r *http.Request) string {
 	return ""
 }
 
+// teamURL returns the URL of a page of the team the request works on
+func teamURL(r *http.Request, path string) string {
+	if team := teamctx.GetTeam(r.Context()); team != nil {
+		return team.Path() + path
+	}
+	return path
+}
+
 // renderTemplate creates a template set and renders it with the provided data
-func renderTemplate(w http.ResponseWriter, templateName string, pageTemplate string, data interface{}) error {
-	return renderTemplateWithStatus(w, http.StatusOK, templateName, pageTemplate, data)
+func renderTemplate(w http.ResponseWriter, r *http.Request, templateName string, pageTemplate string, data interface{}) error {
+	return renderTemplateWithStatus(w, r, http.StatusOK, templateName, pageTemplate, data)
 }
 
 // renderTemplateWithStatus creates a template set and renders it with the provided data and status code
-func renderTemplateWithStatus(w http.ResponseWriter, statusCode int, templateName string, pageTemplate string, data interface{}) error {
+func renderTemplateWithStatus(w http.ResponseWriter, r *http.Request, statusCode int, templateName string, pageTemplate string, data interface{}) error {
 	// Create a new template set with only the templates we need
 	tmpl := template.New(templateName)
 	tmpl.Funcs(template.FuncMap{
 		"add": func(a, b int) int { return a + b },
 		"sub": func(a, b int) int { return a - b },
 		"eq":  func(a, b interface{}) bool { return a == b },
+		// Team of the request, for links to its pages and the team switcher
+		"teamPath":    func() string { return teamURL(r, "") },
+		"currentTeam": func() *models.Team { return teamctx.GetTeam(r.Context()) },
+		"teams":       func() []models.Team { return teamctx.GetTeams(r.Context()) },
 	})
 
 	// Parse layout and page template
@@ -52,22 +68,51 @@ func renderTemplateWithStatus(w http.ResponseWriter, statusCode int, templateNam
 	return nil
 }
 
+// wantsJSON checks if the client asked for a JSON response instead of a page
+func wantsJSON(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "application/json") || r.URL.Query().Get("format") == "json"
+}
+
+// renderJSON writes the provided data as a JSON response with the given status code
+func renderJSON(w http.ResponseWriter, statusCode int, data interface{}) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	return json.NewEncoder(w).Encode(data)
+}
+
+// renderJSONError writes an error message as a JSON response with the given status code
+func renderJSONError(w http.ResponseWriter, statusCode int, message string) error {
+	return renderJSON(w, statusCode, map[string]string{"error": message})
+}
+
 // Controllers holds all controller instances
 type Controllers struct {
-	Auth         *AuthController
-	Dashboard    *DashboardController
-	Team         *TeamController
-	WorkingHours *WorkingHoursController
-	Schedule     *ScheduleController
+	Auth          *AuthController
+	Dashboard     *DashboardController
+	Team          *TeamController
+	WorkingHours  *WorkingHoursController
+	Schedule      *ScheduleController
+	TimeOff       *TimeOffController
+	Holiday       *HolidayController
+	Notifications *NotificationController
+	Webhooks      *WebhookController
+	Teams         *TeamsController
+	Slack         *SlackController
 }
 
 // NewControllers creates and initializes all controller instances
 func NewControllers(services *services.Services) *Controllers {
 	return &Controllers{
-		Auth:         NewAuthController(),
-		Dashboard:    NewDashboardController(services),
-		Team:         NewTeamController(services),
-		WorkingHours: NewWorkingHoursController(services),
-		Schedule:     NewScheduleController(services),
+		Auth:          NewAuthController(),
+		Dashboard:     NewDashboardController(services),
+		Team:          NewTeamController(services),
+		WorkingHours:  NewWorkingHoursController(services),
+		Schedule:      NewScheduleController(services),
+		TimeOff:       NewTimeOffController(services),
+		Holiday:       NewHolidayController(services),
+		Notifications: NewNotificationController(services),
+		Webhooks:      NewWebhookController(services),
+		Teams:         NewTeamsController(services),
+		Slack:         NewSlackController(services),
 	}
 }
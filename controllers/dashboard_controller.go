@@ -58,7 +58,7 @@ func (c *DashboardController) Index(w http.ResponseWriter, r *http.Request) {
 		User:        user,
 	}
 
-	renderTemplate(w, "dashboard", "templates/dashboard.html", templateData)
+	renderTemplate(w, r, "dashboard", "templates/dashboard.html", templateData)
 }
 
 // showLandingPage displays a landing page for unauthenticated users
@@ -85,5 +85,5 @@ func (c *DashboardController) showLandingPage(w http.ResponseWriter, r *http.Req
 		User:        "",
 	}
 
-	renderTemplate(w, "landing", "templates/landing.html", templateData)
+	renderTemplate(w, r, "landing", "templates/landing.html", templateData)
 }
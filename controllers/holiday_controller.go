@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// maxCalendarSize limits the size of uploaded holiday calendars
+const maxCalendarSize = 1 << 20 // 1 MB
+
+// HolidayController handles holiday calendar requests
+type HolidayController struct {
+	services *services.Services
+}
+
+// NewHolidayController creates a new holiday controller
+func NewHolidayController(services *services.Services) *HolidayController {
+	return &HolidayController{
+		services: services,
+	}
+}
+
+// holidaysPageData represents the data for the holidays page
+type holidaysPageData struct {
+	Title       string
+	CurrentPage string
+	Error       string
+	Success     string
+	Holidays    []models.Holiday
+	Behaviors   map[string]string
+	Form        *models.HolidayForm
+	User        string
+}
+
+// Index handles GET /holidays
+func (c *HolidayController) Index(w http.ResponseWriter, r *http.Request) {
+	c.renderIndex(w, r, http.StatusOK, &models.HolidayForm{Behavior: models.HolidayBehaviorNoDuty}, r.URL.Query().Get("error"))
+}
+
+// Create handles POST /holidays
+func (c *HolidayController) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := parseHolidayForm(r)
+	if _, err := c.services.Holiday.CreateHoliday(r.Context(), form); err != nil {
+		c.renderIndex(w, r, http.StatusBadRequest, form, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/holidays?success="+url.QueryEscape("Holiday added"), http.StatusSeeOther)
+}
+
+// Import handles POST /holidays/import
+func (c *HolidayController) Import(w http.ResponseWriter, r *http.Request) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxCalendarSize)
+	if err := r.ParseMultipartForm(maxCalendarSize); err != nil {
+		http.Redirect(w, r, "/holidays?error="+url.QueryEscape("Failed to read upload: "+err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	file, _, err := r.FormFile("calendar")
+	if err != nil {
+		http.Redirect(w, r, "/holidays?error="+url.QueryEscape("Choose an .ics file to import"), http.StatusSeeOther)
+		return
+	}
+	defer file.Close()
+
+	settings := &models.HolidayForm{
+		Behavior:  r.FormValue("behavior"),
+		StartTime: r.FormValue("start_time"),
+		EndTime:   r.FormValue("end_time"),
+	}
+
+	result, err := c.services.Holiday.ImportCalendar(r.Context(), file, settings)
+	if err != nil {
+		http.Redirect(w, r, "/holidays?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	message := fmt.Sprintf("Imported %d holiday(s), %d already existed", result.Imported, result.Skipped)
+	http.Redirect(w, r, "/holidays?success="+url.QueryEscape(message), http.StatusSeeOther)
+}
+
+// Edit handles GET /holidays/{id}/edit
+func (c *HolidayController) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid holiday ID", http.StatusBadRequest)
+		return
+	}
+
+	holiday, err := c.services.Holiday.GetHolidayByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Holiday not found: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	form := &models.HolidayForm{
+		Date:      holiday.GetFormattedDate(),
+		Name:      holiday.Name,
+		Recurring: holiday.Recurring,
+		Behavior:  holiday.Behavior,
+		StartTime: holiday.StartTime,
+		EndTime:   holiday.EndTime,
+	}
+
+	c.renderEdit(w, r, http.StatusOK, holiday, form, "")
+}
+
+// Update handles POST /holidays/{id}
+func (c *HolidayController) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid holiday ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := parseHolidayForm(r)
+	if _, err := c.services.Holiday.UpdateHoliday(r.Context(), id, form); err != nil {
+		// Reload edit page with form data and error
+		holiday, loadErr := c.services.Holiday.GetHolidayByID(r.Context(), id)
+		if loadErr != nil {
+			http.Error(w, "Holiday not found: "+loadErr.Error(), http.StatusNotFound)
+			return
+		}
+
+		c.renderEdit(w, r, http.StatusBadRequest, holiday, form, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, "/holidays?success="+url.QueryEscape("Holiday updated"), http.StatusSeeOther)
+}
+
+// Delete handles POST /holidays/{id}/delete
+func (c *HolidayController) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid holiday ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.services.Holiday.DeleteHoliday(r.Context(), id); err != nil {
+		http.Redirect(w, r, "/holidays?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/holidays", http.StatusSeeOther)
+}
+
+// renderIndex renders the holidays page with the given form and error
+func (c *HolidayController) renderIndex(w http.ResponseWriter, r *http.Request, statusCode int, form *models.HolidayForm, errorMessage string) {
+	holidays, err := c.services.Holiday.GetAllHolidays(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load holidays: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := holidaysPageData{
+		Title:       "Holidays",
+		CurrentPage: "hours",
+		Error:       errorMessage,
+		Success:     r.URL.Query().Get("success"),
+		Holidays:    holidays,
+		Behaviors:   models.HolidayBehaviorNames,
+		Form:        form,
+		User:        getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "holidays", "templates/holidays.html", templateData)
+}
+
+// renderEdit renders the holiday edit page
+func (c *HolidayController) renderEdit(w http.ResponseWriter, r *http.Request, statusCode int, holiday *models.Holiday, form *models.HolidayForm, errorMessage string) {
+	templateData := struct {
+		Title       string
+		CurrentPage string
+		Error       string
+		Success     string
+		Holiday     *models.Holiday
+		Behaviors   map[string]string
+		Form        *models.HolidayForm
+		User        string
+	}{
+		Title:       "Edit Holiday",
+		CurrentPage: "hours",
+		Error:       errorMessage,
+		Success:     "",
+		Holiday:     holiday,
+		Behaviors:   models.HolidayBehaviorNames,
+		Form:        form,
+		User:        getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "holiday_edit", "templates/holiday_edit.html", templateData)
+}
+
+// parseHolidayForm reads the holiday form fields from a parsed request
+func parseHolidayForm(r *http.Request) *models.HolidayForm {
+	return &models.HolidayForm{
+		Date:      r.FormValue("date"),
+		Name:      r.FormValue("name"),
+		Recurring: r.FormValue("recurring") == "on",
+		Behavior:  r.FormValue("behavior"),
+		StartTime: r.FormValue("start_time"),
+		EndTime:   r.FormValue("end_time"),
+	}
+}
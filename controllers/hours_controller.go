@@ -2,7 +2,12 @@ package controllers
 
 import (
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
 
 	"github.com/blogem/eod-scheduler/models"
 	"github.com/blogem/eod-scheduler/services"
@@ -20,35 +25,45 @@ func NewWorkingHoursController(services *services.Services) *WorkingHoursControl
 	}
 }
 
-// Index handles GET /hours
+// workingDayView groups the shifts of a weekday for the working hours page
+type workingDayView struct {
+	DayOfWeek int
+	Name      string
+	Active    bool
+	Shifts    []models.WorkingHours // Inactive days show a default shift to start from
+}
+
+// workingHoursChangeView shows the days changed by an upcoming change of the working hours
+type workingHoursChangeView struct {
+	EffectiveFrom time.Time
+	Days          []workingDayView // Only the changed days, without default shifts for days off
+}
+
+// hoursPageData represents the data for the working hours page
+type hoursPageData struct {
+	Title         string
+	CurrentPage   string
+	Error         string
+	Success       string
+	WorkingHours  []models.WorkingHours
+	Days          []workingDayView
+	EffectiveFrom string // Date the shown working hours are in effect on, and changes take effect on
+	Today         string
+	Changes       []workingHoursChangeView
+	Exceptions    []models.WorkingHoursException
+	ExceptionKind map[string]string
+	ShiftNames    []string // Shifts an extra working day can belong to
+	User          string
+}
+
+// Index handles GET /hours, showing the working hours in effect on the date in the from parameter
 func (c *WorkingHoursController) Index(w http.ResponseWriter, r *http.Request) {
-	workingHours, err := c.services.WorkingHours.GetAllWorkingHours(r.Context())
+	date, err := dateParam(r, "from", time.Now())
 	if err != nil {
-		http.Error(w, "Failed to load working hours: "+err.Error(), http.StatusInternalServerError)
+		http.Error(w, "Invalid date format. Use YYYY-MM-DD", http.StatusBadRequest)
 		return
 	}
-
-	dayNames := c.services.WorkingHours.GetDayNames()
-
-	templateData := struct {
-		Title        string
-		CurrentPage  string
-		Error        string
-		Success      string
-		WorkingHours []models.WorkingHours
-		DayNames     map[int]string
-		User         string
-	}{
-		Title:        "Working Hours Configuration",
-		CurrentPage:  "hours",
-		Error:        "",
-		Success:      "",
-		WorkingHours: workingHours,
-		DayNames:     dayNames,
-		User:         getUserNickname(r),
-	}
-
-	renderTemplate(w, "hours", "templates/hours.html", templateData)
+	c.render(w, r, http.StatusOK, date, "")
 }
 
 // Update handles POST /hours
@@ -58,58 +73,209 @@ func (c *WorkingHoursController) Update(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// Parse form data into working hours forms for each day
-	forms := make(map[int]*models.WorkingHoursForm)
+	// Parse form data into shift forms for each day. Every shift of a day submits a name, start
+	// and end time under the same keys, inactive days submit no times at all.
+	forms := make(map[int][]*models.WorkingHoursForm)
 	dayNames := c.services.WorkingHours.GetDayNames()
 
 	for dayNum := range dayNames {
-		activeKey := "active_" + strconv.Itoa(dayNum)
-		startKey := "start_time_" + strconv.Itoa(dayNum)
-		endKey := "end_time_" + strconv.Itoa(dayNum)
-
-		isActive := r.FormValue(activeKey) == "on"
-		startTime := r.FormValue(startKey)
-		endTime := r.FormValue(endKey)
-
-		forms[dayNum] = &models.WorkingHoursForm{
-			DayOfWeek: dayNum,
-			Active:    isActive,
-			StartTime: startTime,
-			EndTime:   endTime,
+		day := strconv.Itoa(dayNum)
+		isActive := r.FormValue("active_"+day) == "on"
+		names := r.Form["shift_name_"+day]
+		startTimes := r.Form["start_time_"+day]
+		endTimes := r.Form["end_time_"+day]
+
+		var dayForms []*models.WorkingHoursForm
+		for i := range startTimes {
+			dayForms = append(dayForms, &models.WorkingHoursForm{
+				DayOfWeek: dayNum,
+				Name:      valueAt(names, i),
+				Active:    isActive,
+				StartTime: startTimes[i],
+				EndTime:   valueAt(endTimes, i),
+			})
+		}
+		if len(dayForms) == 0 {
+			dayForms = append(dayForms, &models.WorkingHoursForm{DayOfWeek: dayNum, Active: isActive})
 		}
+
+		forms[dayNum] = dayForms
+	}
+
+	effectiveFrom, err := models.ParseDate(r.FormValue("effective_from"))
+	if err != nil {
+		c.render(w, r, http.StatusBadRequest, time.Now(), "Effective date must be in YYYY-MM-DD format")
+		return
 	}
 
-	err := c.services.WorkingHours.UpdateAllWorkingHours(r.Context(), forms)
+	err = c.services.WorkingHours.UpdateAllWorkingHours(r.Context(), effectiveFrom, forms)
 	if err != nil {
 		// Reload page with error
-		workingHours, loadErr := c.services.WorkingHours.GetAllWorkingHours(r.Context())
-		if loadErr != nil {
-			http.Error(w, "Failed to load working hours: "+loadErr.Error(), http.StatusInternalServerError)
-			return
+		c.render(w, r, http.StatusBadRequest, effectiveFrom, err.Error())
+		return
+	}
+
+	// Redirect to hours page after successful update
+	http.Redirect(w, r, teamURL(r, "/hours"), http.StatusSeeOther)
+}
+
+// CancelChange handles POST /hours/changes/{date}/delete
+func (c *WorkingHoursController) CancelChange(w http.ResponseWriter, r *http.Request) {
+	effectiveFrom, err := models.ParseDate(chi.URLParam(r, "date"))
+	if err != nil {
+		http.Error(w, "Invalid date format. Use YYYY-MM-DD", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.services.WorkingHours.CancelChange(r.Context(), effectiveFrom); err != nil {
+		http.Redirect(w, r, teamURL(r, "/hours?error="+url.QueryEscape(err.Error())), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/hours"), http.StatusSeeOther)
+}
+
+// CreateException handles POST /hours/exceptions
+func (c *WorkingHoursController) CreateException(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := &models.WorkingHoursExceptionForm{
+		Date:      r.FormValue("date"),
+		Kind:      r.FormValue("kind"),
+		Name:      r.FormValue("name"),
+		StartTime: r.FormValue("start_time"),
+		EndTime:   r.FormValue("end_time"),
+		Reason:    r.FormValue("reason"),
+	}
+
+	if _, err := c.services.WorkingHours.SaveException(r.Context(), form); err != nil {
+		c.render(w, r, http.StatusBadRequest, time.Now(), err.Error())
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/hours"), http.StatusSeeOther)
+}
+
+// DeleteException handles POST /hours/exceptions/{id}/delete
+func (c *WorkingHoursController) DeleteException(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid exception ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.services.WorkingHours.DeleteException(r.Context(), id); err != nil {
+		http.Redirect(w, r, teamURL(r, "/hours?error="+url.QueryEscape(err.Error())), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/hours"), http.StatusSeeOther)
+}
+
+// render renders the working hours in effect on the date with an optional error
+func (c *WorkingHoursController) render(w http.ResponseWriter, r *http.Request, statusCode int, date time.Time, errorMessage string) {
+	workingHours, err := c.services.WorkingHours.GetWorkingHoursOn(r.Context(), date)
+	if err != nil {
+		http.Error(w, "Failed to load working hours: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	changes, err := c.services.WorkingHours.GetUpcomingChanges(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load working hours changes: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	exceptions, err := c.services.WorkingHours.GetUpcomingExceptions(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load working hours exceptions: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	dayNames := c.services.WorkingHours.GetDayNames()
+	templateData := hoursPageData{
+		Title:         "Working Hours Configuration",
+		CurrentPage:   "hours",
+		Error:         errorMessage,
+		Success:       "",
+		WorkingHours:  workingHours,
+		Days:          newWorkingDayViews(workingHours, dayNames),
+		EffectiveFrom: models.FormatDate(date),
+		Today:         models.FormatDate(time.Now()),
+		Changes:       newWorkingHoursChangeViews(changes, dayNames),
+		Exceptions:    exceptions,
+		ExceptionKind: models.ExceptionKindNames,
+		ShiftNames:    uniqueShiftNames(workingHours),
+		User:          getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "hours", "templates/hours.html", templateData)
+}
+
+// newWorkingHoursChangeViews groups the shifts of every change by weekday, Monday first
+func newWorkingHoursChangeViews(changes []models.WorkingHoursChange, dayNames map[int]string) []workingHoursChangeView {
+	views := make([]workingHoursChangeView, 0, len(changes))
+	for _, change := range changes {
+		view := workingHoursChangeView{EffectiveFrom: change.EffectiveFrom}
+		for _, shift := range change.Shifts {
+			last := len(view.Days) - 1
+			if last < 0 || view.Days[last].DayOfWeek != shift.DayOfWeek {
+				view.Days = append(view.Days, workingDayView{DayOfWeek: shift.DayOfWeek, Name: dayNames[shift.DayOfWeek]})
+				last++
+			}
+			if shift.Active {
+				view.Days[last].Active = true
+				view.Days[last].Shifts = append(view.Days[last].Shifts, shift)
+			}
 		}
+		views = append(views, view)
+	}
+	return views
+}
 
-		templateData := struct {
-			Title        string
-			CurrentPage  string
-			Error        string
-			Success      string
-			WorkingHours []models.WorkingHours
-			DayNames     map[int]string
-			User         string
-		}{
-			Title:        "Working Hours Configuration",
-			CurrentPage:  "hours",
-			Error:        err.Error(),
-			Success:      "",
-			WorkingHours: workingHours,
-			DayNames:     dayNames,
-			User:         getUserNickname(r),
+// newWorkingDayViews groups the shifts by weekday, Monday first
+func newWorkingDayViews(workingHours []models.WorkingHours, dayNames map[int]string) []workingDayView {
+	days := make([]workingDayView, 0, len(dayNames))
+	for dayNum, name := range dayNames {
+		day := workingDayView{DayOfWeek: dayNum, Name: name}
+		for _, shift := range workingHours {
+			if shift.DayOfWeek == dayNum && shift.Active {
+				day.Active = true
+				day.Shifts = append(day.Shifts, shift)
+			}
+		}
+		if !day.Active {
+			day.Shifts = []models.WorkingHours{{DayOfWeek: dayNum, StartTime: "09:00", EndTime: "17:00"}}
 		}
+		days = append(days, day)
+	}
 
-		renderTemplateWithStatus(w, http.StatusBadRequest, "hours_update_error", "templates/hours.html", templateData)
-		return
+	sort.Slice(days, func(i, j int) bool {
+		return days[i].DayOfWeek < days[j].DayOfWeek
+	})
+	return days
+}
+
+// uniqueShiftNames returns the names of the active shifts in order of their first appearance
+func uniqueShiftNames(workingHours []models.WorkingHours) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, shift := range workingHours {
+		if shift.Active && !seen[shift.Name] {
+			seen[shift.Name] = true
+			names = append(names, shift.Name)
+		}
 	}
+	return names
+}
 
-	// Redirect to hours page after successful update
-	http.Redirect(w, r, "/hours", http.StatusSeeOther)
+// valueAt returns the value at index i, or an empty string if there is none
+func valueAt(values []string, i int) string {
+	if i < len(values) {
+		return values[i]
+	}
+	return ""
 }
@@ -0,0 +1,193 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// NotificationController handles notification channel requests
+type NotificationController struct {
+	services *services.Services
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(services *services.Services) *NotificationController {
+	return &NotificationController{
+		services: services,
+	}
+}
+
+// notificationsPageData represents the data for the notification channels page
+type notificationsPageData struct {
+	Title       string
+	CurrentPage string
+	Error       string
+	Success     string
+	Channels    []models.NotificationChannel
+	Kinds       map[string]string
+	Events      []string
+	EventNames  map[string]string
+	Form        *models.NotificationChannelForm
+	User        string
+}
+
+// Index handles GET /notifications
+func (c *NotificationController) Index(w http.ResponseWriter, r *http.Request) {
+	form := &models.NotificationChannelForm{Kind: models.ChannelKindSlack, Events: models.NotificationEvents, Active: true}
+	c.renderIndex(w, r, http.StatusOK, form, r.URL.Query().Get("error"))
+}
+
+// Create handles POST /notifications
+func (c *NotificationController) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := parseNotificationChannelForm(r)
+	if _, err := c.services.Notifications.CreateChannel(r.Context(), form); err != nil {
+		c.renderIndex(w, r, http.StatusBadRequest, form, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/notifications")+"?success="+url.QueryEscape("Notification channel added"), http.StatusSeeOther)
+}
+
+// Edit handles GET /notifications/{id}/edit
+func (c *NotificationController) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid notification channel ID", http.StatusBadRequest)
+		return
+	}
+
+	channel, err := c.services.Notifications.GetChannelByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Notification channel not found: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	form := &models.NotificationChannelForm{
+		Name:   channel.Name,
+		Kind:   channel.Kind,
+		Target: channel.Target,
+		Events: channel.Events,
+		Active: channel.Active,
+	}
+
+	c.renderEdit(w, r, http.StatusOK, channel, form, "")
+}
+
+// Update handles POST /notifications/{id}
+func (c *NotificationController) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid notification channel ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := parseNotificationChannelForm(r)
+	if _, err := c.services.Notifications.UpdateChannel(r.Context(), id, form); err != nil {
+		// Reload edit page with form data and error
+		channel, loadErr := c.services.Notifications.GetChannelByID(r.Context(), id)
+		if loadErr != nil {
+			http.Error(w, "Notification channel not found: "+loadErr.Error(), http.StatusNotFound)
+			return
+		}
+
+		c.renderEdit(w, r, http.StatusBadRequest, channel, form, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/notifications")+"?success="+url.QueryEscape("Notification channel updated"), http.StatusSeeOther)
+}
+
+// Delete handles POST /notifications/{id}/delete
+func (c *NotificationController) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid notification channel ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.services.Notifications.DeleteChannel(r.Context(), id); err != nil {
+		http.Redirect(w, r, teamURL(r, "/notifications")+"?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/notifications"), http.StatusSeeOther)
+}
+
+// renderIndex renders the notification channels page with the given form and error
+func (c *NotificationController) renderIndex(w http.ResponseWriter, r *http.Request, statusCode int, form *models.NotificationChannelForm, errorMessage string) {
+	channels, err := c.services.Notifications.GetChannels(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load notification channels: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := notificationsPageData{
+		Title:       "Notifications",
+		CurrentPage: "team",
+		Error:       errorMessage,
+		Success:     r.URL.Query().Get("success"),
+		Channels:    channels,
+		Kinds:       models.ChannelKindNames,
+		Events:      models.NotificationEvents,
+		EventNames:  models.NotificationEventNames,
+		Form:        form,
+		User:        getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "notifications", "templates/notifications.html", templateData)
+}
+
+// renderEdit renders the notification channel edit page
+func (c *NotificationController) renderEdit(w http.ResponseWriter, r *http.Request, statusCode int, channel *models.NotificationChannel, form *models.NotificationChannelForm, errorMessage string) {
+	templateData := struct {
+		Title       string
+		CurrentPage string
+		Error       string
+		Success     string
+		Channel     *models.NotificationChannel
+		Kinds       map[string]string
+		Events      []string
+		EventNames  map[string]string
+		Form        *models.NotificationChannelForm
+		User        string
+	}{
+		Title:       "Edit Notification Channel",
+		CurrentPage: "team",
+		Error:       errorMessage,
+		Success:     "",
+		Channel:     channel,
+		Kinds:       models.ChannelKindNames,
+		Events:      models.NotificationEvents,
+		EventNames:  models.NotificationEventNames,
+		Form:        form,
+		User:        getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "notification_edit", "templates/notification_edit.html", templateData)
+}
+
+// parseNotificationChannelForm reads the notification channel form fields from a parsed request
+func parseNotificationChannelForm(r *http.Request) *models.NotificationChannelForm {
+	return &models.NotificationChannelForm{
+		Name:   r.FormValue("name"),
+		Kind:   r.FormValue("kind"),
+		Target: r.FormValue("target"),
+		Events: r.Form["events"],
+		Active: r.FormValue("active") == "on",
+	}
+}
@@ -1,13 +1,17 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/blogem/eod-scheduler/models"
 	"github.com/blogem/eod-scheduler/services"
+	"github.com/blogem/eod-scheduler/teamctx"
 	"github.com/go-chi/chi/v5"
 )
 
@@ -51,7 +55,7 @@ func (c *ScheduleController) Index(w http.ResponseWriter, r *http.Request) {
 		User:        getUserNickname(r),
 	}
 
-	renderTemplate(w, "schedule", "templates/schedule.html", templateData)
+	renderTemplate(w, r, "schedule", "templates/schedule.html", templateData)
 }
 
 // Week handles GET /schedule/week/{date}
@@ -88,20 +92,20 @@ func (c *ScheduleController) Week(w http.ResponseWriter, r *http.Request) {
 		User:        getUserNickname(r),
 	}
 
-	renderTemplate(w, "schedule_week", "templates/schedule.html", templateData)
+	renderTemplate(w, r, "schedule_week", "templates/schedule.html", templateData)
 }
 
 // Generate handles POST /schedule/generate
 func (c *ScheduleController) Generate(w http.ResponseWriter, r *http.Request) {
 	// Always force regenerate - simplifies the interface
-	result, err := c.services.Schedule.GenerateSchedule(r.Context(), true)
+	result, err := c.services.Schedule.RunGeneration(r.Context(), models.GenerationTriggerManual)
 	if err != nil {
 		http.Error(w, "Failed to generate schedule: "+err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	// Redirect back to schedule page with generation result
-	redirectURL := "/schedule"
+	redirectURL := teamURL(r, "/schedule")
 	if !result.Success {
 		redirectURL += "?error=" + result.Message
 	} else {
@@ -111,6 +115,124 @@ func (c *ScheduleController) Generate(w http.ResponseWriter, r *http.Request) {
 	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
 
+// Preview handles GET /schedule/preview. It shows what generating the schedule again would
+// change, so the user can confirm or cancel before anything is written.
+func (c *ScheduleController) Preview(w http.ResponseWriter, r *http.Request) {
+	preview, err := c.services.Schedule.PreviewSchedule(r.Context())
+	if err != nil {
+		if wantsJSON(r) {
+			renderJSONError(w, http.StatusInternalServerError, "Failed to preview schedule: "+err.Error())
+			return
+		}
+		http.Error(w, "Failed to preview schedule: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	if wantsJSON(r) {
+		renderJSON(w, http.StatusOK, preview)
+		return
+	}
+
+	// A generation that would fail can't be confirmed
+	errorMessage := ""
+	if !preview.Result.Success {
+		errorMessage = preview.Result.Message
+	}
+
+	templateData := struct {
+		Title       string
+		CurrentPage string
+		Error       string
+		Success     string
+		Preview     *models.SchedulePreview
+		User        string
+	}{
+		Title:       "Preview Schedule",
+		CurrentPage: "schedule",
+		Error:       errorMessage,
+		Success:     "",
+		Preview:     preview,
+		User:        getUserNickname(r),
+	}
+
+	renderTemplate(w, r, "schedule_preview", "templates/schedule_preview.html", templateData)
+}
+
+// Export handles GET /schedule/export. The format is csv (default) or ics, the optional layer
+// limits the export to working hours or on-call entries. It exports the current week and the
+// generation horizon after it unless from and to are given.
+func (c *ScheduleController) Export(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	format := query.Get("format")
+	if format == "" {
+		format = services.ExportFormatCSV
+	}
+	if format != services.ExportFormatCSV && format != services.ExportFormatICS {
+		http.Error(w, "Unsupported export format: "+format, http.StatusBadRequest)
+		return
+	}
+
+	layer := query.Get("layer")
+	if _, ok := models.ScheduleLayerNames[layer]; layer != "" && !ok {
+		http.Error(w, "Unknown schedule layer: "+layer, http.StatusBadRequest)
+		return
+	}
+
+	from, err := dateParam(r, "from", models.GetCurrentWeek().Start)
+	if err != nil {
+		http.Error(w, "Invalid date format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	state, err := c.services.Schedule.GetSettings(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load schedule settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	to, err := dateParam(r, "to", state.HorizonEnd(from))
+	if err != nil {
+		http.Error(w, "Invalid date format: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entries, err := c.services.Schedule.GetScheduleByDateRange(r.Context(), from, to)
+	if err != nil {
+		http.Error(w, "Failed to load schedule: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	entries = services.FilterByLayer(entries, layer)
+
+	name := "schedule"
+	if team := teamctx.GetTeam(r.Context()); team != nil {
+		name = team.Slug + "-schedule"
+	}
+	if layer != "" {
+		name += "-" + strings.ReplaceAll(layer, "_", "-")
+	}
+
+	if format == services.ExportFormatICS {
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".ics"))
+		err = services.WriteScheduleICS(w, entries, "EOD "+name)
+	} else {
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name+".csv"))
+		err = services.WriteScheduleCSV(w, entries)
+	}
+	if err != nil {
+		http.Error(w, "Failed to export schedule: "+err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// dateParam reads a date from the query string, or returns the fallback when it is missing
+func dateParam(r *http.Request, name string, fallback time.Time) (time.Time, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return models.ParseDate(value)
+}
+
 // ShowTakeoverForm handles GET /schedule/takeover
 func (c *ScheduleController) ShowTakeoverForm(w http.ResponseWriter, r *http.Request) {
 	// Get all active team members for the dropdown
@@ -152,6 +274,7 @@ func (c *ScheduleController) ShowTakeoverForm(w http.ResponseWriter, r *http.Req
 		TeamMembers []models.TeamMember
 		Entries     []models.ScheduleEntry
 		Form        *models.TakeoverForm
+		Warnings    []string
 		Redirect    string
 		User        string
 	}{
@@ -166,7 +289,7 @@ func (c *ScheduleController) ShowTakeoverForm(w http.ResponseWriter, r *http.Req
 		User:        getUserNickname(r),
 	}
 
-	renderTemplate(w, "schedule_takeover", "templates/schedule_takeover.html", templateData)
+	renderTemplate(w, r, "schedule_takeover", "templates/schedule_takeover.html", templateData)
 }
 
 // CreateTakeover handles POST /schedule/takeover
@@ -195,44 +318,7 @@ func (c *ScheduleController) CreateTakeover(w http.ResponseWriter, r *http.Reque
 
 	// Validate the form
 	if errors := form.Validate(); len(errors) > 0 {
-		// Reload form with error
-		teamMembers, loadErr := c.services.Team.GetActiveMembers(r.Context())
-		if loadErr != nil {
-			http.Error(w, "Failed to load team members: "+loadErr.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		today := time.Now().Truncate(24 * time.Hour)
-		endDate := today.AddDate(0, 0, 14)
-		entries, loadErr := c.services.Schedule.GetScheduleByDateRange(r.Context(), today, endDate)
-		if loadErr != nil {
-			http.Error(w, "Failed to load schedule entries: "+loadErr.Error(), http.StatusInternalServerError)
-			return
-		}
-
-		templateData := struct {
-			Title       string
-			CurrentPage string
-			Error       string
-			Success     string
-			TeamMembers []models.TeamMember
-			Entries     []models.ScheduleEntry
-			Form        *models.TakeoverForm
-			Redirect    string
-			User        string
-		}{
-			Title:       "Take Over Shift",
-			CurrentPage: "schedule",
-			Error:       strings.Join(errors, ", "),
-			Success:     "",
-			TeamMembers: teamMembers,
-			Entries:     entries,
-			Form:        form,
-			Redirect:    r.FormValue("redirect"),
-			User:        getUserNickname(r),
-		}
-
-		renderTemplateWithStatus(w, http.StatusBadRequest, "schedule_takeover_error", "templates/schedule_takeover.html", templateData)
+		c.renderTakeoverError(w, r, http.StatusBadRequest, form, strings.Join(errors, ", "), nil)
 		return
 	}
 
@@ -249,9 +335,16 @@ func (c *ScheduleController) CreateTakeover(w http.ResponseWriter, r *http.Reque
 		TeamMemberID: newTeamMemberID,
 		StartTime:    entry.StartTime,
 		EndTime:      entry.EndTime,
+		Confirmed:    r.FormValue("confirmed") == "true",
 	}
 
 	_, err = c.services.Schedule.CreateManualOverride(r.Context(), scheduleEntryID, updateForm)
+	var violation *services.RuleViolationError
+	if errors.As(err, &violation) {
+		// Ask for confirmation before breaking the assignment rules
+		c.renderTakeoverError(w, r, http.StatusConflict, form, "", violation.Violations)
+		return
+	}
 	if err != nil {
 		http.Error(w, "Failed to process takeover: "+err.Error(), http.StatusInternalServerError)
 		return
@@ -260,17 +353,61 @@ func (c *ScheduleController) CreateTakeover(w http.ResponseWriter, r *http.Reque
 	// Redirect to originating page or schedule page by default after successful takeover
 	redirectURL := r.FormValue("redirect")
 	if redirectURL == "" {
-		redirectURL = "/schedule"
+		redirectURL = teamURL(r, "/schedule")
 	}
 	// Add success message as URL parameter
 	if redirectURL == "/" {
-		redirectURL += "?success=Shift takeover completed successfully"
+		redirectURL += "?success=" + url.QueryEscape(entry.GetRoleName()+" shift takeover completed successfully")
 	} else {
-		redirectURL += "?success=Shift takeover completed successfully"
+		redirectURL += "?success=" + url.QueryEscape(entry.GetRoleName()+" shift takeover completed successfully")
 	}
 	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
 
+// renderTakeoverError renders the takeover form again with an error, or with the assignment rules
+// the takeover breaks for the user to confirm
+func (c *ScheduleController) renderTakeoverError(w http.ResponseWriter, r *http.Request, statusCode int, form *models.TakeoverForm, errorMessage string, warnings []string) {
+	teamMembers, err := c.services.Team.GetActiveMembers(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load team members: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	endDate := today.AddDate(0, 0, 14)
+	entries, err := c.services.Schedule.GetScheduleByDateRange(r.Context(), today, endDate)
+	if err != nil {
+		http.Error(w, "Failed to load schedule entries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		Title       string
+		CurrentPage string
+		Error       string
+		Success     string
+		TeamMembers []models.TeamMember
+		Entries     []models.ScheduleEntry
+		Form        *models.TakeoverForm
+		Warnings    []string
+		Redirect    string
+		User        string
+	}{
+		Title:       "Take Over Shift",
+		CurrentPage: "schedule",
+		Error:       errorMessage,
+		Success:     "",
+		TeamMembers: teamMembers,
+		Entries:     entries,
+		Form:        form,
+		Warnings:    warnings,
+		Redirect:    r.FormValue("redirect"),
+		User:        getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "schedule_takeover_error", "templates/schedule_takeover.html", templateData)
+}
+
 // ShowEditForm handles GET /schedule/edit/{id}
 func (c *ScheduleController) ShowEditForm(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -307,6 +444,7 @@ func (c *ScheduleController) ShowEditForm(w http.ResponseWriter, r *http.Request
 		Entry       *models.ScheduleEntry
 		TeamMembers []models.TeamMember
 		Form        *models.ScheduleEntryForm
+		Warnings    []string
 		Redirect    string
 		User        string
 	}{
@@ -321,7 +459,7 @@ func (c *ScheduleController) ShowEditForm(w http.ResponseWriter, r *http.Request
 		User:        getUserNickname(r),
 	}
 
-	renderTemplate(w, "schedule_edit", "templates/schedule_edit.html", templateData)
+	renderTemplate(w, r, "schedule_edit", "templates/schedule_edit.html", templateData)
 }
 
 // UpdateEntry handles POST /schedule/edit/{id}
@@ -349,10 +487,19 @@ func (c *ScheduleController) UpdateEntry(w http.ResponseWriter, r *http.Request)
 		TeamMemberID: teamMemberID,
 		StartTime:    r.FormValue("start_time"),
 		EndTime:      r.FormValue("end_time"),
+		Confirmed:    r.FormValue("confirmed") == "true",
 	}
 
 	_, err = c.services.Schedule.UpdateScheduleEntry(r.Context(), id, form)
 	if err != nil {
+		// Breaking the assignment rules needs confirmation, other errors are shown as they are
+		statusCode, errorMessage := http.StatusBadRequest, err.Error()
+		var violation *services.RuleViolationError
+		var warnings []string
+		if errors.As(err, &violation) {
+			statusCode, errorMessage, warnings = http.StatusConflict, "", violation.Violations
+		}
+
 		// Reload form with error
 		entry, loadErr := c.services.Schedule.GetScheduleEntry(r.Context(), id)
 		if loadErr != nil {
@@ -374,28 +521,30 @@ func (c *ScheduleController) UpdateEntry(w http.ResponseWriter, r *http.Request)
 			Entry       *models.ScheduleEntry
 			TeamMembers []models.TeamMember
 			Form        *models.ScheduleEntryForm
+			Warnings    []string
 			Redirect    string
 			User        string
 		}{
 			Title:       "Edit Schedule Entry",
 			CurrentPage: "schedule",
-			Error:       err.Error(),
+			Error:       errorMessage,
 			Success:     "",
 			Entry:       entry,
 			TeamMembers: teamMembers,
 			Form:        form,
+			Warnings:    warnings,
 			Redirect:    r.FormValue("redirect"),
 			User:        getUserNickname(r),
 		}
 
-		renderTemplateWithStatus(w, http.StatusBadRequest, "schedule_edit_error", "templates/schedule_edit.html", templateData)
+		renderTemplateWithStatus(w, r, statusCode, "schedule_edit_error", "templates/schedule_edit.html", templateData)
 		return
 	}
 
 	// Redirect to originating page or schedule page by default
 	redirectURL := r.FormValue("redirect")
 	if redirectURL == "" {
-		redirectURL = "/schedule"
+		redirectURL = teamURL(r, "/schedule")
 	}
 	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
@@ -413,7 +562,7 @@ func (c *ScheduleController) RemoveOverride(w http.ResponseWriter, r *http.Reque
 		// Redirect back with error to originating page or schedule page
 		redirectURL := r.FormValue("redirect")
 		if redirectURL == "" {
-			redirectURL = "/schedule"
+			redirectURL = teamURL(r, "/schedule")
 		}
 		http.Redirect(w, r, redirectURL+"?error="+err.Error(), http.StatusSeeOther)
 		return
@@ -422,7 +571,102 @@ func (c *ScheduleController) RemoveOverride(w http.ResponseWriter, r *http.Reque
 	// Redirect to originating page or schedule page by default after successful removal
 	redirectURL := r.FormValue("redirect")
 	if redirectURL == "" {
-		redirectURL = "/schedule"
+		redirectURL = teamURL(r, "/schedule")
 	}
 	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
 }
+
+// ShowSettings handles GET /schedule/settings
+func (c *ScheduleController) ShowSettings(w http.ResponseWriter, r *http.Request) {
+	state, err := c.services.Schedule.GetSettings(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load schedule settings: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	form := &models.ScheduleSettingsForm{
+		RotationStrategy:   state.GetRotationStrategy(),
+		RotationSeed:       strconv.FormatInt(state.RotationSeed, 10),
+		RotationPeriod:     state.GetRotationPeriod(),
+		RotationPeriodDays: strconv.Itoa(max(state.RotationPeriodDays, 2)),
+		HandoverDay:        strconv.Itoa(state.HandoverDay),
+		BackupEnabled:      state.BackupEnabled,
+		OnCallEnabled:      state.OnCallEnabled,
+		MinGapDays:         ruleValue(state.MinGapDays),
+		MaxShiftsPerWeek:   ruleValue(state.MaxShiftsPerWeek),
+		MaxShiftsPerMonth:  ruleValue(state.MaxShiftsPerMonth),
+		HorizonLength:      strconv.Itoa(state.GetHorizonLength()),
+		HorizonUnit:        state.GetHorizonUnit(),
+		UpToDateDays:       strconv.Itoa(state.GetUpToDateDays()),
+	}
+
+	c.renderSettings(w, r, http.StatusOK, form, "")
+}
+
+// UpdateSettings handles POST /schedule/settings
+func (c *ScheduleController) UpdateSettings(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := &models.ScheduleSettingsForm{
+		RotationStrategy:   r.FormValue("rotation_strategy"),
+		RotationSeed:       r.FormValue("rotation_seed"),
+		RotationPeriod:     r.FormValue("rotation_period"),
+		RotationPeriodDays: r.FormValue("rotation_period_days"),
+		HandoverDay:        r.FormValue("handover_day"),
+		BackupEnabled:      r.FormValue("backup_enabled") == "on",
+		OnCallEnabled:      r.FormValue("on_call_enabled") == "on",
+		MinGapDays:         r.FormValue("min_gap_days"),
+		MaxShiftsPerWeek:   r.FormValue("max_shifts_per_week"),
+		MaxShiftsPerMonth:  r.FormValue("max_shifts_per_month"),
+		HorizonLength:      r.FormValue("horizon_length"),
+		HorizonUnit:        r.FormValue("horizon_unit"),
+		UpToDateDays:       r.FormValue("up_to_date_days"),
+	}
+
+	if _, err := c.services.Schedule.UpdateSettings(r.Context(), form); err != nil {
+		c.renderSettings(w, r, http.StatusBadRequest, form, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/schedule/settings")+"?success=Settings saved. Regenerate the schedule to apply them.", http.StatusSeeOther)
+}
+
+// ruleValue shows an assignment rule in the settings form, leaving the field empty when the rule is off
+func ruleValue(value int) string {
+	if value <= 0 {
+		return ""
+	}
+	return strconv.Itoa(value)
+}
+
+// renderSettings renders the schedule settings page with the given form and error
+func (c *ScheduleController) renderSettings(w http.ResponseWriter, r *http.Request, statusCode int, form *models.ScheduleSettingsForm, errorMessage string) {
+	templateData := struct {
+		Title       string
+		CurrentPage string
+		Error       string
+		Success     string
+		Form        *models.ScheduleSettingsForm
+		Strategies  map[string]string
+		Periods     map[string]string
+		Units       map[string]string
+		DayNames    map[int]string
+		User        string
+	}{
+		Title:       "Schedule Settings",
+		CurrentPage: "schedule",
+		Error:       errorMessage,
+		Success:     r.URL.Query().Get("success"),
+		Form:        form,
+		Strategies:  models.RotationStrategyNames,
+		Periods:     models.RotationPeriodNames,
+		Units:       models.HorizonUnitNames,
+		DayNames:    c.services.WorkingHours.GetDayNames(),
+		User:        getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "schedule_settings", "templates/schedule_settings.html", templateData)
+}
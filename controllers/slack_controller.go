@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/blogem/eod-scheduler/services"
+)
+
+// SlackController handles the slash commands Slack sends
+type SlackController struct {
+	services *services.Services
+}
+
+// NewSlackController creates a new slack controller
+func NewSlackController(services *services.Services) *SlackController {
+	return &SlackController{
+		services: services,
+	}
+}
+
+// Command handles POST /slack/commands. Slack shows the reply to the user, so failures are
+// answered with a message instead of an error status.
+func (c *SlackController) Command(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Slack checks the certificate of the endpoint from time to time
+	if r.FormValue("ssl_check") == "1" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	command := &services.SlackCommand{
+		Command:  r.FormValue("command"),
+		Text:     r.FormValue("text"),
+		UserID:   r.FormValue("user_id"),
+		UserName: r.FormValue("user_name"),
+	}
+
+	reply, err := c.services.SlackCommands.Handle(r.Context(), command)
+	if err != nil {
+		log.Printf("Failed to handle slack command %s %q: %v", command.Command, command.Text, err)
+		reply = &services.SlackReply{ResponseType: services.SlackReplyEphemeral, Text: "Something went wrong: " + err.Error()}
+	}
+
+	renderJSON(w, http.StatusOK, reply)
+}
@@ -3,6 +3,7 @@ package controllers
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/blogem/eod-scheduler/models"
@@ -30,25 +31,37 @@ func (c *TeamController) Index(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	rotationOrder, err := c.services.Team.GetRotationOrder(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load rotation order: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	templateData := struct {
-		Title       string
-		CurrentPage string
-		Error       string
-		Success     string
-		Members     []models.TeamMember
-		Form        *models.TeamMemberForm
-		User        string
+		Title             string
+		CurrentPage       string
+		Error             string
+		Success           string
+		Members           []models.TeamMember
+		RotationOrder     *services.RotationOrder
+		Form              *models.TeamMemberForm
+		DayNames          map[int]string
+		PreferenceOptions []models.WeekdayPreferenceOption
+		User              string
 	}{
-		Title:       "Team Management",
-		CurrentPage: "team",
-		Error:       "",
-		Success:     "",
-		Members:     members,
-		Form:        &models.TeamMemberForm{Active: true}, // Default to active for new members
-		User:        getUserNickname(r),
+		Title:             "Team Management",
+		CurrentPage:       "team",
+		Error:             "",
+		Success:           "",
+		Members:           members,
+		RotationOrder:     rotationOrder,
+		Form:              &models.TeamMemberForm{Active: true, Participation: models.FullParticipation}, // Default to active for new members
+		DayNames:          models.DayNames,
+		PreferenceOptions: models.WeekdayPreferenceOptions,
+		User:              getUserNickname(r),
 	}
 
-	renderTemplate(w, "team", "templates/team.html", templateData)
+	renderTemplate(w, r, "team", "templates/team.html", templateData)
 }
 
 // Create handles POST /team
@@ -58,15 +71,7 @@ func (c *TeamController) Create(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get the last value for 'active' (checkbox will override hidden field if checked)
-	activeValues := r.Form["active"]
-	isActive := len(activeValues) > 0 && activeValues[len(activeValues)-1] == "on"
-
-	form := &models.TeamMemberForm{
-		Name:        r.FormValue("name"),
-		SlackHandle: r.FormValue("slack_handle"),
-		Active:      isActive,
-	}
+	form := parseTeamMemberForm(r)
 
 	_, err := c.services.Team.CreateMember(r.Context(), form)
 	if err != nil {
@@ -77,30 +82,42 @@ func (c *TeamController) Create(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 
+		rotationOrder, loadErr := c.services.Team.GetRotationOrder(r.Context())
+		if loadErr != nil {
+			http.Error(w, "Failed to load rotation order: "+loadErr.Error(), http.StatusInternalServerError)
+			return
+		}
+
 		templateData := struct {
-			Title       string
-			CurrentPage string
-			Error       string
-			Success     string
-			Members     []models.TeamMember
-			Form        *models.TeamMemberForm
-			User        string
+			Title             string
+			CurrentPage       string
+			Error             string
+			Success           string
+			Members           []models.TeamMember
+			RotationOrder     *services.RotationOrder
+			Form              *models.TeamMemberForm
+			DayNames          map[int]string
+			PreferenceOptions []models.WeekdayPreferenceOption
+			User              string
 		}{
-			Title:       "Team Management",
-			CurrentPage: "team",
-			Error:       err.Error(),
-			Success:     "",
-			Members:     members,
-			Form:        form,
-			User:        getUserNickname(r),
+			Title:             "Team Management",
+			CurrentPage:       "team",
+			Error:             err.Error(),
+			Success:           "",
+			Members:           members,
+			RotationOrder:     rotationOrder,
+			Form:              form,
+			DayNames:          models.DayNames,
+			PreferenceOptions: models.WeekdayPreferenceOptions,
+			User:              getUserNickname(r),
 		}
 
-		renderTemplateWithStatus(w, http.StatusBadRequest, "team_create_error", "templates/team.html", templateData)
+		renderTemplateWithStatus(w, r, http.StatusBadRequest, "team_create_error", "templates/team.html", templateData)
 		return
 	}
 
 	// Redirect to team page after successful creation
-	http.Redirect(w, r, "/team", http.StatusSeeOther)
+	http.Redirect(w, r, teamURL(r, "/team"), http.StatusSeeOther)
 }
 
 // Edit handles GET /team/{id}/edit
@@ -119,30 +136,43 @@ func (c *TeamController) Edit(w http.ResponseWriter, r *http.Request) {
 	}
 
 	form := &models.TeamMemberForm{
-		Name:        member.Name,
-		SlackHandle: member.SlackHandle,
-		Active:      member.Active,
+		Name:               member.Name,
+		SlackHandle:        member.SlackHandle,
+		Email:              member.Email,
+		Active:             member.Active,
+		Participation:      member.GetParticipation(),
+		WorkDays:           member.WorkDays,
+		Preferences:        member.Preferences,
+		MutedNotifications: member.MutedNotifications,
 	}
 
 	templateData := struct {
-		Title       string
-		CurrentPage string
-		Error       string
-		Success     string
-		Member      *models.TeamMember
-		Form        *models.TeamMemberForm
-		User        string
+		Title                  string
+		CurrentPage            string
+		Error                  string
+		Success                string
+		Member                 *models.TeamMember
+		Form                   *models.TeamMemberForm
+		DayNames               map[int]string
+		PreferenceOptions      []models.WeekdayPreferenceOption
+		NotificationEvents     []string
+		NotificationEventNames map[string]string
+		User                   string
 	}{
-		Title:       "Edit Team Member",
-		CurrentPage: "team",
-		Error:       "",
-		Success:     "",
-		Member:      member,
-		Form:        form,
-		User:        getUserNickname(r),
+		Title:                  "Edit Team Member",
+		CurrentPage:            "team",
+		Error:                  "",
+		Success:                "",
+		Member:                 member,
+		Form:                   form,
+		DayNames:               models.DayNames,
+		PreferenceOptions:      models.WeekdayPreferenceOptions,
+		NotificationEvents:     models.NotificationEvents,
+		NotificationEventNames: models.NotificationEventNames,
+		User:                   getUserNickname(r),
 	}
 
-	renderTemplate(w, "team_edit", "templates/team_edit.html", templateData)
+	renderTemplate(w, r, "team_edit", "templates/team_edit.html", templateData)
 }
 
 // Update handles POST /team/{id}
@@ -162,17 +192,7 @@ func (c *TeamController) Update(w http.ResponseWriter, r *http.Request) {
 	// Debug: Print all form values to see what's being sent
 	fmt.Printf("Debug - All form values: %+v\n", r.Form)
 
-	// Get the last value for 'active' (checkbox will override hidden field if checked)
-	activeValues := r.Form["active"]
-	isActive := len(activeValues) > 0 && activeValues[len(activeValues)-1] == "on"
-
-	fmt.Printf("Debug - Active values: %v, isActive: %v\n", activeValues, isActive)
-
-	form := &models.TeamMemberForm{
-		Name:        r.FormValue("name"),
-		SlackHandle: r.FormValue("slack_handle"),
-		Active:      isActive,
-	}
+	form := parseTeamMemberForm(r)
 
 	_, err = c.services.Team.UpdateMember(r.Context(), id, form)
 	if err != nil {
@@ -184,29 +204,37 @@ func (c *TeamController) Update(w http.ResponseWriter, r *http.Request) {
 		}
 
 		templateData := struct {
-			Title       string
-			CurrentPage string
-			Error       string
-			Success     string
-			Member      *models.TeamMember
-			Form        *models.TeamMemberForm
-			User        string
+			Title                  string
+			CurrentPage            string
+			Error                  string
+			Success                string
+			Member                 *models.TeamMember
+			Form                   *models.TeamMemberForm
+			DayNames               map[int]string
+			PreferenceOptions      []models.WeekdayPreferenceOption
+			NotificationEvents     []string
+			NotificationEventNames map[string]string
+			User                   string
 		}{
-			Title:       "Edit Team Member",
-			CurrentPage: "team",
-			Error:       err.Error(),
-			Success:     "",
-			Member:      member,
-			Form:        form,
-			User:        getUserNickname(r),
+			Title:                  "Edit Team Member",
+			CurrentPage:            "team",
+			Error:                  err.Error(),
+			Success:                "",
+			Member:                 member,
+			Form:                   form,
+			DayNames:               models.DayNames,
+			PreferenceOptions:      models.WeekdayPreferenceOptions,
+			NotificationEvents:     models.NotificationEvents,
+			NotificationEventNames: models.NotificationEventNames,
+			User:                   getUserNickname(r),
 		}
 
-		renderTemplateWithStatus(w, http.StatusBadRequest, "team_update_error", "templates/team_edit.html", templateData)
+		renderTemplateWithStatus(w, r, http.StatusBadRequest, "team_update_error", "templates/team_edit.html", templateData)
 		return
 	}
 
 	// Redirect to team page after successful update
-	http.Redirect(w, r, "/team", http.StatusSeeOther)
+	http.Redirect(w, r, teamURL(r, "/team"), http.StatusSeeOther)
 }
 
 // Delete handles POST /team/{id}/delete
@@ -221,10 +249,92 @@ func (c *TeamController) Delete(w http.ResponseWriter, r *http.Request) {
 	if err := c.services.Team.DeleteMember(r.Context(), id); err != nil {
 		// For delete errors, we'll redirect back with error in URL params
 		// (In a real app, you might want to use sessions/flash messages)
-		http.Redirect(w, r, "/team?error="+err.Error(), http.StatusSeeOther)
+		http.Redirect(w, r, teamURL(r, "/team")+"?error="+err.Error(), http.StatusSeeOther)
 		return
 	}
 
 	// Redirect to team page after successful deletion
-	http.Redirect(w, r, "/team", http.StatusSeeOther)
+	http.Redirect(w, r, teamURL(r, "/team"), http.StatusSeeOther)
+}
+
+// Move handles POST /team/{id}/move
+func (c *TeamController) Move(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		http.Error(w, "Invalid team member ID", http.StatusBadRequest)
+		return
+	}
+
+	var offset int
+	switch r.FormValue("direction") {
+	case "up":
+		offset = -1
+	case "down":
+		offset = 1
+	default:
+		http.Error(w, "Invalid direction", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.services.Team.MoveMember(r.Context(), id, offset); err != nil {
+		http.Redirect(w, r, teamURL(r, "/team")+"?error="+err.Error(), http.StatusSeeOther)
+		return
+	}
+
+	// Redirect back to the rotation order
+	http.Redirect(w, r, teamURL(r, "/team#rotation-order"), http.StatusSeeOther)
+}
+
+// parseTeamMemberForm reads the team member form fields from a parsed request
+func parseTeamMemberForm(r *http.Request) *models.TeamMemberForm {
+	// Get the last value for 'active' (checkbox will override hidden field if checked)
+	activeValues := r.Form["active"]
+	isActive := len(activeValues) > 0 && activeValues[len(activeValues)-1] == "on"
+
+	// An unreadable participation fails validation instead of silently meaning full participation
+	participation := models.FullParticipation
+	if value := r.FormValue("participation"); value != "" {
+		var err error
+		if participation, err = strconv.Atoi(value); err != nil {
+			participation = -1
+		}
+	}
+
+	var workDays []int
+	for _, value := range r.Form["work_days"] {
+		day, err := strconv.Atoi(value)
+		if err != nil {
+			day = -1
+		}
+		workDays = append(workDays, day)
+	}
+
+	var preferences []models.WeekdayPreference
+	for day := range models.DayNames {
+		if preference := models.ParseWeekdayPreference(day, r.FormValue(fmt.Sprintf("preference_%d", day))); preference != nil {
+			preferences = append(preferences, *preference)
+		}
+	}
+
+	// Only the edit page has notification checkboxes, its marker tells them all unchecked from a form without them
+	var muted []string
+	if r.FormValue("notifications") == "on" {
+		for _, event := range models.NotificationEvents {
+			if !slices.Contains(r.Form["notify"], event) {
+				muted = append(muted, event)
+			}
+		}
+	}
+
+	return &models.TeamMemberForm{
+		Name:               r.FormValue("name"),
+		SlackHandle:        r.FormValue("slack_handle"),
+		Email:              r.FormValue("email"),
+		Active:             isActive,
+		Participation:      participation,
+		WorkDays:           workDays,
+		Preferences:        preferences,
+		MutedNotifications: muted,
+	}
 }
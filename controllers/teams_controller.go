@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/services"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// TeamsController handles requests for the teams sharing the deployment
+type TeamsController struct {
+	services *services.Services
+}
+
+// NewTeamsController creates a new teams controller
+func NewTeamsController(services *services.Services) *TeamsController {
+	return &TeamsController{
+		services: services,
+	}
+}
+
+// Index handles GET /teams
+func (c *TeamsController) Index(w http.ResponseWriter, r *http.Request) {
+	c.renderIndex(w, r, http.StatusOK, &models.TeamForm{}, r.URL.Query().Get("error"))
+}
+
+// Create handles POST /teams
+func (c *TeamsController) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := &models.TeamForm{
+		Name: r.FormValue("name"),
+		Slug: r.FormValue("slug"),
+	}
+
+	team, err := c.services.Teams.CreateTeam(r.Context(), form)
+	if err != nil {
+		c.renderIndex(w, r, http.StatusBadRequest, form, err.Error())
+		return
+	}
+
+	// Continue with adding the members of the new team
+	http.Redirect(w, r, team.Path()+"/team", http.StatusSeeOther)
+}
+
+// Rename handles POST /teams/{slug}/rename
+func (c *TeamsController) Rename(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := &models.TeamForm{
+		Name: r.FormValue("name"),
+		Slug: r.FormValue("slug"),
+	}
+
+	team := teamctx.GetTeam(r.Context())
+	if _, err := c.services.Teams.UpdateTeam(r.Context(), team.ID, form); err != nil {
+		http.Redirect(w, r, "/teams?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, "/teams?success="+url.QueryEscape("Team renamed"), http.StatusSeeOther)
+}
+
+// renderIndex renders the teams page with the given form and error
+func (c *TeamsController) renderIndex(w http.ResponseWriter, r *http.Request, statusCode int, form *models.TeamForm, errorMessage string) {
+	teams, err := c.services.Teams.GetAllTeams(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load teams: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		Title       string
+		CurrentPage string
+		Error       string
+		Success     string
+		Teams       []models.Team
+		Form        *models.TeamForm
+		User        string
+	}{
+		Title:       "Teams",
+		CurrentPage: "teams",
+		Error:       errorMessage,
+		Success:     r.URL.Query().Get("success"),
+		Teams:       teams,
+		Form:        form,
+		User:        getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "teams", "templates/teams.html", templateData)
+}
@@ -0,0 +1,232 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// TimeOffController handles time off requests of team members
+type TimeOffController struct {
+	services *services.Services
+}
+
+// NewTimeOffController creates a new time off controller
+func NewTimeOffController(services *services.Services) *TimeOffController {
+	return &TimeOffController{
+		services: services,
+	}
+}
+
+// timeOffPageData represents the data for the time off page
+type timeOffPageData struct {
+	Title       string
+	CurrentPage string
+	Error       string
+	Success     string
+	Member      *models.TeamMember
+	Periods     []services.TimeOffPeriod
+	Form        *models.TimeOffForm
+	User        string
+}
+
+// Index handles GET /team/{id}/time-off
+func (c *TimeOffController) Index(w http.ResponseWriter, r *http.Request) {
+	member, ok := c.loadMember(w, r)
+	if !ok {
+		return
+	}
+
+	periods, err := c.services.TimeOff.GetMemberTimeOff(r.Context(), member.ID)
+	if err != nil {
+		c.renderError(w, r, http.StatusInternalServerError, "Failed to load time off: "+err.Error())
+		return
+	}
+
+	if wantsJSON(r) {
+		renderJSON(w, http.StatusOK, struct {
+			Member  *models.TeamMember       `json:"member"`
+			TimeOff []services.TimeOffPeriod `json:"time_off"`
+		}{member, periods})
+		return
+	}
+
+	templateData := timeOffPageData{
+		Title:       "Time Off - " + member.Name,
+		CurrentPage: "team",
+		Error:       r.URL.Query().Get("error"),
+		Success:     r.URL.Query().Get("success"),
+		Member:      member,
+		Periods:     periods,
+		Form:        &models.TimeOffForm{},
+		User:        getUserNickname(r),
+	}
+
+	renderTemplate(w, r, "team_time_off", "templates/team_time_off.html", templateData)
+}
+
+// Create handles POST /team/{id}/time-off
+func (c *TimeOffController) Create(w http.ResponseWriter, r *http.Request) {
+	member, ok := c.loadMember(w, r)
+	if !ok {
+		return
+	}
+
+	form := &models.TimeOffForm{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(form); err != nil {
+			renderJSONError(w, http.StatusBadRequest, "Failed to parse request body: "+err.Error())
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		form.StartDate = r.FormValue("start_date")
+		form.EndDate = r.FormValue("end_date")
+		form.Reason = r.FormValue("reason")
+	}
+
+	period, err := c.services.TimeOff.AddTimeOff(r.Context(), member.ID, form)
+	if err != nil {
+		if wantsJSON(r) {
+			renderJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+
+		// Reload page with form data and error
+		periods, loadErr := c.services.TimeOff.GetMemberTimeOff(r.Context(), member.ID)
+		if loadErr != nil {
+			http.Error(w, "Failed to load time off: "+loadErr.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		templateData := timeOffPageData{
+			Title:       "Time Off - " + member.Name,
+			CurrentPage: "team",
+			Error:       err.Error(),
+			Member:      member,
+			Periods:     periods,
+			Form:        form,
+			User:        getUserNickname(r),
+		}
+
+		renderTemplateWithStatus(w, r, http.StatusBadRequest, "team_time_off_error", "templates/team_time_off.html", templateData)
+		return
+	}
+
+	if wantsJSON(r) {
+		renderJSON(w, http.StatusCreated, period)
+		return
+	}
+
+	message := "Time off added"
+	if len(period.Conflicts) > 0 {
+		message = fmt.Sprintf("Time off added. %s is still scheduled on %d day(s) during this period, you can reassign them below.", member.Name, len(period.Conflicts))
+	}
+	c.redirect(w, r, member.ID, "success", message)
+}
+
+// Delete handles POST /team/{id}/time-off/{timeOffID}/delete
+func (c *TimeOffController) Delete(w http.ResponseWriter, r *http.Request) {
+	memberID, timeOffID, ok := c.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	if err := c.services.TimeOff.DeleteTimeOff(r.Context(), memberID, timeOffID); err != nil {
+		if wantsJSON(r) {
+			renderJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.redirect(w, r, memberID, "error", err.Error())
+		return
+	}
+
+	if wantsJSON(r) {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+	c.redirect(w, r, memberID, "success", "Time off removed")
+}
+
+// Reassign handles POST /team/{id}/time-off/{timeOffID}/reassign
+func (c *TimeOffController) Reassign(w http.ResponseWriter, r *http.Request) {
+	memberID, timeOffID, ok := c.parseIDs(w, r)
+	if !ok {
+		return
+	}
+
+	reassigned, err := c.services.TimeOff.ReassignConflicts(r.Context(), memberID, timeOffID)
+	if err != nil {
+		if wantsJSON(r) {
+			renderJSONError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		c.redirect(w, r, memberID, "error", err.Error())
+		return
+	}
+
+	if wantsJSON(r) {
+		renderJSON(w, http.StatusOK, map[string]int{"reassigned": reassigned})
+		return
+	}
+	c.redirect(w, r, memberID, "success", fmt.Sprintf("Reassigned %d day(s) to the next available team members", reassigned))
+}
+
+// loadMember loads the team member from the URL, writing an error response if that fails
+func (c *TimeOffController) loadMember(w http.ResponseWriter, r *http.Request) (*models.TeamMember, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		c.renderError(w, r, http.StatusBadRequest, "Invalid team member ID")
+		return nil, false
+	}
+
+	member, err := c.services.Team.GetMemberByID(r.Context(), id)
+	if err != nil {
+		c.renderError(w, r, http.StatusNotFound, "Team member not found: "+err.Error())
+		return nil, false
+	}
+
+	return member, true
+}
+
+// parseIDs parses the team member and time off IDs from the URL
+func (c *TimeOffController) parseIDs(w http.ResponseWriter, r *http.Request) (int, int, bool) {
+	memberID, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		c.renderError(w, r, http.StatusBadRequest, "Invalid team member ID")
+		return 0, 0, false
+	}
+
+	timeOffID, err := strconv.Atoi(chi.URLParam(r, "timeOffID"))
+	if err != nil {
+		c.renderError(w, r, http.StatusBadRequest, "Invalid time off ID")
+		return 0, 0, false
+	}
+
+	return memberID, timeOffID, true
+}
+
+// renderError writes an error in the format the client asked for
+func (c *TimeOffController) renderError(w http.ResponseWriter, r *http.Request, statusCode int, message string) {
+	if wantsJSON(r) {
+		renderJSONError(w, statusCode, message)
+		return
+	}
+	http.Error(w, message, statusCode)
+}
+
+// redirect sends the browser back to the member's time off page with a message
+func (c *TimeOffController) redirect(w http.ResponseWriter, r *http.Request, memberID int, kind, message string) {
+	redirectURL := teamURL(r, fmt.Sprintf("/team/%d/time-off?%s=%s", memberID, kind, url.QueryEscape(message)))
+	http.Redirect(w, r, redirectURL, http.StatusSeeOther)
+}
@@ -0,0 +1,233 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/services"
+	"github.com/go-chi/chi/v5"
+)
+
+// WebhookController handles webhook requests
+type WebhookController struct {
+	services *services.Services
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(services *services.Services) *WebhookController {
+	return &WebhookController{
+		services: services,
+	}
+}
+
+// webhooksPageData represents the data for the webhooks page
+type webhooksPageData struct {
+	Title         string
+	CurrentPage   string
+	Error         string
+	Success       string
+	Subscriptions []models.WebhookSubscription
+	Events        []string
+	EventNames    map[string]string
+	Form          *models.WebhookSubscriptionForm
+	User          string
+}
+
+// Index handles GET /webhooks
+func (c *WebhookController) Index(w http.ResponseWriter, r *http.Request) {
+	form := &models.WebhookSubscriptionForm{Events: models.WebhookEvents, Active: true}
+	c.renderIndex(w, r, http.StatusOK, form, r.URL.Query().Get("error"))
+}
+
+// Create handles POST /webhooks
+func (c *WebhookController) Create(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := parseWebhookSubscriptionForm(r)
+	subscription, err := c.services.Webhooks.CreateSubscription(r.Context(), form)
+	if err != nil {
+		c.renderIndex(w, r, http.StatusBadRequest, form, err.Error())
+		return
+	}
+
+	// The edit page shows the secret, to set it up on the receiving end
+	http.Redirect(w, r, teamURL(r, "/webhooks/"+strconv.Itoa(subscription.ID)+"/edit")+"?success="+url.QueryEscape("Webhook added, verify its deliveries with the secret below"), http.StatusSeeOther)
+}
+
+// Edit handles GET /webhooks/{id}/edit
+func (c *WebhookController) Edit(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	subscription, err := c.services.Webhooks.GetSubscriptionByID(r.Context(), id)
+	if err != nil {
+		http.Error(w, "Webhook not found: "+err.Error(), http.StatusNotFound)
+		return
+	}
+
+	form := &models.WebhookSubscriptionForm{
+		Name:   subscription.Name,
+		URL:    subscription.URL,
+		Events: subscription.Events,
+		Active: subscription.Active,
+	}
+
+	c.renderEdit(w, r, http.StatusOK, subscription, form, "")
+}
+
+// Update handles POST /webhooks/{id}
+func (c *WebhookController) Update(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "Failed to parse form: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	form := parseWebhookSubscriptionForm(r)
+	if _, err := c.services.Webhooks.UpdateSubscription(r.Context(), id, form); err != nil {
+		// Reload edit page with form data and error
+		subscription, loadErr := c.services.Webhooks.GetSubscriptionByID(r.Context(), id)
+		if loadErr != nil {
+			http.Error(w, "Webhook not found: "+loadErr.Error(), http.StatusNotFound)
+			return
+		}
+
+		c.renderEdit(w, r, http.StatusBadRequest, subscription, form, err.Error())
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/webhooks")+"?success="+url.QueryEscape("Webhook updated"), http.StatusSeeOther)
+}
+
+// Delete handles POST /webhooks/{id}/delete
+func (c *WebhookController) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.services.Webhooks.DeleteSubscription(r.Context(), id); err != nil {
+		http.Redirect(w, r, teamURL(r, "/webhooks")+"?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/webhooks"), http.StatusSeeOther)
+}
+
+// Deliveries handles GET /webhooks/deliveries
+func (c *WebhookController) Deliveries(w http.ResponseWriter, r *http.Request) {
+	deliveries, err := c.services.Webhooks.GetDeliveries(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load webhook deliveries: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := struct {
+		Title       string
+		CurrentPage string
+		Error       string
+		Success     string
+		Deliveries  []models.WebhookDelivery
+		User        string
+	}{
+		Title:       "Webhook Deliveries",
+		CurrentPage: "team",
+		Error:       r.URL.Query().Get("error"),
+		Success:     r.URL.Query().Get("success"),
+		Deliveries:  deliveries,
+		User:        getUserNickname(r),
+	}
+
+	renderTemplate(w, r, "webhook_deliveries", "templates/webhook_deliveries.html", templateData)
+}
+
+// Retry handles POST /webhooks/deliveries/{id}/retry
+func (c *WebhookController) Retry(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid webhook delivery ID", http.StatusBadRequest)
+		return
+	}
+
+	if err := c.services.Webhooks.RetryDelivery(r.Context(), id); err != nil {
+		http.Redirect(w, r, teamURL(r, "/webhooks/deliveries")+"?error="+url.QueryEscape(err.Error()), http.StatusSeeOther)
+		return
+	}
+
+	http.Redirect(w, r, teamURL(r, "/webhooks/deliveries")+"?success="+url.QueryEscape("Delivery queued again"), http.StatusSeeOther)
+}
+
+// renderIndex renders the webhooks page with the given form and error
+func (c *WebhookController) renderIndex(w http.ResponseWriter, r *http.Request, statusCode int, form *models.WebhookSubscriptionForm, errorMessage string) {
+	subscriptions, err := c.services.Webhooks.GetSubscriptions(r.Context())
+	if err != nil {
+		http.Error(w, "Failed to load webhooks: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	templateData := webhooksPageData{
+		Title:         "Webhooks",
+		CurrentPage:   "team",
+		Error:         errorMessage,
+		Success:       r.URL.Query().Get("success"),
+		Subscriptions: subscriptions,
+		Events:        models.WebhookEvents,
+		EventNames:    models.WebhookEventNames,
+		Form:          form,
+		User:          getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "webhooks", "templates/webhooks.html", templateData)
+}
+
+// renderEdit renders the webhook edit page
+func (c *WebhookController) renderEdit(w http.ResponseWriter, r *http.Request, statusCode int, subscription *models.WebhookSubscription, form *models.WebhookSubscriptionForm, errorMessage string) {
+	templateData := struct {
+		Title        string
+		CurrentPage  string
+		Error        string
+		Success      string
+		Subscription *models.WebhookSubscription
+		Events       []string
+		EventNames   map[string]string
+		Form         *models.WebhookSubscriptionForm
+		User         string
+	}{
+		Title:        "Edit Webhook",
+		CurrentPage:  "team",
+		Error:        errorMessage,
+		Success:      r.URL.Query().Get("success"),
+		Subscription: subscription,
+		Events:       models.WebhookEvents,
+		EventNames:   models.WebhookEventNames,
+		Form:         form,
+		User:         getUserNickname(r),
+	}
+
+	renderTemplateWithStatus(w, r, statusCode, "webhook_edit", "templates/webhook_edit.html", templateData)
+}
+
+// parseWebhookSubscriptionForm reads the webhook form fields from a parsed request
+func parseWebhookSubscriptionForm(r *http.Request) *models.WebhookSubscriptionForm {
+	return &models.WebhookSubscriptionForm{
+		Name:   r.FormValue("name"),
+		URL:    r.FormValue("url"),
+		Secret: r.FormValue("secret"),
+		Events: r.Form["events"],
+		Active: r.FormValue("active") == "on",
+	}
+}
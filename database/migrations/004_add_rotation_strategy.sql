@@ -0,0 +1,2 @@
+-- Add selectable rotation strategy to schedule state
+ALTER TABLE schedule_state ADD COLUMN rotation_strategy TEXT NOT NULL DEFAULT 'epoch_modulo';
@@ -0,0 +1,2 @@
+-- Add seed for the seeded shuffle rotation strategy
+ALTER TABLE schedule_state ADD COLUMN rotation_seed INTEGER NOT NULL DEFAULT 0;
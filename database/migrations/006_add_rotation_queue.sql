@@ -0,0 +1,4 @@
+-- Persist the rotation queue and cursor so roster changes only affect the future
+ALTER TABLE schedule_state ADD COLUMN rotation_queue TEXT NOT NULL DEFAULT '[]';
+ALTER TABLE schedule_state ADD COLUMN rotation_cursor INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE schedule_state ADD COLUMN rotation_cursor_date DATE;
@@ -0,0 +1,16 @@
+-- Time off periods during which a team member can't be scheduled
+CREATE TABLE time_off (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_member_id INTEGER NOT NULL,
+    start_date DATE NOT NULL,
+    end_date DATE NOT NULL, -- inclusive
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT DEFAULT 'system',
+    modified_by TEXT,
+    modified_at DATETIME,
+    FOREIGN KEY (team_member_id) REFERENCES team_members(id) ON DELETE CASCADE,
+    CHECK (end_date >= start_date)
+);
+
+CREATE INDEX idx_time_off_team_member ON time_off(team_member_id);
+CREATE INDEX idx_time_off_dates ON time_off(start_date, end_date);
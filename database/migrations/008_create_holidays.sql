@@ -0,0 +1,15 @@
+-- Public holidays and other days that don't follow the regular working hours
+CREATE TABLE holidays (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    date DATE NOT NULL,
+    name TEXT NOT NULL,
+    recurring BOOLEAN NOT NULL DEFAULT 0, -- repeats every year on the same month and day
+    behavior TEXT NOT NULL DEFAULT 'no_duty' CHECK (behavior IN ('no_duty', 'alternative_hours')),
+    start_time TEXT NOT NULL DEFAULT '', -- alternative hours, "09:00" format
+    end_time TEXT NOT NULL DEFAULT '',   -- alternative hours, "17:00" format
+    created_by TEXT DEFAULT 'system',
+    modified_by TEXT,
+    modified_at DATETIME
+);
+
+CREATE INDEX idx_holidays_date ON holidays(date);
@@ -0,0 +1,10 @@
+-- Allow several named shifts per weekday instead of a single working hours row
+DROP INDEX IF EXISTS idx_working_hours_day_unique;
+ALTER TABLE working_hours ADD COLUMN name TEXT NOT NULL DEFAULT '';
+CREATE UNIQUE INDEX idx_working_hours_day_shift_unique ON working_hours(day_of_week, name) WHERE active = 1;
+
+-- Schedule entries remember the shift they cover
+ALTER TABLE schedule_entries ADD COLUMN shift TEXT NOT NULL DEFAULT '';
+
+-- Every named shift keeps its own position in the rotation queue, the unnamed shift uses rotation_cursor
+ALTER TABLE schedule_state ADD COLUMN rotation_shift_cursors TEXT NOT NULL DEFAULT '{}';
@@ -0,0 +1,4 @@
+-- How long a member stays on duty before handing over: every working day, a week or a number of working days
+ALTER TABLE schedule_state ADD COLUMN rotation_period TEXT NOT NULL DEFAULT 'daily';
+ALTER TABLE schedule_state ADD COLUMN rotation_period_days INTEGER NOT NULL DEFAULT 0; -- working days per turn
+ALTER TABLE schedule_state ADD COLUMN handover_day INTEGER NOT NULL DEFAULT 0;         -- first day of a weekly turn, 0=Monday
@@ -0,0 +1,3 @@
+-- Every shift has a primary member on duty and optionally a backup who is next in line
+ALTER TABLE schedule_entries ADD COLUMN role TEXT NOT NULL DEFAULT 'primary';
+ALTER TABLE schedule_state ADD COLUMN backup_enabled BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,50 @@
+-- Several teams can share one deployment, everything that existed so far belongs to the default team
+CREATE TABLE teams (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL,
+    slug TEXT NOT NULL UNIQUE, -- used in URLs like /teams/{slug}/schedule
+    created_by TEXT DEFAULT 'system',
+    modified_by TEXT,
+    modified_at DATETIME
+);
+
+INSERT INTO teams (id, name, slug) VALUES (1, 'Default', 'default');
+
+-- Members, working hours and schedule entries belong to a team
+ALTER TABLE team_members ADD COLUMN team_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE working_hours ADD COLUMN team_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE schedule_entries ADD COLUMN team_id INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX idx_team_members_team ON team_members(team_id);
+CREATE INDEX idx_schedule_entries_team_date ON schedule_entries(team_id, date);
+
+-- Shift names are unique per team and weekday
+DROP INDEX IF EXISTS idx_working_hours_day_shift_unique;
+CREATE UNIQUE INDEX idx_working_hours_team_day_shift_unique ON working_hours(team_id, day_of_week, name) WHERE active = 1;
+
+-- The schedule state was a single record, every team gets its own
+CREATE TABLE team_schedule_state (
+    team_id INTEGER PRIMARY KEY REFERENCES teams(id) ON DELETE CASCADE,
+    last_generation_date DATE,
+    rotation_strategy TEXT NOT NULL DEFAULT 'epoch_modulo',
+    rotation_seed INTEGER NOT NULL DEFAULT 0,
+    rotation_queue TEXT NOT NULL DEFAULT '[]',
+    rotation_cursor INTEGER NOT NULL DEFAULT 0,
+    rotation_cursor_date DATE,
+    rotation_shift_cursors TEXT NOT NULL DEFAULT '{}',
+    rotation_period TEXT NOT NULL DEFAULT 'daily',
+    rotation_period_days INTEGER NOT NULL DEFAULT 0,
+    handover_day INTEGER NOT NULL DEFAULT 0,
+    backup_enabled BOOLEAN NOT NULL DEFAULT 0
+);
+
+INSERT INTO team_schedule_state (team_id, last_generation_date, rotation_strategy, rotation_seed,
+    rotation_queue, rotation_cursor, rotation_cursor_date, rotation_shift_cursors,
+    rotation_period, rotation_period_days, handover_day, backup_enabled)
+SELECT 1, last_generation_date, rotation_strategy, rotation_seed,
+    rotation_queue, rotation_cursor, rotation_cursor_date, rotation_shift_cursors,
+    rotation_period, rotation_period_days, handover_day, backup_enabled
+FROM schedule_state WHERE id = 1;
+
+DROP TABLE schedule_state;
+ALTER TABLE team_schedule_state RENAME TO schedule_state;
@@ -0,0 +1,8 @@
+-- Schedule entries belong to the working hours layer or to the on-call layer covering the time in between
+ALTER TABLE schedule_entries ADD COLUMN layer TEXT NOT NULL DEFAULT 'working_hours';
+-- On-call blocks can span several days, working hours entries leave the end date empty
+ALTER TABLE schedule_entries ADD COLUMN end_date DATE;
+
+-- On-call coverage is optional and rotates through the queue with its own cursor
+ALTER TABLE schedule_state ADD COLUMN on_call_enabled BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE schedule_state ADD COLUMN on_call_cursor INTEGER NOT NULL DEFAULT 0;
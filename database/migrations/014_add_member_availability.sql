@@ -0,0 +1,4 @@
+-- Part-time members take a share of the turns in proportion to their participation in percent
+ALTER TABLE team_members ADD COLUMN participation INTEGER NOT NULL DEFAULT 100;
+-- Weekdays a member can be on duty (0=Monday), an empty list means every day
+ALTER TABLE team_members ADD COLUMN work_days TEXT NOT NULL DEFAULT '[]';
@@ -0,0 +1,2 @@
+-- Weekdays a member prefers or avoids, as a list of {day_of_week, kind, strength}
+ALTER TABLE team_members ADD COLUMN preferences TEXT NOT NULL DEFAULT '[]';
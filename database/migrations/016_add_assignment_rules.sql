@@ -0,0 +1,4 @@
+-- Rules limiting how often a member is on duty, 0 leaves a rule off
+ALTER TABLE schedule_state ADD COLUMN min_gap_days INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE schedule_state ADD COLUMN max_shifts_per_week INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE schedule_state ADD COLUMN max_shifts_per_month INTEGER NOT NULL DEFAULT 0;
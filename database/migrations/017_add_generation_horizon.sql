@@ -0,0 +1,12 @@
+-- How far ahead the schedule is generated, and how long a full generation counts as up to date
+ALTER TABLE schedule_state ADD COLUMN horizon_length INTEGER NOT NULL DEFAULT 3;
+ALTER TABLE schedule_state ADD COLUMN horizon_unit TEXT NOT NULL DEFAULT 'months'; -- weeks or months
+ALTER TABLE schedule_state ADD COLUMN up_to_date_days INTEGER NOT NULL DEFAULT 7;
+-- End of the period filled so far, the nightly run extends the schedule from here
+ALTER TABLE schedule_state ADD COLUMN generated_until DATE;
+
+-- Outcome of the last generation run, started by hand or by the nightly run
+ALTER TABLE schedule_state ADD COLUMN last_run_at DATETIME;
+ALTER TABLE schedule_state ADD COLUMN last_run_trigger TEXT NOT NULL DEFAULT '';
+ALTER TABLE schedule_state ADD COLUMN last_run_success BOOLEAN NOT NULL DEFAULT 0;
+ALTER TABLE schedule_state ADD COLUMN last_run_message TEXT NOT NULL DEFAULT '';
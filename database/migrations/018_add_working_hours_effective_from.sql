@@ -0,0 +1,6 @@
+-- Working hours change from an effective date on, the rows without a date are the initial configuration
+ALTER TABLE working_hours ADD COLUMN effective_from DATE;
+
+-- Shift names are unique per team, weekday and version
+DROP INDEX IF EXISTS idx_working_hours_team_day_shift_unique;
+CREATE UNIQUE INDEX idx_working_hours_team_day_shift_unique ON working_hours(team_id, day_of_week, COALESCE(effective_from, ''), name) WHERE active = 1;
@@ -0,0 +1,17 @@
+-- Dates on which the working hours of a team differ from its weekly working hours
+CREATE TABLE working_hours_exceptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL DEFAULT 1 REFERENCES teams(id) ON DELETE CASCADE,
+    date DATE NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('hours', 'non_working', 'working')),
+    name TEXT NOT NULL DEFAULT '',       -- shift of an extra working day
+    start_time TEXT NOT NULL DEFAULT '', -- "09:00" format
+    end_time TEXT NOT NULL DEFAULT '',   -- "17:00" format
+    reason TEXT NOT NULL DEFAULT '',
+    created_by TEXT DEFAULT 'system',
+    modified_by TEXT,
+    modified_at DATETIME
+);
+
+-- A date has at most one exception per team
+CREATE UNIQUE INDEX idx_working_hours_exceptions_team_date ON working_hours_exceptions(team_id, date);
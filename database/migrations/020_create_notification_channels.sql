@@ -0,0 +1,17 @@
+-- Channels the notifications of a team are sent to, each with the events it gets
+CREATE TABLE notification_channels (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL DEFAULT 1 REFERENCES teams(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('slack', 'teams', 'email', 'webhook')),
+    target TEXT NOT NULL DEFAULT '',  -- webhook URL, or an address that gets every email
+    events TEXT NOT NULL DEFAULT '[]', -- list of notification events
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_by TEXT DEFAULT 'system',
+    modified_by TEXT,
+    modified_at DATETIME
+);
+
+-- Members get email notifications at their address, and can turn off the events they don't want
+ALTER TABLE team_members ADD COLUMN email TEXT NOT NULL DEFAULT '';
+ALTER TABLE team_members ADD COLUMN muted_notifications TEXT NOT NULL DEFAULT '[]';
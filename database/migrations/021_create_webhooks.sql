@@ -0,0 +1,33 @@
+-- Webhooks of a team, each signed with its own secret and getting the events chosen for it
+CREATE TABLE webhook_subscriptions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL DEFAULT 1 REFERENCES teams(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,              -- HMAC-SHA256 key the deliveries are signed with
+    events TEXT NOT NULL DEFAULT '[]', -- list of webhook events
+    active BOOLEAN NOT NULL DEFAULT 1,
+    created_by TEXT DEFAULT 'system',
+    modified_by TEXT,
+    modified_at DATETIME
+);
+
+-- Queue and log of the deliveries of webhook events, kept until a while after they are done
+CREATE TABLE webhook_deliveries (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    team_id INTEGER NOT NULL DEFAULT 1 REFERENCES teams(id) ON DELETE CASCADE,
+    subscription_id INTEGER NOT NULL REFERENCES webhook_subscriptions(id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,            -- the same for the deliveries of one event to several webhooks
+    event TEXT NOT NULL,
+    payload TEXT NOT NULL,             -- JSON body that is posted
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME NOT NULL,
+    last_attempt_at DATETIME,
+    response_status INTEGER NOT NULL DEFAULT 0, -- HTTP status of the last attempt, 0 without a response
+    last_error TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL,
+    delivered_at DATETIME
+);
+
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(team_id, status, next_attempt_at);
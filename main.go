@@ -1,10 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,6 +16,7 @@ import (
 	"github.com/blogem/eod-scheduler/controllers"
 	"github.com/blogem/eod-scheduler/database"
 	authmiddleware "github.com/blogem/eod-scheduler/middleware"
+	"github.com/blogem/eod-scheduler/notifications"
 	"github.com/blogem/eod-scheduler/repositories"
 	"github.com/blogem/eod-scheduler/services"
 	"github.com/go-chi/chi/v5"
@@ -49,12 +52,67 @@ func main() {
 	// Initialize repositories
 	repos := repositories.NewRepositories(db)
 
+	// Notifications go to the channels of each team, email through the SMTP_HOST mail server
+	smtpPort := 587
+	if value := os.Getenv("SMTP_PORT"); value != "" {
+		if smtpPort, err = strconv.Atoi(value); err != nil {
+			log.Fatalf("Invalid SMTP_PORT: %v", err)
+		}
+	}
+	notifier := notifications.NewDispatcher(repos.NotificationChannels, repos.Team, &notifications.SMTPConfig{
+		Host:     os.Getenv("SMTP_HOST"),
+		Port:     smtpPort,
+		Username: os.Getenv("SMTP_USERNAME"),
+		Password: os.Getenv("SMTP_PASSWORD"),
+		From:     os.Getenv("SMTP_FROM"),
+	})
+
+	// Webhooks get the schedule and team events of their team, queued and retried until delivered
+	webhookSender := services.NewWebhookSender(repos.Teams, repos.Webhooks)
+
 	// Initialize services
-	srvs := services.NewServices(repos)
+	srvs := services.NewServices(repos, notifier, webhookSender)
 
 	// Initialize controllers
 	ctrl := controllers.NewControllers(srvs)
 
+	// Extend the schedule of every team each night, at 02:00 unless GENERATION_TIME says otherwise
+	runAt, err := timeOfDay(os.Getenv("GENERATION_TIME"), 2*time.Hour)
+	if err != nil {
+		log.Fatalf("Invalid GENERATION_TIME: %v", err)
+	}
+	go services.NewNightlyGeneration(repos.Teams, srvs.Schedule, runAt).Start(context.Background())
+
+	// Announce who is on duty in Slack each working day, at 09:00 unless SLACK_ANNOUNCEMENT_TIME says otherwise
+	slackWebhookURL := os.Getenv("SLACK_WEBHOOK_URL")
+	announceAt, err := timeOfDay(os.Getenv("SLACK_ANNOUNCEMENT_TIME"), 9*time.Hour)
+	if err != nil {
+		log.Fatalf("Invalid SLACK_ANNOUNCEMENT_TIME: %v", err)
+	}
+	if slackWebhookURL != "" {
+		webhook := services.NewSlackWebhook(slackWebhookURL)
+		go services.NewDailyAnnouncement(repos.Teams, repos.Schedule, webhook, announceAt).Start(context.Background())
+	}
+
+	// Remind members shortly before their shifts start, 15 minutes unless SHIFT_REMINDER_LEAD says otherwise
+	reminderLead := 15 * time.Minute
+	if value := os.Getenv("SHIFT_REMINDER_LEAD"); value != "" {
+		if reminderLead, err = time.ParseDuration(value); err != nil || reminderLead <= 0 {
+			log.Fatalf("Invalid SHIFT_REMINDER_LEAD, expected a duration like 15m: %q", value)
+		}
+	}
+	go services.NewShiftReminders(repos.Teams, repos.Schedule, notifier, reminderLead).Start(context.Background())
+
+	// Post the queued webhook deliveries as they come due
+	go webhookSender.Start(context.Background())
+
+	// Keep a Slack user group like @eod set to the members on duty, synced at every shift start
+	slackUserGroupID := os.Getenv("SLACK_USER_GROUP_ID")
+	if slackUserGroupID != "" {
+		slackAPI := services.NewSlackAPI(requireEnv("SLACK_BOT_TOKEN"))
+		go services.NewUserGroupSync(repos.Teams, repos.Schedule, slackAPI, slackUserGroupID).Start(context.Background())
+	}
+
 	// Read OpenID Connect configuration from environment
 	openIDConfig := authenticator.OpenIDConfig{
 		Domain:       requireEnv("OPENID_DOMAIN"),
@@ -84,10 +142,29 @@ func main() {
 	fmt.Printf("🚀 EoD Scheduler starting on port %s\n", port)
 	fmt.Printf("📂 Visit: http://localhost:%s\n", port)
 	fmt.Printf("🗃️  Database: %s\n", dbPath)
+	fmt.Printf("🌙 Nightly generation at %02d:%02d\n", int(runAt.Hours()), int(runAt.Minutes())%60)
+	if slackWebhookURL != "" {
+		fmt.Printf("💬 Slack announcement at %02d:%02d\n", int(announceAt.Hours()), int(announceAt.Minutes())%60)
+	}
+	if slackUserGroupID != "" {
+		fmt.Printf("👥 Slack user group %s follows the members on duty\n", slackUserGroupID)
+	}
 
 	log.Fatal(http.ListenAndServe(":"+port, r))
 }
 
+// timeOfDay parses a time like "02:00" into the time since midnight, or returns the fallback if empty
+func timeOfDay(value string, fallback time.Duration) (time.Duration, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	parsed, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("expected a time like 02:00, got %q", value)
+	}
+	return time.Duration(parsed.Hour())*time.Hour + time.Duration(parsed.Minute())*time.Minute, nil
+}
+
 // setupRouter configures all routes
 func setupRouter(ctrl *controllers.Controllers, auth authenticator.Provider, repos *repositories.Repositories) (*chi.Mux, error) {
 	r := chi.NewRouter()
@@ -129,7 +206,6 @@ func setupRouter(ctrl *controllers.Controllers, auth authenticator.Provider, rep
 	r.Handle("/static/*", http.StripPrefix("/static/", http.FileServer(http.Dir("static/"))))
 
 	// PUBLIC ROUTES (no authentication required)
-	r.Get("/", ctrl.Dashboard.Index) // Home page - shows landing or dashboard based on auth
 	r.Get("/login", ctrl.Auth.Login(auth))
 	r.Get("/callback", ctrl.Auth.Callback(auth))
 	r.Get("/logout", ctrl.Auth.Logout)
@@ -143,43 +219,120 @@ func setupRouter(ctrl *controllers.Controllers, auth authenticator.Provider, rep
 		fmt.Fprintf(w, "<h1>Test Route Works!</h1><p>Server is responding correctly.</p>")
 	})
 
-	// PROTECTED ROUTES (authentication required)
+	// Slack slash commands, signed by Slack instead of a login
+	if signingSecret := os.Getenv("SLACK_SIGNING_SECRET"); signingSecret != "" {
+		r.With(authmiddleware.SlackSignature(signingSecret)).Post("/slack/commands", ctrl.Slack.Command)
+	}
+
+	// PAGES (work on the team visited last, or the team in the URL)
 	r.Group(func(r chi.Router) {
-		r.Use(authmiddleware.RequireAuth)
-
-		// Team management routes
-		r.Route("/team", func(r chi.Router) {
-			r.Get("/", ctrl.Team.Index)
-			r.Post("/", ctrl.Team.Create)
-			r.Get("/{id}/edit", ctrl.Team.Edit)
-			r.Post("/{id}", ctrl.Team.Update)
-			r.Post("/{id}/delete", ctrl.Team.Delete)
-		})
+		r.Use(authmiddleware.TeamContext(repos.Teams))
 
-		// Working hours configuration routes
-		r.Route("/hours", func(r chi.Router) {
-			r.Get("/", ctrl.WorkingHours.Index)
-			r.Post("/", ctrl.WorkingHours.Update)
-		})
+		r.Get("/", ctrl.Dashboard.Index) // Home page - shows landing or dashboard based on auth
+
+		// PROTECTED ROUTES (authentication required)
+		r.Group(func(r chi.Router) {
+			r.Use(authmiddleware.RequireAuth)
 
-		// Schedule routes
-		r.Route("/schedule", func(r chi.Router) {
-			r.Get("/", ctrl.Schedule.Index)
-			r.Get("/week/{date}", ctrl.Schedule.Week)
-			r.Post("/generate", ctrl.Schedule.Generate)
+			// Pages of the team visited last
+			setupTeamRoutes(r, ctrl)
 
-			// Takeover routes
-			r.Get("/takeover", ctrl.Schedule.ShowTakeoverForm)
-			r.Post("/takeover", ctrl.Schedule.CreateTakeover)
+			// Team management and the pages of a specific team
+			r.Route("/teams", func(r chi.Router) {
+				r.Get("/", ctrl.Teams.Index)
+				r.Post("/", ctrl.Teams.Create)
 
-			// Edit routes
-			r.Get("/edit/{id}", ctrl.Schedule.ShowEditForm)
-			r.Post("/edit/{id}", ctrl.Schedule.UpdateEntry)
+				r.Route("/{slug}", func(r chi.Router) {
+					r.Use(authmiddleware.TeamFromURL)
+					r.Get("/", ctrl.Dashboard.Index)
+					r.Post("/rename", ctrl.Teams.Rename)
+					setupTeamRoutes(r, ctrl)
+				})
+			})
 
-			// Remove override
-			r.Post("/remove/{id}", ctrl.Schedule.RemoveOverride)
+			// Holiday calendar routes, shared by all teams
+			r.Route("/holidays", func(r chi.Router) {
+				r.Get("/", ctrl.Holiday.Index)
+				r.Post("/", ctrl.Holiday.Create)
+				r.Post("/import", ctrl.Holiday.Import)
+				r.Get("/{id}/edit", ctrl.Holiday.Edit)
+				r.Post("/{id}", ctrl.Holiday.Update)
+				r.Post("/{id}/delete", ctrl.Holiday.Delete)
+			})
 		})
 	})
 
 	return r, nil
 }
+
+// setupTeamRoutes configures the routes of the pages that work on a single team
+func setupTeamRoutes(r chi.Router, ctrl *controllers.Controllers) {
+	// Team management routes
+	r.Route("/team", func(r chi.Router) {
+		r.Get("/", ctrl.Team.Index)
+		r.Post("/", ctrl.Team.Create)
+		r.Get("/{id}/edit", ctrl.Team.Edit)
+		r.Post("/{id}", ctrl.Team.Update)
+		r.Post("/{id}/delete", ctrl.Team.Delete)
+		r.Post("/{id}/move", ctrl.Team.Move)
+
+		// Time off routes (JSON when requested with Accept: application/json)
+		r.Get("/{id}/time-off", ctrl.TimeOff.Index)
+		r.Post("/{id}/time-off", ctrl.TimeOff.Create)
+		r.Post("/{id}/time-off/{timeOffID}/delete", ctrl.TimeOff.Delete)
+		r.Post("/{id}/time-off/{timeOffID}/reassign", ctrl.TimeOff.Reassign)
+	})
+
+	// Working hours configuration routes
+	r.Route("/hours", func(r chi.Router) {
+		r.Get("/", ctrl.WorkingHours.Index)
+		r.Post("/", ctrl.WorkingHours.Update)
+		r.Post("/changes/{date}/delete", ctrl.WorkingHours.CancelChange)
+		r.Post("/exceptions", ctrl.WorkingHours.CreateException)
+		r.Post("/exceptions/{id}/delete", ctrl.WorkingHours.DeleteException)
+	})
+
+	// Notification channel routes
+	r.Route("/notifications", func(r chi.Router) {
+		r.Get("/", ctrl.Notifications.Index)
+		r.Post("/", ctrl.Notifications.Create)
+		r.Get("/{id}/edit", ctrl.Notifications.Edit)
+		r.Post("/{id}", ctrl.Notifications.Update)
+		r.Post("/{id}/delete", ctrl.Notifications.Delete)
+	})
+
+	// Webhook routes
+	r.Route("/webhooks", func(r chi.Router) {
+		r.Get("/", ctrl.Webhooks.Index)
+		r.Post("/", ctrl.Webhooks.Create)
+		r.Get("/deliveries", ctrl.Webhooks.Deliveries)
+		r.Post("/deliveries/{id}/retry", ctrl.Webhooks.Retry)
+		r.Get("/{id}/edit", ctrl.Webhooks.Edit)
+		r.Post("/{id}", ctrl.Webhooks.Update)
+		r.Post("/{id}/delete", ctrl.Webhooks.Delete)
+	})
+
+	// Schedule routes
+	r.Route("/schedule", func(r chi.Router) {
+		r.Get("/", ctrl.Schedule.Index)
+		r.Get("/week/{date}", ctrl.Schedule.Week)
+		r.Get("/preview", ctrl.Schedule.Preview)
+		r.Post("/generate", ctrl.Schedule.Generate)
+		r.Get("/export", ctrl.Schedule.Export)
+
+		// Generation settings
+		r.Get("/settings", ctrl.Schedule.ShowSettings)
+		r.Post("/settings", ctrl.Schedule.UpdateSettings)
+
+		// Takeover routes
+		r.Get("/takeover", ctrl.Schedule.ShowTakeoverForm)
+		r.Post("/takeover", ctrl.Schedule.CreateTakeover)
+
+		// Edit routes
+		r.Get("/edit/{id}", ctrl.Schedule.ShowEditForm)
+		r.Post("/edit/{id}", ctrl.Schedule.UpdateEntry)
+
+		// Remove override
+		r.Post("/remove/{id}", ctrl.Schedule.RemoveOverride)
+	})
+}
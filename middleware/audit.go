@@ -1,7 +1,9 @@
 package middleware
 
 import (
+	"bytes"
 	"encoding/json"
+	"io"
 	"log"
 	"net/http"
 	"strings"
@@ -11,6 +13,9 @@ import (
 	"github.com/blogem/eod-scheduler/userctx"
 )
 
+// maxFormSize is the size of the forms ParseForm reads at most
+const maxFormSize = 10 << 20 // 10 MB
+
 // AuditLogger middleware logs all POST/PUT/DELETE requests
 func AuditLogger(auditRepo repositories.AuditRepository) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
@@ -68,6 +73,16 @@ func getIPAddress(r *http.Request) string {
 
 // captureFormData captures form data as JSON string
 func captureFormData(r *http.Request) string {
+	// Parsing a form reads the body, keep it readable for handlers that verify its signature
+	if r.Body != nil && strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxFormSize))
+		if err != nil {
+			return ""
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		defer func() { r.Body = io.NopCloser(bytes.NewReader(body)) }()
+	}
+
 	// Parse form data
 	if err := r.ParseForm(); err != nil {
 		return ""
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/blogem/eod-scheduler/services"
+)
+
+// maxSlackRequestSize limits the size of the requests Slack sends
+const maxSlackRequestSize = 64 << 10 // 64 KB
+
+// SlackSignature middleware rejects requests that weren't signed by Slack with the signing secret
+// of the app
+func SlackSignature(signingSecret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(io.LimitReader(r.Body, maxSlackRequestSize))
+			if err != nil {
+				http.Error(w, "Failed to read request", http.StatusBadRequest)
+				return
+			}
+
+			err = services.VerifySlackSignature(signingSecret,
+				r.Header.Get("X-Slack-Request-Timestamp"), r.Header.Get("X-Slack-Signature"), body)
+			if err != nil {
+				log.Printf("Rejected slack request: %v", err)
+				http.Error(w, "Invalid signature", http.StatusUnauthorized)
+				return
+			}
+
+			// The handler reads the verified body again
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
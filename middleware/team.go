@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+
+	"gitea.com/go-chi/session"
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/go-chi/chi/v5"
+)
+
+// teamSessionKey is the session key remembering the team a user visited last
+const teamSessionKey = "team_id"
+
+// TeamContext middleware adds all teams, and the team the request works on, to the context.
+// Pages outside /teams/{slug} work on the team the user visited last, or on the default team.
+func TeamContext(teamsRepo repositories.TeamsRepository) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			teams, err := teamsRepo.GetAll(r.Context())
+			if err != nil {
+				http.Error(w, "Failed to load teams: "+err.Error(), http.StatusInternalServerError)
+				return
+			}
+
+			teamID := models.DefaultTeamID
+			if id, ok := session.GetSession(r).Get(teamSessionKey).(int); ok {
+				teamID = id
+			}
+
+			ctx := teamctx.SetTeams(r.Context(), teams)
+			if team := findTeam(teams, func(t *models.Team) bool { return t.ID == teamID }); team != nil {
+				ctx = teamctx.SetTeam(ctx, team)
+			} else if team := findTeam(teams, func(t *models.Team) bool { return t.ID == models.DefaultTeamID }); team != nil {
+				// The team visited last no longer exists
+				ctx = teamctx.SetTeam(ctx, team)
+			}
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// TeamFromURL middleware switches the request to the team in the {slug} URL parameter and
+// remembers it for the pages outside /teams/{slug}. It must run after TeamContext.
+func TeamFromURL(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		slug := chi.URLParam(r, "slug")
+		team := findTeam(teamctx.GetTeams(r.Context()), func(t *models.Team) bool { return t.Slug == slug })
+		if team == nil {
+			http.Error(w, "Team not found", http.StatusNotFound)
+			return
+		}
+
+		session.GetSession(r).Set(teamSessionKey, team.ID)
+
+		next.ServeHTTP(w, r.WithContext(teamctx.SetTeam(r.Context(), team)))
+	})
+}
+
+// findTeam returns the first team matching the condition, or nil if there is none
+func findTeam(teams []models.Team, match func(*models.Team) bool) *models.Team {
+	for i := range teams {
+		if match(&teams[i]) {
+			return &teams[i]
+		}
+	}
+	return nil
+}
@@ -33,14 +33,6 @@ type DateRange struct {
 	End   time.Time `json:"end"`
 }
 
-// GetNext3Months returns a date range for the next 3 months
-func GetNext3Months() DateRange {
-	now := time.Now()
-	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-	end := start.AddDate(0, 3, 0)
-	return DateRange{Start: start, End: end}
-}
-
 // GetCurrentWeek returns a date range for the current week (Monday to Sunday)
 func GetCurrentWeek() DateRange {
 	now := time.Now()
@@ -99,6 +91,30 @@ func GetWeekdayNumber(t time.Time) int {
 	return weekday - 1 // Monday=1 becomes 0, Tuesday=2 becomes 1, etc.
 }
 
+// calendarDay is the day of a date in its own location, it's comparable without formatting the date
+type calendarDay struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+// dayOf returns the calendar day of a date
+func dayOf(date time.Time) calendarDay {
+	year, month, day := date.Date()
+	return calendarDay{year: year, month: month, day: day}
+}
+
+// before checks if the day comes before another day
+func (d calendarDay) before(other calendarDay) bool {
+	if d.year != other.year {
+		return d.year < other.year
+	}
+	if d.month != other.month {
+		return d.month < other.month
+	}
+	return d.day < other.day
+}
+
 // ValidationError represents a validation error
 type ValidationError struct {
 	Field   string `json:"field"`
@@ -0,0 +1,270 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// Holiday represents a public holiday or other company-wide day off
+type Holiday struct {
+	ID        int       `json:"id" db:"id"`
+	Date      time.Time `json:"date" db:"date"`
+	Name      string    `json:"name" db:"name"`
+	Recurring bool      `json:"recurring" db:"recurring"`   // Repeats every year on the same month and day
+	Behavior  string    `json:"behavior" db:"behavior"`     // What happens to the duty on this day
+	StartTime string    `json:"start_time" db:"start_time"` // Alternative hours, "09:00" format
+	EndTime   string    `json:"end_time" db:"end_time"`     // Alternative hours, "17:00" format
+	AuditFields
+}
+
+// Holiday behaviors
+const (
+	HolidayBehaviorNoDuty           = "no_duty"           // Nobody is on duty, the rotation continues on the next working day
+	HolidayBehaviorAlternativeHours = "alternative_hours" // Duty as usual, but with different hours
+)
+
+// HolidayBehaviorNames maps the holiday behaviors to their display names
+var HolidayBehaviorNames = map[string]string{
+	HolidayBehaviorNoDuty:           "No duty",
+	HolidayBehaviorAlternativeHours: "Duty with alternative hours",
+}
+
+// AppliesTo checks if the holiday falls on the given date
+func (h *Holiday) AppliesTo(date time.Time) bool {
+	if h.Recurring {
+		return h.Date.Month() == date.Month() && h.Date.Day() == date.Day()
+	}
+	return FormatDate(h.Date) == FormatDate(date)
+}
+
+// IsDutyFree checks if nobody is on duty during the holiday
+func (h *Holiday) IsDutyFree() bool {
+	return h.Behavior != HolidayBehaviorAlternativeHours
+}
+
+// EndsNextDay checks if the alternative hours cross midnight and end on the following day
+func (h *Holiday) EndsNextDay() bool {
+	return EndsNextDay(h.StartTime, h.EndTime)
+}
+
+// GetFormattedDate returns the date formatted as YYYY-MM-DD
+func (h *Holiday) GetFormattedDate() string {
+	return FormatDate(h.Date)
+}
+
+// GetBehaviorName returns the display name of the holiday behavior
+func (h *Holiday) GetBehaviorName() string {
+	if name, ok := HolidayBehaviorNames[h.Behavior]; ok {
+		return name
+	}
+	return HolidayBehaviorNames[HolidayBehaviorNoDuty]
+}
+
+// HolidayCalendar looks up the holiday for a date. One-off holidays take precedence over
+// recurring ones on the same date. A nil calendar has no holidays.
+//
+// The calendar also holds the working hours exceptions of a team, which take precedence over a
+// holiday on the same date.
+type HolidayCalendar struct {
+	oneOff     map[calendarDay]Holiday               // By date
+	recurring  map[calendarDay]Holiday               // By month and day, without a year
+	exceptions map[calendarDay]WorkingHoursException // By date
+}
+
+// NewHolidayCalendar creates a calendar for the given holidays
+func NewHolidayCalendar(holidays []Holiday) *HolidayCalendar {
+	calendar := &HolidayCalendar{
+		oneOff:    make(map[calendarDay]Holiday),
+		recurring: make(map[calendarDay]Holiday),
+	}
+	for _, holiday := range holidays {
+		if holiday.Recurring {
+			calendar.recurring[calendarDay{month: holiday.Date.Month(), day: holiday.Date.Day()}] = holiday
+		} else {
+			calendar.oneOff[dayOf(holiday.Date)] = holiday
+		}
+	}
+	return calendar
+}
+
+// Find returns the holiday that falls on the date, or nil if it's a regular day
+func (c *HolidayCalendar) Find(date time.Time) *Holiday {
+	if c == nil {
+		return nil
+	}
+	day := dayOf(date)
+	if holiday, ok := c.oneOff[day]; ok {
+		return &holiday
+	}
+	if holiday, ok := c.recurring[calendarDay{month: day.month, day: day.day}]; ok {
+		return &holiday
+	}
+	return nil
+}
+
+// IsDutyFree checks if the date is a holiday on which nobody is on duty
+func (c *HolidayCalendar) IsDutyFree(date time.Time) bool {
+	holiday := c.Find(date)
+	return holiday != nil && holiday.IsDutyFree()
+}
+
+// WithExceptions returns the calendar with the working hours exceptions of a team added
+func (c *HolidayCalendar) WithExceptions(exceptions []WorkingHoursException) *HolidayCalendar {
+	if c == nil {
+		c = NewHolidayCalendar(nil)
+	}
+	c.exceptions = make(map[calendarDay]WorkingHoursException, len(exceptions))
+	for _, exception := range exceptions {
+		c.exceptions[dayOf(exception.Date)] = exception
+	}
+	return c
+}
+
+// FindException returns the working hours exception of the date, or nil if there is none
+func (c *HolidayCalendar) FindException(date time.Time) *WorkingHoursException {
+	if c == nil {
+		return nil
+	}
+	if exception, ok := c.exceptions[dayOf(date)]; ok {
+		return &exception
+	}
+	return nil
+}
+
+// ForShift returns the calendar of a single shift, on which the extra working days of other shifts
+// are non-working days
+func (c *HolidayCalendar) ForShift(shift string) *HolidayCalendar {
+	if c == nil || len(c.exceptions) == 0 {
+		return c
+	}
+	narrowed := *c
+	narrowed.exceptions = make(map[calendarDay]WorkingHoursException, len(c.exceptions))
+	for day, exception := range c.exceptions {
+		if exception.Kind == ExceptionKindWorking && exception.Name != shift {
+			exception.Kind = ExceptionKindNonWorking
+		}
+		narrowed.exceptions[day] = exception
+	}
+	return &narrowed
+}
+
+// ExceptionDates returns the dates from from up to but not including to that have a working hours
+// exception, in no particular order
+func (c *HolidayCalendar) ExceptionDates(from, to time.Time) []time.Time {
+	if c == nil {
+		return nil
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	var dates []time.Time
+	for _, exception := range c.exceptions {
+		date := time.Date(exception.Date.Year(), exception.Date.Month(), exception.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if !date.Before(from) && date.Before(to) {
+			dates = append(dates, date)
+		}
+	}
+	return dates
+}
+
+// ShiftsOn returns the shifts on a date: the active shifts of the working hours in effect on the
+// date, with the exception or holiday of the date applied. Nobody is on duty on duty-free holidays,
+// holidays with alternative hours give every shift those hours.
+func (c *HolidayCalendar) ShiftsOn(hours []WorkingHours, date time.Time) []WorkingHours {
+	shifts := ShiftsOn(hours, date)
+	if exception := c.FindException(date); exception != nil {
+		return exception.Apply(shifts)
+	}
+
+	holiday := c.Find(date)
+	switch {
+	case holiday == nil:
+		return shifts
+	case holiday.IsDutyFree():
+		return nil
+	default:
+		return withHours(shifts, holiday.StartTime, holiday.EndTime)
+	}
+}
+
+// DutyFreeDates returns the dates from from up to but not including to on which nobody is on duty,
+// in no particular order
+func (c *HolidayCalendar) DutyFreeDates(from, to time.Time) []time.Time {
+	if c == nil {
+		return nil
+	}
+	from = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.UTC)
+	inRange := func(date time.Time) bool {
+		return !date.Before(from) && date.Before(to)
+	}
+
+	var dates []time.Time
+	for _, holiday := range c.oneOff {
+		date := time.Date(holiday.Date.Year(), holiday.Date.Month(), holiday.Date.Day(), 0, 0, 0, 0, time.UTC)
+		if holiday.IsDutyFree() && inRange(date) {
+			dates = append(dates, date)
+		}
+	}
+	for _, holiday := range c.recurring {
+		if !holiday.IsDutyFree() {
+			continue
+		}
+		for year := from.Year(); year <= to.Year(); year++ {
+			date := time.Date(year, holiday.Date.Month(), holiday.Date.Day(), 0, 0, 0, 0, time.UTC)
+			// February 29 only recurs in leap years
+			if date.Month() != holiday.Date.Month() || !inRange(date) {
+				continue
+			}
+			if _, overridden := c.oneOff[dayOf(date)]; !overridden {
+				dates = append(dates, date)
+			}
+		}
+	}
+	return dates
+}
+
+// HolidayForm represents form data for creating/updating holidays
+type HolidayForm struct {
+	Date      string `json:"date"`
+	Name      string `json:"name"`
+	Recurring bool   `json:"recurring"`
+	Behavior  string `json:"behavior"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+}
+
+// Validate validates the holiday form data
+func (f *HolidayForm) Validate() []string {
+	var errors []string
+
+	if f.Date == "" {
+		errors = append(errors, "Date is required")
+	} else if _, err := ParseDate(strings.TrimSpace(f.Date)); err != nil {
+		errors = append(errors, "Date must be in YYYY-MM-DD format")
+	}
+
+	name := strings.TrimSpace(f.Name)
+	if name == "" {
+		errors = append(errors, "Name is required")
+	}
+	if len(name) > 100 {
+		errors = append(errors, "Name must be less than 100 characters")
+	}
+
+	if _, ok := HolidayBehaviorNames[f.Behavior]; !ok {
+		errors = append(errors, "Unknown holiday behavior")
+	}
+
+	// Alternative hours use the same validation as working hours
+	if f.Behavior == HolidayBehaviorAlternativeHours {
+		if !isValidTimeFormat(f.StartTime) {
+			errors = append(errors, "Start time must be in HH:MM format (e.g., 09:00)")
+		}
+		if !isValidTimeFormat(f.EndTime) {
+			errors = append(errors, "End time must be in HH:MM format (e.g., 17:00)")
+		}
+		if isValidTimeFormat(f.StartTime) && isValidTimeFormat(f.EndTime) && f.StartTime == f.EndTime {
+			errors = append(errors, "Start and end time must differ")
+		}
+	}
+
+	return errors
+}
@@ -1,6 +1,9 @@
 package models
 
 import (
+	"reflect"
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,6 +29,110 @@ func TestTeamMemberFormValidation(t *testing.T) {
 	if len(errors) != 2 {
 		t.Errorf("Expected 2 errors for invalid form, got: %v", errors)
 	}
+
+	// Notification preferences
+	notifiedForm := TeamMemberForm{Name: "John Doe", Email: "john@example.com", MutedNotifications: []string{NotificationRegenerated}}
+	if errors := notifiedForm.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for notification preferences, got: %v", errors)
+	}
+	invalidNotifiedForm := TeamMemberForm{Name: "John Doe", Email: "John <john@example.com>", MutedNotifications: []string{"lunch_break"}}
+	if errors := invalidNotifiedForm.Validate(); len(errors) != 2 {
+		t.Errorf("Expected 2 errors for invalid notification preferences, got: %v", errors)
+	}
+
+	member := TeamMember{MutedNotifications: notifiedForm.MutedNotifications}
+	if member.WantsNotification(NotificationRegenerated) || !member.WantsNotification(NotificationTakeover) {
+		t.Errorf("Expected only regenerations to be muted, got: %v", member.MutedNotifications)
+	}
+}
+
+// Test participation and work days of part-time members
+func TestTeamMemberPartTime(t *testing.T) {
+	form := TeamMemberForm{Name: "Jane Doe", Participation: 60, WorkDays: []int{3, 0, 1, 0}}
+	if errors := form.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for part-time form, got: %v", errors)
+	}
+	if days := form.GetWorkDays(); !reflect.DeepEqual(days, []int{0, 1, 3}) {
+		t.Errorf("Expected work days [0 1 3], got %v", days)
+	}
+
+	everyDay := TeamMemberForm{WorkDays: []int{0, 1, 2, 3, 4, 5, 6}}
+	if days := everyDay.GetWorkDays(); days != nil {
+		t.Errorf("Expected every day to store no work days, got %v", days)
+	}
+	if everyDay.GetParticipation() != FullParticipation {
+		t.Errorf("Expected unset participation to be full, got %d", everyDay.GetParticipation())
+	}
+
+	invalidForm := TeamMemberForm{Name: "Jane Doe", Participation: 120, WorkDays: []int{7}}
+	if errors := invalidForm.Validate(); len(errors) != 2 {
+		t.Errorf("Expected 2 errors for invalid part-time form, got: %v", errors)
+	}
+
+	member := TeamMember{Participation: 60, WorkDays: []int{0, 1, 3}}
+	monday := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	if !member.WorksOn(monday) || member.WorksOn(monday.AddDate(0, 0, 2)) {
+		t.Error("Expected member to work on Monday but not on Wednesday")
+	}
+	if names := member.GetWorkDayNames(); names != "Mon, Tue, Thu" {
+		t.Errorf("Expected work day names 'Mon, Tue, Thu', got %s", names)
+	}
+	if !member.IsPartTime() || (&TeamMember{}).IsPartTime() {
+		t.Error("Expected only the member with participation and work days to be part-time")
+	}
+}
+
+// Test weekday preferences of members
+func TestWeekdayPreferences(t *testing.T) {
+	monday := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	friday := monday.AddDate(0, 0, 4)
+
+	member := TeamMember{Preferences: []WeekdayPreference{
+		{DayOfWeek: 0, Kind: PreferenceKindAvoid, Strength: PreferenceStrengthHard},
+		{DayOfWeek: 1, Kind: PreferenceKindAvoid, Strength: PreferenceStrengthSoft},
+		{DayOfWeek: 4, Kind: PreferenceKindPrefer, Strength: PreferenceStrengthSoft},
+	}}
+	if member.WorksOn(monday) || !member.WorksOn(monday.AddDate(0, 0, 1)) {
+		t.Error("Expected a hard avoided Monday to be off and a soft avoided Tuesday to be on")
+	}
+	if !member.Avoids(monday.AddDate(0, 0, 1)) || member.Avoids(monday) || !member.Prefers(friday) {
+		t.Error("Expected only the soft avoided Tuesday to be avoided and Friday to be preferred")
+	}
+	if summary := member.GetPreferenceSummary(); summary != "Prefers Fri; Rather not Tue; Never Mon" {
+		t.Errorf("Expected preference summary, got %s", summary)
+	}
+
+	onlyFridays := TeamMember{Preferences: []WeekdayPreference{{DayOfWeek: 4, Kind: PreferenceKindPrefer, Strength: PreferenceStrengthHard}}}
+	if onlyFridays.WorksOn(monday) || !onlyFridays.WorksOn(friday) {
+		t.Error("Expected hard preferred days to be the only days on duty")
+	}
+
+	if preference := ParseWeekdayPreference(2, "avoid:soft"); preference == nil || preference.Kind != PreferenceKindAvoid || preference.Strength != PreferenceStrengthSoft || preference.DayOfWeek != 2 {
+		t.Errorf("Expected a soft avoided Wednesday, got %v", preference)
+	}
+	if ParseWeekdayPreference(2, "") != nil {
+		t.Error("Expected no preference for an empty value")
+	}
+
+	form := TeamMemberForm{Name: "Jane Doe", Preferences: []WeekdayPreference{
+		{DayOfWeek: 4, Kind: PreferenceKindPrefer, Strength: PreferenceStrengthSoft},
+		{DayOfWeek: 0, Kind: PreferenceKindAvoid, Strength: PreferenceStrengthHard},
+	}}
+	if errors := form.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for valid preferences, got: %v", errors)
+	}
+	if preferences := form.GetPreferences(); preferences[0].DayOfWeek != 0 || form.GetPreferenceValue(4) != "prefer:soft" {
+		t.Errorf("Expected preferences ordered by weekday, got %v", preferences)
+	}
+
+	invalidForm := TeamMemberForm{Name: "Jane Doe", Preferences: []WeekdayPreference{
+		{DayOfWeek: 0, Kind: "maybe", Strength: PreferenceStrengthSoft},
+		{DayOfWeek: 0, Kind: PreferenceKindAvoid, Strength: "very"},
+		{DayOfWeek: 9, Kind: PreferenceKindAvoid, Strength: PreferenceStrengthSoft},
+	}}
+	if errors := invalidForm.Validate(); len(errors) != 4 {
+		t.Errorf("Expected 4 errors for invalid preferences, got: %v", errors)
+	}
 }
 
 // Test WorkingHoursForm validation
@@ -53,6 +160,780 @@ func TestWorkingHoursFormValidation(t *testing.T) {
 	if len(errors) < 2 {
 		t.Errorf("Expected at least 2 errors for invalid form, got: %v", errors)
 	}
+
+	// Test shifts of a day
+	shift := func(name, start, end string, active bool) *WorkingHoursForm {
+		return &WorkingHoursForm{DayOfWeek: 0, Name: name, StartTime: start, EndTime: end, Active: active}
+	}
+
+	if errors := ValidateShifts([]*WorkingHoursForm{shift("", "09:00", "17:00", true)}); len(errors) != 0 {
+		t.Errorf("Expected a single unnamed shift to be valid, got: %v", errors)
+	}
+	if errors := ValidateShifts([]*WorkingHoursForm{shift("Morning", "08:00", "13:00", true), shift("Afternoon", "13:00", "18:00", true)}); len(errors) != 0 {
+		t.Errorf("Expected named shifts to be valid, got: %v", errors)
+	}
+	if errors := ValidateShifts([]*WorkingHoursForm{shift("Morning", "08:00", "13:00", true), shift("", "13:00", "18:00", true)}); len(errors) != 1 {
+		t.Errorf("Expected an error for an unnamed shift next to another shift, got: %v", errors)
+	}
+	if errors := ValidateShifts([]*WorkingHoursForm{shift("Morning", "08:00", "13:00", true), shift("morning", "13:00", "18:00", true)}); len(errors) != 1 {
+		t.Errorf("Expected an error for duplicate shift names, got: %v", errors)
+	}
+	if errors := ValidateShifts([]*WorkingHoursForm{shift("Morning", "13:00", "13:00", true)}); len(errors) != 1 {
+		t.Errorf("Expected an error for an invalid shift, got: %v", errors)
+	}
+}
+
+// Test ScheduleSettingsForm validation
+// Test the working hours in effect on a date follow the latest version of every day
+func TestWorkingHoursVersions(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := ParseDate(value)
+		return parsed
+	}
+
+	summer := date("2024-06-01")
+	autumn := date("2024-09-01")
+	hours := []WorkingHours{
+		{DayOfWeek: 0, StartTime: "09:00", EndTime: "17:00", Active: true},
+		{DayOfWeek: 4, StartTime: "09:00", EndTime: "17:00", Active: true},
+		{DayOfWeek: 5, StartTime: "00:00", EndTime: "00:00", Active: false},
+		{DayOfWeek: 4, Name: "Morning", StartTime: "08:00", EndTime: "12:00", Active: true, EffectiveFrom: summer},
+		{DayOfWeek: 4, Name: "Evening", StartTime: "18:00", EndTime: "22:00", Active: true, EffectiveFrom: summer},
+		{DayOfWeek: 0, StartTime: "00:00", EndTime: "00:00", Active: false, EffectiveFrom: summer},
+		{DayOfWeek: 0, StartTime: "10:00", EndTime: "16:00", Active: true, EffectiveFrom: autumn},
+	}
+
+	// The version of a day applies from its effective date on, later in the day included
+	if version := DayVersionOn(hours, 4, date("2024-05-31").Add(20*time.Hour)); len(version) != 1 || version[0].StartTime != "09:00" {
+		t.Errorf("Expected the initial Friday before summer, got %+v", version)
+	}
+	if version := DayVersionOn(hours, 4, summer.Add(10*time.Hour)); len(version) != 2 {
+		t.Errorf("Expected both summer shifts on Friday, got %+v", version)
+	}
+	if version := DayVersionOn(hours, 0, date("2024-06-03")); len(version) != 1 || version[0].Active {
+		t.Errorf("Expected Monday to be off in summer, got %+v", version)
+	}
+	if version := DayVersionOn(hours, 2, summer); version != nil {
+		t.Errorf("Expected no version for Wednesday, got %+v", version)
+	}
+
+	testCases := []struct {
+		date     string
+		expected []string
+	}{
+		{"2024-05-27", []string{"09:00"}},          // Monday before summer
+		{"2024-06-03", nil},                        // Monday off in summer
+		{"2024-06-07", []string{"08:00", "18:00"}}, // Friday in summer
+		{"2024-06-08", nil},                        // Saturday is always off
+		{"2024-09-02", []string{"10:00"}},          // Monday from autumn
+		{"2024-09-06", []string{"08:00", "18:00"}}, // Friday keeps its summer hours
+	}
+
+	for _, tc := range testCases {
+		var starts []string
+		for _, shift := range ShiftsOn(hours, date(tc.date)) {
+			starts = append(starts, shift.StartTime)
+		}
+		if !reflect.DeepEqual(starts, tc.expected) {
+			t.Errorf("Expected shifts starting at %v on %s, got %v", tc.expected, tc.date, starts)
+		}
+	}
+
+	if inEffect := ShiftsInEffect(hours, summer); len(inEffect) != 2 || inEffect[0].DayOfWeek != 4 {
+		t.Errorf("Expected only the Friday shifts in effect in summer, got %+v", inEffect)
+	}
+
+	// Upcoming changes are grouped by date in order, the change of the date itself isn't upcoming
+	changes := UpcomingChanges(hours, date("2024-05-01"))
+	if len(changes) != 2 || !changes[0].EffectiveFrom.Equal(summer) || !changes[1].EffectiveFrom.Equal(autumn) {
+		t.Fatalf("Expected the summer and autumn changes, got %+v", changes)
+	}
+	if shifts := changes[0].Shifts; len(shifts) != 3 || shifts[0].DayOfWeek != 0 || shifts[1].Name != "Morning" {
+		t.Errorf("Expected the summer shifts ordered by day and start time, got %+v", shifts)
+	}
+	if changes := UpcomingChanges(hours, summer); len(changes) != 1 || !changes[0].EffectiveFrom.Equal(autumn) {
+		t.Errorf("Expected only the autumn change after summer started, got %+v", changes)
+	}
+}
+
+func TestScheduleSettingsFormValidation(t *testing.T) {
+	for strategy := range RotationStrategyNames {
+		form := ScheduleSettingsForm{RotationStrategy: strategy}
+		if errors := form.Validate(); len(errors) != 0 {
+			t.Errorf("Expected no errors for strategy %s, got: %v", strategy, errors)
+		}
+	}
+
+	invalidForm := ScheduleSettingsForm{RotationStrategy: "random"}
+	if errors := invalidForm.Validate(); len(errors) != 1 {
+		t.Errorf("Expected 1 error for unknown strategy, got: %v", errors)
+	}
+
+	seededForm := ScheduleSettingsForm{RotationStrategy: RotationStrategySeededShuffle, RotationSeed: " -42 "}
+	if errors := seededForm.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for a negative seed, got: %v", errors)
+	}
+	if seededForm.GetRotationSeed() != -42 {
+		t.Errorf("Expected rotation seed -42, got %d", seededForm.GetRotationSeed())
+	}
+
+	invalidSeedForm := ScheduleSettingsForm{RotationStrategy: RotationStrategySeededShuffle, RotationSeed: "abc"}
+	if errors := invalidSeedForm.Validate(); len(errors) != 1 {
+		t.Errorf("Expected 1 error for a non-numeric seed, got: %v", errors)
+	}
+
+	weeklyForm := ScheduleSettingsForm{RotationStrategy: RotationStrategyRoundRobin, RotationPeriod: RotationPeriodWeekly, HandoverDay: "2"}
+	if errors := weeklyForm.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for a weekly period, got: %v", errors)
+	}
+	if weeklyForm.GetHandoverDay() != 2 || weeklyForm.GetRotationPeriodDays() != 0 {
+		t.Errorf("Expected handover day 2 without a turn length, got %d and %d", weeklyForm.GetHandoverDay(), weeklyForm.GetRotationPeriodDays())
+	}
+
+	blockForm := ScheduleSettingsForm{RotationStrategy: RotationStrategyRoundRobin, RotationPeriod: RotationPeriodWorkingDays, RotationPeriodDays: "3", HandoverDay: "4"}
+	if errors := blockForm.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for a working days period, got: %v", errors)
+	}
+	if blockForm.GetRotationPeriodDays() != 3 || blockForm.GetHandoverDay() != 0 {
+		t.Errorf("Expected a turn length of 3 without a handover day, got %d and %d", blockForm.GetRotationPeriodDays(), blockForm.GetHandoverDay())
+	}
+
+	invalidPeriodForms := []ScheduleSettingsForm{
+		{RotationStrategy: RotationStrategyRoundRobin, RotationPeriod: "monthly"},
+		{RotationStrategy: RotationStrategyRoundRobin, RotationPeriod: RotationPeriodWeekly, HandoverDay: "7"},
+		{RotationStrategy: RotationStrategyRoundRobin, RotationPeriod: RotationPeriodWorkingDays, RotationPeriodDays: "0"},
+		{RotationStrategy: RotationStrategyRoundRobin, RotationPeriod: RotationPeriodWorkingDays, RotationPeriodDays: "61"},
+	}
+	for _, form := range invalidPeriodForms {
+		if errors := form.Validate(); len(errors) != 1 {
+			t.Errorf("Expected 1 error for period %s (%q days, handover %q), got: %v", form.RotationPeriod, form.RotationPeriodDays, form.HandoverDay, errors)
+		}
+	}
+
+	rulesForm := ScheduleSettingsForm{RotationStrategy: RotationStrategyFairShare, MinGapDays: "2", MaxShiftsPerWeek: " 2 ", MaxShiftsPerMonth: "0"}
+	if errors := rulesForm.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for assignment rules, got: %v", errors)
+	}
+	if rulesForm.GetMinGapDays() != 2 || rulesForm.GetMaxShiftsPerWeek() != 2 || rulesForm.GetMaxShiftsPerMonth() != 0 {
+		t.Errorf("Expected rules 2, 2 and 0, got %d, %d and %d", rulesForm.GetMinGapDays(), rulesForm.GetMaxShiftsPerWeek(), rulesForm.GetMaxShiftsPerMonth())
+	}
+
+	invalidRulesForms := []ScheduleSettingsForm{
+		{RotationStrategy: RotationStrategyFairShare, MinGapDays: "-1"},
+		{RotationStrategy: RotationStrategyFairShare, MinGapDays: "21"},
+		{RotationStrategy: RotationStrategyFairShare, MaxShiftsPerWeek: "8"},
+		{RotationStrategy: RotationStrategyFairShare, MaxShiftsPerMonth: "many"},
+		{RotationStrategy: RotationStrategyFairShare, RotationPeriod: RotationPeriodWeekly, HandoverDay: "0", MaxShiftsPerWeek: "1"},
+	}
+	for _, form := range invalidRulesForms {
+		if errors := form.Validate(); len(errors) != 1 {
+			t.Errorf("Expected 1 error for rules %q, %q and %q with period %q, got: %v", form.MinGapDays, form.MaxShiftsPerWeek, form.MaxShiftsPerMonth, form.RotationPeriod, errors)
+		}
+	}
+
+	horizonForm := ScheduleSettingsForm{RotationStrategy: RotationStrategyFairShare, HorizonLength: " 6 ", HorizonUnit: HorizonUnitWeeks, UpToDateDays: "1"}
+	if errors := horizonForm.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for a horizon of 6 weeks, got: %v", errors)
+	}
+	if horizonForm.GetHorizonLength() != 6 || horizonForm.GetHorizonUnit() != HorizonUnitWeeks || horizonForm.GetUpToDateDays() != 1 {
+		t.Errorf("Expected a horizon of 6 weeks up to date for 1 day, got %d %s and %d", horizonForm.GetHorizonLength(), horizonForm.GetHorizonUnit(), horizonForm.GetUpToDateDays())
+	}
+
+	invalidHorizonForms := []ScheduleSettingsForm{
+		{RotationStrategy: RotationStrategyFairShare, HorizonLength: "0"},
+		{RotationStrategy: RotationStrategyFairShare, HorizonLength: "25"},
+		{RotationStrategy: RotationStrategyFairShare, HorizonLength: "105", HorizonUnit: HorizonUnitWeeks},
+		{RotationStrategy: RotationStrategyFairShare, HorizonLength: "2", HorizonUnit: "years"},
+		{RotationStrategy: RotationStrategyFairShare, UpToDateDays: "0"},
+		{RotationStrategy: RotationStrategyFairShare, UpToDateDays: "91"},
+	}
+	for _, form := range invalidHorizonForms {
+		if errors := form.Validate(); len(errors) != 1 {
+			t.Errorf("Expected 1 error for horizon %q %q up to date for %q days, got: %v", form.HorizonLength, form.HorizonUnit, form.UpToDateDays, errors)
+		}
+	}
+
+	// The limit depends on the unit
+	weeksForm := ScheduleSettingsForm{RotationStrategy: RotationStrategyFairShare, HorizonLength: "25", HorizonUnit: HorizonUnitWeeks}
+	if errors := weeksForm.Validate(); len(errors) != 0 {
+		t.Errorf("Expected no errors for a horizon of 25 weeks, got: %v", errors)
+	}
+
+	// An empty state falls back to the deterministic daily rotation
+	state := ScheduleState{}
+	if state.GetRotationStrategy() != RotationStrategyEpochModulo {
+		t.Errorf("Expected default strategy %s, got %s", RotationStrategyEpochModulo, state.GetRotationStrategy())
+	}
+	if state.GetRotationPeriod() != RotationPeriodDaily {
+		t.Errorf("Expected default period %s, got %s", RotationPeriodDaily, state.GetRotationPeriod())
+	}
+	if state.HasAssignmentRules() {
+		t.Error("Expected no assignment rules by default")
+	}
+	if state.DescribeHorizon() != "3 months" || state.GetUpToDateDays() != DefaultUpToDateDays {
+		t.Errorf("Expected a horizon of 3 months up to date for 7 days, got %s and %d", state.DescribeHorizon(), state.GetUpToDateDays())
+	}
+}
+
+// Test the generation horizon and the up-to-date interval
+func TestGenerationHorizon(t *testing.T) {
+	from := time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		state       ScheduleState
+		end         string
+		description string
+	}{
+		{state: ScheduleState{}, end: "2025-05-01", description: "3 months"},
+		{state: ScheduleState{HorizonLength: 1, HorizonUnit: HorizonUnitMonths}, end: "2025-03-03", description: "1 month"},
+		{state: ScheduleState{HorizonLength: 4, HorizonUnit: HorizonUnitWeeks}, end: "2025-02-28", description: "4 weeks"},
+		{state: ScheduleState{HorizonLength: 1, HorizonUnit: HorizonUnitWeeks}, end: "2025-02-07", description: "1 week"},
+	}
+	for _, tc := range testCases {
+		if end := FormatDate(tc.state.HorizonEnd(from)); end != tc.end {
+			t.Errorf("Expected a horizon of %s to end on %s, got %s", tc.description, tc.end, end)
+		}
+		if description := tc.state.DescribeHorizon(); description != tc.description {
+			t.Errorf("Expected horizon %s, got %s", tc.description, description)
+		}
+	}
+
+	state := ScheduleState{LastGenerationDate: from, UpToDateDays: 3}
+	if due := FormatDate(state.NextGenerationDue()); due != "2025-02-03" {
+		t.Errorf("Expected the next generation to be due on 2025-02-03, got %s", due)
+	}
+}
+
+// Test TimeOffForm validation
+func TestTimeOffFormValidation(t *testing.T) {
+	validForms := []TimeOffForm{
+		{StartDate: "2025-07-14", EndDate: "2025-07-18", Reason: "Summer holiday"},
+		{StartDate: "2025-07-14", EndDate: "2025-07-14"}, // A single day without a reason
+	}
+	for _, form := range validForms {
+		if errors := form.Validate(); len(errors) != 0 {
+			t.Errorf("Expected no errors for %+v, got: %v", form, errors)
+		}
+	}
+
+	invalidForms := []struct {
+		form     TimeOffForm
+		expected int
+	}{
+		{TimeOffForm{}, 2},
+		{TimeOffForm{StartDate: "14-07-2025", EndDate: "2025-07-18"}, 1},
+		{TimeOffForm{StartDate: "2025-07-18", EndDate: "2025-07-14"}, 1},
+		{TimeOffForm{StartDate: "2025-07-14", EndDate: "2025-07-18", Reason: strings.Repeat("x", 256)}, 1},
+	}
+	for _, tc := range invalidForms {
+		if errors := tc.form.Validate(); len(errors) != tc.expected {
+			t.Errorf("Expected %d errors for %+v, got: %v", tc.expected, tc.form, errors)
+		}
+	}
+
+	// Covers includes both ends of the period
+	timeOff := TimeOff{StartDate: time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC), EndDate: time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC)}
+	for date, expected := range map[string]bool{"2025-07-13": false, "2025-07-14": true, "2025-07-18": true, "2025-07-19": false} {
+		parsed, _ := ParseDate(date)
+		if timeOff.Covers(parsed.Add(10*time.Hour)) != expected {
+			t.Errorf("Expected Covers(%s) to be %v", date, expected)
+		}
+	}
+	if timeOff.Days() != 5 {
+		t.Errorf("Expected 5 days of time off, got %d", timeOff.Days())
+	}
+}
+
+func TestHolidayFormValidation(t *testing.T) {
+	validForms := []HolidayForm{
+		{Date: "2025-12-25", Name: "Christmas Day", Recurring: true, Behavior: HolidayBehaviorNoDuty},
+		{Date: "2025-12-24", Name: "Christmas Eve", Behavior: HolidayBehaviorAlternativeHours, StartTime: "09:00", EndTime: "13:00"},
+	}
+	for _, form := range validForms {
+		if errors := form.Validate(); len(errors) != 0 {
+			t.Errorf("Expected no errors for %+v, got: %v", form, errors)
+		}
+	}
+
+	invalidForms := []struct {
+		form     HolidayForm
+		expected int
+	}{
+		{HolidayForm{}, 3},
+		{HolidayForm{Date: "25-12-2025", Name: "Christmas Day", Behavior: HolidayBehaviorNoDuty}, 1},
+		{HolidayForm{Date: "2025-12-25", Name: strings.Repeat("x", 101), Behavior: HolidayBehaviorNoDuty}, 1},
+		{HolidayForm{Date: "2025-12-25", Name: "Christmas Day", Behavior: "half_day"}, 1},
+		{HolidayForm{Date: "2025-12-24", Name: "Christmas Eve", Behavior: HolidayBehaviorAlternativeHours}, 2},
+		{HolidayForm{Date: "2025-12-24", Name: "Christmas Eve", Behavior: HolidayBehaviorAlternativeHours, StartTime: "13:00", EndTime: "13:00"}, 1},
+	}
+	for _, tc := range invalidForms {
+		if errors := tc.form.Validate(); len(errors) != tc.expected {
+			t.Errorf("Expected %d errors for %+v, got: %v", tc.expected, tc.form, errors)
+		}
+	}
+}
+
+func TestHolidayCalendar(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := ParseDate(value)
+		return parsed
+	}
+
+	calendar := NewHolidayCalendar([]Holiday{
+		{Name: "Christmas Day", Date: date("2020-12-25"), Recurring: true, Behavior: HolidayBehaviorNoDuty},
+		{Name: "Company Day", Date: date("2025-12-25"), Behavior: HolidayBehaviorAlternativeHours, StartTime: "10:00", EndTime: "12:00"},
+		{Name: "Liberation Day", Date: date("2025-05-05"), Behavior: HolidayBehaviorNoDuty},
+	})
+
+	tests := []struct {
+		date     string
+		expected string
+		dutyFree bool
+	}{
+		{"2024-12-25", "Christmas Day", true},  // Recurring holidays apply to every year
+		{"2025-12-25", "Company Day", false},   // One-off holidays take precedence
+		{"2025-05-05", "Liberation Day", true}, // One-off holidays only apply to their own year
+		{"2026-05-05", "", false},
+		{"2025-12-26", "", false},
+	}
+	for _, tc := range tests {
+		holiday := calendar.Find(date(tc.date).Add(10 * time.Hour))
+		name := ""
+		if holiday != nil {
+			name = holiday.Name
+		}
+		if name != tc.expected {
+			t.Errorf("Expected holiday %q on %s, got %q", tc.expected, tc.date, name)
+		}
+		if calendar.IsDutyFree(date(tc.date)) != tc.dutyFree {
+			t.Errorf("Expected IsDutyFree(%s) to be %v", tc.date, tc.dutyFree)
+		}
+	}
+
+	// A nil calendar has no holidays
+	var empty *HolidayCalendar
+	if empty.Find(date("2025-12-25")) != nil || empty.IsDutyFree(date("2025-12-25")) {
+		t.Error("Expected a nil calendar to have no holidays")
+	}
+}
+
+func TestHolidayCalendarDutyFreeDates(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := ParseDate(value)
+		return parsed
+	}
+
+	calendar := NewHolidayCalendar([]Holiday{
+		{Name: "Christmas Day", Date: date("2020-12-25"), Recurring: true, Behavior: HolidayBehaviorNoDuty},
+		{Name: "Leap Day", Date: date("2020-02-29"), Recurring: true, Behavior: HolidayBehaviorNoDuty},
+		{Name: "Company Day", Date: date("2025-12-25"), Behavior: HolidayBehaviorAlternativeHours, StartTime: "10:00", EndTime: "12:00"},
+		{Name: "Liberation Day", Date: date("2025-05-05"), Behavior: HolidayBehaviorNoDuty},
+	})
+
+	// The end date is excluded, the start date included even when it's later in the day
+	dates := calendar.DutyFreeDates(date("2023-12-25").Add(10*time.Hour), date("2025-12-31"))
+	var formatted []string
+	for _, dutyFree := range dates {
+		formatted = append(formatted, FormatDate(dutyFree))
+	}
+	sort.Strings(formatted)
+
+	// Christmas 2025 has alternative hours, February 29 only recurs in leap years
+	expected := []string{"2023-12-25", "2024-02-29", "2024-12-25", "2025-05-05"}
+	if !reflect.DeepEqual(formatted, expected) {
+		t.Errorf("Expected duty-free dates %v, got %v", expected, formatted)
+	}
+
+	if dates := calendar.DutyFreeDates(date("2024-01-01"), date("2024-02-29")); len(dates) != 0 {
+		t.Errorf("Expected no duty-free dates before February 29, got %v", dates)
+	}
+
+	var empty *HolidayCalendar
+	if dates := empty.DutyFreeDates(date("2020-01-01"), date("2030-01-01")); dates != nil {
+		t.Errorf("Expected a nil calendar to have no duty-free dates, got %v", dates)
+	}
+}
+
+// Test working hours exceptions override the shifts and holidays of their date
+func TestWorkingHoursExceptions(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := ParseDate(value)
+		return parsed
+	}
+
+	hours := []WorkingHours{
+		{DayOfWeek: 3, Name: "Morning", StartTime: "08:00", EndTime: "13:00", Active: true},
+		{DayOfWeek: 3, Name: "Afternoon", StartTime: "13:00", EndTime: "18:00", Active: true},
+		{DayOfWeek: 4, Name: "Morning", StartTime: "08:00", EndTime: "13:00", Active: true},
+		{DayOfWeek: 4, Name: "Afternoon", StartTime: "13:00", EndTime: "18:00", Active: true},
+	}
+	calendar := NewHolidayCalendar([]Holiday{
+		{Name: "Christmas Day", Date: date("2020-12-25"), Recurring: true, Behavior: HolidayBehaviorNoDuty},
+		{Name: "Boxing Day", Date: date("2020-12-26"), Recurring: true, Behavior: HolidayBehaviorAlternativeHours, StartTime: "10:00", EndTime: "12:00"},
+	}).WithExceptions([]WorkingHoursException{
+		{Date: date("2026-12-24"), Kind: ExceptionKindHours, StartTime: "09:00", EndTime: "13:00"},
+		{Date: date("2026-12-25"), Kind: ExceptionKindHours, StartTime: "10:00", EndTime: "14:00"},
+		{Date: date("2026-12-31"), Kind: ExceptionKindNonWorking},
+		{Date: date("2027-01-02"), Kind: ExceptionKindWorking, Name: "Morning", StartTime: "07:00", EndTime: "12:00"},
+	})
+
+	tests := []struct {
+		date     string
+		expected []string // Shift hours as name start-end
+	}{
+		{"2026-12-23", nil}, // No shifts on Wednesdays
+		{"2026-12-24", []string{"Morning 09:00-13:00", "Afternoon 09:00-13:00"}}, // Every shift gets the hours
+		{"2026-12-25", []string{"Morning 10:00-14:00", "Afternoon 10:00-14:00"}}, // The exception takes precedence over the holiday
+		{"2027-12-24", []string{"Morning 08:00-13:00", "Afternoon 13:00-18:00"}}, // Exceptions only apply to their own date
+		{"2025-12-25", nil}, // The holidays apply without an exception
+		{"2025-12-26", []string{"Morning 10:00-12:00", "Afternoon 10:00-12:00"}},
+		{"2026-12-31", nil},
+		{"2027-01-02", []string{"Morning 07:00-12:00"}}, // A Saturday with a single shift
+	}
+	for _, tc := range tests {
+		var shifts []string
+		for _, shift := range calendar.ShiftsOn(hours, date(tc.date).Add(10*time.Hour)) {
+			shifts = append(shifts, shift.Name+" "+shift.StartTime+"-"+shift.EndTime)
+		}
+		if !reflect.DeepEqual(shifts, tc.expected) {
+			t.Errorf("Expected shifts %v on %s, got %v", tc.expected, tc.date, shifts)
+		}
+	}
+
+	// The extra working day of a shift is a non-working day for the other shifts
+	if shifts := calendar.ForShift("Afternoon").ShiftsOn(hours, date("2027-01-02")); len(shifts) != 0 {
+		t.Errorf("Expected no afternoon shift on the extra working day, got %v", shifts)
+	}
+	if shifts := calendar.ForShift("Morning").ShiftsOn(hours, date("2027-01-02")); len(shifts) != 1 {
+		t.Errorf("Expected the morning shift on the extra working day, got %v", shifts)
+	}
+	if calendar.FindException(date("2027-01-02")).Kind != ExceptionKindWorking {
+		t.Error("Expected narrowing the calendar to leave the exceptions of the calendar unchanged")
+	}
+
+	// The end date is excluded, the start date included even when it's later in the day
+	var formatted []string
+	for _, exceptionDate := range calendar.ExceptionDates(date("2026-12-24").Add(10*time.Hour), date("2027-01-02")) {
+		formatted = append(formatted, FormatDate(exceptionDate))
+	}
+	sort.Strings(formatted)
+	expected := []string{"2026-12-24", "2026-12-25", "2026-12-31"}
+	if !reflect.DeepEqual(formatted, expected) {
+		t.Errorf("Expected exception dates %v, got %v", expected, formatted)
+	}
+
+	// A nil calendar has no exceptions, but can get them
+	var empty *HolidayCalendar
+	if empty.FindException(date("2026-12-24")) != nil || empty.ExceptionDates(date("2026-01-01"), date("2027-01-01")) != nil {
+		t.Error("Expected a nil calendar to have no exceptions")
+	}
+	if empty.WithExceptions([]WorkingHoursException{{Date: date("2026-12-24"), Kind: ExceptionKindNonWorking}}).FindException(date("2026-12-24")) == nil {
+		t.Error("Expected exceptions added to a nil calendar to apply")
+	}
+}
+
+func TestWorkingHoursExceptionFormValidation(t *testing.T) {
+	tests := []struct {
+		form     WorkingHoursExceptionForm
+		expected int
+	}{
+		{WorkingHoursExceptionForm{Date: "2026-12-24", Kind: ExceptionKindHours, StartTime: "09:00", EndTime: "13:00"}, 0},
+		{WorkingHoursExceptionForm{Date: "2026-12-24", Kind: ExceptionKindHours, StartTime: "22:00", EndTime: "06:00"}, 0}, // Overnight hours
+		{WorkingHoursExceptionForm{Date: "2026-12-31", Kind: ExceptionKindNonWorking}, 0},                                  // Non-working days have no hours
+		{WorkingHoursExceptionForm{Date: "2027-01-02", Kind: ExceptionKindWorking, Name: "Release", StartTime: "10:00", EndTime: "16:00"}, 0},
+		{WorkingHoursExceptionForm{Kind: ExceptionKindNonWorking}, 1},
+		{WorkingHoursExceptionForm{Date: "24-12-2026", Kind: ExceptionKindNonWorking}, 1},
+		{WorkingHoursExceptionForm{Date: "2026-12-24", Kind: "holiday"}, 1},
+		{WorkingHoursExceptionForm{Date: "2026-12-24", Kind: ExceptionKindHours, StartTime: "9", EndTime: ""}, 2},
+		{WorkingHoursExceptionForm{Date: "2026-12-24", Kind: ExceptionKindWorking, StartTime: "13:00", EndTime: "13:00"}, 1},
+		{WorkingHoursExceptionForm{Date: "2026-12-24", Kind: ExceptionKindNonWorking, Reason: strings.Repeat("a", 101)}, 1},
+	}
+	for _, tc := range tests {
+		if errors := tc.form.Validate(); len(errors) != tc.expected {
+			t.Errorf("Expected %d errors for %+v, got: %v", tc.expected, tc.form, errors)
+		}
+	}
+}
+
+// Test rotation queue changes keep the cursor on the next member
+func TestRotationQueue(t *testing.T) {
+	testCases := []struct {
+		name           string
+		queue          []int
+		cursor         int
+		change         func(s *ScheduleState)
+		expectedQueue  []int
+		expectedCursor int
+	}{
+		{
+			name:           "insert at the cursor keeps the next member",
+			queue:          []int{1, 2, 3},
+			cursor:         1,
+			change:         func(s *ScheduleState) { s.InsertIntoQueue(4, 1) },
+			expectedQueue:  []int{1, 4, 2, 3},
+			expectedCursor: 2,
+		},
+		{
+			name:           "insert at the end",
+			queue:          []int{1, 2, 3},
+			cursor:         1,
+			change:         func(s *ScheduleState) { s.InsertIntoQueue(4, 3) },
+			expectedQueue:  []int{1, 2, 3, 4},
+			expectedCursor: 1,
+		},
+		{
+			name:           "insert into an empty queue",
+			queue:          nil,
+			cursor:         0,
+			change:         func(s *ScheduleState) { s.InsertIntoQueue(4, 0) },
+			expectedQueue:  []int{4},
+			expectedCursor: 0,
+		},
+		{
+			name:           "insert an already queued member",
+			queue:          []int{1, 2, 3},
+			cursor:         1,
+			change:         func(s *ScheduleState) { s.InsertIntoQueue(3, 0) },
+			expectedQueue:  []int{1, 2, 3},
+			expectedCursor: 1,
+		},
+		{
+			name:           "remove the member at the cursor",
+			queue:          []int{1, 2, 3},
+			cursor:         1,
+			change:         func(s *ScheduleState) { s.RemoveFromQueue(2) },
+			expectedQueue:  []int{1, 3},
+			expectedCursor: 1,
+		},
+		{
+			name:           "remove a member before the cursor",
+			queue:          []int{1, 2, 3},
+			cursor:         2,
+			change:         func(s *ScheduleState) { s.RemoveFromQueue(1) },
+			expectedQueue:  []int{2, 3},
+			expectedCursor: 1,
+		},
+		{
+			name:           "remove the last member at the cursor wraps around",
+			queue:          []int{1, 2, 3},
+			cursor:         2,
+			change:         func(s *ScheduleState) { s.RemoveFromQueue(3) },
+			expectedQueue:  []int{1, 2},
+			expectedCursor: 0,
+		},
+		{
+			name:           "remove the only member",
+			queue:          []int{1},
+			cursor:         0,
+			change:         func(s *ScheduleState) { s.RemoveFromQueue(1) },
+			expectedQueue:  []int{},
+			expectedCursor: 0,
+		},
+		{
+			name:           "move the next member",
+			queue:          []int{1, 2, 3},
+			cursor:         1,
+			change:         func(s *ScheduleState) { s.MoveInQueue(2, 0) },
+			expectedQueue:  []int{2, 1, 3},
+			expectedCursor: 0,
+		},
+		{
+			name:           "move the next member down",
+			queue:          []int{1, 2, 3},
+			cursor:         0,
+			change:         func(s *ScheduleState) { s.MoveInQueue(1, 2) },
+			expectedQueue:  []int{2, 3, 1},
+			expectedCursor: 2,
+		},
+		{
+			name:           "move another member ahead of the next member",
+			queue:          []int{1, 2, 3},
+			cursor:         1,
+			change:         func(s *ScheduleState) { s.MoveInQueue(3, 0) },
+			expectedQueue:  []int{3, 1, 2},
+			expectedCursor: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := &ScheduleState{RotationQueue: append([]int{}, tc.queue...), RotationCursor: tc.cursor}
+			tc.change(state)
+
+			if len(state.RotationQueue) != len(tc.expectedQueue) ||
+				(len(tc.expectedQueue) > 0 && !reflect.DeepEqual(state.RotationQueue, tc.expectedQueue)) {
+				t.Errorf("Expected queue %v, got %v", tc.expectedQueue, state.RotationQueue)
+			}
+			if state.RotationCursor != tc.expectedCursor {
+				t.Errorf("Expected cursor %d, got %d", tc.expectedCursor, state.RotationCursor)
+			}
+		})
+	}
+}
+
+// Test rotation queue changes keep the cursor of every shift on its next member
+func TestRotationQueueShiftCursors(t *testing.T) {
+	state := &ScheduleState{
+		RotationQueue:  []int{1, 2, 3},
+		RotationCursor: 0,
+		ShiftCursors:   map[string]int{"Morning": 1, "Afternoon": 2},
+	}
+
+	if cursor, ok := state.ShiftCursor(""); !ok || cursor != 0 {
+		t.Errorf("Expected the unnamed shift to use the rotation cursor, got %d (%v)", cursor, ok)
+	}
+	if _, ok := state.ShiftCursor("Evening"); ok {
+		t.Errorf("Expected no cursor for an unknown shift")
+	}
+
+	state.InsertIntoQueue(4, 1)
+	expected := map[string]int{"Morning": 2, "Afternoon": 3}
+	if state.RotationCursor != 0 || !reflect.DeepEqual(state.ShiftCursors, expected) {
+		t.Errorf("After insert expected cursors 0 and %v, got %d and %v", expected, state.RotationCursor, state.ShiftCursors)
+	}
+
+	state.RemoveFromQueue(1)
+	expected = map[string]int{"Morning": 1, "Afternoon": 2}
+	if state.RotationCursor != 0 || !reflect.DeepEqual(state.ShiftCursors, expected) {
+		t.Errorf("After remove expected cursors 0 and %v, got %d and %v", expected, state.RotationCursor, state.ShiftCursors)
+	}
+
+	// Queue is 4, 2, 3 and member 2 is next in the morning shift
+	state.MoveInQueue(2, 0)
+	if cursor, _ := state.ShiftCursor("Morning"); state.RotationQueue[cursor] != 2 {
+		t.Errorf("Expected member 2 to stay next in the morning shift, got queue %v cursor %d", state.RotationQueue, cursor)
+	}
+	if cursor, _ := state.ShiftCursor("Afternoon"); state.RotationQueue[cursor] != 3 {
+		t.Errorf("Expected member 3 to stay next in the afternoon shift, got queue %v cursor %d", state.RotationQueue, cursor)
+	}
+}
+
+func TestScheduleEntryRole(t *testing.T) {
+	entry := ScheduleEntry{}
+	if entry.GetRole() != ScheduleRolePrimary || entry.IsBackup() || entry.GetRoleName() != "Primary" {
+		t.Errorf("Expected an entry without a role to be the primary, got %q", entry.GetRole())
+	}
+
+	entry.Role = ScheduleRoleBackup
+	if !entry.IsBackup() || entry.GetRoleName() != "Backup" {
+		t.Errorf("Expected a backup entry, got %q", entry.GetRoleName())
+	}
+}
+
+func TestTeamFormValidation(t *testing.T) {
+	validForms := []TeamForm{
+		{Name: "Platform Team"},
+		{Name: "Platform Team", Slug: "platform"},
+	}
+	for _, form := range validForms {
+		if errors := form.Validate(); len(errors) != 0 {
+			t.Errorf("Expected no errors for %+v, got: %v", form, errors)
+		}
+	}
+
+	invalidForms := []struct {
+		form     TeamForm
+		expected int
+	}{
+		{TeamForm{}, 1},
+		{TeamForm{Name: "🚀"}, 1},
+		{TeamForm{Name: strings.Repeat("x", 101)}, 2},
+		{TeamForm{Name: "Platform Team", Slug: strings.Repeat("x", 51)}, 1},
+	}
+	for _, tc := range invalidForms {
+		if errors := tc.form.Validate(); len(errors) != tc.expected {
+			t.Errorf("Expected %d errors for %+v, got: %v", tc.expected, tc.form, errors)
+		}
+	}
+}
+
+func TestNotificationChannelFormValidation(t *testing.T) {
+	events := []string{NotificationTakeover, NotificationOverrideRemoved}
+	validForms := []NotificationChannelForm{
+		{Name: "On-call channel", Kind: ChannelKindSlack, Target: "https://hooks.slack.com/services/T0/B0/x", Events: events},
+		{Name: "Team channel", Kind: ChannelKindTeams, Target: "https://example.webhook.office.com/webhookb2/x", Events: events},
+		{Name: "Members", Kind: ChannelKindEmail, Events: events},
+		{Name: "Mailing list", Kind: ChannelKindEmail, Target: "team@example.com", Events: events},
+		{Name: "Incident tool", Kind: ChannelKindWebhook, Target: "http://localhost:9000/hook", Events: events},
+	}
+	for _, form := range validForms {
+		if errors := form.Validate(); len(errors) != 0 {
+			t.Errorf("Expected no errors for %+v, got: %v", form, errors)
+		}
+	}
+
+	invalidForms := []struct {
+		form     NotificationChannelForm
+		expected int
+	}{
+		{NotificationChannelForm{}, 3},
+		{NotificationChannelForm{Name: strings.Repeat("x", 101), Kind: ChannelKindEmail, Events: events}, 1},
+		{NotificationChannelForm{Name: "On-call channel", Kind: ChannelKindSlack, Events: events}, 1},
+		{NotificationChannelForm{Name: "On-call channel", Kind: ChannelKindSlack, Target: "hooks.slack.com/services/x", Events: events}, 1},
+		{NotificationChannelForm{Name: "Mailing list", Kind: ChannelKindEmail, Target: "team.example.com", Events: events}, 1},
+		{NotificationChannelForm{Name: "Members", Kind: ChannelKindEmail}, 1},
+		{NotificationChannelForm{Name: "Members", Kind: ChannelKindEmail, Events: []string{"lunch_break"}}, 1},
+	}
+	for _, tc := range invalidForms {
+		if errors := tc.form.Validate(); len(errors) != tc.expected {
+			t.Errorf("Expected %d errors for %+v, got: %v", tc.expected, tc.form, errors)
+		}
+	}
+}
+
+func TestWebhookSubscriptionFormValidation(t *testing.T) {
+	events := []string{WebhookEventEntryCreated, WebhookEventMemberDeactivated}
+	validForms := []WebhookSubscriptionForm{
+		{Name: "Paging", URL: "https://example.com/hooks/eod", Events: events},
+		{Name: "Local", URL: "http://localhost:9000/hook", Secret: "0123456789abcdef", Events: events},
+	}
+	for _, form := range validForms {
+		if errors := form.Validate(); len(errors) != 0 {
+			t.Errorf("Expected no errors for %+v, got: %v", form, errors)
+		}
+	}
+
+	invalidForms := []struct {
+		form     WebhookSubscriptionForm
+		expected int
+	}{
+		{WebhookSubscriptionForm{}, 3},
+		{WebhookSubscriptionForm{Name: strings.Repeat("x", 101), URL: "https://example.com", Events: events}, 1},
+		{WebhookSubscriptionForm{Name: "Paging", URL: "example.com/hooks", Events: events}, 1},
+		{WebhookSubscriptionForm{Name: "Paging", URL: "https://example.com", Secret: "too short", Events: events}, 1},
+		{WebhookSubscriptionForm{Name: "Paging", URL: "https://example.com", Secret: strings.Repeat("x", 256), Events: events}, 1},
+		{WebhookSubscriptionForm{Name: "Paging", URL: "https://example.com", Events: []string{"entry.archived"}}, 1},
+	}
+	for _, tc := range invalidForms {
+		if errors := tc.form.Validate(); len(errors) != tc.expected {
+			t.Errorf("Expected %d errors for %+v, got: %v", tc.expected, tc.form, errors)
+		}
+	}
+
+	// Events come in display order
+	form := WebhookSubscriptionForm{Events: []string{WebhookEventMemberDeactivated, WebhookEventEntryCreated}}
+	if got := form.GetEvents(); len(got) != 2 || got[0] != WebhookEventEntryCreated {
+		t.Errorf("Expected events in display order, got %v", got)
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	testCases := map[string]string{
+		"Platform Team":      "platform-team",
+		"  Team #2 (Night) ": "team-2-night",
+		"On-Call---Crew":     "on-call-crew",
+		"!!!":                "",
+	}
+	for name, expected := range testCases {
+		if slug := Slugify(name); slug != expected {
+			t.Errorf("Expected slug %q for %q, got %q", expected, name, slug)
+		}
+	}
+
+	team := &Team{Slug: "platform-team"}
+	if team.Path() != "/teams/platform-team" {
+		t.Errorf("Expected path /teams/platform-team, got %s", team.Path())
+	}
 }
 
 // Test time validation functions
@@ -73,13 +954,76 @@ func TestTimeValidation(t *testing.T) {
 		}
 	}
 
-	// Test start before end
-	if !isStartBeforeEnd("09:00", "17:00") {
-		t.Error("Expected 09:00 to be before 17:00")
+	// Test shifts ending on the following day
+	if EndsNextDay("09:00", "17:00") {
+		t.Error("Expected 09:00-17:00 to end on the same day")
+	}
+
+	if !EndsNextDay("18:00", "02:00") {
+		t.Error("Expected 18:00-02:00 to end on the following day")
+	}
+
+	if !EndsNextDay("16:00", "00:00") {
+		t.Error("Expected 16:00-00:00 to end at midnight of the following day")
+	}
+}
+
+// Test shifts that cross midnight
+func TestOvernightShifts(t *testing.T) {
+	overnightHours := WorkingHoursForm{DayOfWeek: 0, StartTime: "18:00", EndTime: "02:00", Active: true}
+	if errors := overnightHours.Validate(); len(errors) > 0 {
+		t.Errorf("Expected overnight working hours to be valid, got errors: %v", errors)
+	}
+
+	emptyHours := WorkingHoursForm{DayOfWeek: 0, StartTime: "09:00", EndTime: "09:00", Active: true}
+	if errors := emptyHours.Validate(); len(errors) == 0 {
+		t.Error("Expected working hours with equal start and end time to be invalid")
+	}
+
+	overnightEntry := ScheduleEntryForm{Date: "2025-10-06", TeamMemberID: 1, StartTime: "18:00", EndTime: "02:00"}
+	if errors := overnightEntry.Validate(); len(errors) > 0 {
+		t.Errorf("Expected overnight schedule entry to be valid, got errors: %v", errors)
+	}
+
+	entry := ScheduleEntry{
+		Date:      time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC),
+		StartTime: "18:00",
+		EndTime:   "02:00",
+	}
+	if !entry.EndsNextDay() {
+		t.Error("Expected the entry to end on the following day")
+	}
+
+	expectedEnd := time.Date(2025, 10, 7, 2, 0, 0, 0, time.Local)
+	if !entry.EndsAt(time.Local).Equal(expectedEnd) {
+		t.Errorf("Expected the entry to end at %v, got %v", expectedEnd, entry.EndsAt(time.Local))
+	}
+
+	onDuty := []time.Time{
+		time.Date(2025, 10, 6, 18, 0, 0, 0, time.Local),
+		time.Date(2025, 10, 6, 23, 30, 0, 0, time.Local),
+		time.Date(2025, 10, 7, 1, 59, 0, 0, time.Local), // Early morning belongs to the previous day's shift
+	}
+	for _, moment := range onDuty {
+		if !entry.IsOnDutyAt(moment) {
+			t.Errorf("Expected the entry to be on duty at %v", moment)
+		}
+	}
+
+	offDuty := []time.Time{
+		time.Date(2025, 10, 6, 17, 59, 0, 0, time.Local),
+		time.Date(2025, 10, 7, 2, 0, 0, 0, time.Local),
+		time.Date(2025, 10, 6, 1, 0, 0, 0, time.Local),
+	}
+	for _, moment := range offDuty {
+		if entry.IsOnDutyAt(moment) {
+			t.Errorf("Expected the entry not to be on duty at %v", moment)
+		}
 	}
 
-	if isStartBeforeEnd("17:00", "09:00") {
-		t.Error("Expected 17:00 not to be before 09:00")
+	dayEntry := ScheduleEntry{Date: entry.Date, StartTime: "09:00", EndTime: "17:00"}
+	if dayEntry.EndsNextDay() || !dayEntry.IsOnDutyAt(time.Date(2025, 10, 6, 12, 0, 0, 0, time.Local)) {
+		t.Error("Expected a day shift to end on the same day and be on duty at noon")
 	}
 }
 
@@ -93,9 +1037,9 @@ func TestDateUtilities(t *testing.T) {
 		t.Error("Current week start should not be after now")
 	}
 
-	next3Months := GetNext3Months()
-	if next3Months.End.Before(now.AddDate(0, 2, 0)) {
-		t.Error("Next 3 months should extend at least 2 months from now")
+	horizon := (&ScheduleState{}).GetHorizon()
+	if horizon.End.Before(now.AddDate(0, 2, 0)) {
+		t.Error("Default horizon of 3 months should extend at least 2 months from now")
 	}
 
 	// Test weekday number conversion
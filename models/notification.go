@@ -0,0 +1,156 @@
+package models
+
+import (
+	"net/mail"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Notification events
+const (
+	NotificationShiftStartsSoon = "shift_starts_soon" // A member's shift starts soon
+	NotificationTakeover        = "takeover"          // A member was assigned a shift by a takeover
+	NotificationOverrideRemoved = "override_removed"  // A member's takeover was removed again
+	NotificationRegenerated     = "regenerated"       // The schedule of the team was generated again
+)
+
+// NotificationEvents lists the notification events in display order
+var NotificationEvents = []string{
+	NotificationShiftStartsSoon,
+	NotificationTakeover,
+	NotificationOverrideRemoved,
+	NotificationRegenerated,
+}
+
+// NotificationEventNames maps the notification events to their display names
+var NotificationEventNames = map[string]string{
+	NotificationShiftStartsSoon: "Shift starts soon",
+	NotificationTakeover:        "Assigned by a takeover",
+	NotificationOverrideRemoved: "Takeover removed",
+	NotificationRegenerated:     "Schedule regenerated",
+}
+
+// Notification channel kinds
+const (
+	ChannelKindSlack   = "slack"   // Slack incoming webhook
+	ChannelKindTeams   = "teams"   // Microsoft Teams incoming webhook
+	ChannelKindEmail   = "email"   // Email to the members concerned
+	ChannelKindWebhook = "webhook" // JSON posted to any URL
+)
+
+// ChannelKindNames maps the notification channel kinds to their display names
+var ChannelKindNames = map[string]string{
+	ChannelKindSlack:   "Slack webhook",
+	ChannelKindTeams:   "Microsoft Teams webhook",
+	ChannelKindEmail:   "Email",
+	ChannelKindWebhook: "JSON webhook",
+}
+
+// NotificationChannel is a place the notifications of a team are sent to
+type NotificationChannel struct {
+	ID     int      `json:"id" db:"id"`
+	Name   string   `json:"name" db:"name"`
+	Kind   string   `json:"kind" db:"kind"`
+	Target string   `json:"target" db:"target"` // Webhook URL, or an address that gets every email
+	Events []string `json:"events" db:"events"` // Notification events the channel gets
+	Active bool     `json:"active" db:"active"`
+	AuditFields
+}
+
+// GetKindName returns the display name of the channel kind
+func (c *NotificationChannel) GetKindName() string {
+	return ChannelKindNames[c.Kind]
+}
+
+// Subscribes checks if the channel gets the notifications of an event
+func (c *NotificationChannel) Subscribes(event string) bool {
+	return slices.Contains(c.Events, event)
+}
+
+// GetEventNames returns the display names of the events the channel gets, like "Shift starts soon, Schedule regenerated"
+func (c *NotificationChannel) GetEventNames() string {
+	var names []string
+	for _, event := range NotificationEvents {
+		if c.Subscribes(event) {
+			names = append(names, NotificationEventNames[event])
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// NotificationChannelForm represents form data for creating and updating notification channels
+type NotificationChannelForm struct {
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`
+	Target string   `json:"target"`
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+}
+
+// Validate validates the notification channel form data
+func (f *NotificationChannelForm) Validate() []string {
+	var errors []string
+
+	name := strings.TrimSpace(f.Name)
+	if name == "" {
+		errors = append(errors, "Name is required")
+	}
+	if len(name) > 100 {
+		errors = append(errors, "Name must be less than 100 characters")
+	}
+
+	target := strings.TrimSpace(f.Target)
+	switch f.Kind {
+	case ChannelKindEmail:
+		if target != "" && !isValidEmail(target) {
+			errors = append(errors, "Email address is invalid")
+		}
+	case ChannelKindSlack, ChannelKindTeams, ChannelKindWebhook:
+		if !isValidWebhookURL(target) {
+			errors = append(errors, "Webhook URL must be an http or https URL")
+		}
+	default:
+		errors = append(errors, "Unknown channel kind")
+	}
+
+	if len(f.Events) == 0 {
+		errors = append(errors, "Choose at least one event")
+	}
+	for _, event := range f.Events {
+		if _, ok := NotificationEventNames[event]; !ok {
+			errors = append(errors, "Unknown notification event")
+			break
+		}
+	}
+
+	return errors
+}
+
+// HasEvent checks if an event is selected
+func (f *NotificationChannelForm) HasEvent(event string) bool {
+	return slices.Contains(f.Events, event)
+}
+
+// GetEvents returns the selected events in display order and without duplicates
+func (f *NotificationChannelForm) GetEvents() []string {
+	var events []string
+	for _, event := range NotificationEvents {
+		if f.HasEvent(event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// isValidEmail checks if a value is a plain email address like alice@example.com
+func isValidEmail(address string) bool {
+	parsed, err := mail.ParseAddress(address)
+	return err == nil && parsed.Address == address
+}
+
+// isValidWebhookURL checks if a value is an absolute http or https URL
+func isValidWebhookURL(value string) bool {
+	parsed, err := url.Parse(value)
+	return err == nil && (parsed.Scheme == "https" || parsed.Scheme == "http") && parsed.Host != ""
+}
@@ -1,19 +1,26 @@
 package models
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
 // ScheduleEntry represents a single schedule entry
 type ScheduleEntry struct {
-	ID                   int       `json:"id" db:"id"`
-	Date                 time.Time `json:"date" db:"date"`
-	TeamMemberID         int       `json:"team_member_id" db:"team_member_id"`
-	StartTime            string    `json:"start_time" db:"start_time"`
-	EndTime              string    `json:"end_time" db:"end_time"`
-	IsManualOverride     bool      `json:"is_manual_override" db:"is_manual_override"`
-	OriginalTeamMemberID *int      `json:"original_team_member_id,omitempty" db:"original_team_member_id"`
-	TakeoverReason       string    `json:"takeover_reason,omitempty" db:"takeover_reason"`
+	ID                   int        `json:"id" db:"id"`
+	Date                 time.Time  `json:"date" db:"date"`
+	TeamMemberID         int        `json:"team_member_id" db:"team_member_id"`
+	StartTime            string     `json:"start_time" db:"start_time"`
+	EndTime              string     `json:"end_time" db:"end_time"`           // Before the start time when the shift ends on the following day
+	Shift                string     `json:"shift,omitempty" db:"shift"`       // Name of the shift, empty for the unnamed shift
+	Role                 string     `json:"role" db:"role"`                   // Primary or backup member of the shift
+	Layer                string     `json:"layer" db:"layer"`                 // Working hours duty or out-of-hours on-call
+	EndDate              *time.Time `json:"end_date,omitempty" db:"end_date"` // Day an on-call block ends, nil for working hours shifts
+	IsManualOverride     bool       `json:"is_manual_override" db:"is_manual_override"`
+	OriginalTeamMemberID *int       `json:"original_team_member_id,omitempty" db:"original_team_member_id"`
+	TakeoverReason       string     `json:"takeover_reason,omitempty" db:"takeover_reason"`
 
 	// Joined fields (populated from joins with team_members table)
 	TeamMemberName        string `json:"team_member_name,omitempty" db:"team_member_name"`
@@ -22,10 +29,497 @@ type ScheduleEntry struct {
 	AuditFields // Embedded audit fields
 }
 
-// ScheduleState represents the current state of schedule generation
+// ScheduleState represents the current state of schedule generation of a team
 type ScheduleState struct {
-	ID                 int       `json:"id" db:"id"`
-	LastGenerationDate time.Time `json:"last_generation_date" db:"last_generation_date"`
+	TeamID             int           `json:"team_id" db:"team_id"`
+	LastGenerationDate time.Time     `json:"last_generation_date" db:"last_generation_date"`
+	RotationStrategy   string        `json:"rotation_strategy" db:"rotation_strategy"`
+	RotationSeed       int64         `json:"rotation_seed" db:"rotation_seed"`
+	RotationPeriod     string        `json:"rotation_period" db:"rotation_period"`           // How long a member stays on duty before handing over
+	RotationPeriodDays int           `json:"rotation_period_days" db:"rotation_period_days"` // Working days per turn, for the working days period
+	HandoverDay        int           `json:"handover_day" db:"handover_day"`                 // Day of the week a weekly turn starts, 0=Monday
+	BackupEnabled      bool          `json:"backup_enabled" db:"backup_enabled"`             // Every shift also gets a backup member
+	OnCallEnabled      bool          `json:"on_call_enabled" db:"on_call_enabled"`           // Cover the time outside working hours with on-call blocks
+	RotationQueue      []int         `json:"rotation_queue" db:"rotation_queue"`             // Active member IDs in rotation order
+	RotationCursor     int           `json:"rotation_cursor" db:"rotation_cursor"`           // Queue position of the next member on duty
+	RotationCursorDate time.Time     `json:"rotation_cursor_date" db:"rotation_cursor_date"` // First date the cursor applies to
+	OnCallCursor       int           `json:"on_call_cursor" db:"on_call_cursor"`             // Queue position of the next member on call
+	MinGapDays         int           `json:"min_gap_days" db:"min_gap_days"`                 // Working days off a member gets at least between two shifts, 0 for no minimum
+	MaxShiftsPerWeek   int           `json:"max_shifts_per_week" db:"max_shifts_per_week"`   // Shifts a member gets at most in a calendar week, 0 for no maximum
+	MaxShiftsPerMonth  int           `json:"max_shifts_per_month" db:"max_shifts_per_month"` // Shifts a member gets at most in a calendar month, 0 for no maximum
+	HorizonLength      int           `json:"horizon_length" db:"horizon_length"`             // How far ahead the schedule is generated, in horizon units
+	HorizonUnit        string        `json:"horizon_unit" db:"horizon_unit"`                 // Weeks or months
+	UpToDateDays       int           `json:"up_to_date_days" db:"up_to_date_days"`           // Days a full generation counts as up to date
+	GeneratedUntil     time.Time     `json:"generated_until" db:"generated_until"`           // End of the period filled so far, exclusive
+	LastRun            GenerationRun `json:"last_run"`                                       // Outcome of the last generation run
+
+	// Queue positions of the next member on duty in each named shift, the unnamed shift uses RotationCursor
+	ShiftCursors map[string]int `json:"shift_cursors,omitempty" db:"rotation_shift_cursors"`
+}
+
+// Roles of the members on duty in a shift
+const (
+	ScheduleRolePrimary = "primary" // On duty
+	ScheduleRoleBackup  = "backup"  // Next in line, steps in when the primary can't
+)
+
+// ScheduleRoleNames maps schedule roles to readable names
+var ScheduleRoleNames = map[string]string{
+	ScheduleRolePrimary: "Primary",
+	ScheduleRoleBackup:  "Backup",
+}
+
+// Layers of the schedule. Working hours entries cover the configured shifts, on-call entries the
+// time in between.
+const (
+	ScheduleLayerWorkingHours = "working_hours"
+	ScheduleLayerOnCall       = "on_call"
+)
+
+// ScheduleLayerNames maps schedule layers to readable names
+var ScheduleLayerNames = map[string]string{
+	ScheduleLayerWorkingHours: "Working hours",
+	ScheduleLayerOnCall:       "On-call",
+}
+
+// Rotation strategies supported by the schedule generator
+const (
+	RotationStrategyEpochModulo         = "epoch_modulo"          // Deterministic rotation based on working days since a fixed epoch
+	RotationStrategyFairShare           = "fair_share"            // Gives each slot to the member furthest behind their fair share
+	RotationStrategyRoundRobin          = "round_robin"           // Continues the persisted rotation queue from its cursor
+	RotationStrategyLeastRecentlyServed = "least_recently_served" // Gives each slot to whoever has gone longest without duty
+	RotationStrategySeededShuffle       = "seeded_shuffle"        // Rotates through a roster order shuffled with a fixed seed
+)
+
+// RotationStrategyNames maps rotation strategies to readable names
+var RotationStrategyNames = map[string]string{
+	RotationStrategyEpochModulo:         "Deterministic rotation",
+	RotationStrategyFairShare:           "Fairness balancing",
+	RotationStrategyRoundRobin:          "Round robin",
+	RotationStrategyLeastRecentlyServed: "Least recently served",
+	RotationStrategySeededShuffle:       "Seeded shuffle",
+}
+
+// Rotation periods: how long a member stays on duty before the next member takes over
+const (
+	RotationPeriodDaily       = "daily"        // A different member every working day
+	RotationPeriodWeekly      = "weekly"       // One member for a week, handing over on the handover day
+	RotationPeriodWorkingDays = "working_days" // One member for a fixed number of working days
+)
+
+// RotationPeriodNames maps rotation periods to readable names
+var RotationPeriodNames = map[string]string{
+	RotationPeriodDaily:       "Daily",
+	RotationPeriodWeekly:      "Weekly",
+	RotationPeriodWorkingDays: "Every N working days",
+}
+
+// MaxRotationPeriodDays limits the length of a turn for the working days period
+const MaxRotationPeriodDays = 60
+
+// Upper limits of the assignment rules
+const (
+	MaxMinGapDays     = 20
+	MaxShiftsPerWeek  = 7
+	MaxShiftsPerMonth = 31
+)
+
+// Units of the generation horizon
+const (
+	HorizonUnitWeeks  = "weeks"
+	HorizonUnitMonths = "months"
+)
+
+// HorizonUnitNames maps horizon units to readable names
+var HorizonUnitNames = map[string]string{
+	HorizonUnitWeeks:  "Weeks",
+	HorizonUnitMonths: "Months",
+}
+
+// Defaults and upper limits of the generation horizon and the up-to-date interval
+const (
+	DefaultHorizonLength = 3
+	DefaultHorizonUnit   = HorizonUnitMonths
+	MaxHorizonWeeks      = 104
+	MaxHorizonMonths     = 24
+	DefaultUpToDateDays  = 7
+	MaxUpToDateDays      = 90
+)
+
+// Triggers of a generation run
+const (
+	GenerationTriggerManual    = "manual"    // Someone clicked Generate
+	GenerationTriggerAutomatic = "automatic" // The nightly run
+)
+
+// GenerationTriggerNames maps generation triggers to readable names
+var GenerationTriggerNames = map[string]string{
+	GenerationTriggerManual:    "Manual",
+	GenerationTriggerAutomatic: "Nightly",
+}
+
+// GenerationRun records the outcome of a generation run
+type GenerationRun struct {
+	At      time.Time `json:"at" db:"last_run_at"` // Zero if the schedule was never generated by a run
+	Trigger string    `json:"trigger" db:"last_run_trigger"`
+	Success bool      `json:"success" db:"last_run_success"`
+	Message string    `json:"message" db:"last_run_message"`
+}
+
+// HasRun checks if a generation run was recorded
+func (r GenerationRun) HasRun() bool {
+	return !r.At.IsZero()
+}
+
+// GetTriggerName returns the readable name of what started the run
+func (r GenerationRun) GetTriggerName() string {
+	return GenerationTriggerNames[r.Trigger]
+}
+
+// GetHorizonLength returns the configured horizon length, defaulting to 3
+func (s *ScheduleState) GetHorizonLength() int {
+	if s.HorizonLength <= 0 {
+		return DefaultHorizonLength
+	}
+	return s.HorizonLength
+}
+
+// GetHorizonUnit returns the configured horizon unit, defaulting to months
+func (s *ScheduleState) GetHorizonUnit() string {
+	if s.HorizonUnit == "" {
+		return DefaultHorizonUnit
+	}
+	return s.HorizonUnit
+}
+
+// HorizonEnd returns the date up to which a schedule generated on the given date runs
+func (s *ScheduleState) HorizonEnd(from time.Time) time.Time {
+	if s.GetHorizonUnit() == HorizonUnitWeeks {
+		return from.AddDate(0, 0, 7*s.GetHorizonLength())
+	}
+	return from.AddDate(0, s.GetHorizonLength(), 0)
+}
+
+// GetHorizon returns the date range from today up to the horizon
+func (s *ScheduleState) GetHorizon() DateRange {
+	now := time.Now()
+	start := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	return DateRange{Start: start, End: s.HorizonEnd(start)}
+}
+
+// DescribeHorizon returns the horizon in words, like "3 months" or "1 week"
+func (s *ScheduleState) DescribeHorizon() string {
+	unit := strings.TrimSuffix(s.GetHorizonUnit(), "s")
+	if s.GetHorizonLength() == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", s.GetHorizonLength(), unit)
+}
+
+// GetUpToDateDays returns the days a full generation counts as up to date, defaulting to 7
+func (s *ScheduleState) GetUpToDateDays() int {
+	if s.UpToDateDays <= 0 {
+		return DefaultUpToDateDays
+	}
+	return s.UpToDateDays
+}
+
+// NextGenerationDue returns when the last full generation stops being up to date
+func (s *ScheduleState) NextGenerationDue() time.Time {
+	return s.LastGenerationDate.AddDate(0, 0, s.GetUpToDateDays())
+}
+
+// HasAssignmentRules checks if any of the rules limiting how often a member is on duty is set
+func (s *ScheduleState) HasAssignmentRules() bool {
+	return s.MinGapDays > 0 || s.MaxShiftsPerWeek > 0 || s.MaxShiftsPerMonth > 0
+}
+
+// GetRotationPeriod returns the configured rotation period, defaulting to daily
+func (s *ScheduleState) GetRotationPeriod() string {
+	if s.RotationPeriod == "" {
+		return RotationPeriodDaily
+	}
+	return s.RotationPeriod
+}
+
+// GetRotationStrategy returns the configured rotation strategy, defaulting to the deterministic rotation
+func (s *ScheduleState) GetRotationStrategy() string {
+	if s.RotationStrategy == "" {
+		return RotationStrategyEpochModulo
+	}
+	return s.RotationStrategy
+}
+
+// QueuePosition returns the position of a member in the rotation queue, or -1 if not queued
+func (s *ScheduleState) QueuePosition(memberID int) int {
+	for i, id := range s.RotationQueue {
+		if id == memberID {
+			return i
+		}
+	}
+	return -1
+}
+
+// ShiftCursor returns the queue position of the next member on duty in a shift, and whether
+// the shift has a cursor yet. The unnamed shift always uses RotationCursor.
+func (s *ScheduleState) ShiftCursor(shift string) (int, bool) {
+	if shift == "" {
+		return s.RotationCursor, true
+	}
+	cursor, ok := s.ShiftCursors[shift]
+	return cursor, ok
+}
+
+// SetShiftCursor sets the queue position of the next member on duty in a shift
+func (s *ScheduleState) SetShiftCursor(shift string, cursor int) {
+	if shift == "" {
+		s.RotationCursor = cursor
+		return
+	}
+	if s.ShiftCursors == nil {
+		s.ShiftCursors = make(map[string]int)
+	}
+	s.ShiftCursors[shift] = cursor
+}
+
+// updateCursors applies a change to the cursor of every shift
+func (s *ScheduleState) updateCursors(update func(cursor int) int) {
+	s.RotationCursor = update(s.RotationCursor)
+	s.OnCallCursor = update(s.OnCallCursor)
+	for shift, cursor := range s.ShiftCursors {
+		s.ShiftCursors[shift] = update(cursor)
+	}
+}
+
+// InsertIntoQueue adds a member to the rotation queue at the given position.
+// The cursors keep pointing at the same next member.
+func (s *ScheduleState) InsertIntoQueue(memberID, position int) {
+	if s.QueuePosition(memberID) >= 0 {
+		return
+	}
+	if position < 0 || position > len(s.RotationQueue) {
+		position = len(s.RotationQueue)
+	}
+
+	if len(s.RotationQueue) > 0 {
+		s.updateCursors(func(cursor int) int {
+			if position <= cursor {
+				return cursor + 1
+			}
+			return cursor
+		})
+	}
+	s.RotationQueue = append(s.RotationQueue[:position], append([]int{memberID}, s.RotationQueue[position:]...)...)
+}
+
+// RemoveFromQueue takes a member out of the rotation queue.
+// If the member was next in line, the cursor moves on to whoever follows them.
+func (s *ScheduleState) RemoveFromQueue(memberID int) {
+	position := s.QueuePosition(memberID)
+	if position < 0 {
+		return
+	}
+
+	s.RotationQueue = append(s.RotationQueue[:position], s.RotationQueue[position+1:]...)
+	s.updateCursors(func(cursor int) int {
+		if position < cursor {
+			cursor--
+		}
+		if cursor >= len(s.RotationQueue) {
+			cursor = 0
+		}
+		return cursor
+	})
+}
+
+// MoveInQueue moves a member to a new position in the rotation queue.
+// Whoever was next in line stays next, even if it is the member being moved.
+func (s *ScheduleState) MoveInQueue(memberID, position int) {
+	current := s.QueuePosition(memberID)
+	if current < 0 {
+		return
+	}
+
+	// Remember the shifts the member was next in line for
+	var wasNext []string
+	if s.RotationCursor == current {
+		wasNext = append(wasNext, "")
+	}
+	for shift, cursor := range s.ShiftCursors {
+		if cursor == current {
+			wasNext = append(wasNext, shift)
+		}
+	}
+	wasNextOnCall := s.OnCallCursor == current
+
+	s.RemoveFromQueue(memberID)
+	s.InsertIntoQueue(memberID, position)
+	for _, shift := range wasNext {
+		s.SetShiftCursor(shift, s.QueuePosition(memberID))
+	}
+	if wasNextOnCall {
+		s.OnCallCursor = s.QueuePosition(memberID)
+	}
+}
+
+// ScheduleSettingsForm represents form data for the schedule generation settings
+type ScheduleSettingsForm struct {
+	RotationStrategy   string `json:"rotation_strategy"`
+	RotationSeed       string `json:"rotation_seed"` // Optional, defaults to 0
+	RotationPeriod     string `json:"rotation_period"`
+	RotationPeriodDays string `json:"rotation_period_days"` // Only used by the working days period
+	HandoverDay        string `json:"handover_day"`         // Only used by the weekly period, 0=Monday
+	BackupEnabled      bool   `json:"backup_enabled"`
+	OnCallEnabled      bool   `json:"on_call_enabled"`
+	MinGapDays         string `json:"min_gap_days"`         // Optional, defaults to no minimum
+	MaxShiftsPerWeek   string `json:"max_shifts_per_week"`  // Optional, defaults to no maximum
+	MaxShiftsPerMonth  string `json:"max_shifts_per_month"` // Optional, defaults to no maximum
+	HorizonLength      string `json:"horizon_length"`       // Optional, defaults to 3
+	HorizonUnit        string `json:"horizon_unit"`         // Optional, defaults to months
+	UpToDateDays       string `json:"up_to_date_days"`      // Optional, defaults to 7
+}
+
+// Validate validates the schedule settings form data
+func (f *ScheduleSettingsForm) Validate() []string {
+	var errors []string
+
+	if _, ok := RotationStrategyNames[f.RotationStrategy]; !ok {
+		errors = append(errors, "Rotation strategy is not supported")
+	}
+
+	if seed := strings.TrimSpace(f.RotationSeed); seed != "" {
+		if _, err := strconv.ParseInt(seed, 10, 64); err != nil {
+			errors = append(errors, "Shuffle seed must be a whole number")
+		}
+	}
+
+	switch f.RotationPeriod {
+	case "", RotationPeriodDaily: // Forms without a period keep the daily rotation
+	case RotationPeriodWeekly:
+		if day, err := strconv.Atoi(strings.TrimSpace(f.HandoverDay)); err != nil || day < 0 || day > 6 {
+			errors = append(errors, "Handover day must be a day of the week")
+		}
+	case RotationPeriodWorkingDays:
+		if days, err := strconv.Atoi(strings.TrimSpace(f.RotationPeriodDays)); err != nil || days < 1 || days > MaxRotationPeriodDays {
+			errors = append(errors, fmt.Sprintf("Turn length must be between 1 and %d working days", MaxRotationPeriodDays))
+		}
+	default:
+		errors = append(errors, "Rotation period is not supported")
+	}
+
+	errors = append(errors, validateRule(f.MinGapDays, MaxMinGapDays, "Minimum gap must be between 0 and %d working days")...)
+	errors = append(errors, validateRule(f.MaxShiftsPerWeek, MaxShiftsPerWeek, "Shifts per week must be between 0 and %d")...)
+	errors = append(errors, validateRule(f.MaxShiftsPerMonth, MaxShiftsPerMonth, "Shifts per month must be between 0 and %d")...)
+
+	switch f.HorizonUnit {
+	case "", HorizonUnitMonths:
+		errors = append(errors, validateSetting(f.HorizonLength, 1, MaxHorizonMonths, "Horizon must be between %d and %d months")...)
+	case HorizonUnitWeeks:
+		errors = append(errors, validateSetting(f.HorizonLength, 1, MaxHorizonWeeks, "Horizon must be between %d and %d weeks")...)
+	default:
+		errors = append(errors, "Horizon unit is not supported")
+	}
+	errors = append(errors, validateSetting(f.UpToDateDays, 1, MaxUpToDateDays, "Up-to-date interval must be between %d and %d days")...)
+
+	// A weekly or longer turn puts a member on duty several days in a row by design
+	if f.RotationPeriod != "" && f.RotationPeriod != RotationPeriodDaily &&
+		(f.GetMinGapDays() > 0 || f.GetMaxShiftsPerWeek() > 0 || f.GetMaxShiftsPerMonth() > 0) {
+		errors = append(errors, "Assignment rules only apply to the daily rotation period")
+	}
+
+	return errors
+}
+
+// validateRule checks that an optional assignment rule is a whole number between 0 and the limit
+func validateRule(value string, limit int, message string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < 0 || n > limit {
+		return []string{fmt.Sprintf(message, limit)}
+	}
+	return nil
+}
+
+// validateSetting checks that an optional setting is a whole number between the bounds
+func validateSetting(value string, lower, upper int, message string) []string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.Atoi(value); err != nil || n < lower || n > upper {
+		return []string{fmt.Sprintf(message, lower, upper)}
+	}
+	return nil
+}
+
+// GetHorizonLength returns the parsed horizon length, or the default if none was given
+func (f *ScheduleSettingsForm) GetHorizonLength() int {
+	length, err := strconv.Atoi(strings.TrimSpace(f.HorizonLength))
+	if err != nil {
+		return DefaultHorizonLength
+	}
+	return length
+}
+
+// GetHorizonUnit returns the horizon unit, or the default if none was given
+func (f *ScheduleSettingsForm) GetHorizonUnit() string {
+	if f.HorizonUnit == "" {
+		return DefaultHorizonUnit
+	}
+	return f.HorizonUnit
+}
+
+// GetUpToDateDays returns the parsed up-to-date interval, or the default if none was given
+func (f *ScheduleSettingsForm) GetUpToDateDays() int {
+	days, err := strconv.Atoi(strings.TrimSpace(f.UpToDateDays))
+	if err != nil {
+		return DefaultUpToDateDays
+	}
+	return days
+}
+
+// GetMinGapDays returns the parsed minimum gap between two shifts, or 0 if none was given
+func (f *ScheduleSettingsForm) GetMinGapDays() int {
+	days, _ := strconv.Atoi(strings.TrimSpace(f.MinGapDays))
+	return days
+}
+
+// GetMaxShiftsPerWeek returns the parsed maximum of shifts per week, or 0 if none was given
+func (f *ScheduleSettingsForm) GetMaxShiftsPerWeek() int {
+	shifts, _ := strconv.Atoi(strings.TrimSpace(f.MaxShiftsPerWeek))
+	return shifts
+}
+
+// GetMaxShiftsPerMonth returns the parsed maximum of shifts per month, or 0 if none was given
+func (f *ScheduleSettingsForm) GetMaxShiftsPerMonth() int {
+	shifts, _ := strconv.Atoi(strings.TrimSpace(f.MaxShiftsPerMonth))
+	return shifts
+}
+
+// GetRotationPeriodDays returns the parsed turn length for the working days period, or 0 for other periods
+func (f *ScheduleSettingsForm) GetRotationPeriodDays() int {
+	if f.RotationPeriod != RotationPeriodWorkingDays {
+		return 0
+	}
+	days, _ := strconv.Atoi(strings.TrimSpace(f.RotationPeriodDays))
+	return days
+}
+
+// GetHandoverDay returns the parsed handover day for the weekly period, or 0 (Monday) for other periods
+func (f *ScheduleSettingsForm) GetHandoverDay() int {
+	if f.RotationPeriod != RotationPeriodWeekly {
+		return 0
+	}
+	day, _ := strconv.Atoi(strings.TrimSpace(f.HandoverDay))
+	return day
+}
+
+// GetRotationSeed returns the parsed shuffle seed, or 0 if none was given
+func (f *ScheduleSettingsForm) GetRotationSeed() int64 {
+	seed, _ := strconv.ParseInt(strings.TrimSpace(f.RotationSeed), 10, 64)
+	return seed
 }
 
 // ScheduleEntryForm represents form data for manual overrides
@@ -34,6 +528,7 @@ type ScheduleEntryForm struct {
 	TeamMemberID int    `json:"team_member_id"`
 	StartTime    string `json:"start_time"` // "09:00" format
 	EndTime      string `json:"end_time"`   // "17:00" format
+	Confirmed    bool   `json:"confirmed"`  // Save the entry even if it breaks the assignment rules
 }
 
 // WeekView represents a week's worth of schedule entries for display
@@ -50,11 +545,89 @@ type DayView struct {
 	IsToday bool
 }
 
+// DayLayer holds the entries of a day that belong to one layer of the schedule
+type DayLayer struct {
+	Layer   string
+	Entries []ScheduleEntry
+}
+
+// Layers returns the entries of the day grouped by layer, working hours first. Layers without
+// entries are left out.
+func (d *DayView) Layers() []DayLayer {
+	var layers []DayLayer
+	for _, layer := range []string{ScheduleLayerWorkingHours, ScheduleLayerOnCall} {
+		dayLayer := DayLayer{Layer: layer}
+		for _, entry := range d.Entries {
+			if entry.GetLayer() == layer {
+				dayLayer.Entries = append(dayLayer.Entries, entry)
+			}
+		}
+		if len(dayLayer.Entries) > 0 {
+			layers = append(layers, dayLayer)
+		}
+	}
+	return layers
+}
+
+// IsOnCall checks if the layer holds the on-call blocks
+func (l *DayLayer) IsOnCall() bool {
+	return l.Layer == ScheduleLayerOnCall
+}
+
+// GetLayerName returns the readable name of the layer
+func (l *DayLayer) GetLayerName() string {
+	return ScheduleLayerNames[l.Layer]
+}
+
 // GetFormattedDate returns the date in YYYY-MM-DD format
 func (s *ScheduleEntry) GetFormattedDate() string {
 	return s.Date.Format("2006-01-02")
 }
 
+// GetRole returns the role of the entry, defaulting to primary
+func (s *ScheduleEntry) GetRole() string {
+	if s.Role == "" {
+		return ScheduleRolePrimary
+	}
+	return s.Role
+}
+
+// IsBackup checks if the entry is for the backup member of the shift
+func (s *ScheduleEntry) IsBackup() bool {
+	return s.Role == ScheduleRoleBackup
+}
+
+// GetRoleName returns the readable name of the role
+func (s *ScheduleEntry) GetRoleName() string {
+	return ScheduleRoleNames[s.GetRole()]
+}
+
+// GetLayer returns the layer of the entry, defaulting to working hours
+func (s *ScheduleEntry) GetLayer() string {
+	if s.Layer == "" {
+		return ScheduleLayerWorkingHours
+	}
+	return s.Layer
+}
+
+// IsOnCall checks if the entry is an on-call block outside working hours
+func (s *ScheduleEntry) IsOnCall() bool {
+	return s.Layer == ScheduleLayerOnCall
+}
+
+// GetLayerName returns the readable name of the layer
+func (s *ScheduleEntry) GetLayerName() string {
+	return ScheduleLayerNames[s.GetLayer()]
+}
+
+// GetEndDay returns the short weekday an on-call block ends on, empty for working hours shifts
+func (s *ScheduleEntry) GetEndDay() string {
+	if s.EndDate == nil {
+		return ""
+	}
+	return s.EndDate.Format("Mon")
+}
+
 // GetWeekday returns the weekday name
 func (s *ScheduleEntry) GetWeekday() string {
 	return s.Date.Weekday().String()
@@ -67,6 +640,40 @@ func (s *ScheduleEntry) IsToday() bool {
 	return today == entryDate
 }
 
+// EndsNextDay checks if the shift crosses midnight and ends on the day after the entry's date.
+// On-call blocks show the day they end instead.
+func (s *ScheduleEntry) EndsNextDay() bool {
+	return s.EndDate == nil && EndsNextDay(s.StartTime, s.EndTime)
+}
+
+// StartsAt returns the moment the shift starts, in the location of the given time
+func (s *ScheduleEntry) StartsAt(loc *time.Location) time.Time {
+	if !isValidTimeFormat(s.StartTime) {
+		return time.Date(s.Date.Year(), s.Date.Month(), s.Date.Day(), 0, 0, 0, 0, loc)
+	}
+	minutes := timeToMinutes(s.StartTime)
+	return time.Date(s.Date.Year(), s.Date.Month(), s.Date.Day(), minutes/60, minutes%60, 0, 0, loc)
+}
+
+// EndsAt returns the moment the shift ends, on the following day for overnight shifts and on the
+// end date for on-call blocks
+func (s *ScheduleEntry) EndsAt(loc *time.Location) time.Time {
+	if !isValidTimeFormat(s.StartTime) || !isValidTimeFormat(s.EndTime) {
+		return s.StartsAt(loc)
+	}
+	if s.EndDate != nil {
+		minutes := timeToMinutes(s.EndTime)
+		return time.Date(s.EndDate.Year(), s.EndDate.Month(), s.EndDate.Day(), minutes/60, minutes%60, 0, 0, loc)
+	}
+	return s.StartsAt(loc).Add(shiftDuration(s.StartTime, s.EndTime))
+}
+
+// IsOnDutyAt checks if the shift is under way at the given time. The early-morning hours of an
+// overnight shift belong to the shift of the previous day.
+func (s *ScheduleEntry) IsOnDutyAt(t time.Time) bool {
+	return !t.Before(s.StartsAt(t.Location())) && t.Before(s.EndsAt(t.Location()))
+}
+
 // IsPast checks if the entry is in the past
 func (s *ScheduleEntry) IsPast() bool {
 	today := time.Now()
@@ -106,10 +713,10 @@ func (f *ScheduleEntryForm) Validate() []string {
 		errors = append(errors, "End time must be in HH:MM format (e.g., 17:00)")
 	}
 
-	// Check that start time is before end time
+	// An end time before the start time means the shift ends on the following day
 	if isValidTimeFormat(f.StartTime) && isValidTimeFormat(f.EndTime) {
-		if !isStartBeforeEnd(f.StartTime, f.EndTime) {
-			errors = append(errors, "Start time must be before end time")
+		if f.StartTime == f.EndTime {
+			errors = append(errors, "Start and end time must differ")
 		}
 	}
 
@@ -123,11 +730,85 @@ type GenerationRequest struct {
 
 // GenerationResult represents the result of schedule generation
 type GenerationResult struct {
-	Success           bool      `json:"success"`
-	Message           string    `json:"message"`
-	EntriesCreated    int       `json:"entries_created"`
-	GenerationDate    time.Time `json:"generation_date"`
-	NextGenerationDue time.Time `json:"next_generation_due"`
+	Success           bool                    `json:"success"`
+	Message           string                  `json:"message"`
+	Extended          bool                    `json:"extended"` // Only the dates that came within the horizon were added
+	EntriesCreated    int                     `json:"entries_created"`
+	GenerationDate    time.Time               `json:"generation_date"`
+	NextGenerationDue time.Time               `json:"next_generation_due"`
+	Unsatisfied       []UnsatisfiedConstraint `json:"unsatisfied,omitempty"` // Constraints the generation couldn't meet
+}
+
+// UnsatisfiedConstraint is a weekday preference, an assignment rule or a shift a generation couldn't honour
+type UnsatisfiedConstraint struct {
+	Date         time.Time `json:"date"`
+	Shift        string    `json:"shift,omitempty"`
+	TeamMemberID int       `json:"team_member_id,omitempty"` // Member whose preference or rule wasn't honoured, if any
+	Hard         bool      `json:"hard"`                     // A shift left open rather than a member on duty against a preference or rule
+	Description  string    `json:"description"`
+}
+
+// SchedulePreview shows what generating the schedule again would change, without changing anything
+type SchedulePreview struct {
+	From    time.Time         `json:"from"`
+	To      time.Time         `json:"to"`
+	Result  *GenerationResult `json:"result"`  // What the generation would report
+	Days    []PreviewDay      `json:"days"`    // Days on which a slot changes member, in date order
+	Members []MemberChanges   `json:"members"` // Totals per member before and after, by name
+}
+
+// HasChanges checks if generating the schedule again would change any slot
+func (p *SchedulePreview) HasChanges() bool {
+	return len(p.Days) > 0
+}
+
+// PreviewDay holds the slots of a day that change member
+type PreviewDay struct {
+	Date    time.Time    `json:"date"`
+	Changes []SlotChange `json:"changes"`
+}
+
+// SlotChange is a shift, backup or on-call block that goes to another member, or that is added or
+// removed
+type SlotChange struct {
+	Shift     string         `json:"shift,omitempty"`
+	StartTime string         `json:"start_time"`
+	EndTime   string         `json:"end_time"`
+	Role      string         `json:"role"`
+	Layer     string         `json:"layer"`
+	Before    *PreviewMember `json:"before,omitempty"` // Nil if the slot is new
+	After     *PreviewMember `json:"after,omitempty"`  // Nil if the slot goes away
+}
+
+// GetRoleName returns the readable name of the role of the slot
+func (c *SlotChange) GetRoleName() string {
+	return ScheduleRoleNames[c.Role]
+}
+
+// GetLayerName returns the readable name of the layer of the slot
+func (c *SlotChange) GetLayerName() string {
+	return ScheduleLayerNames[c.Layer]
+}
+
+// PreviewMember is the member on duty in a slot before or after generating
+type PreviewMember struct {
+	TeamMemberID int    `json:"team_member_id"`
+	Name         string `json:"name"`
+}
+
+// MemberChanges sums up how generating the schedule again changes the duties of a member
+type MemberChanges struct {
+	TeamMemberID int         `json:"team_member_id"`
+	Name         string      `json:"name"`
+	Before       int         `json:"before"` // Entries in the previewed period now
+	After        int         `json:"after"`  // Entries in the previewed period after generating
+	Gained       []time.Time `json:"gained,omitempty"`
+	Lost         []time.Time `json:"lost,omitempty"`
+}
+
+// Difference returns how many entries the member gains, negative if they lose entries
+func (m *MemberChanges) Difference() int {
+	return m.After - m.Before
 }
 
 // TakeoverForm represents form data for taking over a shift
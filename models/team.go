@@ -0,0 +1,77 @@
+package models
+
+import (
+	"strings"
+)
+
+// DefaultTeamID is the team everything belonged to before several teams could share a deployment
+const DefaultTeamID = 1
+
+// Team is a group of members with its own working hours, rotation settings and schedule
+type Team struct {
+	ID   int    `json:"id" db:"id"`
+	Name string `json:"name" db:"name"`
+	Slug string `json:"slug" db:"slug"` // Identifies the team in URLs like /teams/{slug}/schedule
+	AuditFields
+}
+
+// Path returns the URL prefix of the pages of the team
+func (t *Team) Path() string {
+	return "/teams/" + t.Slug
+}
+
+// TeamForm represents form data for creating and renaming teams
+type TeamForm struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"` // Optional, derived from the name when empty
+}
+
+// Validate validates the team form data
+func (f *TeamForm) Validate() []string {
+	var errors []string
+
+	name := strings.TrimSpace(f.Name)
+	if name == "" {
+		errors = append(errors, "Name is required")
+	}
+	if len(name) > 100 {
+		errors = append(errors, "Name must be less than 100 characters")
+	}
+
+	slug := f.GetSlug()
+	if slug == "" && name != "" {
+		errors = append(errors, "Slug must contain at least one letter or digit")
+	}
+	if len(slug) > 50 {
+		errors = append(errors, "Slug must be less than 50 characters")
+	}
+
+	return errors
+}
+
+// GetSlug returns the slug for the team: the given one, or one derived from the name
+func (f *TeamForm) GetSlug() string {
+	if slug := strings.TrimSpace(f.Slug); slug != "" {
+		return Slugify(slug)
+	}
+	return Slugify(f.Name)
+}
+
+// Slugify turns a name into a URL-friendly identifier: lowercase letters and digits separated by
+// single dashes
+func Slugify(name string) string {
+	var slug strings.Builder
+	dash := false
+	for _, c := range strings.ToLower(name) {
+		if (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') {
+			if dash && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			slug.WriteRune(c)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	return slug.String()
+}
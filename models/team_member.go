@@ -1,24 +1,93 @@
 package models
 
 import (
+	"slices"
+	"strings"
 	"time"
 )
 
+// FullParticipation is the participation of a member who takes a full share of the turns
+const FullParticipation = 100
+
 // TeamMember represents a team member in the EoD scheduler
 type TeamMember struct {
 	ID          int       `json:"id" db:"id"`
 	Name        string    `json:"name" db:"name"`
 	SlackHandle string    `json:"slack_handle" db:"slack_handle"`
+	Email       string    `json:"email" db:"email"` // Address for email notifications, empty for none
 	Active      bool      `json:"active" db:"active"`
 	DateAdded   time.Time `json:"date_added" db:"date_added"`
-	AuditFields           // Embedded audit fields
+	// Participation is the share of a full-time member's turns in percent, like 60 for someone
+	// working three days a week
+	Participation int                 `json:"participation" db:"participation"`
+	WorkDays      []int               `json:"work_days" db:"work_days"`     // Weekdays the member can be on duty (0=Monday), empty for every day
+	Preferences   []WeekdayPreference `json:"preferences" db:"preferences"` // At most one per weekday
+	// MutedNotifications are the notification events the member doesn't want to be notified about
+	MutedNotifications []string `json:"muted_notifications" db:"muted_notifications"`
+	AuditFields                 // Embedded audit fields
+}
+
+// GetParticipation returns the participation in percent, full participation when not set
+func (m *TeamMember) GetParticipation() int {
+	if m.Participation <= 0 {
+		return FullParticipation
+	}
+	return m.Participation
+}
+
+// IsPartTime checks if the member takes less than a full share of the turns or only works on some
+// weekdays
+func (m *TeamMember) IsPartTime() bool {
+	return m.GetParticipation() < FullParticipation || len(m.WorkDays) > 0
+}
+
+// WorksOn checks if the member can be on duty on the weekday of the date: it is one of their work
+// days, not a day they never want to be on duty, and one of their preferred days if those are hard
+func (m *TeamMember) WorksOn(date time.Time) bool {
+	if len(m.WorkDays) > 0 && !slices.Contains(m.WorkDays, GetWeekdayNumber(date)) {
+		return false
+	}
+
+	preference := m.PreferenceOn(date)
+	if preference != nil && preference.Kind == PreferenceKindAvoid && preference.IsHard() {
+		return false
+	}
+	if m.hasHardPreferredDays() {
+		return preference != nil && preference.Kind == PreferenceKindPrefer && preference.IsHard()
+	}
+	return true
+}
+
+// WantsNotification checks if the member wants to be notified about an event
+func (m *TeamMember) WantsNotification(event string) bool {
+	return !slices.Contains(m.MutedNotifications, event)
+}
+
+// GetWorkDayNames returns the short names of the weekdays the member works, like "Mon, Tue, Wed"
+func (m *TeamMember) GetWorkDayNames() string {
+	if len(m.WorkDays) == 0 {
+		return "Every day"
+	}
+
+	names := make([]string, 0, len(m.WorkDays))
+	for _, day := range m.WorkDays {
+		names = append(names, DayNames[day][:3])
+	}
+	return strings.Join(names, ", ")
 }
 
 // TeamMemberForm represents form data for creating/updating team members
 type TeamMemberForm struct {
 	Name        string `json:"name"`
 	SlackHandle string `json:"slack_handle"`
+	Email       string `json:"email"`
 	Active      bool   `json:"active"`
+	// Participation in percent, 100 or 0 for a full-time member
+	Participation int                 `json:"participation"`
+	WorkDays      []int               `json:"work_days"`   // Weekdays the member works (0=Monday), none or all for every day
+	Preferences   []WeekdayPreference `json:"preferences"` // At most one per weekday
+	// MutedNotifications are the notification events the member turned off
+	MutedNotifications []string `json:"muted_notifications"`
 }
 
 // Validate validates the team member form data
@@ -42,9 +111,66 @@ func (f *TeamMemberForm) Validate() []string {
 		errors = append(errors, "Slack handle format is invalid (should start with @)")
 	}
 
+	email := strings.TrimSpace(f.Email)
+	if len(email) > 255 {
+		errors = append(errors, "Email must be less than 255 characters")
+	} else if email != "" && !isValidEmail(email) {
+		errors = append(errors, "Email address is invalid")
+	}
+
+	for _, event := range f.MutedNotifications {
+		if _, ok := NotificationEventNames[event]; !ok {
+			errors = append(errors, "Unknown notification event")
+			break
+		}
+	}
+
+	if f.Participation < 0 || f.Participation > FullParticipation {
+		errors = append(errors, "Participation must be between 1 and 100 percent")
+	}
+
+	for _, day := range f.WorkDays {
+		if day < 0 || day > 6 {
+			errors = append(errors, "Work days must be between 0 (Monday) and 6 (Sunday)")
+			break
+		}
+	}
+
+	errors = append(errors, validateWeekdayPreferences(f.Preferences)...)
+
 	return errors
 }
 
+// GetParticipation returns the participation in percent, full participation when not set
+func (f *TeamMemberForm) GetParticipation() int {
+	if f.Participation <= 0 {
+		return FullParticipation
+	}
+	return f.Participation
+}
+
+// WantsNotification checks if the member wants to be notified about an event
+func (f *TeamMemberForm) WantsNotification(event string) bool {
+	return !slices.Contains(f.MutedNotifications, event)
+}
+
+// HasWorkDay checks if a weekday is selected as a work day
+func (f *TeamMemberForm) HasWorkDay(day int) bool {
+	return slices.Contains(f.WorkDays, day)
+}
+
+// GetWorkDays returns the selected work days sorted and without duplicates. Selecting every day is
+// the same as selecting none, so it returns nil for both.
+func (f *TeamMemberForm) GetWorkDays() []int {
+	days := slices.Clone(f.WorkDays)
+	slices.Sort(days)
+	days = slices.Compact(days)
+	if len(days) == 0 || len(days) == len(DayNames) {
+		return nil
+	}
+	return days
+}
+
 // isValidSlackHandle performs basic slack handle validation
 func isValidSlackHandle(handle string) bool {
 	// Simple validation: must start with @ and be at least 2 characters
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// TimeOff represents a period during which a team member isn't available for duty
+type TimeOff struct {
+	ID           int       `json:"id" db:"id"`
+	TeamMemberID int       `json:"team_member_id" db:"team_member_id"`
+	StartDate    time.Time `json:"start_date" db:"start_date"`
+	EndDate      time.Time `json:"end_date" db:"end_date"` // Inclusive
+	Reason       string    `json:"reason" db:"reason"`
+
+	// Joined fields (populated from joins with team_members table)
+	TeamMemberName string `json:"team_member_name,omitempty" db:"team_member_name"`
+
+	AuditFields // Embedded audit fields
+}
+
+// Covers checks if the time off includes the given date
+func (t *TimeOff) Covers(date time.Time) bool {
+	day := FormatDate(date)
+	return FormatDate(t.StartDate) <= day && day <= FormatDate(t.EndDate)
+}
+
+// GetFormattedStartDate returns the start date formatted as YYYY-MM-DD
+func (t *TimeOff) GetFormattedStartDate() string {
+	return FormatDate(t.StartDate)
+}
+
+// GetFormattedEndDate returns the end date formatted as YYYY-MM-DD
+func (t *TimeOff) GetFormattedEndDate() string {
+	return FormatDate(t.EndDate)
+}
+
+// Days returns the number of calendar days the time off spans
+func (t *TimeOff) Days() int {
+	return int(t.EndDate.Sub(t.StartDate).Hours()/24) + 1
+}
+
+// TimeOffForm represents form data for registering time off
+type TimeOffForm struct {
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason"`
+}
+
+// Validate validates the time off form data
+func (f *TimeOffForm) Validate() []string {
+	var errors []string
+
+	startDate, startErr := ParseDate(strings.TrimSpace(f.StartDate))
+	if f.StartDate == "" {
+		errors = append(errors, "Start date is required")
+	} else if startErr != nil {
+		errors = append(errors, "Start date must be in YYYY-MM-DD format")
+	}
+
+	endDate, endErr := ParseDate(strings.TrimSpace(f.EndDate))
+	if f.EndDate == "" {
+		errors = append(errors, "End date is required")
+	} else if endErr != nil {
+		errors = append(errors, "End date must be in YYYY-MM-DD format")
+	}
+
+	if startErr == nil && endErr == nil && endDate.Before(startDate) {
+		errors = append(errors, "End date can't be before the start date")
+	}
+
+	if len(f.Reason) > 255 {
+		errors = append(errors, "Reason must be less than 255 characters")
+	}
+
+	return errors
+}
@@ -0,0 +1,187 @@
+package models
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Webhook events
+const (
+	WebhookEventEntryCreated        = "entry.created"        // A schedule entry was added outside of a generation
+	WebhookEventEntryUpdated        = "entry.updated"        // A schedule entry was edited
+	WebhookEventEntryDeleted        = "entry.deleted"        // A schedule entry was removed outside of a generation
+	WebhookEventOverrideCreated     = "override.created"     // A member took over a shift
+	WebhookEventOverrideRemoved     = "override.removed"     // A takeover was removed and the original assignment is back
+	WebhookEventGenerationCompleted = "generation.completed" // The schedule was generated or extended
+	WebhookEventMemberActivated     = "member.activated"     // A member joined the rotation
+	WebhookEventMemberDeactivated   = "member.deactivated"   // A member left the rotation
+)
+
+// WebhookEvents lists the webhook events in display order
+var WebhookEvents = []string{
+	WebhookEventEntryCreated,
+	WebhookEventEntryUpdated,
+	WebhookEventEntryDeleted,
+	WebhookEventOverrideCreated,
+	WebhookEventOverrideRemoved,
+	WebhookEventGenerationCompleted,
+	WebhookEventMemberActivated,
+	WebhookEventMemberDeactivated,
+}
+
+// WebhookEventNames maps the webhook events to their display names
+var WebhookEventNames = map[string]string{
+	WebhookEventEntryCreated:        "Entry created",
+	WebhookEventEntryUpdated:        "Entry updated",
+	WebhookEventEntryDeleted:        "Entry deleted",
+	WebhookEventOverrideCreated:     "Override created",
+	WebhookEventOverrideRemoved:     "Override removed",
+	WebhookEventGenerationCompleted: "Generation completed",
+	WebhookEventMemberActivated:     "Member activated",
+	WebhookEventMemberDeactivated:   "Member deactivated",
+}
+
+// MinWebhookSecretLength is the length a webhook secret needs at least
+const MinWebhookSecretLength = 16
+
+// WebhookSubscription is a URL the events of a team are posted to
+type WebhookSubscription struct {
+	ID     int      `json:"id" db:"id"`
+	Name   string   `json:"name" db:"name"`
+	URL    string   `json:"url" db:"url"`
+	Secret string   `json:"-" db:"secret"`      // Key the deliveries are signed with
+	Events []string `json:"events" db:"events"` // Webhook events the subscription gets
+	Active bool     `json:"active" db:"active"`
+	AuditFields
+}
+
+// Subscribes checks if the subscription gets an event
+func (s *WebhookSubscription) Subscribes(event string) bool {
+	return slices.Contains(s.Events, event)
+}
+
+// GetEventNames returns the display names of the events the subscription gets
+func (s *WebhookSubscription) GetEventNames() string {
+	var names []string
+	for _, event := range WebhookEvents {
+		if s.Subscribes(event) {
+			names = append(names, WebhookEventNames[event])
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// WebhookSubscriptionForm represents form data for creating and updating webhook subscriptions
+type WebhookSubscriptionForm struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"` // Empty to generate one, or to keep the current one
+	Events []string `json:"events"`
+	Active bool     `json:"active"`
+}
+
+// Validate validates the webhook subscription form data
+func (f *WebhookSubscriptionForm) Validate() []string {
+	var errors []string
+
+	name := strings.TrimSpace(f.Name)
+	if name == "" {
+		errors = append(errors, "Name is required")
+	}
+	if len(name) > 100 {
+		errors = append(errors, "Name must be less than 100 characters")
+	}
+
+	if !isValidWebhookURL(strings.TrimSpace(f.URL)) {
+		errors = append(errors, "URL must be an http or https URL")
+	}
+
+	secret := strings.TrimSpace(f.Secret)
+	if secret != "" && len(secret) < MinWebhookSecretLength {
+		errors = append(errors, "Secret must be at least 16 characters")
+	}
+	if len(secret) > 255 {
+		errors = append(errors, "Secret must be less than 255 characters")
+	}
+
+	if len(f.Events) == 0 {
+		errors = append(errors, "Choose at least one event")
+	}
+	for _, event := range f.Events {
+		if _, ok := WebhookEventNames[event]; !ok {
+			errors = append(errors, "Unknown webhook event")
+			break
+		}
+	}
+
+	return errors
+}
+
+// HasEvent checks if an event is selected
+func (f *WebhookSubscriptionForm) HasEvent(event string) bool {
+	return slices.Contains(f.Events, event)
+}
+
+// GetEvents returns the selected events in display order and without duplicates
+func (f *WebhookSubscriptionForm) GetEvents() []string {
+	var events []string
+	for _, event := range WebhookEvents {
+		if f.HasEvent(event) {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"   // Waiting for its first or next attempt
+	WebhookDeliveryDelivered = "delivered" // Accepted by the webhook
+	WebhookDeliveryFailed    = "failed"    // Gave up after the last attempt
+)
+
+// WebhookDeliveryStatusNames maps the webhook delivery statuses to their display names
+var WebhookDeliveryStatusNames = map[string]string{
+	WebhookDeliveryPending:   "Pending",
+	WebhookDeliveryDelivered: "Delivered",
+	WebhookDeliveryFailed:    "Failed",
+}
+
+// WebhookDelivery is an event queued for a webhook subscription, and the log of posting it
+type WebhookDelivery struct {
+	ID               int        `json:"id" db:"id"`
+	SubscriptionID   int        `json:"subscription_id" db:"subscription_id"`
+	SubscriptionName string     `json:"subscription_name" db:"subscription_name"` // Populated from JOIN
+	EventID          string     `json:"event_id" db:"event_id"`                   // The same for every delivery of one event
+	Event            string     `json:"event" db:"event"`
+	Payload          string     `json:"payload" db:"payload"` // JSON body that is posted
+	Status           string     `json:"status" db:"status"`
+	Attempts         int        `json:"attempts" db:"attempts"`
+	NextAttemptAt    time.Time  `json:"next_attempt_at" db:"next_attempt_at"`
+	LastAttemptAt    *time.Time `json:"last_attempt_at,omitempty" db:"last_attempt_at"`
+	ResponseStatus   int        `json:"response_status" db:"response_status"` // HTTP status of the last attempt, 0 without a response
+	LastError        string     `json:"last_error" db:"last_error"`
+	CreatedAt        time.Time  `json:"created_at" db:"created_at"`
+	DeliveredAt      *time.Time `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+// GetStatusName returns the display name of the delivery status
+func (d *WebhookDelivery) GetStatusName() string {
+	return WebhookDeliveryStatusNames[d.Status]
+}
+
+// GetEventName returns the display name of the delivered event
+func (d *WebhookDelivery) GetEventName() string {
+	return WebhookEventNames[d.Event]
+}
+
+// IsPending checks if the delivery is still waiting for an attempt
+func (d *WebhookDelivery) IsPending() bool {
+	return d.Status == WebhookDeliveryPending
+}
+
+// IsFailed checks if the delivery gave up after its last attempt
+func (d *WebhookDelivery) IsFailed() bool {
+	return d.Status == WebhookDeliveryFailed
+}
@@ -0,0 +1,180 @@
+package models
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Kinds of weekday preferences
+const (
+	PreferenceKindPrefer = "prefer"
+	PreferenceKindAvoid  = "avoid"
+)
+
+// Strengths of weekday preferences. The generator always satisfies hard preferences and tries to
+// honour soft ones without giving anyone more or fewer turns.
+const (
+	PreferenceStrengthSoft = "soft"
+	PreferenceStrengthHard = "hard"
+)
+
+// WeekdayPreference is a member's wish to be on duty, or not, on a day of the week. A hard
+// preference for some days means the member is only on duty on those days, a hard avoided day
+// means never on that day.
+type WeekdayPreference struct {
+	DayOfWeek int    `json:"day_of_week"` // 0=Monday, 6=Sunday
+	Kind      string `json:"kind"`        // "prefer" or "avoid"
+	Strength  string `json:"strength"`    // "soft" or "hard"
+}
+
+// WeekdayPreferenceOption is a choice for the preference of a single weekday in the member forms
+type WeekdayPreferenceOption struct {
+	Value string
+	Label string
+}
+
+// WeekdayPreferenceOptions lists the preferences a member can have for a weekday, in form order
+var WeekdayPreferenceOptions = []WeekdayPreferenceOption{
+	{Value: "", Label: "No preference"},
+	{Value: PreferenceKindPrefer + ":" + PreferenceStrengthSoft, Label: "Prefers"},
+	{Value: PreferenceKindPrefer + ":" + PreferenceStrengthHard, Label: "Only on"},
+	{Value: PreferenceKindAvoid + ":" + PreferenceStrengthSoft, Label: "Rather not"},
+	{Value: PreferenceKindAvoid + ":" + PreferenceStrengthHard, Label: "Never"},
+}
+
+// ParseWeekdayPreference reads the preference for a weekday from a form value like "avoid:hard".
+// It returns nil for no preference.
+func ParseWeekdayPreference(day int, value string) *WeekdayPreference {
+	if value == "" {
+		return nil
+	}
+	kind, strength, _ := strings.Cut(value, ":")
+	return &WeekdayPreference{DayOfWeek: day, Kind: kind, Strength: strength}
+}
+
+// Value returns the form value of the preference
+func (p *WeekdayPreference) Value() string {
+	return p.Kind + ":" + p.Strength
+}
+
+// IsHard checks if the preference must always be satisfied
+func (p *WeekdayPreference) IsHard() bool {
+	return p.Strength == PreferenceStrengthHard
+}
+
+// String describes the preference, like "avoids Monday" or "only on Friday"
+func (p *WeekdayPreference) String() string {
+	day := DayNames[p.DayOfWeek]
+	switch {
+	case p.Kind == PreferenceKindAvoid && p.IsHard():
+		return "never on " + day
+	case p.Kind == PreferenceKindAvoid:
+		return "rather not on " + day
+	case p.IsHard():
+		return "only on preferred days like " + day
+	default:
+		return "prefers " + day
+	}
+}
+
+// validateWeekdayPreferences checks the preferences of a member form
+func validateWeekdayPreferences(preferences []WeekdayPreference) []string {
+	var errors []string
+
+	seen := make(map[int]bool)
+	for _, preference := range preferences {
+		if preference.DayOfWeek < 0 || preference.DayOfWeek > 6 {
+			errors = append(errors, "Preference days must be between 0 (Monday) and 6 (Sunday)")
+			continue
+		}
+		if seen[preference.DayOfWeek] {
+			errors = append(errors, fmt.Sprintf("Only one preference per weekday is allowed (%s)", DayNames[preference.DayOfWeek]))
+		}
+		seen[preference.DayOfWeek] = true
+
+		if preference.Kind != PreferenceKindPrefer && preference.Kind != PreferenceKindAvoid {
+			errors = append(errors, fmt.Sprintf("Invalid preference for %s", DayNames[preference.DayOfWeek]))
+			continue
+		}
+		if preference.Strength != PreferenceStrengthSoft && preference.Strength != PreferenceStrengthHard {
+			errors = append(errors, fmt.Sprintf("Invalid preference strength for %s", DayNames[preference.DayOfWeek]))
+		}
+	}
+
+	return errors
+}
+
+// PreferenceOn returns the member's preference for the weekday of the date, or nil if they have none
+func (m *TeamMember) PreferenceOn(date time.Time) *WeekdayPreference {
+	day := GetWeekdayNumber(date)
+	for i := range m.Preferences {
+		if m.Preferences[i].DayOfWeek == day {
+			return &m.Preferences[i]
+		}
+	}
+	return nil
+}
+
+// Avoids checks if the member would rather not be on duty on the weekday of the date. Hard avoided
+// days are left out by WorksOn already.
+func (m *TeamMember) Avoids(date time.Time) bool {
+	preference := m.PreferenceOn(date)
+	return preference != nil && preference.Kind == PreferenceKindAvoid && !preference.IsHard()
+}
+
+// Prefers checks if the member prefers to be on duty on the weekday of the date
+func (m *TeamMember) Prefers(date time.Time) bool {
+	preference := m.PreferenceOn(date)
+	return preference != nil && preference.Kind == PreferenceKindPrefer
+}
+
+// hasHardPreferredDays checks if the member is only on duty on their preferred days
+func (m *TeamMember) hasHardPreferredDays() bool {
+	for _, preference := range m.Preferences {
+		if preference.Kind == PreferenceKindPrefer && preference.IsHard() {
+			return true
+		}
+	}
+	return false
+}
+
+// GetPreferenceSummary describes the preferences of the member, like "Prefers Fri; Never Mon"
+func (m *TeamMember) GetPreferenceSummary() string {
+	var parts []string
+	for _, option := range WeekdayPreferenceOptions[1:] {
+		var days []string
+		for _, preference := range m.Preferences {
+			if preference.Value() == option.Value {
+				days = append(days, DayNames[preference.DayOfWeek][:3])
+			}
+		}
+		if len(days) > 0 {
+			parts = append(parts, option.Label+" "+strings.Join(days, ", "))
+		}
+	}
+	return strings.Join(parts, "; ")
+}
+
+// GetPreferenceValue returns the form value of the preference for a weekday, empty for none
+func (f *TeamMemberForm) GetPreferenceValue(day int) string {
+	for _, preference := range f.Preferences {
+		if preference.DayOfWeek == day {
+			return preference.Value()
+		}
+	}
+	return ""
+}
+
+// GetPreferences returns the preferences ordered by weekday, nil when there are none
+func (f *TeamMemberForm) GetPreferences() []WeekdayPreference {
+	if len(f.Preferences) == 0 {
+		return nil
+	}
+	preferences := slices.Clone(f.Preferences)
+	slices.SortFunc(preferences, func(a, b WeekdayPreference) int {
+		return a.DayOfWeek - b.DayOfWeek
+	})
+	return preferences
+}
@@ -1,18 +1,38 @@
 package models
 
-// WorkingHours represents working hours configuration for a day of the week
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// WorkingHours represents a shift on a day of the week. A day can have several shifts,
+// shifts with the same name on different days share a rotation.
+//
+// The shifts of a day change from an effective date on: the shifts of a day with the same effective
+// date form a version, which applies until the next version of that day. A version without active
+// shifts keeps a single inactive row, so the day is off from its effective date on.
 type WorkingHours struct {
-	ID          int    `json:"id" db:"id"`
-	DayOfWeek   int    `json:"day_of_week" db:"day_of_week"` // 0=Monday, 6=Sunday
-	StartTime   string `json:"start_time" db:"start_time"`   // "09:00" format
-	EndTime     string `json:"end_time" db:"end_time"`       // "17:00" format
-	Active      bool   `json:"active" db:"active"`
-	AuditFields        // Embedded audit fields
+	ID            int       `json:"id" db:"id"`
+	DayOfWeek     int       `json:"day_of_week" db:"day_of_week"` // 0=Monday, 6=Sunday
+	Name          string    `json:"name" db:"name"`               // Shift name, empty for a day with a single unnamed shift
+	StartTime     string    `json:"start_time" db:"start_time"`   // "09:00" format
+	EndTime       string    `json:"end_time" db:"end_time"`       // "17:00" format, before the start time for overnight shifts
+	Active        bool      `json:"active" db:"active"`
+	EffectiveFrom time.Time `json:"effective_from" db:"effective_from"` // First date of the version, zero for the initial configuration
+	AuditFields             // Embedded audit fields
+}
+
+// WorkingHoursChange groups the versions of the days that take effect on the same date
+type WorkingHoursChange struct {
+	EffectiveFrom time.Time
+	Shifts        []WorkingHours // The shifts of the changed days, inactive for days that are off from then on
 }
 
-// WorkingHoursForm represents form data for updating working hours
+// WorkingHoursForm represents form data for a single shift of a working day
 type WorkingHoursForm struct {
 	DayOfWeek int    `json:"day_of_week"`
+	Name      string `json:"name"`
 	StartTime string `json:"start_time"`
 	EndTime   string `json:"end_time"`
 	Active    bool   `json:"active"`
@@ -37,6 +57,103 @@ func (w *WorkingHours) GetDayName() string {
 	return "Unknown"
 }
 
+// GetShiftName returns the shift name, or a generic name for the unnamed shift
+func (w *WorkingHours) GetShiftName() string {
+	if w.Name == "" {
+		return "EOD"
+	}
+	return w.Name
+}
+
+// EndsNextDay checks if the shift crosses midnight and ends on the following day
+func (w *WorkingHours) EndsNextDay() bool {
+	return EndsNextDay(w.StartTime, w.EndTime)
+}
+
+// DayVersionOn returns the shifts of a day of the week in the version in effect on the date,
+// including the inactive row of a day off. Nil means the day has no version yet.
+func DayVersionOn(hours []WorkingHours, dayOfWeek int, date time.Time) []WorkingHours {
+	on := dayOf(date)
+	var version []WorkingHours
+	var latest calendarDay
+	for _, workingHours := range hours {
+		if workingHours.DayOfWeek != dayOfWeek {
+			continue
+		}
+		from := dayOf(workingHours.EffectiveFrom)
+		switch {
+		case on.before(from):
+			continue
+		case version == nil || latest.before(from):
+			version, latest = []WorkingHours{workingHours}, from
+		case from == latest:
+			version = append(version, workingHours)
+		}
+	}
+	return version
+}
+
+// ShiftsOn returns the active shifts on a date, from the version of its day in effect on the date
+func ShiftsOn(hours []WorkingHours, date time.Time) []WorkingHours {
+	var shifts []WorkingHours
+	for _, workingHours := range DayVersionOn(hours, GetWeekdayNumber(date), date) {
+		if workingHours.Active {
+			shifts = append(shifts, workingHours)
+		}
+	}
+	return shifts
+}
+
+// ShiftsInEffect returns the active shifts of every day of the week in the versions in effect on
+// the date, Monday first
+func ShiftsInEffect(hours []WorkingHours, date time.Time) []WorkingHours {
+	var shifts []WorkingHours
+	for day := 0; day < 7; day++ {
+		for _, workingHours := range DayVersionOn(hours, day, date) {
+			if workingHours.Active {
+				shifts = append(shifts, workingHours)
+			}
+		}
+	}
+	return shifts
+}
+
+// UpcomingChanges returns the versions taking effect after the date, grouped by effective date in
+// chronological order
+func UpcomingChanges(hours []WorkingHours, date time.Time) []WorkingHoursChange {
+	after := dayOf(date)
+	byDate := make(map[string]*WorkingHoursChange)
+	var changes []*WorkingHoursChange
+	for _, workingHours := range hours {
+		if workingHours.EffectiveFrom.IsZero() || !after.before(dayOf(workingHours.EffectiveFrom)) {
+			continue
+		}
+		key := FormatDate(workingHours.EffectiveFrom)
+		change, ok := byDate[key]
+		if !ok {
+			change = &WorkingHoursChange{EffectiveFrom: workingHours.EffectiveFrom}
+			byDate[key] = change
+			changes = append(changes, change)
+		}
+		change.Shifts = append(change.Shifts, workingHours)
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].EffectiveFrom.Before(changes[j].EffectiveFrom)
+	})
+	result := make([]WorkingHoursChange, len(changes))
+	for i, change := range changes {
+		sort.SliceStable(change.Shifts, func(a, b int) bool {
+			if change.Shifts[a].DayOfWeek != change.Shifts[b].DayOfWeek {
+				return change.Shifts[a].DayOfWeek < change.Shifts[b].DayOfWeek
+			}
+			return change.Shifts[a].StartTime < change.Shifts[b].StartTime
+		})
+		result[i] = *change
+	}
+	return result
+}
+
 // Validate validates the working hours form data
 func (f *WorkingHoursForm) Validate() []string {
 	var errors []string
@@ -46,6 +163,10 @@ func (f *WorkingHoursForm) Validate() []string {
 		errors = append(errors, "Day of week must be between 0 (Monday) and 6 (Sunday)")
 	}
 
+	if len(strings.TrimSpace(f.Name)) > 50 {
+		errors = append(errors, "Shift name must be less than 50 characters")
+	}
+
 	// Only validate times if the day is active
 	if f.Active {
 		if !isValidTimeFormat(f.StartTime) {
@@ -56,10 +177,10 @@ func (f *WorkingHoursForm) Validate() []string {
 			errors = append(errors, "End time must be in HH:MM format (e.g., 17:00)")
 		}
 
-		// Check that start time is before end time
+		// An end time before the start time means the shift ends on the following day
 		if isValidTimeFormat(f.StartTime) && isValidTimeFormat(f.EndTime) {
-			if !isStartBeforeEnd(f.StartTime, f.EndTime) {
-				errors = append(errors, "Start time must be before end time")
+			if f.StartTime == f.EndTime {
+				errors = append(errors, "Start and end time must differ")
 			}
 		}
 	}
@@ -67,6 +188,38 @@ func (f *WorkingHoursForm) Validate() []string {
 	return errors
 }
 
+// ValidateShifts validates the shifts of a single day. Every shift is validated on its own, and
+// when a day has more than one active shift each of them needs a unique name.
+func ValidateShifts(forms []*WorkingHoursForm) []string {
+	var errors []string
+	var active []*WorkingHoursForm
+
+	for _, form := range forms {
+		errors = append(errors, form.Validate()...)
+		if form.Active {
+			active = append(active, form)
+		}
+	}
+
+	if len(active) > 1 {
+		names := make(map[string]bool)
+		for _, form := range active {
+			name := strings.ToLower(strings.TrimSpace(form.Name))
+			if name == "" {
+				errors = append(errors, "Every shift needs a name when a day has several shifts")
+				break
+			}
+			if names[name] {
+				errors = append(errors, "Shift names must be unique within a day")
+				break
+			}
+			names[name] = true
+		}
+	}
+
+	return errors
+}
+
 // isValidTimeFormat validates HH:MM format
 func isValidTimeFormat(timeStr string) bool {
 	if len(timeStr) != 5 {
@@ -100,11 +253,22 @@ func isValidTimeFormat(timeStr string) bool {
 	return true
 }
 
-// isStartBeforeEnd checks if start time is before end time
-func isStartBeforeEnd(start, end string) bool {
-	startMinutes := timeToMinutes(start)
-	endMinutes := timeToMinutes(end)
-	return startMinutes < endMinutes
+// EndsNextDay checks if a shift from start to end crosses midnight, like an evening duty from
+// 18:00 to 02:00. A shift ending at 00:00 ends at midnight of the following day.
+func EndsNextDay(start, end string) bool {
+	if !isValidTimeFormat(start) || !isValidTimeFormat(end) {
+		return false
+	}
+	return timeToMinutes(end) <= timeToMinutes(start)
+}
+
+// shiftDuration returns the length of a shift from start to end, which may end on the following day
+func shiftDuration(start, end string) time.Duration {
+	minutes := timeToMinutes(end) - timeToMinutes(start)
+	if minutes <= 0 {
+		minutes += 24 * 60
+	}
+	return time.Duration(minutes) * time.Minute
 }
 
 // timeToMinutes converts HH:MM to total minutes
@@ -0,0 +1,133 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// WorkingHoursException overrides the working hours of a team for a single date, like a day that
+// ends early or an extra release day on a Saturday
+type WorkingHoursException struct {
+	ID        int       `json:"id" db:"id"`
+	Date      time.Time `json:"date" db:"date"`
+	Kind      string    `json:"kind" db:"kind"`             // How the date differs from the working hours
+	Name      string    `json:"name" db:"name"`             // Shift of an extra working day, empty for the unnamed shift
+	StartTime string    `json:"start_time" db:"start_time"` // "09:00" format, unused for non-working days
+	EndTime   string    `json:"end_time" db:"end_time"`     // "17:00" format, unused for non-working days
+	Reason    string    `json:"reason" db:"reason"`
+	AuditFields
+}
+
+// Working hours exception kinds
+const (
+	ExceptionKindHours      = "hours"       // The shifts of the date get different hours
+	ExceptionKindNonWorking = "non_working" // Nobody is on duty, the rotation continues on the next working day
+	ExceptionKindWorking    = "working"     // The date is a working day with a single shift
+)
+
+// ExceptionKindNames maps the exception kinds to their display names
+var ExceptionKindNames = map[string]string{
+	ExceptionKindHours:      "Different hours",
+	ExceptionKindNonWorking: "Non-working day",
+	ExceptionKindWorking:    "Extra working day",
+}
+
+// GetFormattedDate returns the date formatted as YYYY-MM-DD
+func (e *WorkingHoursException) GetFormattedDate() string {
+	return FormatDate(e.Date)
+}
+
+// GetKindName returns the display name of the exception kind
+func (e *WorkingHoursException) GetKindName() string {
+	return ExceptionKindNames[e.Kind]
+}
+
+// GetShiftName returns the shift name of an extra working day, or a generic name for the unnamed shift
+func (e *WorkingHoursException) GetShiftName() string {
+	if e.Name == "" {
+		return "EOD"
+	}
+	return e.Name
+}
+
+// EndsNextDay checks if the hours of the exception cross midnight and end on the following day
+func (e *WorkingHoursException) EndsNextDay() bool {
+	return EndsNextDay(e.StartTime, e.EndTime)
+}
+
+// Apply returns the shifts of the date with the exception applied to the shifts of its working hours
+func (e *WorkingHoursException) Apply(shifts []WorkingHours) []WorkingHours {
+	switch e.Kind {
+	case ExceptionKindNonWorking:
+		return nil
+	case ExceptionKindWorking:
+		return []WorkingHours{{
+			DayOfWeek: GetWeekdayNumber(e.Date),
+			Name:      e.Name,
+			StartTime: e.StartTime,
+			EndTime:   e.EndTime,
+			Active:    true,
+		}}
+	default:
+		return withHours(shifts, e.StartTime, e.EndTime)
+	}
+}
+
+// withHours returns copies of the shifts with the given hours. With several shifts on a day, each
+// of them gets the hours.
+func withHours(shifts []WorkingHours, startTime, endTime string) []WorkingHours {
+	var changed []WorkingHours
+	for _, shift := range shifts {
+		shift.StartTime = startTime
+		shift.EndTime = endTime
+		changed = append(changed, shift)
+	}
+	return changed
+}
+
+// WorkingHoursExceptionForm represents form data for creating a working hours exception
+type WorkingHoursExceptionForm struct {
+	Date      string `json:"date"`
+	Kind      string `json:"kind"`
+	Name      string `json:"name"`
+	StartTime string `json:"start_time"`
+	EndTime   string `json:"end_time"`
+	Reason    string `json:"reason"`
+}
+
+// Validate validates the working hours exception form data
+func (f *WorkingHoursExceptionForm) Validate() []string {
+	var errors []string
+
+	if f.Date == "" {
+		errors = append(errors, "Date is required")
+	} else if _, err := ParseDate(strings.TrimSpace(f.Date)); err != nil {
+		errors = append(errors, "Date must be in YYYY-MM-DD format")
+	}
+
+	if _, ok := ExceptionKindNames[f.Kind]; !ok {
+		errors = append(errors, "Unknown exception kind")
+	}
+
+	if len(strings.TrimSpace(f.Name)) > 50 {
+		errors = append(errors, "Shift name must be less than 50 characters")
+	}
+	if len(strings.TrimSpace(f.Reason)) > 100 {
+		errors = append(errors, "Reason must be less than 100 characters")
+	}
+
+	// The hours use the same validation as working hours
+	if f.Kind == ExceptionKindHours || f.Kind == ExceptionKindWorking {
+		if !isValidTimeFormat(f.StartTime) {
+			errors = append(errors, "Start time must be in HH:MM format (e.g., 09:00)")
+		}
+		if !isValidTimeFormat(f.EndTime) {
+			errors = append(errors, "End time must be in HH:MM format (e.g., 17:00)")
+		}
+		if isValidTimeFormat(f.StartTime) && isValidTimeFormat(f.EndTime) && f.StartTime == f.EndTime {
+			errors = append(errors, "Start and end time must differ")
+		}
+	}
+
+	return errors
+}
@@ -0,0 +1,112 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+)
+
+// Dispatcher sends the notifications of schedule events to the channels of the team in the context
+type Dispatcher interface {
+	// Dispatch sends a notification in the background, so a slow or failing channel never holds up
+	// or fails the change it is about. Failures are logged.
+	Dispatch(ctx context.Context, n *Notification)
+}
+
+// dispatcher implements Dispatcher interface
+type dispatcher struct {
+	channelRepo repositories.NotificationChannelRepository
+	teamRepo    repositories.TeamRepository
+	smtpConfig  *SMTPConfig
+}
+
+// NewDispatcher creates a new dispatcher. Email channels fail without a mail server.
+func NewDispatcher(channelRepo repositories.NotificationChannelRepository, teamRepo repositories.TeamRepository, smtpConfig *SMTPConfig) Dispatcher {
+	return &dispatcher{
+		channelRepo: channelRepo,
+		teamRepo:    teamRepo,
+		smtpConfig:  smtpConfig,
+	}
+}
+
+// Dispatch sends a notification in the background
+func (d *dispatcher) Dispatch(ctx context.Context, n *Notification) {
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		if err := d.deliver(ctx, n); err != nil {
+			log.Printf("Notification %s failed: %v", n.Event, err)
+		}
+	}()
+}
+
+// deliver sends a notification to every active channel that gets its event. A notification goes
+// out to the members concerned who want to be notified about the event, and isn't sent at all when
+// none of them do. A failing channel doesn't stop the others.
+func (d *dispatcher) deliver(ctx context.Context, n *Notification) error {
+	channels, err := d.channelRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification channels: %w", err)
+	}
+
+	var subscribed []models.NotificationChannel
+	for _, channel := range channels {
+		if channel.Active && channel.Subscribes(n.Event) {
+			subscribed = append(subscribed, channel)
+		}
+	}
+	if len(subscribed) == 0 {
+		return nil
+	}
+
+	recipients, err := d.recipients(ctx, n)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	var errs []error
+	for i := range subscribed {
+		channel := &subscribed[i]
+		notifier, err := NewNotifier(channel, d.smtpConfig)
+		if err == nil {
+			err = notifier.Notify(ctx, n, recipients)
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("channel %s: %w", channel.Name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// recipients returns the members the notification is about who want to be notified about its event
+func (d *dispatcher) recipients(ctx context.Context, n *Notification) ([]models.TeamMember, error) {
+	var members []models.TeamMember
+	if n.IsPersonal() {
+		for _, id := range n.MemberIDs {
+			member, err := d.teamRepo.GetByID(ctx, id)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get team member: %w", err)
+			}
+			members = append(members, *member)
+		}
+	} else {
+		var err error
+		if members, err = d.teamRepo.GetActiveMembers(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get active team members: %w", err)
+		}
+	}
+
+	var recipients []models.TeamMember
+	for _, member := range members {
+		if member.WantsNotification(n.Event) {
+			recipients = append(recipients, member)
+		}
+	}
+	return recipients, nil
+}
@@ -0,0 +1,116 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+func TestDispatcherDeliver(t *testing.T) {
+	ctx := context.Background()
+	regenerated := &Notification{Event: models.NotificationRegenerated, Subject: "Schedule regenerated", Text: "Check your shifts."}
+	alice := models.TeamMember{ID: 1, Name: "Alice", SlackHandle: "alice"}
+	muted := models.TeamMember{ID: 2, Name: "Bob", SlackHandle: "bob", MutedNotifications: []string{models.NotificationTakeover}}
+
+	testCases := []struct {
+		name          string
+		notification  *Notification
+		channels      func(url string) []models.NotificationChannel
+		expectMembers func(mockTeamRepo *dbMocks.MockTeamRepository)
+		expectedPosts int
+		expectedError string
+	}{
+		{
+			name:         "active subscribed channels only",
+			notification: regenerated,
+			channels: func(url string) []models.NotificationChannel {
+				return []models.NotificationChannel{
+					{Name: "General", Kind: models.ChannelKindSlack, Target: url, Events: []string{models.NotificationRegenerated}, Active: true},
+					{Name: "Paused", Kind: models.ChannelKindSlack, Target: url, Events: []string{models.NotificationRegenerated}},
+					{Name: "Takeovers", Kind: models.ChannelKindSlack, Target: url, Events: []string{models.NotificationTakeover}, Active: true},
+				}
+			},
+			expectMembers: func(mockTeamRepo *dbMocks.MockTeamRepository) {
+				mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return([]models.TeamMember{alice, muted}, nil)
+			},
+			expectedPosts: 1,
+		},
+		{
+			name:         "no subscribed channel",
+			notification: takeover,
+			channels: func(url string) []models.NotificationChannel {
+				return []models.NotificationChannel{
+					{Name: "General", Kind: models.ChannelKindSlack, Target: url, Events: []string{models.NotificationRegenerated}, Active: true},
+				}
+			},
+		},
+		{
+			name:         "muted by the member concerned",
+			notification: takeover,
+			channels: func(url string) []models.NotificationChannel {
+				return []models.NotificationChannel{
+					{Name: "Takeovers", Kind: models.ChannelKindSlack, Target: url, Events: []string{models.NotificationTakeover}, Active: true},
+				}
+			},
+			expectMembers: func(mockTeamRepo *dbMocks.MockTeamRepository) {
+				mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&muted, nil)
+			},
+		},
+		{
+			name:         "a failing channel doesn't stop the others",
+			notification: regenerated,
+			channels: func(url string) []models.NotificationChannel {
+				return []models.NotificationChannel{
+					{Name: "Mail", Kind: models.ChannelKindEmail, Events: []string{models.NotificationRegenerated}, Active: true},
+					{Name: "General", Kind: models.ChannelKindSlack, Target: url, Events: []string{models.NotificationRegenerated}, Active: true},
+				}
+			},
+			expectMembers: func(mockTeamRepo *dbMocks.MockTeamRepository) {
+				mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return([]models.TeamMember{alice}, nil)
+			},
+			expectedPosts: 1,
+			expectedError: "channel Mail: email needs a mail server, set SMTP_HOST",
+		},
+		{
+			name:         "members can't be loaded",
+			notification: regenerated,
+			channels: func(url string) []models.NotificationChannel {
+				return []models.NotificationChannel{
+					{Name: "General", Kind: models.ChannelKindSlack, Target: url, Events: []string{models.NotificationRegenerated}, Active: true},
+				}
+			},
+			expectMembers: func(mockTeamRepo *dbMocks.MockTeamRepository) {
+				mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(nil, errors.New("database is locked"))
+			},
+			expectedError: "failed to get active team members: database is locked",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			server, bodies := webhookStandIn(t, http.StatusOK)
+			mockChannelRepo := dbMocks.NewMockNotificationChannelRepository(t)
+			mockTeamRepo := dbMocks.NewMockTeamRepository(t)
+			mockChannelRepo.EXPECT().GetAll(ctx).Return(tc.channels(server.URL), nil)
+			if tc.expectMembers != nil {
+				tc.expectMembers(mockTeamRepo)
+			}
+
+			d := NewDispatcher(mockChannelRepo, mockTeamRepo, nil).(*dispatcher)
+			err := d.deliver(ctx, tc.notification)
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, *bodies, tc.expectedPosts)
+		})
+	}
+}
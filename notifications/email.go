@@ -0,0 +1,78 @@
+package notifications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// EmailNotifier emails notifications to the recipients with an email address, each in a message
+// of their own
+type EmailNotifier struct {
+	config   *SMTPConfig
+	extra    string                                                                     // Address that gets every notification, like a team mailing list
+	sendMail func(addr string, a smtp.Auth, from string, to []string, msg []byte) error // Sends a message, smtp.SendMail outside tests
+}
+
+// NewEmailNotifier creates a notifier sending through the given mail server. The extra address,
+// when not empty, gets every notification as well.
+func NewEmailNotifier(config *SMTPConfig, extra string) *EmailNotifier {
+	return &EmailNotifier{config: config, extra: extra, sendMail: smtp.SendMail}
+}
+
+// Notify emails the notification to every recipient with an address and to the extra address
+func (e *EmailNotifier) Notify(ctx context.Context, n *Notification, recipients []models.TeamMember) error {
+	var addresses []string
+	for _, member := range recipients {
+		if member.Email != "" {
+			addresses = append(addresses, member.Email)
+		}
+	}
+	if e.extra != "" {
+		addresses = append(addresses, e.extra)
+	}
+
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	}
+	port := e.config.Port
+	if port == 0 {
+		port = 587
+	}
+	addr := net.JoinHostPort(e.config.Host, strconv.Itoa(port))
+
+	var errs []error
+	for _, address := range addresses {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := e.sendMail(addr, auth, e.config.From, []string{address}, e.message(address, n)); err != nil {
+			errs = append(errs, fmt.Errorf("failed to email %s: %w", address, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// message returns the email of a notification to the given address
+func (e *EmailNotifier) message(to string, n *Notification) []byte {
+	var message strings.Builder
+	message.WriteString("From: " + e.config.From + "\r\n")
+	message.WriteString("To: " + to + "\r\n")
+	message.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", "EOD: "+n.Subject) + "\r\n")
+	message.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	message.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	message.WriteString("\r\n")
+	message.WriteString(n.Text + "\r\n")
+	return []byte(message.String())
+}
@@ -0,0 +1,95 @@
+// Package notifications sends notifications about schedule events, like a shift that starts soon
+// or a takeover, to the Slack, Microsoft Teams, email and webhook channels of a team.
+package notifications
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// Notification is a message about a schedule event
+type Notification struct {
+	Event     string                // One of the models.Notification* events
+	Subject   string                // Short summary, like "Shift taken over"
+	Text      string                // The message, naming the members concerned
+	Entry     *models.ScheduleEntry // Schedule entry the notification is about, nil for the whole schedule
+	MemberIDs []int                 // Members the notification is about, every active member when empty
+}
+
+// IsPersonal checks if the notification is about specific members rather than the whole team
+func (n *Notification) IsPersonal() bool {
+	return len(n.MemberIDs) > 0
+}
+
+// Notifier delivers notifications to a single channel. The recipients are the members the
+// notification is about who want to be notified about its event.
+type Notifier interface {
+	Notify(ctx context.Context, n *Notification, recipients []models.TeamMember) error
+}
+
+// SMTPConfig is the mail server email notifications are sent through
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string // Empty for a server that doesn't need authentication
+	Password string
+	From     string // Sender address
+}
+
+// NewNotifier creates the notifier of a channel
+func NewNotifier(channel *models.NotificationChannel, smtpConfig *SMTPConfig) (Notifier, error) {
+	switch channel.Kind {
+	case models.ChannelKindSlack:
+		return NewSlackNotifier(channel.Target), nil
+	case models.ChannelKindTeams:
+		return NewTeamsNotifier(channel.Target), nil
+	case models.ChannelKindWebhook:
+		return NewWebhookNotifier(channel.Target), nil
+	case models.ChannelKindEmail:
+		if smtpConfig == nil || smtpConfig.Host == "" {
+			return nil, fmt.Errorf("email needs a mail server, set SMTP_HOST")
+		}
+		return NewEmailNotifier(smtpConfig, channel.Target), nil
+	default:
+		return nil, fmt.Errorf("unknown channel kind %q", channel.Kind)
+	}
+}
+
+// newHTTPClient returns the client webhook notifiers post with
+func newHTTPClient() *http.Client {
+	return &http.Client{Timeout: 10 * time.Second}
+}
+
+// postJSON posts a payload as JSON and fails unless the response is a success
+func postJSON(ctx context.Context, client *http.Client, url string, payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return fmt.Errorf("responded with %s: %s", resp.Status, strings.TrimSpace(string(reason)))
+	}
+	return nil
+}
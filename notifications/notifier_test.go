@@ -0,0 +1,159 @@
+package notifications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// takeover is a personal notification used across the tests
+var takeover = &Notification{
+	Event:     models.NotificationTakeover,
+	Subject:   "Shift taken over",
+	Text:      "Bob takes over the EOD of Mon 2023-10-02 (09:00–17:00) from Alice.",
+	MemberIDs: []int{2},
+}
+
+// bob is the recipient of the takeover
+var bob = models.TeamMember{ID: 2, Name: "Bob", SlackHandle: "@bob", Email: "bob@example.com"}
+
+// webhookStandIn records the bodies posted to it and responds with the given status
+func webhookStandIn(t *testing.T, status int) (*httptest.Server, *[]string) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			w.Write([]byte("invalid_payload\n"))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &bodies
+}
+
+func TestSlackNotifier(t *testing.T) {
+	server, bodies := webhookStandIn(t, http.StatusOK)
+	notifier := NewSlackNotifier(server.URL)
+
+	assert.NoError(t, notifier.Notify(context.Background(), takeover, []models.TeamMember{bob}))
+	regenerated := &Notification{Event: models.NotificationRegenerated, Subject: "Schedule regenerated", Text: "Check your shifts."}
+	assert.NoError(t, notifier.Notify(context.Background(), regenerated, []models.TeamMember{bob}))
+
+	assert.Equal(t, []string{
+		`{"text":"*Shift taken over*: Bob takes over the EOD of Mon 2023-10-02 (09:00–17:00) from Alice. cc @bob"}`,
+		`{"text":"*Schedule regenerated*: Check your shifts."}`,
+	}, *bodies)
+}
+
+func TestTeamsNotifier(t *testing.T) {
+	server, bodies := webhookStandIn(t, http.StatusOK)
+
+	assert.NoError(t, NewTeamsNotifier(server.URL).Notify(context.Background(), takeover, []models.TeamMember{bob}))
+
+	var message teamsMessage
+	assert.NoError(t, json.Unmarshal([]byte((*bodies)[0]), &message))
+	card := message.Attachments[0].Content
+	assert.Equal(t, "AdaptiveCard", card.Type)
+	assert.Equal(t, "Shift taken over", card.Body[0].Text)
+	assert.Equal(t, takeover.Text, card.Body[1].Text)
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server, bodies := webhookStandIn(t, http.StatusOK)
+	ctx := teamctx.SetTeam(context.Background(), &models.Team{ID: 1, Name: "Default", Slug: "default"})
+
+	assert.NoError(t, NewWebhookNotifier(server.URL).Notify(ctx, takeover, []models.TeamMember{bob}))
+
+	var payload WebhookPayload
+	assert.NoError(t, json.Unmarshal([]byte((*bodies)[0]), &payload))
+	assert.Equal(t, models.NotificationTakeover, payload.Event)
+	assert.Equal(t, &WebhookTeam{ID: 1, Name: "Default", Slug: "default"}, payload.Team)
+	assert.Equal(t, []WebhookMember{{ID: 2, Name: "Bob", SlackHandle: "@bob", Email: "bob@example.com"}}, payload.Members)
+	assert.False(t, payload.SentAt.IsZero())
+}
+
+func TestWebhookNotifier_Failure(t *testing.T) {
+	server, _ := webhookStandIn(t, http.StatusBadRequest)
+
+	err := NewWebhookNotifier(server.URL).Notify(context.Background(), takeover, nil)
+	assert.EqualError(t, err, "failed to post to webhook: responded with 400 Bad Request: invalid_payload")
+}
+
+func TestEmailNotifier(t *testing.T) {
+	type sentMail struct {
+		addr string
+		auth bool
+		to   []string
+		msg  string
+	}
+	var sent []sentMail
+	config := &SMTPConfig{Host: "mail.example.com", Username: "eod", Password: "secret", From: "eod@example.com"}
+	notifier := NewEmailNotifier(config, "team@example.com")
+	notifier.sendMail = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		assert.Equal(t, "eod@example.com", from)
+		sent = append(sent, sentMail{addr: addr, auth: a != nil, to: to, msg: string(msg)})
+		return nil
+	}
+
+	// Members without an address are skipped, the extra address gets every notification
+	noEmail := models.TeamMember{ID: 3, Name: "Carol"}
+	assert.NoError(t, notifier.Notify(context.Background(), takeover, []models.TeamMember{bob, noEmail}))
+
+	if assert.Len(t, sent, 2) {
+		assert.Equal(t, "mail.example.com:587", sent[0].addr)
+		assert.True(t, sent[0].auth)
+		assert.Equal(t, []string{"bob@example.com"}, sent[0].to)
+		assert.Equal(t, []string{"team@example.com"}, sent[1].to)
+		assert.Contains(t, sent[0].msg, "To: bob@example.com\r\n")
+		assert.Contains(t, sent[0].msg, "Subject: EOD: Shift taken over\r\n")
+		assert.True(t, strings.HasSuffix(sent[0].msg, "\r\n\r\n"+takeover.Text+"\r\n"))
+	}
+}
+
+func TestNewNotifier(t *testing.T) {
+	testCases := []struct {
+		name          string
+		channel       models.NotificationChannel
+		smtpConfig    *SMTPConfig
+		expectedError string
+	}{
+		{name: "slack", channel: models.NotificationChannel{Kind: models.ChannelKindSlack, Target: "https://hooks.slack.com/services/T/B/X"}},
+		{name: "teams", channel: models.NotificationChannel{Kind: models.ChannelKindTeams, Target: "https://example.webhook.office.com/x"}},
+		{name: "webhook", channel: models.NotificationChannel{Kind: models.ChannelKindWebhook, Target: "https://example.com/hook"}},
+		{name: "email", channel: models.NotificationChannel{Kind: models.ChannelKindEmail}, smtpConfig: &SMTPConfig{Host: "mail.example.com"}},
+		{
+			name:          "email without a mail server",
+			channel:       models.NotificationChannel{Kind: models.ChannelKindEmail},
+			expectedError: "email needs a mail server, set SMTP_HOST",
+		},
+		{
+			name:          "unknown kind",
+			channel:       models.NotificationChannel{Kind: "pager"},
+			expectedError: `unknown channel kind "pager"`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			notifier, err := NewNotifier(&tc.channel, tc.smtpConfig)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.NotNil(t, notifier)
+		})
+	}
+}
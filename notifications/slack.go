@@ -0,0 +1,42 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// SlackNotifier posts notifications to a Slack incoming webhook
+type SlackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewSlackNotifier creates a notifier for the Slack incoming webhook at the given URL
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{url: url, client: newHTTPClient()}
+}
+
+// Notify posts the notification, mentioning the recipients of a personal notification
+func (s *SlackNotifier) Notify(ctx context.Context, n *Notification, recipients []models.TeamMember) error {
+	text := "*" + n.Subject + "*: " + n.Text
+	if n.IsPersonal() {
+		var mentions []string
+		for _, member := range recipients {
+			if handle := strings.TrimPrefix(member.SlackHandle, "@"); handle != "" {
+				mentions = append(mentions, "@"+handle)
+			}
+		}
+		if len(mentions) > 0 {
+			text += " cc " + strings.Join(mentions, " ")
+		}
+	}
+
+	if err := postJSON(ctx, s.client, s.url, map[string]string{"text": text}); err != nil {
+		return fmt.Errorf("failed to post to slack: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// TeamsNotifier posts notifications to a Microsoft Teams incoming webhook as an adaptive card
+type TeamsNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewTeamsNotifier creates a notifier for the Microsoft Teams incoming webhook at the given URL
+func NewTeamsNotifier(url string) *TeamsNotifier {
+	return &TeamsNotifier{url: url, client: newHTTPClient()}
+}
+
+// teamsMessage is a message with a single adaptive card, as incoming webhooks expect it
+type teamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []teamsAttachment `json:"attachments"`
+}
+
+// teamsAttachment holds a card of a message
+type teamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     teamsCard `json:"content"`
+}
+
+// teamsCard is an adaptive card
+type teamsCard struct {
+	Schema  string           `json:"$schema"`
+	Type    string           `json:"type"`
+	Version string           `json:"version"`
+	Body    []teamsTextBlock `json:"body"`
+}
+
+// teamsTextBlock is a paragraph of an adaptive card
+type teamsTextBlock struct {
+	Type   string `json:"type"`
+	Text   string `json:"text"`
+	Weight string `json:"weight,omitempty"`
+	Wrap   bool   `json:"wrap"`
+}
+
+// Notify posts the notification with its subject as the title of the card
+func (t *TeamsNotifier) Notify(ctx context.Context, n *Notification, recipients []models.TeamMember) error {
+	message := teamsMessage{
+		Type: "message",
+		Attachments: []teamsAttachment{{
+			ContentType: "application/vnd.microsoft.card.adaptive",
+			Content: teamsCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body: []teamsTextBlock{
+					{Type: "TextBlock", Text: n.Subject, Weight: "Bolder", Wrap: true},
+					{Type: "TextBlock", Text: n.Text, Wrap: true},
+				},
+			},
+		}},
+	}
+
+	if err := postJSON(ctx, t.client, t.url, message); err != nil {
+		return fmt.Errorf("failed to post to microsoft teams: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package notifications
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// WebhookNotifier posts notifications as JSON to any URL, for tools with their own integrations
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier creates a notifier for the webhook at the given URL
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: newHTTPClient()}
+}
+
+// WebhookPayload is the JSON posted to webhooks
+type WebhookPayload struct {
+	Event   string                `json:"event"`
+	Team    *WebhookTeam          `json:"team,omitempty"`
+	Subject string                `json:"subject"`
+	Text    string                `json:"text"`
+	Entry   *models.ScheduleEntry `json:"entry,omitempty"`
+	Members []WebhookMember       `json:"members"` // Recipients of the notification
+	SentAt  time.Time             `json:"sent_at"`
+}
+
+// WebhookTeam is the team of a webhook payload
+type WebhookTeam struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Slug string `json:"slug"`
+}
+
+// WebhookMember is a recipient of a webhook payload
+type WebhookMember struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	SlackHandle string `json:"slack_handle,omitempty"`
+	Email       string `json:"email,omitempty"`
+}
+
+// NewWebhookPayload returns the payload of a notification for the team in the context
+func NewWebhookPayload(ctx context.Context, n *Notification, recipients []models.TeamMember) *WebhookPayload {
+	payload := &WebhookPayload{
+		Event:   n.Event,
+		Subject: n.Subject,
+		Text:    n.Text,
+		Entry:   n.Entry,
+		Members: []WebhookMember{},
+		SentAt:  time.Now().UTC(),
+	}
+	if team := teamctx.GetTeam(ctx); team != nil {
+		payload.Team = &WebhookTeam{ID: team.ID, Name: team.Name, Slug: team.Slug}
+	}
+	for _, member := range recipients {
+		payload.Members = append(payload.Members, WebhookMember{
+			ID:          member.ID,
+			Name:        member.Name,
+			SlackHandle: member.SlackHandle,
+			Email:       member.Email,
+		})
+	}
+	return payload
+}
+
+// Notify posts the notification
+func (w *WebhookNotifier) Notify(ctx context.Context, n *Notification, recipients []models.TeamMember) error {
+	if err := postJSON(ctx, w.client, w.url, NewWebhookPayload(ctx, n, recipients)); err != nil {
+		return fmt.Errorf("failed to post to webhook: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// HolidayRepository interface defines holiday database operations
+type HolidayRepository interface {
+	GetAll(ctx context.Context) ([]models.Holiday, error)
+	GetByID(ctx context.Context, id int) (*models.Holiday, error)
+	Create(ctx context.Context, holiday *models.Holiday) error
+	Update(ctx context.Context, holiday *models.Holiday) error
+	Delete(ctx context.Context, id int) error
+}
+
+// holidayRepository implements HolidayRepository interface
+type holidayRepository struct {
+	db *sql.DB
+}
+
+// NewHolidayRepository creates a new holiday repository
+func NewHolidayRepository(db *sql.DB) HolidayRepository {
+	return &holidayRepository{db: db}
+}
+
+// GetAll retrieves all holidays, recurring ones first and then by date
+func (r *holidayRepository) GetAll(ctx context.Context) ([]models.Holiday, error) {
+	query := `
+		SELECT id, date, name, recurring, behavior, start_time, end_time,
+		       created_by, modified_by, modified_at
+		FROM holidays
+		ORDER BY recurring DESC, date ASC, name ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holidays: %w", err)
+	}
+	defer rows.Close()
+
+	var holidays []models.Holiday
+	for rows.Next() {
+		holiday, err := scanHoliday(rows)
+		if err != nil {
+			return nil, err
+		}
+		holidays = append(holidays, *holiday)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating holidays: %w", err)
+	}
+
+	return holidays, nil
+}
+
+// GetByID retrieves a holiday by ID
+func (r *holidayRepository) GetByID(ctx context.Context, id int) (*models.Holiday, error) {
+	query := `
+		SELECT id, date, name, recurring, behavior, start_time, end_time,
+		       created_by, modified_by, modified_at
+		FROM holidays
+		WHERE id = ?
+	`
+
+	holiday, err := scanHoliday(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("holiday with ID %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return holiday, nil
+}
+
+// Create creates a new holiday with audit fields
+func (r *holidayRepository) Create(ctx context.Context, holiday *models.Holiday) error {
+	query := `
+		INSERT INTO holidays (date, name, recurring, behavior, start_time, end_time, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		holiday.Date.Format("2006-01-02"),
+		holiday.Name,
+		holiday.Recurring,
+		holiday.Behavior,
+		holiday.StartTime,
+		holiday.EndTime,
+		userEmail,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get inserted ID: %w", err)
+	}
+
+	holiday.ID = int(id)
+	holiday.CreatedBy = userEmail
+	return nil
+}
+
+// Update updates an existing holiday with audit fields
+func (r *holidayRepository) Update(ctx context.Context, holiday *models.Holiday) error {
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+	now := time.Now()
+
+	query := `
+		UPDATE holidays
+		SET date = ?, name = ?, recurring = ?, behavior = ?, start_time = ?, end_time = ?,
+		    modified_by = ?, modified_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		holiday.Date.Format("2006-01-02"),
+		holiday.Name,
+		holiday.Recurring,
+		holiday.Behavior,
+		holiday.StartTime,
+		holiday.EndTime,
+		userEmail,
+		now,
+		holiday.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update holiday: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("holiday with ID %d not found", holiday.ID)
+	}
+
+	holiday.ModifiedBy = userEmail
+	holiday.ModifiedAt = &now
+	return nil
+}
+
+// Delete deletes a holiday
+func (r *holidayRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM holidays WHERE id = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete holiday: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("holiday with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanHoliday scans a holiday row
+func scanHoliday(row rowScanner) (*models.Holiday, error) {
+	var holiday models.Holiday
+	var createdBy, modifiedBy sql.NullString
+	var modifiedAt sql.NullTime
+
+	err := row.Scan(
+		&holiday.ID,
+		&holiday.Date,
+		&holiday.Name,
+		&holiday.Recurring,
+		&holiday.Behavior,
+		&holiday.StartTime,
+		&holiday.EndTime,
+		&createdBy,
+		&modifiedBy,
+		&modifiedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan holiday: %w", err)
+	}
+
+	// Convert NULL values to empty string/nil
+	if createdBy.Valid {
+		holiday.CreatedBy = createdBy.String
+	}
+	if modifiedBy.Valid {
+		holiday.ModifiedBy = modifiedBy.String
+	}
+	if modifiedAt.Valid {
+		holiday.ModifiedAt = &modifiedAt.Time
+	}
+
+	return &holiday, nil
+}
@@ -162,6 +162,63 @@ func (_c *MockScheduleRepository_Create_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
+// CreateBatch provides a mock function for the type MockScheduleRepository
+func (_mock *MockScheduleRepository) CreateBatch(ctx context.Context, entries []*models.ScheduleEntry) error {
+	ret := _mock.Called(ctx, entries)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateBatch")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []*models.ScheduleEntry) error); ok {
+		r0 = returnFunc(ctx, entries)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockScheduleRepository_CreateBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateBatch'
+type MockScheduleRepository_CreateBatch_Call struct {
+	*mock.Call
+}
+
+// CreateBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - entries []*models.ScheduleEntry
+func (_e *MockScheduleRepository_Expecter) CreateBatch(ctx interface{}, entries interface{}) *MockScheduleRepository_CreateBatch_Call {
+	return &MockScheduleRepository_CreateBatch_Call{Call: _e.mock.On("CreateBatch", ctx, entries)}
+}
+
+func (_c *MockScheduleRepository_CreateBatch_Call) Run(run func(ctx context.Context, entries []*models.ScheduleEntry)) *MockScheduleRepository_CreateBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []*models.ScheduleEntry
+		if args[1] != nil {
+			arg1 = args[1].([]*models.ScheduleEntry)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_CreateBatch_Call) Return(err error) *MockScheduleRepository_CreateBatch_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockScheduleRepository_CreateBatch_Call) RunAndReturn(run func(ctx context.Context, entries []*models.ScheduleEntry) error) *MockScheduleRepository_CreateBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // Delete provides a mock function for the type MockScheduleRepository
 func (_mock *MockScheduleRepository) Delete(ctx context.Context, id int) error {
 	ret := _mock.Called(ctx, id)
@@ -219,6 +276,63 @@ func (_c *MockScheduleRepository_Delete_Call) RunAndReturn(run func(ctx context.
 	return _c
 }
 
+// DeleteBatch provides a mock function for the type MockScheduleRepository
+func (_mock *MockScheduleRepository) DeleteBatch(ctx context.Context, ids []int) error {
+	ret := _mock.Called(ctx, ids)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteBatch")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, []int) error); ok {
+		r0 = returnFunc(ctx, ids)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockScheduleRepository_DeleteBatch_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteBatch'
+type MockScheduleRepository_DeleteBatch_Call struct {
+	*mock.Call
+}
+
+// DeleteBatch is a helper method to define mock.On call
+//   - ctx context.Context
+//   - ids []int
+func (_e *MockScheduleRepository_Expecter) DeleteBatch(ctx interface{}, ids interface{}) *MockScheduleRepository_DeleteBatch_Call {
+	return &MockScheduleRepository_DeleteBatch_Call{Call: _e.mock.On("DeleteBatch", ctx, ids)}
+}
+
+func (_c *MockScheduleRepository_DeleteBatch_Call) Run(run func(ctx context.Context, ids []int)) *MockScheduleRepository_DeleteBatch_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 []int
+		if args[1] != nil {
+			arg1 = args[1].([]int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockScheduleRepository_DeleteBatch_Call) Return(err error) *MockScheduleRepository_DeleteBatch_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockScheduleRepository_DeleteBatch_Call) RunAndReturn(run func(ctx context.Context, ids []int) error) *MockScheduleRepository_DeleteBatch_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // DeleteByDateRange provides a mock function for the type MockScheduleRepository
 func (_mock *MockScheduleRepository) DeleteByDateRange(ctx context.Context, from time.Time, to time.Time) error {
 	ret := _mock.Called(ctx, from, to)
@@ -6,6 +6,7 @@ package repositories
 
 import (
 	"context"
+	"time"
 
 	"github.com/blogem/eod-scheduler/models"
 	mock "github.com/stretchr/testify/mock"
@@ -38,6 +39,120 @@ func (_m *MockWorkingHoursRepository) EXPECT() *MockWorkingHoursRepository_Expec
 	return &MockWorkingHoursRepository_Expecter{mock: &_m.Mock}
 }
 
+// DeleteChange provides a mock function for the type MockWorkingHoursRepository
+func (_mock *MockWorkingHoursRepository) DeleteChange(ctx context.Context, effectiveFrom time.Time) error {
+	ret := _mock.Called(ctx, effectiveFrom)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteChange")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time) error); ok {
+		r0 = returnFunc(ctx, effectiveFrom)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockWorkingHoursRepository_DeleteChange_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteChange'
+type MockWorkingHoursRepository_DeleteChange_Call struct {
+	*mock.Call
+}
+
+// DeleteChange is a helper method to define mock.On call
+//   - ctx context.Context
+//   - effectiveFrom time.Time
+func (_e *MockWorkingHoursRepository_Expecter) DeleteChange(ctx interface{}, effectiveFrom interface{}) *MockWorkingHoursRepository_DeleteChange_Call {
+	return &MockWorkingHoursRepository_DeleteChange_Call{Call: _e.mock.On("DeleteChange", ctx, effectiveFrom)}
+}
+
+func (_c *MockWorkingHoursRepository_DeleteChange_Call) Run(run func(ctx context.Context, effectiveFrom time.Time)) *MockWorkingHoursRepository_DeleteChange_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 time.Time
+		if args[1] != nil {
+			arg1 = args[1].(time.Time)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_DeleteChange_Call) Return(err error) *MockWorkingHoursRepository_DeleteChange_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_DeleteChange_Call) RunAndReturn(run func(ctx context.Context, effectiveFrom time.Time) error) *MockWorkingHoursRepository_DeleteChange_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// DeleteException provides a mock function for the type MockWorkingHoursRepository
+func (_mock *MockWorkingHoursRepository) DeleteException(ctx context.Context, id int) error {
+	ret := _mock.Called(ctx, id)
+
+	if len(ret) == 0 {
+		panic("no return value specified for DeleteException")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int) error); ok {
+		r0 = returnFunc(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockWorkingHoursRepository_DeleteException_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'DeleteException'
+type MockWorkingHoursRepository_DeleteException_Call struct {
+	*mock.Call
+}
+
+// DeleteException is a helper method to define mock.On call
+//   - ctx context.Context
+//   - id int
+func (_e *MockWorkingHoursRepository_Expecter) DeleteException(ctx interface{}, id interface{}) *MockWorkingHoursRepository_DeleteException_Call {
+	return &MockWorkingHoursRepository_DeleteException_Call{Call: _e.mock.On("DeleteException", ctx, id)}
+}
+
+func (_c *MockWorkingHoursRepository_DeleteException_Call) Run(run func(ctx context.Context, id int)) *MockWorkingHoursRepository_DeleteException_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		var arg1 int
+		if args[1] != nil {
+			arg1 = args[1].(int)
+		}
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_DeleteException_Call) Return(err error) *MockWorkingHoursRepository_DeleteException_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_DeleteException_Call) RunAndReturn(run func(ctx context.Context, id int) error) *MockWorkingHoursRepository_DeleteException_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // GetActiveDays provides a mock function for the type MockWorkingHoursRepository
 func (_mock *MockWorkingHoursRepository) GetActiveDays(ctx context.Context) ([]models.WorkingHours, error) {
 	ret := _mock.Called(ctx)
@@ -163,27 +278,27 @@ func (_c *MockWorkingHoursRepository_GetAll_Call) RunAndReturn(run func(ctx cont
 }
 
 // GetByDay provides a mock function for the type MockWorkingHoursRepository
-func (_mock *MockWorkingHoursRepository) GetByDay(ctx context.Context, dayOfWeek int) (*models.WorkingHours, error) {
-	ret := _mock.Called(ctx, dayOfWeek)
+func (_mock *MockWorkingHoursRepository) GetByDay(ctx context.Context, dayOfWeek int, date time.Time) ([]models.WorkingHours, error) {
+	ret := _mock.Called(ctx, dayOfWeek, date)
 
 	if len(ret) == 0 {
 		panic("no return value specified for GetByDay")
 	}
 
-	var r0 *models.WorkingHours
+	var r0 []models.WorkingHours
 	var r1 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) (*models.WorkingHours, error)); ok {
-		return returnFunc(ctx, dayOfWeek)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) ([]models.WorkingHours, error)); ok {
+		return returnFunc(ctx, dayOfWeek, date)
 	}
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int) *models.WorkingHours); ok {
-		r0 = returnFunc(ctx, dayOfWeek)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, int, time.Time) []models.WorkingHours); ok {
+		r0 = returnFunc(ctx, dayOfWeek, date)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*models.WorkingHours)
+			r0 = ret.Get(0).([]models.WorkingHours)
 		}
 	}
-	if returnFunc, ok := ret.Get(1).(func(context.Context, int) error); ok {
-		r1 = returnFunc(ctx, dayOfWeek)
+	if returnFunc, ok := ret.Get(1).(func(context.Context, int, time.Time) error); ok {
+		r1 = returnFunc(ctx, dayOfWeek, date)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -198,11 +313,12 @@ type MockWorkingHoursRepository_GetByDay_Call struct {
 // GetByDay is a helper method to define mock.On call
 //   - ctx context.Context
 //   - dayOfWeek int
-func (_e *MockWorkingHoursRepository_Expecter) GetByDay(ctx interface{}, dayOfWeek interface{}) *MockWorkingHoursRepository_GetByDay_Call {
-	return &MockWorkingHoursRepository_GetByDay_Call{Call: _e.mock.On("GetByDay", ctx, dayOfWeek)}
+//   - date time.Time
+func (_e *MockWorkingHoursRepository_Expecter) GetByDay(ctx interface{}, dayOfWeek interface{}, date interface{}) *MockWorkingHoursRepository_GetByDay_Call {
+	return &MockWorkingHoursRepository_GetByDay_Call{Call: _e.mock.On("GetByDay", ctx, dayOfWeek, date)}
 }
 
-func (_c *MockWorkingHoursRepository_GetByDay_Call) Run(run func(ctx context.Context, dayOfWeek int)) *MockWorkingHoursRepository_GetByDay_Call {
+func (_c *MockWorkingHoursRepository_GetByDay_Call) Run(run func(ctx context.Context, dayOfWeek int, date time.Time)) *MockWorkingHoursRepository_GetByDay_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
@@ -212,152 +328,270 @@ func (_c *MockWorkingHoursRepository_GetByDay_Call) Run(run func(ctx context.Con
 		if args[1] != nil {
 			arg1 = args[1].(int)
 		}
+		var arg2 time.Time
+		if args[2] != nil {
+			arg2 = args[2].(time.Time)
+		}
 		run(
 			arg0,
 			arg1,
+			arg2,
 		)
 	})
 	return _c
 }
 
-func (_c *MockWorkingHoursRepository_GetByDay_Call) Return(workingHours *models.WorkingHours, err error) *MockWorkingHoursRepository_GetByDay_Call {
-	_c.Call.Return(workingHours, err)
+func (_c *MockWorkingHoursRepository_GetByDay_Call) Return(workingHourss []models.WorkingHours, err error) *MockWorkingHoursRepository_GetByDay_Call {
+	_c.Call.Return(workingHourss, err)
 	return _c
 }
 
-func (_c *MockWorkingHoursRepository_GetByDay_Call) RunAndReturn(run func(ctx context.Context, dayOfWeek int) (*models.WorkingHours, error)) *MockWorkingHoursRepository_GetByDay_Call {
+func (_c *MockWorkingHoursRepository_GetByDay_Call) RunAndReturn(run func(ctx context.Context, dayOfWeek int, date time.Time) ([]models.WorkingHours, error)) *MockWorkingHoursRepository_GetByDay_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// Update provides a mock function for the type MockWorkingHoursRepository
-func (_mock *MockWorkingHoursRepository) Update(ctx context.Context, hours *models.WorkingHours) error {
-	ret := _mock.Called(ctx, hours)
+// GetExceptions provides a mock function for the type MockWorkingHoursRepository
+func (_mock *MockWorkingHoursRepository) GetExceptions(ctx context.Context) ([]models.WorkingHoursException, error) {
+	ret := _mock.Called(ctx)
 
 	if len(ret) == 0 {
-		panic("no return value specified for Update")
+		panic("no return value specified for GetExceptions")
+	}
+
+	var r0 []models.WorkingHoursException
+	var r1 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context) ([]models.WorkingHoursException, error)); ok {
+		return returnFunc(ctx)
+	}
+	if returnFunc, ok := ret.Get(0).(func(context.Context) []models.WorkingHoursException); ok {
+		r0 = returnFunc(ctx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]models.WorkingHoursException)
+		}
+	}
+	if returnFunc, ok := ret.Get(1).(func(context.Context) error); ok {
+		r1 = returnFunc(ctx)
+	} else {
+		r1 = ret.Error(1)
+	}
+	return r0, r1
+}
+
+// MockWorkingHoursRepository_GetExceptions_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'GetExceptions'
+type MockWorkingHoursRepository_GetExceptions_Call struct {
+	*mock.Call
+}
+
+// GetExceptions is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *MockWorkingHoursRepository_Expecter) GetExceptions(ctx interface{}) *MockWorkingHoursRepository_GetExceptions_Call {
+	return &MockWorkingHoursRepository_GetExceptions_Call{Call: _e.mock.On("GetExceptions", ctx)}
+}
+
+func (_c *MockWorkingHoursRepository_GetExceptions_Call) Run(run func(ctx context.Context)) *MockWorkingHoursRepository_GetExceptions_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
+		}
+		run(
+			arg0,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_GetExceptions_Call) Return(workingHoursExceptions []models.WorkingHoursException, err error) *MockWorkingHoursRepository_GetExceptions_Call {
+	_c.Call.Return(workingHoursExceptions, err)
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_GetExceptions_Call) RunAndReturn(run func(ctx context.Context) ([]models.WorkingHoursException, error)) *MockWorkingHoursRepository_GetExceptions_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// ReplaceDay provides a mock function for the type MockWorkingHoursRepository
+func (_mock *MockWorkingHoursRepository) ReplaceDay(ctx context.Context, effectiveFrom time.Time, dayOfWeek int, shifts []models.WorkingHours) error {
+	ret := _mock.Called(ctx, effectiveFrom, dayOfWeek, shifts)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceDay")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.WorkingHours) error); ok {
-		r0 = returnFunc(ctx, hours)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, time.Time, int, []models.WorkingHours) error); ok {
+		r0 = returnFunc(ctx, effectiveFrom, dayOfWeek, shifts)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockWorkingHoursRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
-type MockWorkingHoursRepository_Update_Call struct {
+// MockWorkingHoursRepository_ReplaceDay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'ReplaceDay'
+type MockWorkingHoursRepository_ReplaceDay_Call struct {
 	*mock.Call
 }
 
-// Update is a helper method to define mock.On call
+// ReplaceDay is a helper method to define mock.On call
 //   - ctx context.Context
-//   - hours *models.WorkingHours
-func (_e *MockWorkingHoursRepository_Expecter) Update(ctx interface{}, hours interface{}) *MockWorkingHoursRepository_Update_Call {
-	return &MockWorkingHoursRepository_Update_Call{Call: _e.mock.On("Update", ctx, hours)}
+//   - effectiveFrom time.Time
+//   - dayOfWeek int
+//   - shifts []models.WorkingHours
+func (_e *MockWorkingHoursRepository_Expecter) ReplaceDay(ctx interface{}, effectiveFrom interface{}, dayOfWeek interface{}, shifts interface{}) *MockWorkingHoursRepository_ReplaceDay_Call {
+	return &MockWorkingHoursRepository_ReplaceDay_Call{Call: _e.mock.On("ReplaceDay", ctx, effectiveFrom, dayOfWeek, shifts)}
 }
 
-func (_c *MockWorkingHoursRepository_Update_Call) Run(run func(ctx context.Context, hours *models.WorkingHours)) *MockWorkingHoursRepository_Update_Call {
+func (_c *MockWorkingHoursRepository_ReplaceDay_Call) Run(run func(ctx context.Context, effectiveFrom time.Time, dayOfWeek int, shifts []models.WorkingHours)) *MockWorkingHoursRepository_ReplaceDay_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 *models.WorkingHours
+		var arg1 time.Time
 		if args[1] != nil {
-			arg1 = args[1].(*models.WorkingHours)
+			arg1 = args[1].(time.Time)
+		}
+		var arg2 int
+		if args[2] != nil {
+			arg2 = args[2].(int)
+		}
+		var arg3 []models.WorkingHours
+		if args[3] != nil {
+			arg3 = args[3].([]models.WorkingHours)
 		}
 		run(
 			arg0,
 			arg1,
+			arg2,
+			arg3,
 		)
 	})
 	return _c
 }
 
-func (_c *MockWorkingHoursRepository_Update_Call) Return(err error) *MockWorkingHoursRepository_Update_Call {
+func (_c *MockWorkingHoursRepository_ReplaceDay_Call) Return(err error) *MockWorkingHoursRepository_ReplaceDay_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockWorkingHoursRepository_Update_Call) RunAndReturn(run func(ctx context.Context, hours *models.WorkingHours) error) *MockWorkingHoursRepository_Update_Call {
+func (_c *MockWorkingHoursRepository_ReplaceDay_Call) RunAndReturn(run func(ctx context.Context, effectiveFrom time.Time, dayOfWeek int, shifts []models.WorkingHours) error) *MockWorkingHoursRepository_ReplaceDay_Call {
 	_c.Call.Return(run)
 	return _c
 }
 
-// UpdateByDay provides a mock function for the type MockWorkingHoursRepository
-func (_mock *MockWorkingHoursRepository) UpdateByDay(ctx context.Context, dayOfWeek int, startTime string, endTime string, active bool) error {
-	ret := _mock.Called(ctx, dayOfWeek, startTime, endTime, active)
+// SaveException provides a mock function for the type MockWorkingHoursRepository
+func (_mock *MockWorkingHoursRepository) SaveException(ctx context.Context, exception *models.WorkingHoursException) error {
+	ret := _mock.Called(ctx, exception)
 
 	if len(ret) == 0 {
-		panic("no return value specified for UpdateByDay")
+		panic("no return value specified for SaveException")
 	}
 
 	var r0 error
-	if returnFunc, ok := ret.Get(0).(func(context.Context, int, string, string, bool) error); ok {
-		r0 = returnFunc(ctx, dayOfWeek, startTime, endTime, active)
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.WorkingHoursException) error); ok {
+		r0 = returnFunc(ctx, exception)
 	} else {
 		r0 = ret.Error(0)
 	}
 	return r0
 }
 
-// MockWorkingHoursRepository_UpdateByDay_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'UpdateByDay'
-type MockWorkingHoursRepository_UpdateByDay_Call struct {
+// MockWorkingHoursRepository_SaveException_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'SaveException'
+type MockWorkingHoursRepository_SaveException_Call struct {
 	*mock.Call
 }
 
-// UpdateByDay is a helper method to define mock.On call
+// SaveException is a helper method to define mock.On call
 //   - ctx context.Context
-//   - dayOfWeek int
-//   - startTime string
-//   - endTime string
-//   - active bool
-func (_e *MockWorkingHoursRepository_Expecter) UpdateByDay(ctx interface{}, dayOfWeek interface{}, startTime interface{}, endTime interface{}, active interface{}) *MockWorkingHoursRepository_UpdateByDay_Call {
-	return &MockWorkingHoursRepository_UpdateByDay_Call{Call: _e.mock.On("UpdateByDay", ctx, dayOfWeek, startTime, endTime, active)}
+//   - exception *models.WorkingHoursException
+func (_e *MockWorkingHoursRepository_Expecter) SaveException(ctx interface{}, exception interface{}) *MockWorkingHoursRepository_SaveException_Call {
+	return &MockWorkingHoursRepository_SaveException_Call{Call: _e.mock.On("SaveException", ctx, exception)}
 }
 
-func (_c *MockWorkingHoursRepository_UpdateByDay_Call) Run(run func(ctx context.Context, dayOfWeek int, startTime string, endTime string, active bool)) *MockWorkingHoursRepository_UpdateByDay_Call {
+func (_c *MockWorkingHoursRepository_SaveException_Call) Run(run func(ctx context.Context, exception *models.WorkingHoursException)) *MockWorkingHoursRepository_SaveException_Call {
 	_c.Call.Run(func(args mock.Arguments) {
 		var arg0 context.Context
 		if args[0] != nil {
 			arg0 = args[0].(context.Context)
 		}
-		var arg1 int
+		var arg1 *models.WorkingHoursException
 		if args[1] != nil {
-			arg1 = args[1].(int)
-		}
-		var arg2 string
-		if args[2] != nil {
-			arg2 = args[2].(string)
+			arg1 = args[1].(*models.WorkingHoursException)
 		}
-		var arg3 string
-		if args[3] != nil {
-			arg3 = args[3].(string)
+		run(
+			arg0,
+			arg1,
+		)
+	})
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_SaveException_Call) Return(err error) *MockWorkingHoursRepository_SaveException_Call {
+	_c.Call.Return(err)
+	return _c
+}
+
+func (_c *MockWorkingHoursRepository_SaveException_Call) RunAndReturn(run func(ctx context.Context, exception *models.WorkingHoursException) error) *MockWorkingHoursRepository_SaveException_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
+// Update provides a mock function for the type MockWorkingHoursRepository
+func (_mock *MockWorkingHoursRepository) Update(ctx context.Context, hours *models.WorkingHours) error {
+	ret := _mock.Called(ctx, hours)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Update")
+	}
+
+	var r0 error
+	if returnFunc, ok := ret.Get(0).(func(context.Context, *models.WorkingHours) error); ok {
+		r0 = returnFunc(ctx, hours)
+	} else {
+		r0 = ret.Error(0)
+	}
+	return r0
+}
+
+// MockWorkingHoursRepository_Update_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Update'
+type MockWorkingHoursRepository_Update_Call struct {
+	*mock.Call
+}
+
+// Update is a helper method to define mock.On call
+//   - ctx context.Context
+//   - hours *models.WorkingHours
+func (_e *MockWorkingHoursRepository_Expecter) Update(ctx interface{}, hours interface{}) *MockWorkingHoursRepository_Update_Call {
+	return &MockWorkingHoursRepository_Update_Call{Call: _e.mock.On("Update", ctx, hours)}
+}
+
+func (_c *MockWorkingHoursRepository_Update_Call) Run(run func(ctx context.Context, hours *models.WorkingHours)) *MockWorkingHoursRepository_Update_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		var arg0 context.Context
+		if args[0] != nil {
+			arg0 = args[0].(context.Context)
 		}
-		var arg4 bool
-		if args[4] != nil {
-			arg4 = args[4].(bool)
+		var arg1 *models.WorkingHours
+		if args[1] != nil {
+			arg1 = args[1].(*models.WorkingHours)
 		}
 		run(
 			arg0,
 			arg1,
-			arg2,
-			arg3,
-			arg4,
 		)
 	})
 	return _c
 }
 
-func (_c *MockWorkingHoursRepository_UpdateByDay_Call) Return(err error) *MockWorkingHoursRepository_UpdateByDay_Call {
+func (_c *MockWorkingHoursRepository_Update_Call) Return(err error) *MockWorkingHoursRepository_Update_Call {
 	_c.Call.Return(err)
 	return _c
 }
 
-func (_c *MockWorkingHoursRepository_UpdateByDay_Call) RunAndReturn(run func(ctx context.Context, dayOfWeek int, startTime string, endTime string, active bool) error) *MockWorkingHoursRepository_UpdateByDay_Call {
+func (_c *MockWorkingHoursRepository_Update_Call) RunAndReturn(run func(ctx context.Context, hours *models.WorkingHours) error) *MockWorkingHoursRepository_Update_Call {
 	_c.Call.Return(run)
 	return _c
 }
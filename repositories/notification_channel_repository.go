@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// NotificationChannelRepository interface defines notification channel database operations
+type NotificationChannelRepository interface {
+	GetAll(ctx context.Context) ([]models.NotificationChannel, error)
+	GetByID(ctx context.Context, id int) (*models.NotificationChannel, error)
+	Create(ctx context.Context, channel *models.NotificationChannel) error
+	Update(ctx context.Context, channel *models.NotificationChannel) error
+	Delete(ctx context.Context, id int) error
+}
+
+// notificationChannelRepository implements NotificationChannelRepository interface
+type notificationChannelRepository struct {
+	db *sql.DB
+}
+
+// NewNotificationChannelRepository creates a new notification channel repository
+func NewNotificationChannelRepository(db *sql.DB) NotificationChannelRepository {
+	return &notificationChannelRepository{db: db}
+}
+
+// GetAll retrieves all notification channels of the team ordered by name
+func (r *notificationChannelRepository) GetAll(ctx context.Context) ([]models.NotificationChannel, error) {
+	query := `
+		SELECT id, name, kind, target, events, active, created_by, modified_by, modified_at
+		FROM notification_channels
+		WHERE team_id = ?
+		ORDER BY name ASC, id ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamctx.GetTeamID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query notification channels: %w", err)
+	}
+	defer rows.Close()
+
+	var channels []models.NotificationChannel
+	for rows.Next() {
+		channel, err := scanNotificationChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, *channel)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating notification channels: %w", err)
+	}
+
+	return channels, nil
+}
+
+// GetByID retrieves a notification channel of the team by ID
+func (r *notificationChannelRepository) GetByID(ctx context.Context, id int) (*models.NotificationChannel, error) {
+	query := `
+		SELECT id, name, kind, target, events, active, created_by, modified_by, modified_at
+		FROM notification_channels
+		WHERE id = ? AND team_id = ?
+	`
+
+	channel, err := scanNotificationChannel(conn(ctx, r.db).QueryRowContext(ctx, query, id, teamctx.GetTeamID(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("notification channel with ID %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return channel, nil
+}
+
+// Create creates a new notification channel with audit fields
+func (r *notificationChannelRepository) Create(ctx context.Context, channel *models.NotificationChannel) error {
+	query := `
+		INSERT INTO notification_channels (team_id, name, kind, target, events, active, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	events, err := encodeEvents(channel.Events)
+	if err != nil {
+		return err
+	}
+
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		teamctx.GetTeamID(ctx),
+		channel.Name,
+		channel.Kind,
+		channel.Target,
+		events,
+		channel.Active,
+		userEmail,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get inserted ID: %w", err)
+	}
+
+	channel.ID = int(id)
+	channel.CreatedBy = userEmail
+	return nil
+}
+
+// Update updates an existing notification channel with audit fields
+func (r *notificationChannelRepository) Update(ctx context.Context, channel *models.NotificationChannel) error {
+	events, err := encodeEvents(channel.Events)
+	if err != nil {
+		return err
+	}
+
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+	now := time.Now()
+
+	query := `
+		UPDATE notification_channels
+		SET name = ?, kind = ?, target = ?, events = ?, active = ?, modified_by = ?, modified_at = ?
+		WHERE id = ? AND team_id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		channel.Name,
+		channel.Kind,
+		channel.Target,
+		events,
+		channel.Active,
+		userEmail,
+		now,
+		channel.ID,
+		teamctx.GetTeamID(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification channel with ID %d not found", channel.ID)
+	}
+
+	channel.ModifiedBy = userEmail
+	channel.ModifiedAt = &now
+	return nil
+}
+
+// Delete deletes a notification channel of the team
+func (r *notificationChannelRepository) Delete(ctx context.Context, id int) error {
+	query := `DELETE FROM notification_channels WHERE id = ? AND team_id = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, teamctx.GetTeamID(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete notification channel: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("notification channel with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// encodeEvents encodes a list of notification or webhook events for storage
+func encodeEvents(events []string) (string, error) {
+	if events == nil {
+		events = []string{}
+	}
+	encoded, err := json.Marshal(events)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode events: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// scanNotificationChannel scans a notification channel row
+func scanNotificationChannel(row rowScanner) (*models.NotificationChannel, error) {
+	var channel models.NotificationChannel
+	var events string
+	var createdBy, modifiedBy sql.NullString
+	var modifiedAt sql.NullTime
+
+	err := row.Scan(
+		&channel.ID,
+		&channel.Name,
+		&channel.Kind,
+		&channel.Target,
+		&events,
+		&channel.Active,
+		&createdBy,
+		&modifiedBy,
+		&modifiedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan notification channel: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(events), &channel.Events); err != nil {
+		return nil, fmt.Errorf("failed to parse notification events: %w", err)
+	}
+
+	// Convert NULL values to empty string/nil
+	if createdBy.Valid {
+		channel.CreatedBy = createdBy.String
+	}
+	if modifiedBy.Valid {
+		channel.ModifiedBy = modifiedBy.String
+	}
+	if modifiedAt.Valid {
+		channel.ModifiedAt = &modifiedAt.Time
+	}
+
+	return &channel, nil
+}
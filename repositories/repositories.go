@@ -6,18 +6,30 @@ import (
 
 // Repositories struct holds all repository interfaces
 type Repositories struct {
-	Team         TeamRepository
-	WorkingHours WorkingHoursRepository
-	Schedule     ScheduleRepository
-	Audit        AuditRepository
+	Team                 TeamRepository
+	WorkingHours         WorkingHoursRepository
+	Schedule             ScheduleRepository
+	Audit                AuditRepository
+	TimeOff              TimeOffRepository
+	Holiday              HolidayRepository
+	Teams                TeamsRepository
+	Transactor           Transactor
+	NotificationChannels NotificationChannelRepository
+	Webhooks             WebhookRepository
 }
 
 // NewRepositories creates and initializes all repositories
 func NewRepositories(db *sql.DB) *Repositories {
 	return &Repositories{
-		Team:         NewTeamRepository(db),
-		WorkingHours: NewWorkingHoursRepository(db),
-		Schedule:     NewScheduleRepository(db),
-		Audit:        NewAuditRepository(db),
+		Team:                 NewTeamRepository(db),
+		WorkingHours:         NewWorkingHoursRepository(db),
+		Schedule:             NewScheduleRepository(db),
+		Audit:                NewAuditRepository(db),
+		TimeOff:              NewTimeOffRepository(db),
+		Holiday:              NewHolidayRepository(db),
+		Teams:                NewTeamsRepository(db),
+		Transactor:           NewTransactor(db),
+		NotificationChannels: NewNotificationChannelRepository(db),
+		Webhooks:             NewWebhookRepository(db),
 	}
 }
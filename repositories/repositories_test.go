@@ -3,16 +3,18 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"fmt"
 	"os"
 	"testing"
 	"time"
 
 	"github.com/blogem/eod-scheduler/database"
 	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
 	_ "github.com/mattn/go-sqlite3"
 )
 
-func setupTestDB(t *testing.T) *sql.DB {
+func setupTestDB(t testing.TB) *sql.DB {
 	// Create a temporary database for testing
 	dbPath := "test_" + time.Now().Format("20060102150405") + ".db"
 
@@ -96,6 +98,55 @@ func TestTeamRepository(t *testing.T) {
 		t.Errorf("Expected updated name 'Updated Name', got %s", updated.Name)
 	}
 
+	if updated.Participation != models.FullParticipation || updated.WorkDays != nil || updated.Preferences != nil {
+		t.Errorf("Expected full participation on every day, got %d%% on %v", updated.Participation, updated.WorkDays)
+	}
+
+	// Test Update of part-time settings
+	member.Participation = 60
+	member.WorkDays = []int{0, 1, 3}
+	member.Preferences = []models.WeekdayPreference{
+		{DayOfWeek: 0, Kind: models.PreferenceKindAvoid, Strength: models.PreferenceStrengthHard},
+		{DayOfWeek: 3, Kind: models.PreferenceKindPrefer, Strength: models.PreferenceStrengthSoft},
+	}
+	err = repo.Update(ctx, member)
+	if err != nil {
+		t.Fatalf("Failed to update part-time settings: %v", err)
+	}
+
+	partTime, err := repo.GetByID(ctx, member.ID)
+	if err != nil {
+		t.Fatalf("Failed to get part-time team member: %v", err)
+	}
+
+	if partTime.Participation != 60 || len(partTime.WorkDays) != 3 || partTime.WorkDays[2] != 3 {
+		t.Errorf("Expected 60%% on days [0 1 3], got %d%% on %v", partTime.Participation, partTime.WorkDays)
+	}
+
+	if len(partTime.Preferences) != 2 || partTime.Preferences[0] != member.Preferences[0] || partTime.Preferences[1] != member.Preferences[1] {
+		t.Errorf("Expected preferences %v, got %v", member.Preferences, partTime.Preferences)
+	}
+
+	if partTime.Email != "" || partTime.MutedNotifications != nil {
+		t.Errorf("Expected no notification preferences, got %q muting %v", partTime.Email, partTime.MutedNotifications)
+	}
+
+	// Test Update of notification preferences
+	member.Email = "test.user@example.com"
+	member.MutedNotifications = []string{models.NotificationRegenerated}
+	if err := repo.Update(ctx, member); err != nil {
+		t.Fatalf("Failed to update notification preferences: %v", err)
+	}
+
+	notified, err := repo.GetByID(ctx, member.ID)
+	if err != nil {
+		t.Fatalf("Failed to get notified team member: %v", err)
+	}
+
+	if notified.Email != member.Email || len(notified.MutedNotifications) != 1 || notified.WantsNotification(models.NotificationRegenerated) {
+		t.Errorf("Expected %s muting regenerations, got %q muting %v", member.Email, notified.Email, notified.MutedNotifications)
+	}
+
 	// Test Count
 	count, err := repo.Count(ctx)
 	if err != nil {
@@ -135,13 +186,13 @@ func TestWorkingHoursRepository(t *testing.T) {
 	}
 
 	// Test GetByDay
-	monday, err := repo.GetByDay(ctx, 0) // Monday
+	monday, err := repo.GetByDay(ctx, 0, time.Now()) // Monday
 	if err != nil {
 		t.Fatalf("Failed to get Monday working hours: %v", err)
 	}
 
-	if monday.StartTime != "09:00" || monday.EndTime != "17:00" {
-		t.Errorf("Expected Monday 09:00-17:00, got %s-%s", monday.StartTime, monday.EndTime)
+	if len(monday) != 1 || monday[0].StartTime != "09:00" || monday[0].EndTime != "17:00" {
+		t.Errorf("Expected a single Monday shift 09:00-17:00, got %+v", monday)
 	}
 
 	// Test GetActiveDays
@@ -154,20 +205,114 @@ func TestWorkingHoursRepository(t *testing.T) {
 		t.Errorf("Expected 5 active days, got %d", len(activeDays))
 	}
 
-	// Test UpdateByDay
-	err = repo.UpdateByDay(ctx, 0, "08:00", "16:00", true)
-	if err != nil {
+	// Test Update
+	monday[0].StartTime = "08:00"
+	monday[0].EndTime = "16:00"
+	if err := repo.Update(ctx, &monday[0]); err != nil {
 		t.Fatalf("Failed to update Monday working hours: %v", err)
 	}
 
 	// Verify update
-	updated, err := repo.GetByDay(ctx, 0)
+	updated, err := repo.GetByDay(ctx, 0, time.Now())
 	if err != nil {
 		t.Fatalf("Failed to get updated Monday working hours: %v", err)
 	}
 
-	if updated.StartTime != "08:00" || updated.EndTime != "16:00" {
-		t.Errorf("Expected updated Monday 08:00-16:00, got %s-%s", updated.StartTime, updated.EndTime)
+	if updated[0].StartTime != "08:00" || updated[0].EndTime != "16:00" {
+		t.Errorf("Expected updated Monday 08:00-16:00, got %s-%s", updated[0].StartTime, updated[0].EndTime)
+	}
+
+	// Test ReplaceDay - split Tuesday into two shifts, returned in order of start time
+	shifts := []models.WorkingHours{
+		{Name: "Afternoon", StartTime: "13:00", EndTime: "18:00", Active: true},
+		{Name: "Morning", StartTime: "08:00", EndTime: "13:00", Active: true},
+	}
+	if err := repo.ReplaceDay(ctx, time.Time{}, 1, shifts); err != nil {
+		t.Fatalf("Failed to replace Tuesday working hours: %v", err)
+	}
+
+	if shifts[0].ID == 0 || shifts[0].DayOfWeek != 1 {
+		t.Errorf("Expected created shifts to get an ID and day, got %+v", shifts[0])
+	}
+
+	tuesday, err := repo.GetByDay(ctx, 1, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to get Tuesday working hours: %v", err)
+	}
+
+	if len(tuesday) != 2 || tuesday[0].Name != "Morning" || tuesday[1].Name != "Afternoon" {
+		t.Errorf("Expected Morning and Afternoon shifts on Tuesday, got %+v", tuesday)
+	}
+
+	activeDays, err = repo.GetActiveDays(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get active days: %v", err)
+	}
+
+	if len(activeDays) != 6 {
+		t.Errorf("Expected 6 active shifts, got %d", len(activeDays))
+	}
+
+	// Active shifts of a day need unique names
+	duplicates := []models.WorkingHours{
+		{Name: "Morning", StartTime: "08:00", EndTime: "13:00", Active: true},
+		{Name: "Morning", StartTime: "13:00", EndTime: "18:00", Active: true},
+	}
+	if err := repo.ReplaceDay(ctx, time.Time{}, 1, duplicates); err == nil {
+		t.Error("Expected error when replacing a day with duplicate shift names")
+	}
+
+	// A failed replacement leaves the day as it was
+	tuesday, err = repo.GetByDay(ctx, 1, time.Now())
+	if err != nil || len(tuesday) != 2 {
+		t.Errorf("Expected Tuesday to keep its 2 shifts, got %+v (%v)", tuesday, err)
+	}
+
+	// A version with an effective date applies from that date on, the day is off from June 1
+	june := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	off := []models.WorkingHours{{StartTime: "00:00", EndTime: "00:00", Active: false}}
+	if err := repo.ReplaceDay(ctx, june, 0, off); err != nil {
+		t.Fatalf("Failed to add a Monday version: %v", err)
+	}
+
+	before, err := repo.GetByDay(ctx, 0, june.AddDate(0, 0, -1))
+	if err != nil || len(before) != 1 || before[0].StartTime != "08:00" || !before[0].EffectiveFrom.IsZero() {
+		t.Errorf("Expected the initial Monday shift before June 1, got %+v (%v)", before, err)
+	}
+
+	after, err := repo.GetByDay(ctx, 0, june.AddDate(0, 0, 6))
+	if err != nil || len(after) != 1 || after[0].Active || !after[0].EffectiveFrom.Equal(june) {
+		t.Errorf("Expected Monday to be off from June 1, got %+v (%v)", after, err)
+	}
+
+	// The dated day off ends the shifts of the version before it, so it's among the active days
+	activeDays, err = repo.GetActiveDays(ctx)
+	if err != nil || len(activeDays) != 7 {
+		t.Errorf("Expected 6 active shifts and the day off, got %d (%v)", len(activeDays), err)
+	}
+
+	// Replacing the initial configuration keeps the upcoming version
+	if err := repo.ReplaceDay(ctx, time.Time{}, 0, []models.WorkingHours{{StartTime: "07:00", EndTime: "15:00", Active: true}}); err != nil {
+		t.Fatalf("Failed to replace the initial Monday: %v", err)
+	}
+
+	hours, err = repo.GetAll(ctx)
+	if err != nil || len(hours) != 9 {
+		t.Errorf("Expected 9 rows across both versions, got %d (%v)", len(hours), err)
+	}
+
+	// Cancelling the change restores the version before it
+	if err := repo.DeleteChange(ctx, june); err != nil {
+		t.Fatalf("Failed to delete the June change: %v", err)
+	}
+
+	after, err = repo.GetByDay(ctx, 0, june.AddDate(0, 0, 6))
+	if err != nil || len(after) != 1 || after[0].StartTime != "07:00" {
+		t.Errorf("Expected the initial Monday shift after cancelling, got %+v (%v)", after, err)
+	}
+
+	if err := repo.DeleteChange(ctx, june); err == nil {
+		t.Error("Expected error when deleting a change that doesn't exist")
 	}
 }
 
@@ -195,6 +340,7 @@ func TestScheduleRepository(t *testing.T) {
 		TeamMemberID:     member.ID,
 		StartTime:        "09:00",
 		EndTime:          "17:00",
+		Shift:            "Morning",
 		IsManualOverride: false,
 	}
 
@@ -217,14 +363,70 @@ func TestScheduleRepository(t *testing.T) {
 		t.Errorf("Expected team member name %s, got %s", member.Name, retrieved.TeamMemberName)
 	}
 
+	if retrieved.Shift != "Morning" {
+		t.Errorf("Expected shift Morning, got %q", retrieved.Shift)
+	}
+
+	if retrieved.Role != models.ScheduleRolePrimary {
+		t.Errorf("Expected role %s for an entry without a role, got %q", models.ScheduleRolePrimary, retrieved.Role)
+	}
+
+	// Test a backup for the same shift with the same hours
+	backup := &models.ScheduleEntry{
+		Date:         tomorrow,
+		TeamMemberID: member.ID,
+		StartTime:    "09:00",
+		EndTime:      "17:00",
+		Shift:        "Morning",
+		Role:         models.ScheduleRoleBackup,
+	}
+	if err := scheduleRepo.Create(ctx, backup); err != nil {
+		t.Fatalf("Failed to create backup entry: %v", err)
+	}
+
 	// Test GetByDateRange
 	entries, err := scheduleRepo.GetByDateRange(ctx, tomorrow, tomorrow)
 	if err != nil {
 		t.Fatalf("Failed to get schedule entries by date range: %v", err)
 	}
 
-	if len(entries) != 1 {
-		t.Errorf("Expected 1 schedule entry, got %d", len(entries))
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 schedule entries, got %d", len(entries))
+	}
+
+	if entries[0].Role != models.ScheduleRolePrimary || entries[1].Role != models.ScheduleRoleBackup {
+		t.Errorf("Expected the primary before the backup, got %s and %s", entries[0].Role, entries[1].Role)
+	}
+
+	if entries[0].Layer != models.ScheduleLayerWorkingHours || entries[0].EndDate != nil {
+		t.Errorf("Expected a working hours entry without end date, got %q ending %v", entries[0].Layer, entries[0].EndDate)
+	}
+
+	// Test an on-call block ending three days later
+	blockEnd := tomorrow.AddDate(0, 0, 3)
+	onCall := &models.ScheduleEntry{
+		Date:         tomorrow,
+		TeamMemberID: member.ID,
+		StartTime:    "17:00",
+		EndTime:      "09:00",
+		Layer:        models.ScheduleLayerOnCall,
+		EndDate:      &blockEnd,
+	}
+	if err := scheduleRepo.Create(ctx, onCall); err != nil {
+		t.Fatalf("Failed to create on-call entry: %v", err)
+	}
+
+	retrieved, err = scheduleRepo.GetByID(ctx, onCall.ID)
+	if err != nil {
+		t.Fatalf("Failed to get on-call entry by ID: %v", err)
+	}
+
+	if !retrieved.IsOnCall() || retrieved.EndDate == nil || retrieved.EndDate.Format("2006-01-02") != blockEnd.Format("2006-01-02") {
+		t.Errorf("Expected an on-call entry ending %s, got %q ending %v", blockEnd.Format("2006-01-02"), retrieved.Layer, retrieved.EndDate)
+	}
+
+	if err := scheduleRepo.Delete(ctx, onCall.ID); err != nil {
+		t.Fatalf("Failed to delete on-call entry: %v", err)
 	}
 
 	// Test GetState
@@ -233,13 +435,61 @@ func TestScheduleRepository(t *testing.T) {
 		t.Fatalf("Failed to get schedule state: %v", err)
 	}
 
-	if state.ID != 1 {
-		t.Errorf("Expected state ID 1, got %d", state.ID)
+	if state.TeamID != 1 {
+		t.Errorf("Expected state team ID 1, got %d", state.TeamID)
+	}
+
+	if state.RotationStrategy != models.RotationStrategyEpochModulo {
+		t.Errorf("Expected default rotation strategy %s, got %s", models.RotationStrategyEpochModulo, state.RotationStrategy)
+	}
+
+	if state.RotationSeed != 0 {
+		t.Errorf("Expected default rotation seed 0, got %d", state.RotationSeed)
+	}
+
+	if len(state.RotationQueue) != 0 || !state.RotationCursorDate.IsZero() {
+		t.Errorf("Expected an uninitialized rotation queue, got %v from %v", state.RotationQueue, state.RotationCursorDate)
+	}
+
+	if state.RotationPeriod != models.RotationPeriodDaily {
+		t.Errorf("Expected default rotation period %s, got %s", models.RotationPeriodDaily, state.RotationPeriod)
+	}
+
+	if len(state.ShiftCursors) != 0 {
+		t.Errorf("Expected no shift cursors, got %v", state.ShiftCursors)
+	}
+
+	if state.DescribeHorizon() != "3 months" || state.UpToDateDays != 7 || state.LastRun.HasRun() {
+		t.Errorf("Expected a horizon of 3 months up to date for 7 days without runs, got %s, %d and %v", state.DescribeHorizon(), state.UpToDateDays, state.LastRun)
 	}
 
-	// Test UpdateState - update the generation date
+	// Test UpdateState - update the generation date, strategy and seed
 	newDate := time.Now().AddDate(0, 0, 1)
 	state.LastGenerationDate = newDate
+	state.RotationStrategy = models.RotationStrategyFairShare
+	state.RotationSeed = 42
+	state.RotationQueue = []int{3, 1, 2}
+	state.RotationCursor = 2
+	state.RotationCursorDate = time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	state.ShiftCursors = map[string]int{"Morning": 1, "Afternoon": 0}
+	state.RotationPeriod = models.RotationPeriodWeekly
+	state.HandoverDay = 2
+	state.BackupEnabled = true
+	state.OnCallEnabled = true
+	state.OnCallCursor = 1
+	state.MinGapDays = 2
+	state.MaxShiftsPerWeek = 2
+	state.MaxShiftsPerMonth = 6
+	state.HorizonLength = 6
+	state.HorizonUnit = models.HorizonUnitWeeks
+	state.UpToDateDays = 1
+	state.GeneratedUntil = time.Date(2025, 2, 17, 0, 0, 0, 0, time.UTC)
+	state.LastRun = models.GenerationRun{
+		At:      time.Date(2025, 1, 6, 2, 0, 0, 0, time.UTC),
+		Trigger: models.GenerationTriggerAutomatic,
+		Success: true,
+		Message: "Extended the schedule with 5 entries",
+	}
 	err = scheduleRepo.UpdateState(ctx, state)
 	if err != nil {
 		t.Fatalf("Failed to update schedule state: %v", err)
@@ -257,4 +507,900 @@ func TestScheduleRepository(t *testing.T) {
 	if actualDate != expectedDate {
 		t.Errorf("Expected updated last generation date %s, got %s", expectedDate, actualDate)
 	}
+
+	if updatedState.RotationStrategy != models.RotationStrategyFairShare {
+		t.Errorf("Expected rotation strategy %s, got %s", models.RotationStrategyFairShare, updatedState.RotationStrategy)
+	}
+
+	if updatedState.RotationSeed != 42 {
+		t.Errorf("Expected rotation seed 42, got %d", updatedState.RotationSeed)
+	}
+
+	if fmt.Sprint(updatedState.RotationQueue) != "[3 1 2]" || updatedState.RotationCursor != 2 {
+		t.Errorf("Expected rotation queue [3 1 2] with cursor 2, got %v with cursor %d", updatedState.RotationQueue, updatedState.RotationCursor)
+	}
+
+	if updatedState.RotationCursorDate.Format("2006-01-02") != "2025-01-06" {
+		t.Errorf("Expected rotation cursor date 2025-01-06, got %s", updatedState.RotationCursorDate.Format("2006-01-02"))
+	}
+
+	if updatedState.ShiftCursors["Morning"] != 1 || updatedState.ShiftCursors["Afternoon"] != 0 || len(updatedState.ShiftCursors) != 2 {
+		t.Errorf("Expected shift cursors Morning 1 and Afternoon 0, got %v", updatedState.ShiftCursors)
+	}
+
+	if updatedState.RotationPeriod != models.RotationPeriodWeekly || updatedState.HandoverDay != 2 {
+		t.Errorf("Expected a weekly rotation handing over on day 2, got %s on day %d", updatedState.RotationPeriod, updatedState.HandoverDay)
+	}
+
+	if !updatedState.BackupEnabled {
+		t.Error("Expected backups to be enabled")
+	}
+
+	if !updatedState.OnCallEnabled || updatedState.OnCallCursor != 1 {
+		t.Errorf("Expected on-call coverage enabled with cursor 1, got %v with cursor %d", updatedState.OnCallEnabled, updatedState.OnCallCursor)
+	}
+
+	if updatedState.MinGapDays != 2 || updatedState.MaxShiftsPerWeek != 2 || updatedState.MaxShiftsPerMonth != 6 {
+		t.Errorf("Expected a gap of 2 days and at most 2 shifts a week and 6 a month, got %d, %d and %d",
+			updatedState.MinGapDays, updatedState.MaxShiftsPerWeek, updatedState.MaxShiftsPerMonth)
+	}
+
+	if updatedState.DescribeHorizon() != "6 weeks" || updatedState.UpToDateDays != 1 || updatedState.GeneratedUntil.Format("2006-01-02") != "2025-02-17" {
+		t.Errorf("Expected a horizon of 6 weeks up to date for 1 day, generated until 2025-02-17, got %s, %d and %v",
+			updatedState.DescribeHorizon(), updatedState.UpToDateDays, updatedState.GeneratedUntil)
+	}
+
+	if !updatedState.LastRun.At.Equal(state.LastRun.At) || updatedState.LastRun.Trigger != models.GenerationTriggerAutomatic ||
+		!updatedState.LastRun.Success || updatedState.LastRun.Message != state.LastRun.Message {
+		t.Errorf("Expected the last run to round trip, got %+v", updatedState.LastRun)
+	}
+}
+
+func TestScheduleRepositoryBatches(t *testing.T) {
+	db := setupTestDB(t)
+	scheduleRepo := NewScheduleRepository(db)
+	teamRepo := NewTeamRepository(db)
+	ctx := context.Background()
+
+	member := &models.TeamMember{Name: "Test User", Active: true}
+	if err := teamRepo.Create(ctx, member); err != nil {
+		t.Fatalf("Failed to create test team member: %v", err)
+	}
+
+	// More entries than fit in one delete statement
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	entries := make([]*models.ScheduleEntry, maxBatchDeleteIDs+20)
+	for i := range entries {
+		entries[i] = &models.ScheduleEntry{Date: start.AddDate(0, 0, i), TeamMemberID: member.ID, StartTime: "09:00", EndTime: "17:00"}
+	}
+	if err := scheduleRepo.CreateBatch(ctx, entries); err != nil {
+		t.Fatalf("Failed to create schedule entries: %v", err)
+	}
+
+	end := start.AddDate(0, 0, len(entries))
+	stored, err := scheduleRepo.GetByDateRange(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get schedule entries: %v", err)
+	}
+	if len(stored) != len(entries) {
+		t.Fatalf("Expected %d entries, got %d", len(entries), len(stored))
+	}
+	for i, entry := range entries {
+		if entry.ID == 0 || stored[i].ID != entry.ID {
+			t.Fatalf("Expected entry %d to have the stored ID %d, got %d", i, stored[i].ID, entry.ID)
+		}
+	}
+
+	// Everything but the first entry is deleted, unknown IDs are ignored
+	var ids []int
+	for _, entry := range entries[1:] {
+		ids = append(ids, entry.ID)
+	}
+	if err := scheduleRepo.DeleteBatch(ctx, append(ids, 999999)); err != nil {
+		t.Fatalf("Failed to delete schedule entries: %v", err)
+	}
+	stored, err = scheduleRepo.GetByDateRange(ctx, start, end)
+	if err != nil {
+		t.Fatalf("Failed to get schedule entries: %v", err)
+	}
+	if len(stored) != 1 || stored[0].ID != entries[0].ID {
+		t.Errorf("Expected only the first entry to remain, got %d entries", len(stored))
+	}
+
+	// A batch with an invalid entry creates nothing
+	invalid := []*models.ScheduleEntry{
+		{Date: end, TeamMemberID: member.ID, StartTime: "09:00", EndTime: "17:00"},
+		{Date: end, TeamMemberID: 999999, StartTime: "09:00", EndTime: "17:00"},
+	}
+	if err := scheduleRepo.CreateBatch(ctx, invalid); err == nil {
+		t.Error("Expected an error for an entry of an unknown member")
+	}
+	if stored, _ := scheduleRepo.GetByDate(ctx, end); len(stored) != 0 {
+		t.Errorf("Expected the batch to be rolled back, got %d entries", len(stored))
+	}
+}
+
+// BenchmarkScheduleRepositoryCreate compares creating a year of entries one by one and in a batch
+func BenchmarkScheduleRepositoryCreate(b *testing.B) {
+	db := setupTestDB(b)
+	scheduleRepo := NewScheduleRepository(db)
+	ctx := context.Background()
+
+	member := &models.TeamMember{Name: "Test User", Active: true}
+	if err := NewTeamRepository(db).Create(ctx, member); err != nil {
+		b.Fatalf("Failed to create test team member: %v", err)
+	}
+
+	start := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	year := func() []*models.ScheduleEntry {
+		entries := make([]*models.ScheduleEntry, 260)
+		for i := range entries {
+			entries[i] = &models.ScheduleEntry{Date: start.AddDate(0, 0, i), TeamMemberID: member.ID, StartTime: "09:00", EndTime: "17:00"}
+		}
+		return entries
+	}
+	deleteYear := func() {
+		if err := scheduleRepo.DeleteByDateRange(ctx, start, start.AddDate(1, 0, 0)); err != nil {
+			b.Fatalf("Failed to delete schedule entries: %v", err)
+		}
+	}
+
+	b.Run("one_by_one", func(b *testing.B) {
+		for b.Loop() {
+			for _, entry := range year() {
+				if err := scheduleRepo.Create(ctx, entry); err != nil {
+					b.Fatalf("Failed to create schedule entry: %v", err)
+				}
+			}
+			b.StopTimer()
+			deleteYear()
+			b.StartTimer()
+		}
+	})
+
+	b.Run("batch", func(b *testing.B) {
+		for b.Loop() {
+			if err := scheduleRepo.CreateBatch(ctx, year()); err != nil {
+				b.Fatalf("Failed to create schedule entries: %v", err)
+			}
+			b.StopTimer()
+			deleteYear()
+			b.StartTimer()
+		}
+	})
+}
+
+func TestTimeOffRepository(t *testing.T) {
+	db := setupTestDB(t)
+	timeOffRepo := NewTimeOffRepository(db)
+	teamRepo := NewTeamRepository(db)
+	ctx := context.Background()
+
+	member := &models.TeamMember{Name: "Test User", SlackHandle: "@test.user", Active: true}
+	if err := teamRepo.Create(ctx, member); err != nil {
+		t.Fatalf("Failed to create test team member: %v", err)
+	}
+
+	// Test Create
+	timeOff := &models.TimeOff{
+		TeamMemberID: member.ID,
+		StartDate:    time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+		EndDate:      time.Date(2025, 7, 18, 0, 0, 0, 0, time.UTC),
+		Reason:       "Summer holiday",
+	}
+	if err := timeOffRepo.Create(ctx, timeOff); err != nil {
+		t.Fatalf("Failed to create time off: %v", err)
+	}
+
+	if timeOff.ID == 0 {
+		t.Error("Expected time off ID to be set after creation")
+	}
+
+	// Test GetByID
+	retrieved, err := timeOffRepo.GetByID(ctx, timeOff.ID)
+	if err != nil {
+		t.Fatalf("Failed to get time off by ID: %v", err)
+	}
+
+	if retrieved.Reason != "Summer holiday" || retrieved.TeamMemberName != member.Name {
+		t.Errorf("Expected time off of %s for Summer holiday, got %+v", member.Name, retrieved)
+	}
+
+	if retrieved.GetFormattedStartDate() != "2025-07-14" || retrieved.GetFormattedEndDate() != "2025-07-18" {
+		t.Errorf("Expected time off from 2025-07-14 to 2025-07-18, got %s to %s", retrieved.GetFormattedStartDate(), retrieved.GetFormattedEndDate())
+	}
+
+	// Test GetByTeamMember
+	periods, err := timeOffRepo.GetByTeamMember(ctx, member.ID)
+	if err != nil {
+		t.Fatalf("Failed to get time off by team member: %v", err)
+	}
+
+	if len(periods) != 1 {
+		t.Errorf("Expected 1 time off period, got %d", len(periods))
+	}
+
+	// Test GetByDateRange - only periods overlapping the range are returned
+	rangeTests := []struct {
+		from, to string
+		expected int
+	}{
+		{"2025-07-01", "2025-07-13", 0},
+		{"2025-07-01", "2025-07-14", 1},
+		{"2025-07-16", "2025-07-16", 1},
+		{"2025-07-18", "2025-07-31", 1},
+		{"2025-07-19", "2025-07-31", 0},
+	}
+	for _, rt := range rangeTests {
+		from, _ := models.ParseDate(rt.from)
+		to, _ := models.ParseDate(rt.to)
+
+		periods, err := timeOffRepo.GetByDateRange(ctx, from, to)
+		if err != nil {
+			t.Fatalf("Failed to get time off by date range: %v", err)
+		}
+
+		if len(periods) != rt.expected {
+			t.Errorf("Expected %d time off periods between %s and %s, got %d", rt.expected, rt.from, rt.to, len(periods))
+		}
+	}
+
+	// Test Delete
+	if err := timeOffRepo.Delete(ctx, timeOff.ID); err != nil {
+		t.Fatalf("Failed to delete time off: %v", err)
+	}
+
+	if _, err := timeOffRepo.GetByID(ctx, timeOff.ID); err == nil {
+		t.Error("Expected error when getting deleted time off")
+	}
+}
+
+func TestWorkingHoursExceptions(t *testing.T) {
+	db := setupTestDB(t)
+	repo := NewWorkingHoursRepository(db)
+	ctx := context.Background()
+
+	// Test SaveException
+	saturday := &models.WorkingHoursException{
+		Date:      time.Date(2026, 12, 26, 0, 0, 0, 0, time.UTC),
+		Kind:      models.ExceptionKindWorking,
+		StartTime: "10:00",
+		EndTime:   "14:00",
+		Reason:    "Release",
+	}
+	if err := repo.SaveException(ctx, saturday); err != nil {
+		t.Fatalf("Failed to save exception: %v", err)
+	}
+	if saturday.ID == 0 {
+		t.Error("Expected exception ID to be set after saving")
+	}
+
+	eve := &models.WorkingHoursException{
+		Date:      time.Date(2026, 12, 24, 0, 0, 0, 0, time.UTC),
+		Kind:      models.ExceptionKindHours,
+		StartTime: "09:00",
+		EndTime:   "13:00",
+	}
+	if err := repo.SaveException(ctx, eve); err != nil {
+		t.Fatalf("Failed to save exception: %v", err)
+	}
+
+	// Test GetExceptions - ordered by date
+	exceptions, err := repo.GetExceptions(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get exceptions: %v", err)
+	}
+	if len(exceptions) != 2 || exceptions[0].GetFormattedDate() != "2026-12-24" || exceptions[1].Reason != "Release" {
+		t.Errorf("Expected Christmas Eve before the release day, got %+v", exceptions)
+	}
+
+	// Saving another exception for a date replaces the one it has
+	dayOff := &models.WorkingHoursException{Date: eve.Date, Kind: models.ExceptionKindNonWorking, Reason: "Office closed"}
+	if err := repo.SaveException(ctx, dayOff); err != nil {
+		t.Fatalf("Failed to replace exception: %v", err)
+	}
+	if dayOff.ID != eve.ID {
+		t.Errorf("Expected the replacement to keep ID %d, got %d", eve.ID, dayOff.ID)
+	}
+
+	exceptions, err = repo.GetExceptions(ctx)
+	if err != nil || len(exceptions) != 2 || exceptions[0].Kind != models.ExceptionKindNonWorking || exceptions[0].Reason != "Office closed" {
+		t.Errorf("Expected Christmas Eve to be a non-working day, got %+v (%v)", exceptions, err)
+	}
+
+	// Exceptions belong to their team
+	other := teamctx.SetTeam(ctx, &models.Team{ID: 2})
+	if exceptions, err := repo.GetExceptions(other); err != nil || len(exceptions) != 0 {
+		t.Errorf("Expected no exceptions for another team, got %+v (%v)", exceptions, err)
+	}
+	if err := repo.DeleteException(other, saturday.ID); err == nil {
+		t.Error("Expected error when deleting the exception of another team")
+	}
+
+	// Test DeleteException
+	if err := repo.DeleteException(ctx, saturday.ID); err != nil {
+		t.Fatalf("Failed to delete exception: %v", err)
+	}
+	if exceptions, err := repo.GetExceptions(ctx); err != nil || len(exceptions) != 1 {
+		t.Errorf("Expected 1 exception after deleting, got %+v (%v)", exceptions, err)
+	}
+	if err := repo.DeleteException(ctx, saturday.ID); err == nil {
+		t.Error("Expected error when deleting a missing exception")
+	}
+}
+
+func TestHolidayRepository(t *testing.T) {
+	db := setupTestDB(t)
+	holidayRepo := NewHolidayRepository(db)
+	ctx := context.Background()
+
+	// Test Create
+	christmas := &models.Holiday{
+		Date:      time.Date(2025, 12, 25, 0, 0, 0, 0, time.UTC),
+		Name:      "Christmas Day",
+		Recurring: true,
+		Behavior:  models.HolidayBehaviorNoDuty,
+	}
+	if err := holidayRepo.Create(ctx, christmas); err != nil {
+		t.Fatalf("Failed to create holiday: %v", err)
+	}
+
+	if christmas.ID == 0 {
+		t.Error("Expected holiday ID to be set after creation")
+	}
+
+	eve := &models.Holiday{
+		Date:      time.Date(2025, 12, 24, 0, 0, 0, 0, time.UTC),
+		Name:      "Christmas Eve",
+		Behavior:  models.HolidayBehaviorAlternativeHours,
+		StartTime: "09:00",
+		EndTime:   "13:00",
+	}
+	if err := holidayRepo.Create(ctx, eve); err != nil {
+		t.Fatalf("Failed to create holiday: %v", err)
+	}
+
+	// Test GetByID
+	retrieved, err := holidayRepo.GetByID(ctx, eve.ID)
+	if err != nil {
+		t.Fatalf("Failed to get holiday by ID: %v", err)
+	}
+
+	if retrieved.Name != "Christmas Eve" || retrieved.Recurring || retrieved.GetFormattedDate() != "2025-12-24" {
+		t.Errorf("Expected one-off Christmas Eve on 2025-12-24, got %+v", retrieved)
+	}
+
+	if retrieved.Behavior != models.HolidayBehaviorAlternativeHours || retrieved.StartTime != "09:00" || retrieved.EndTime != "13:00" {
+		t.Errorf("Expected alternative hours 09:00 - 13:00, got %s %s - %s", retrieved.Behavior, retrieved.StartTime, retrieved.EndTime)
+	}
+
+	// Test GetAll - recurring holidays come first
+	holidays, err := holidayRepo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get all holidays: %v", err)
+	}
+
+	if len(holidays) != 2 || holidays[0].Name != "Christmas Day" || !holidays[0].Recurring {
+		t.Errorf("Expected recurring Christmas Day first of 2 holidays, got %+v", holidays)
+	}
+
+	// Test Update
+	eve.Behavior = models.HolidayBehaviorNoDuty
+	eve.StartTime = ""
+	eve.EndTime = ""
+	if err := holidayRepo.Update(ctx, eve); err != nil {
+		t.Fatalf("Failed to update holiday: %v", err)
+	}
+
+	updated, err := holidayRepo.GetByID(ctx, eve.ID)
+	if err != nil {
+		t.Fatalf("Failed to get updated holiday: %v", err)
+	}
+
+	if !updated.IsDutyFree() {
+		t.Errorf("Expected updated holiday to be duty-free, got %s", updated.Behavior)
+	}
+
+	// Test Delete
+	if err := holidayRepo.Delete(ctx, eve.ID); err != nil {
+		t.Fatalf("Failed to delete holiday: %v", err)
+	}
+
+	if _, err := holidayRepo.GetByID(ctx, eve.ID); err == nil {
+		t.Error("Expected error when getting deleted holiday")
+	}
+
+	if err := holidayRepo.Delete(ctx, eve.ID); err == nil {
+		t.Error("Expected error when deleting a missing holiday")
+	}
+}
+
+func TestNotificationChannelRepository(t *testing.T) {
+	db := setupTestDB(t)
+	channelRepo := NewNotificationChannelRepository(db)
+	ctx := context.Background()
+
+	// Test Create
+	slack := &models.NotificationChannel{
+		Name:   "On-call channel",
+		Kind:   models.ChannelKindSlack,
+		Target: "https://hooks.slack.com/services/T0/B0/x",
+		Events: []string{models.NotificationShiftStartsSoon, models.NotificationTakeover},
+		Active: true,
+	}
+	if err := channelRepo.Create(ctx, slack); err != nil {
+		t.Fatalf("Failed to create notification channel: %v", err)
+	}
+
+	if slack.ID == 0 {
+		t.Error("Expected notification channel ID to be set after creation")
+	}
+
+	email := &models.NotificationChannel{
+		Name:   "Members",
+		Kind:   models.ChannelKindEmail,
+		Events: []string{models.NotificationRegenerated},
+	}
+	if err := channelRepo.Create(ctx, email); err != nil {
+		t.Fatalf("Failed to create notification channel: %v", err)
+	}
+
+	// Test GetByID
+	retrieved, err := channelRepo.GetByID(ctx, slack.ID)
+	if err != nil {
+		t.Fatalf("Failed to get notification channel by ID: %v", err)
+	}
+
+	if retrieved.Name != slack.Name || retrieved.Kind != models.ChannelKindSlack || retrieved.Target != slack.Target || !retrieved.Active {
+		t.Errorf("Expected active Slack channel %s, got %+v", slack.Target, retrieved)
+	}
+
+	if !retrieved.Subscribes(models.NotificationTakeover) || retrieved.Subscribes(models.NotificationRegenerated) {
+		t.Errorf("Expected events %v, got %v", slack.Events, retrieved.Events)
+	}
+
+	// Test GetAll - ordered by name
+	channels, err := channelRepo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get all notification channels: %v", err)
+	}
+
+	if len(channels) != 2 || channels[0].Name != "Members" || channels[0].Active {
+		t.Errorf("Expected inactive Members first of 2 channels, got %+v", channels)
+	}
+
+	// Test Update
+	email.Target = "team@example.com"
+	email.Active = true
+	if err := channelRepo.Update(ctx, email); err != nil {
+		t.Fatalf("Failed to update notification channel: %v", err)
+	}
+
+	updated, err := channelRepo.GetByID(ctx, email.ID)
+	if err != nil {
+		t.Fatalf("Failed to get updated notification channel: %v", err)
+	}
+
+	if updated.Target != "team@example.com" || !updated.Active {
+		t.Errorf("Expected active channel to team@example.com, got %+v", updated)
+	}
+
+	// Test Delete
+	if err := channelRepo.Delete(ctx, email.ID); err != nil {
+		t.Fatalf("Failed to delete notification channel: %v", err)
+	}
+
+	if _, err := channelRepo.GetByID(ctx, email.ID); err == nil {
+		t.Error("Expected error when getting deleted notification channel")
+	}
+
+	if err := channelRepo.Delete(ctx, email.ID); err == nil {
+		t.Error("Expected error when deleting a missing notification channel")
+	}
+}
+
+func TestWebhookRepository(t *testing.T) {
+	db := setupTestDB(t)
+	webhookRepo := NewWebhookRepository(db)
+	ctx := context.Background()
+
+	// Test CreateSubscription
+	paging := &models.WebhookSubscription{
+		Name:   "Paging",
+		URL:    "https://example.com/hooks/eod",
+		Secret: "0123456789abcdef",
+		Events: []string{models.WebhookEventOverrideCreated, models.WebhookEventMemberActivated},
+		Active: true,
+	}
+	if err := webhookRepo.CreateSubscription(ctx, paging); err != nil {
+		t.Fatalf("Failed to create webhook subscription: %v", err)
+	}
+
+	if paging.ID == 0 {
+		t.Error("Expected webhook subscription ID to be set after creation")
+	}
+
+	audit := &models.WebhookSubscription{
+		Name:   "Audit log",
+		URL:    "https://audit.example.com/events",
+		Secret: "fedcba9876543210",
+		Events: models.WebhookEvents,
+	}
+	if err := webhookRepo.CreateSubscription(ctx, audit); err != nil {
+		t.Fatalf("Failed to create webhook subscription: %v", err)
+	}
+
+	// Test GetSubscriptionByID
+	retrieved, err := webhookRepo.GetSubscriptionByID(ctx, paging.ID)
+	if err != nil {
+		t.Fatalf("Failed to get webhook subscription by ID: %v", err)
+	}
+
+	if retrieved.URL != paging.URL || retrieved.Secret != paging.Secret || !retrieved.Active {
+		t.Errorf("Expected active webhook to %s, got %+v", paging.URL, retrieved)
+	}
+
+	if !retrieved.Subscribes(models.WebhookEventOverrideCreated) || retrieved.Subscribes(models.WebhookEventEntryCreated) {
+		t.Errorf("Expected events %v, got %v", paging.Events, retrieved.Events)
+	}
+
+	// Test GetSubscriptions - ordered by name
+	subscriptions, err := webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get webhook subscriptions: %v", err)
+	}
+
+	if len(subscriptions) != 2 || subscriptions[0].Name != "Audit log" || subscriptions[0].Active {
+		t.Errorf("Expected inactive Audit log first of 2 webhooks, got %+v", subscriptions)
+	}
+
+	// Test UpdateSubscription
+	audit.Active = true
+	if err := webhookRepo.UpdateSubscription(ctx, audit); err != nil {
+		t.Fatalf("Failed to update webhook subscription: %v", err)
+	}
+
+	// Test CreateDeliveries - one event for both webhooks, the second queued later
+	now := time.Date(2023, 10, 2, 9, 0, 0, 0, time.Local)
+	deliveries := []*models.WebhookDelivery{
+		{SubscriptionID: paging.ID, EventID: "evt1", Event: models.WebhookEventOverrideCreated, Payload: `{"id":"evt1"}`, Status: models.WebhookDeliveryPending, NextAttemptAt: now, CreatedAt: now},
+		{SubscriptionID: audit.ID, EventID: "evt1", Event: models.WebhookEventOverrideCreated, Payload: `{"id":"evt1"}`, Status: models.WebhookDeliveryPending, NextAttemptAt: now.Add(time.Minute), CreatedAt: now},
+	}
+	if err := webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		t.Fatalf("Failed to create webhook deliveries: %v", err)
+	}
+
+	if deliveries[0].ID == 0 || deliveries[1].ID == 0 {
+		t.Error("Expected webhook delivery IDs to be set after creation")
+	}
+
+	// Test GetDueDeliveries - only what is due
+	due, err := webhookRepo.GetDueDeliveries(ctx, now.Add(30*time.Second), 10)
+	if err != nil {
+		t.Fatalf("Failed to get due webhook deliveries: %v", err)
+	}
+
+	if len(due) != 1 || due[0].ID != deliveries[0].ID || due[0].SubscriptionName != "Paging" || !due[0].NextAttemptAt.Equal(now) {
+		t.Errorf("Expected the delivery to Paging to be due, got %+v", due)
+	}
+
+	// Test UpdateDelivery
+	delivered := due[0]
+	delivered.Status = models.WebhookDeliveryDelivered
+	delivered.Attempts = 1
+	delivered.ResponseStatus = 204
+	delivered.LastAttemptAt = &now
+	delivered.DeliveredAt = &now
+	if err := webhookRepo.UpdateDelivery(ctx, &delivered); err != nil {
+		t.Fatalf("Failed to update webhook delivery: %v", err)
+	}
+
+	retrievedDelivery, err := webhookRepo.GetDeliveryByID(ctx, delivered.ID)
+	if err != nil {
+		t.Fatalf("Failed to get webhook delivery by ID: %v", err)
+	}
+
+	if retrievedDelivery.Status != models.WebhookDeliveryDelivered || retrievedDelivery.ResponseStatus != 204 ||
+		retrievedDelivery.DeliveredAt == nil || !retrievedDelivery.DeliveredAt.Equal(now) {
+		t.Errorf("Expected delivery delivered at %s, got %+v", now, retrievedDelivery)
+	}
+
+	// Deliveries of paused webhooks wait
+	audit.Active = false
+	if err := webhookRepo.UpdateSubscription(ctx, audit); err != nil {
+		t.Fatalf("Failed to update webhook subscription: %v", err)
+	}
+
+	due, err = webhookRepo.GetDueDeliveries(ctx, now.Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("Failed to get due webhook deliveries: %v", err)
+	}
+
+	if len(due) != 0 {
+		t.Errorf("Expected no due deliveries of a paused webhook, got %+v", due)
+	}
+
+	// Test GetDeliveries - newest first
+	logged, err := webhookRepo.GetDeliveries(ctx, 10)
+	if err != nil {
+		t.Fatalf("Failed to get webhook deliveries: %v", err)
+	}
+
+	if len(logged) != 2 || logged[0].ID != deliveries[1].ID {
+		t.Errorf("Expected 2 deliveries newest first, got %+v", logged)
+	}
+
+	// Test DeleteDeliveriesBefore - pending deliveries are kept
+	deleted, err := webhookRepo.DeleteDeliveriesBefore(ctx, now.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("Failed to delete webhook deliveries: %v", err)
+	}
+
+	if deleted != 1 {
+		t.Errorf("Expected 1 finished delivery to be deleted, got %d", deleted)
+	}
+
+	// Test DeleteSubscription - deletes its deliveries as well
+	if err := webhookRepo.DeleteSubscription(ctx, audit.ID); err != nil {
+		t.Fatalf("Failed to delete webhook subscription: %v", err)
+	}
+
+	if _, err := webhookRepo.GetDeliveryByID(ctx, deliveries[1].ID); err == nil {
+		t.Error("Expected error when getting a delivery of a deleted webhook")
+	}
+
+	if err := webhookRepo.DeleteSubscription(ctx, audit.ID); err == nil {
+		t.Error("Expected error when deleting a missing webhook subscription")
+	}
+
+	// Webhooks belong to their team
+	otherCtx := teamctx.SetTeam(ctx, &models.Team{ID: 99})
+	if _, err := webhookRepo.GetSubscriptionByID(otherCtx, paging.ID); err == nil {
+		t.Error("Expected error when getting the webhook of another team")
+	}
+}
+
+func TestTeamsRepository(t *testing.T) {
+	db := setupTestDB(t)
+	teamsRepo := NewTeamsRepository(db)
+	ctx := context.Background()
+
+	// The migration creates the default team that owns all existing data
+	defaultTeam, err := teamsRepo.GetBySlug(ctx, "default")
+	if err != nil {
+		t.Fatalf("Failed to get default team: %v", err)
+	}
+	if defaultTeam.ID != models.DefaultTeamID {
+		t.Errorf("Expected default team ID %d, got %d", models.DefaultTeamID, defaultTeam.ID)
+	}
+
+	// Test Create
+	platform := &models.Team{Name: "Platform", Slug: "platform"}
+	if err := teamsRepo.Create(ctx, platform); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+
+	if err := teamsRepo.Create(ctx, &models.Team{Name: "Other Platform", Slug: "platform"}); err == nil {
+		t.Error("Expected error when creating a team with a slug in use")
+	}
+
+	// Test Update
+	platform.Name = "Platform Engineering"
+	if err := teamsRepo.Update(ctx, platform); err != nil {
+		t.Fatalf("Failed to update team: %v", err)
+	}
+
+	retrieved, err := teamsRepo.GetByID(ctx, platform.ID)
+	if err != nil {
+		t.Fatalf("Failed to get team by ID: %v", err)
+	}
+	if retrieved.Name != "Platform Engineering" || retrieved.Slug != "platform" {
+		t.Errorf("Expected renamed team with slug platform, got %+v", retrieved)
+	}
+
+	// Test GetAll - ordered by name
+	teams, err := teamsRepo.GetAll(ctx)
+	if err != nil {
+		t.Fatalf("Failed to get all teams: %v", err)
+	}
+	if len(teams) != 2 || teams[0].Slug != "default" || teams[1].Slug != "platform" {
+		t.Errorf("Expected default and platform teams, got %+v", teams)
+	}
+
+	if _, err := teamsRepo.GetBySlug(ctx, "missing"); err == nil {
+		t.Error("Expected error when getting a missing team")
+	}
+}
+
+// TestRepositoriesScopedToTeam tests that the data of one team is invisible to another
+func TestRepositoriesScopedToTeam(t *testing.T) {
+	db := setupTestDB(t)
+	repos := NewRepositories(db)
+
+	platform := &models.Team{Name: "Platform", Slug: "platform"}
+	if err := repos.Teams.Create(context.Background(), platform); err != nil {
+		t.Fatalf("Failed to create team: %v", err)
+	}
+
+	defaultCtx := context.Background()
+	platformCtx := teamctx.SetTeam(context.Background(), platform)
+
+	// Members
+	member := &models.TeamMember{Name: "Platform User", Active: true}
+	if err := repos.Team.Create(platformCtx, member); err != nil {
+		t.Fatalf("Failed to create team member: %v", err)
+	}
+
+	if count, _ := repos.Team.Count(defaultCtx); count != 0 {
+		t.Errorf("Expected no members in the default team, got %d", count)
+	}
+	if count, _ := repos.Team.Count(platformCtx); count != 1 {
+		t.Errorf("Expected 1 member in the platform team, got %d", count)
+	}
+	if _, err := repos.Team.GetByID(defaultCtx, member.ID); err == nil {
+		t.Error("Expected error when getting a member of another team")
+	}
+	if err := repos.Team.Delete(defaultCtx, member.ID); err == nil {
+		t.Error("Expected error when deleting a member of another team")
+	}
+
+	// Working hours - a new team has none until they are configured
+	if hours, _ := repos.WorkingHours.GetAll(platformCtx); len(hours) != 0 {
+		t.Errorf("Expected no working hours for the platform team, got %d", len(hours))
+	}
+	shift := []models.WorkingHours{{StartTime: "08:00", EndTime: "16:00", Active: true}}
+	if err := repos.WorkingHours.ReplaceDay(platformCtx, time.Time{}, 0, shift); err != nil {
+		t.Fatalf("Failed to replace working hours: %v", err)
+	}
+
+	defaultMonday, err := repos.WorkingHours.GetByDay(defaultCtx, 0, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to get working hours: %v", err)
+	}
+	if len(defaultMonday) != 1 || defaultMonday[0].StartTime != "09:00" {
+		t.Errorf("Expected the default team to keep 09:00 on Monday, got %+v", defaultMonday)
+	}
+
+	// Time off
+	date := time.Date(2025, 10, 6, 0, 0, 0, 0, time.UTC)
+	timeOff := &models.TimeOff{TeamMemberID: member.ID, StartDate: date, EndDate: date}
+	if err := repos.TimeOff.Create(platformCtx, timeOff); err != nil {
+		t.Fatalf("Failed to create time off: %v", err)
+	}
+	if periods, _ := repos.TimeOff.GetByDateRange(defaultCtx, date, date); len(periods) != 0 {
+		t.Errorf("Expected no time off in the default team, got %d", len(periods))
+	}
+	if periods, _ := repos.TimeOff.GetByDateRange(platformCtx, date, date); len(periods) != 1 {
+		t.Errorf("Expected 1 time off period in the platform team, got %d", len(periods))
+	}
+
+	// Schedule entries
+	entry := &models.ScheduleEntry{Date: date, TeamMemberID: member.ID, StartTime: "08:00", EndTime: "16:00"}
+	if err := repos.Schedule.Create(platformCtx, entry); err != nil {
+		t.Fatalf("Failed to create schedule entry: %v", err)
+	}
+	if err := repos.Schedule.DeleteByDateRange(defaultCtx, date, date); err != nil {
+		t.Fatalf("Failed to delete schedule entries: %v", err)
+	}
+	if entries, _ := repos.Schedule.GetByDate(platformCtx, date); len(entries) != 1 {
+		t.Errorf("Expected deleting the default team's entries to keep the platform entry, got %d", len(entries))
+	}
+	if entries, _ := repos.Schedule.GetByDate(defaultCtx, date); len(entries) != 0 {
+		t.Errorf("Expected no entries in the default team, got %d", len(entries))
+	}
+
+	// Schedule state
+	state, err := repos.Schedule.GetState(platformCtx)
+	if err != nil {
+		t.Fatalf("Failed to get schedule state: %v", err)
+	}
+	state.RotationStrategy = models.RotationStrategyRoundRobin
+	if err := repos.Schedule.UpdateState(platformCtx, state); err != nil {
+		t.Fatalf("Failed to update schedule state: %v", err)
+	}
+
+	defaultState, err := repos.Schedule.GetState(defaultCtx)
+	if err != nil {
+		t.Fatalf("Failed to get schedule state: %v", err)
+	}
+	if defaultState.TeamID != models.DefaultTeamID || defaultState.RotationStrategy != models.RotationStrategyEpochModulo {
+		t.Errorf("Expected the default team to keep its own strategy, got %+v", defaultState)
+	}
+
+	platformState, err := repos.Schedule.GetState(platformCtx)
+	if err != nil {
+		t.Fatalf("Failed to get schedule state: %v", err)
+	}
+	if platformState.TeamID != platform.ID || platformState.RotationStrategy != models.RotationStrategyRoundRobin {
+		t.Errorf("Expected the platform team's round robin strategy, got %+v", platformState)
+	}
+
+	// Notification channels
+	channel := &models.NotificationChannel{Name: "Platform", Kind: models.ChannelKindEmail, Events: []string{models.NotificationTakeover}, Active: true}
+	if err := repos.NotificationChannels.Create(platformCtx, channel); err != nil {
+		t.Fatalf("Failed to create notification channel: %v", err)
+	}
+	if channels, _ := repos.NotificationChannels.GetAll(defaultCtx); len(channels) != 0 {
+		t.Errorf("Expected no notification channels in the default team, got %d", len(channels))
+	}
+	if err := repos.NotificationChannels.Delete(defaultCtx, channel.ID); err == nil {
+		t.Error("Expected error when deleting a notification channel of another team")
+	}
+}
+
+func TestTransactor(t *testing.T) {
+	db := setupTestDB(t)
+	repos := NewRepositories(db)
+	ctx := context.Background()
+
+	member := &models.TeamMember{Name: "Test User", Active: true}
+	if err := repos.Team.Create(ctx, member); err != nil {
+		t.Fatalf("Failed to create test team member: %v", err)
+	}
+
+	date := time.Now().AddDate(0, 0, 1)
+	newEntry := func() *models.ScheduleEntry {
+		return &models.ScheduleEntry{Date: date, TeamMemberID: member.ID, StartTime: "09:00", EndTime: "17:00"}
+	}
+	countEntries := func() int {
+		entries, err := repos.Schedule.GetByDate(ctx, date)
+		if err != nil {
+			t.Fatalf("Failed to get schedule entries: %v", err)
+		}
+		return len(entries)
+	}
+
+	// A failing unit leaves nothing behind
+	failure := fmt.Errorf("generation failed")
+	err := repos.Transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := repos.Schedule.Create(ctx, newEntry()); err != nil {
+			return err
+		}
+		if entries, _ := repos.Schedule.GetByDate(ctx, date); len(entries) != 1 {
+			t.Errorf("Expected the transaction to see its own entry, got %d entries", len(entries))
+		}
+		return failure
+	})
+	if err != failure {
+		t.Errorf("Expected the error of the unit, got %v", err)
+	}
+	if count := countEntries(); count != 0 {
+		t.Errorf("Expected the entry to be rolled back, got %d entries", count)
+	}
+
+	// A nested unit joins the running transaction and commits with it
+	err = repos.Transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := repos.Schedule.Create(ctx, newEntry()); err != nil {
+			return err
+		}
+		return repos.Transactor.WithTx(ctx, func(ctx context.Context) error {
+			return repos.Schedule.Create(ctx, newEntry())
+		})
+	})
+	if err != nil {
+		t.Fatalf("Failed to run transaction: %v", err)
+	}
+	if count := countEntries(); count != 2 {
+		t.Errorf("Expected both entries to be committed, got %d entries", count)
+	}
+
+	// Repositories that replace rows in a transaction of their own join it as well
+	err = repos.Transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := repos.WorkingHours.ReplaceDay(ctx, time.Time{}, 0, []models.WorkingHours{{Name: "Early", StartTime: "07:00", EndTime: "15:00", Active: true}}); err != nil {
+			return err
+		}
+		return failure
+	})
+	if err != failure {
+		t.Errorf("Expected the error of the unit, got %v", err)
+	}
+	shifts, err := repos.WorkingHours.GetByDay(ctx, 0, time.Now())
+	if err != nil {
+		t.Fatalf("Failed to get working hours: %v", err)
+	}
+	for _, shift := range shifts {
+		if shift.Name == "Early" {
+			t.Error("Expected the replaced day to be rolled back")
+		}
+	}
 }
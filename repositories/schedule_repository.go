@@ -3,10 +3,13 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
 	"github.com/blogem/eod-scheduler/userctx"
 )
 
@@ -16,8 +19,10 @@ type ScheduleRepository interface {
 	GetByDate(ctx context.Context, date time.Time) ([]models.ScheduleEntry, error)
 	GetByID(ctx context.Context, id int) (*models.ScheduleEntry, error)
 	Create(ctx context.Context, entry *models.ScheduleEntry) error
+	CreateBatch(ctx context.Context, entries []*models.ScheduleEntry) error
 	Update(ctx context.Context, entry *models.ScheduleEntry) error
 	Delete(ctx context.Context, id int) error
+	DeleteBatch(ctx context.Context, ids []int) error
 	DeleteByDateRange(ctx context.Context, from, to time.Time) error
 	GetState(ctx context.Context) (*models.ScheduleState, error)
 	UpdateState(ctx context.Context, state *models.ScheduleState) error
@@ -38,16 +43,16 @@ func NewScheduleRepository(db *sql.DB) ScheduleRepository {
 // GetByDateRange retrieves schedule entries within a date range with team member info
 func (r *scheduleRepository) GetByDateRange(ctx context.Context, from, to time.Time) ([]models.ScheduleEntry, error) {
 	query := `
-		SELECT se.id, se.date, se.team_member_id, se.start_time, se.end_time, 
-			   se.is_manual_override, se.original_team_member_id,
+		SELECT se.id, se.date, se.team_member_id, se.start_time, se.end_time, se.shift, se.role,
+			   se.layer, se.end_date, se.is_manual_override, se.original_team_member_id,
 			   t.name as team_member_name, t.slack_handle as team_member_slack_handle
 		FROM schedule_entries se
 		LEFT JOIN team_members t ON se.team_member_id = t.id
-		WHERE se.date >= ? AND se.date <= ?
-		ORDER BY se.date, se.start_time
+		WHERE se.team_id = ? AND se.date >= ? AND se.date <= ?
+		ORDER BY se.date, se.start_time, CASE se.role WHEN 'backup' THEN 1 ELSE 0 END
 		`
 
-	rows, err := r.db.Query(query, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	rows, err := conn(ctx, r.db).Query(query, teamctx.GetTeamID(ctx), from.Format("2006-01-02"), to.Format("2006-01-02"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query schedule entries: %w", err)
 	}
@@ -57,6 +62,7 @@ func (r *scheduleRepository) GetByDateRange(ctx context.Context, from, to time.T
 	for rows.Next() {
 		var entry models.ScheduleEntry
 		var teamMemberName, teamMemberSlackHandle sql.NullString
+		var endDate sql.NullTime
 
 		err := rows.Scan(
 			&entry.ID,
@@ -64,6 +70,10 @@ func (r *scheduleRepository) GetByDateRange(ctx context.Context, from, to time.T
 			&entry.TeamMemberID,
 			&entry.StartTime,
 			&entry.EndTime,
+			&entry.Shift,
+			&entry.Role,
+			&entry.Layer,
+			&endDate,
 			&entry.IsManualOverride,
 			&entry.OriginalTeamMemberID,
 			&teamMemberName,
@@ -80,6 +90,9 @@ func (r *scheduleRepository) GetByDateRange(ctx context.Context, from, to time.T
 		if teamMemberSlackHandle.Valid {
 			entry.TeamMemberSlackHandle = teamMemberSlackHandle.String
 		}
+		if endDate.Valid {
+			entry.EndDate = &endDate.Time
+		}
 
 		entries = append(entries, entry)
 	}
@@ -100,22 +113,27 @@ func (r *scheduleRepository) GetByDate(ctx context.Context, date time.Time) ([]m
 func (r *scheduleRepository) GetByID(ctx context.Context, id int) (*models.ScheduleEntry, error) {
 	query := `
 		SELECT 
-			s.id, s.date, s.team_member_id, s.start_time, s.end_time, s.is_manual_override, s.original_team_member_id,
+			s.id, s.date, s.team_member_id, s.start_time, s.end_time, s.shift, s.role, s.layer, s.end_date, s.is_manual_override, s.original_team_member_id,
 			t.name as team_member_name, t.slack_handle as team_member_slack_handle
 		FROM schedule_entries s
 		LEFT JOIN team_members t ON s.team_member_id = t.id
-		WHERE s.id = ?
+		WHERE s.id = ? AND s.team_id = ?
 	`
 
 	var entry models.ScheduleEntry
 	var teamMemberName, teamMemberSlackHandle sql.NullString
+	var endDate sql.NullTime
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := conn(ctx, r.db).QueryRow(query, id, teamctx.GetTeamID(ctx)).Scan(
 		&entry.ID,
 		&entry.Date,
 		&entry.TeamMemberID,
 		&entry.StartTime,
 		&entry.EndTime,
+		&entry.Shift,
+		&entry.Role,
+		&entry.Layer,
+		&endDate,
 		&entry.IsManualOverride,
 		&entry.OriginalTeamMemberID,
 		&teamMemberName,
@@ -136,6 +154,9 @@ func (r *scheduleRepository) GetByID(ctx context.Context, id int) (*models.Sched
 	if teamMemberSlackHandle.Valid {
 		entry.TeamMemberSlackHandle = teamMemberSlackHandle.String
 	}
+	if endDate.Valid {
+		entry.EndDate = &endDate.Time
+	}
 
 	return &entry, nil
 }
@@ -146,20 +167,7 @@ func (r *scheduleRepository) Create(ctx context.Context, entry *models.ScheduleE
 	userEmail := userctx.GetUserEmail(ctx)
 
 	fmt.Println("Creating schedule entry:", entry)
-	query := `
-		INSERT INTO schedule_entries (date, team_member_id, start_time, end_time, is_manual_override, original_team_member_id, created_by) 
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-	`
-
-	result, err := r.db.Exec(query,
-		entry.Date.Format("2006-01-02"),
-		entry.TeamMemberID,
-		entry.StartTime,
-		entry.EndTime,
-		entry.IsManualOverride,
-		entry.OriginalTeamMemberID,
-		userEmail,
-	)
+	result, err := conn(ctx, r.db).Exec(insertScheduleEntryQuery, insertScheduleEntryArgs(ctx, entry, userEmail)...)
 	if err != nil {
 		return fmt.Errorf("failed to create schedule entry: %w", err)
 	}
@@ -174,6 +182,64 @@ func (r *scheduleRepository) Create(ctx context.Context, entry *models.ScheduleE
 	return nil
 }
 
+// CreateBatch creates several schedule entries at once. The entries are inserted with a single
+// prepared statement, in the running transaction or in one of their own.
+func (r *scheduleRepository) CreateBatch(ctx context.Context, entries []*models.ScheduleEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// Get user email from context for audit
+	userEmail := userctx.GetUserEmail(ctx)
+
+	return NewTransactor(r.db).WithTx(ctx, func(ctx context.Context) error {
+		stmt, err := conn(ctx, r.db).PrepareContext(ctx, insertScheduleEntryQuery)
+		if err != nil {
+			return fmt.Errorf("failed to prepare schedule entry insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, entry := range entries {
+			result, err := stmt.ExecContext(ctx, insertScheduleEntryArgs(ctx, entry, userEmail)...)
+			if err != nil {
+				return fmt.Errorf("failed to create schedule entry: %w", err)
+			}
+
+			id, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get inserted ID: %w", err)
+			}
+			entry.ID = int(id)
+		}
+
+		return nil
+	})
+}
+
+// insertScheduleEntryQuery inserts a schedule entry with the arguments of insertScheduleEntryArgs
+const insertScheduleEntryQuery = `
+	INSERT INTO schedule_entries (team_id, date, team_member_id, start_time, end_time, shift, role, layer, end_date, is_manual_override, original_team_member_id, created_by) 
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+`
+
+// insertScheduleEntryArgs returns the arguments of insertScheduleEntryQuery for an entry
+func insertScheduleEntryArgs(ctx context.Context, entry *models.ScheduleEntry, userEmail string) []any {
+	return []any{
+		teamctx.GetTeamID(ctx),
+		entry.Date.Format("2006-01-02"),
+		entry.TeamMemberID,
+		entry.StartTime,
+		entry.EndTime,
+		entry.Shift,
+		entry.GetRole(),
+		entry.GetLayer(),
+		formatEndDate(entry),
+		entry.IsManualOverride,
+		entry.OriginalTeamMemberID,
+		userEmail,
+	}
+}
+
 // Update updates an existing schedule entry with audit fields
 func (r *scheduleRepository) Update(ctx context.Context, entry *models.ScheduleEntry) error {
 	// Get user email from context for audit
@@ -182,21 +248,27 @@ func (r *scheduleRepository) Update(ctx context.Context, entry *models.ScheduleE
 
 	query := `
 		UPDATE schedule_entries 
-		SET date = ?, team_member_id = ?, start_time = ?, end_time = ?, is_manual_override = ?, original_team_member_id = ?,
+		SET date = ?, team_member_id = ?, start_time = ?, end_time = ?, shift = ?, role = ?, layer = ?, end_date = ?,
+		    is_manual_override = ?, original_team_member_id = ?,
 		    modified_by = ?, modified_at = ?
-		WHERE id = ?
+		WHERE id = ? AND team_id = ?
 	`
 
-	result, err := r.db.Exec(query,
+	result, err := conn(ctx, r.db).Exec(query,
 		entry.Date.Format("2006-01-02"),
 		entry.TeamMemberID,
 		entry.StartTime,
 		entry.EndTime,
+		entry.Shift,
+		entry.GetRole(),
+		entry.GetLayer(),
+		formatEndDate(entry),
 		entry.IsManualOverride,
 		entry.OriginalTeamMemberID,
 		userEmail,
 		now,
 		entry.ID,
+		teamctx.GetTeamID(ctx),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update schedule entry: %w", err)
@@ -214,11 +286,19 @@ func (r *scheduleRepository) Update(ctx context.Context, entry *models.ScheduleE
 	return nil
 }
 
+// formatEndDate returns the end date of an on-call block for storage, or nil for working hours entries
+func formatEndDate(entry *models.ScheduleEntry) interface{} {
+	if entry.EndDate == nil {
+		return nil
+	}
+	return entry.EndDate.Format("2006-01-02")
+}
+
 // Delete deletes a schedule entry by ID
 func (r *scheduleRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM schedule_entries WHERE id = ?`
+	query := `DELETE FROM schedule_entries WHERE id = ? AND team_id = ?`
 
-	result, err := r.db.Exec(query, id)
+	result, err := conn(ctx, r.db).Exec(query, id, teamctx.GetTeamID(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete schedule entry: %w", err)
 	}
@@ -235,11 +315,44 @@ func (r *scheduleRepository) Delete(ctx context.Context, id int) error {
 	return nil
 }
 
+// maxBatchDeleteIDs limits the IDs of one delete statement, SQLite limits the number of parameters
+const maxBatchDeleteIDs = 500
+
+// DeleteBatch deletes several schedule entries by ID, in the running transaction or in one of their
+// own. IDs of entries that don't exist are ignored.
+func (r *scheduleRepository) DeleteBatch(ctx context.Context, ids []int) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	teamID := teamctx.GetTeamID(ctx)
+
+	return NewTransactor(r.db).WithTx(ctx, func(ctx context.Context) error {
+		for start := 0; start < len(ids); start += maxBatchDeleteIDs {
+			chunk := ids[start:min(start+maxBatchDeleteIDs, len(ids))]
+
+			args := []any{teamID}
+			placeholders := make([]string, len(chunk))
+			for i, id := range chunk {
+				placeholders[i] = "?"
+				args = append(args, id)
+			}
+
+			query := `DELETE FROM schedule_entries WHERE team_id = ? AND id IN (` + strings.Join(placeholders, ", ") + `)`
+			if _, err := conn(ctx, r.db).ExecContext(ctx, query, args...); err != nil {
+				return fmt.Errorf("failed to delete schedule entries: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
 // DeleteByDateRange deletes schedule entries within a date range
 func (r *scheduleRepository) DeleteByDateRange(ctx context.Context, from, to time.Time) error {
-	query := `DELETE FROM schedule_entries WHERE date >= ? AND date <= ?`
+	query := `DELETE FROM schedule_entries WHERE team_id = ? AND date >= ? AND date <= ?`
 
-	_, err := r.db.Exec(query, from.Format("2006-01-02"), to.Format("2006-01-02"))
+	_, err := conn(ctx, r.db).Exec(query, teamctx.GetTeamID(ctx), from.Format("2006-01-02"), to.Format("2006-01-02"))
 	if err != nil {
 		return fmt.Errorf("failed to delete schedule entries in range: %w", err)
 	}
@@ -247,25 +360,60 @@ func (r *scheduleRepository) DeleteByDateRange(ctx context.Context, from, to tim
 	return nil
 }
 
-// GetState retrieves the current schedule state
+// GetState retrieves the schedule state of the team
 func (r *scheduleRepository) GetState(ctx context.Context) (*models.ScheduleState, error) {
 	query := `
-		SELECT id, last_generation_date 
+		SELECT team_id, last_generation_date, rotation_strategy, rotation_seed,
+			   rotation_queue, rotation_cursor, rotation_cursor_date, rotation_shift_cursors,
+			   rotation_period, rotation_period_days, handover_day, backup_enabled,
+			   on_call_enabled, on_call_cursor, min_gap_days, max_shifts_per_week, max_shifts_per_month,
+			   horizon_length, horizon_unit, up_to_date_days, generated_until,
+			   last_run_at, last_run_trigger, last_run_success, last_run_message
 		FROM schedule_state 
-		WHERE id = 1
+		WHERE team_id = ?
 	`
 
 	var state models.ScheduleState
-	err := r.db.QueryRow(query).Scan(
-		&state.ID,
+	var rotationQueue, shiftCursors string
+	var cursorDate, generatedUntil, lastRunAt sql.NullTime
+	err := conn(ctx, r.db).QueryRow(query, teamctx.GetTeamID(ctx)).Scan(
+		&state.TeamID,
 		&state.LastGenerationDate,
+		&state.RotationStrategy,
+		&state.RotationSeed,
+		&rotationQueue,
+		&state.RotationCursor,
+		&cursorDate,
+		&shiftCursors,
+		&state.RotationPeriod,
+		&state.RotationPeriodDays,
+		&state.HandoverDay,
+		&state.BackupEnabled,
+		&state.OnCallEnabled,
+		&state.OnCallCursor,
+		&state.MinGapDays,
+		&state.MaxShiftsPerWeek,
+		&state.MaxShiftsPerMonth,
+		&state.HorizonLength,
+		&state.HorizonUnit,
+		&state.UpToDateDays,
+		&generatedUntil,
+		&lastRunAt,
+		&state.LastRun.Trigger,
+		&state.LastRun.Success,
+		&state.LastRun.Message,
 	)
 
 	if err == sql.ErrNoRows {
 		// Initialize default state if not exists
 		defaultState := &models.ScheduleState{
-			ID:                 1,
+			TeamID:             teamctx.GetTeamID(ctx),
 			LastGenerationDate: time.Now(),
+			RotationStrategy:   models.RotationStrategyEpochModulo,
+			RotationPeriod:     models.RotationPeriodDaily,
+			HorizonLength:      models.DefaultHorizonLength,
+			HorizonUnit:        models.DefaultHorizonUnit,
+			UpToDateDays:       models.DefaultUpToDateDays,
 		}
 		if err := r.UpdateState(ctx, defaultState); err != nil {
 			return nil, fmt.Errorf("failed to initialize schedule state: %w", err)
@@ -276,17 +424,98 @@ func (r *scheduleRepository) GetState(ctx context.Context) (*models.ScheduleStat
 		return nil, fmt.Errorf("failed to get schedule state: %w", err)
 	}
 
+	if err := json.Unmarshal([]byte(rotationQueue), &state.RotationQueue); err != nil {
+		return nil, fmt.Errorf("failed to parse rotation queue: %w", err)
+	}
+	if err := json.Unmarshal([]byte(shiftCursors), &state.ShiftCursors); err != nil {
+		return nil, fmt.Errorf("failed to parse shift cursors: %w", err)
+	}
+	if cursorDate.Valid {
+		state.RotationCursorDate = cursorDate.Time
+	}
+	if generatedUntil.Valid {
+		state.GeneratedUntil = generatedUntil.Time
+	}
+	if lastRunAt.Valid {
+		state.LastRun.At = lastRunAt.Time
+	}
+
 	return &state, nil
 }
 
-// UpdateState updates the schedule state
+// UpdateState updates the schedule state of the team
 func (r *scheduleRepository) UpdateState(ctx context.Context, state *models.ScheduleState) error {
 	query := `
-		INSERT OR REPLACE INTO schedule_state (id, last_generation_date) 
-		VALUES (1, ?)
+		INSERT OR REPLACE INTO schedule_state (team_id, last_generation_date, rotation_strategy, rotation_seed,
+			rotation_queue, rotation_cursor, rotation_cursor_date, rotation_shift_cursors,
+			rotation_period, rotation_period_days, handover_day, backup_enabled,
+			on_call_enabled, on_call_cursor, min_gap_days, max_shifts_per_week, max_shifts_per_month,
+			horizon_length, horizon_unit, up_to_date_days, generated_until,
+			last_run_at, last_run_trigger, last_run_success, last_run_message) 
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
-	_, err := r.db.Exec(query, state.LastGenerationDate.Format("2006-01-02"))
+	queue := state.RotationQueue
+	if queue == nil {
+		queue = []int{}
+	}
+	rotationQueue, err := json.Marshal(queue)
+	if err != nil {
+		return fmt.Errorf("failed to encode rotation queue: %w", err)
+	}
+
+	cursors := state.ShiftCursors
+	if cursors == nil {
+		cursors = map[string]int{}
+	}
+	shiftCursors, err := json.Marshal(cursors)
+	if err != nil {
+		return fmt.Errorf("failed to encode shift cursors: %w", err)
+	}
+
+	var cursorDate interface{}
+	if !state.RotationCursorDate.IsZero() {
+		cursorDate = state.RotationCursorDate.Format("2006-01-02")
+	}
+
+	var generatedUntil interface{}
+	if !state.GeneratedUntil.IsZero() {
+		generatedUntil = state.GeneratedUntil.Format("2006-01-02")
+	}
+
+	var lastRunAt interface{}
+	if state.LastRun.HasRun() {
+		lastRunAt = state.LastRun.At
+	}
+
+	state.TeamID = teamctx.GetTeamID(ctx)
+	_, err = conn(ctx, r.db).Exec(query,
+		state.TeamID,
+		state.LastGenerationDate.Format("2006-01-02"),
+		state.GetRotationStrategy(),
+		state.RotationSeed,
+		string(rotationQueue),
+		state.RotationCursor,
+		cursorDate,
+		string(shiftCursors),
+		state.GetRotationPeriod(),
+		state.RotationPeriodDays,
+		state.HandoverDay,
+		state.BackupEnabled,
+		state.OnCallEnabled,
+		state.OnCallCursor,
+		state.MinGapDays,
+		state.MaxShiftsPerWeek,
+		state.MaxShiftsPerMonth,
+		state.GetHorizonLength(),
+		state.GetHorizonUnit(),
+		state.GetUpToDateDays(),
+		generatedUntil,
+		lastRunAt,
+		state.LastRun.Trigger,
+		state.LastRun.Success,
+		state.LastRun.Message,
+	)
 	if err != nil {
 		return fmt.Errorf("failed to update schedule state: %w", err)
 	}
@@ -296,10 +525,10 @@ func (r *scheduleRepository) UpdateState(ctx context.Context, state *models.Sche
 
 // CountByTeamMember counts schedule entries for a specific team member
 func (r *scheduleRepository) CountByTeamMember(ctx context.Context, teamMemberID int) (int, error) {
-	query := `SELECT COUNT(*) FROM schedule_entries WHERE team_member_id = ?`
+	query := `SELECT COUNT(*) FROM schedule_entries WHERE team_member_id = ? AND team_id = ?`
 
 	var count int
-	err := r.db.QueryRow(query, teamMemberID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(query, teamMemberID, teamctx.GetTeamID(ctx)).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count schedule entries for team member: %w", err)
 	}
@@ -312,11 +541,11 @@ func (r *scheduleRepository) HasFutureEntries(ctx context.Context, teamMemberID
 	query := `
 		SELECT COUNT(*) 
 		FROM schedule_entries 
-		WHERE team_member_id = ? AND date > date('now')
+		WHERE team_member_id = ? AND team_id = ? AND date > date('now')
 	`
 
 	var count int
-	err := r.db.QueryRow(query, teamMemberID).Scan(&count)
+	err := conn(ctx, r.db).QueryRow(query, teamMemberID, teamctx.GetTeamID(ctx)).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check future entries for team member: %w", err)
 	}
@@ -3,10 +3,12 @@ package repositories
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
 	"time"
 
 	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
 	"github.com/blogem/eod-scheduler/userctx"
 )
 
@@ -34,13 +36,15 @@ func NewTeamRepository(db *sql.DB) TeamRepository {
 // GetAll retrieves all team members
 func (r *teamRepository) GetAll(ctx context.Context) ([]models.TeamMember, error) {
 	query := `
-		SELECT id, name, slack_handle, active, date_added, 
+		SELECT id, name, slack_handle, email, active, date_added, participation, work_days, preferences,
+		       muted_notifications,
 		       created_by, modified_by, modified_at
 		FROM team_members 
+		WHERE team_id = ?
 		ORDER BY name ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamctx.GetTeamID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query team members: %w", err)
 	}
@@ -49,6 +53,7 @@ func (r *teamRepository) GetAll(ctx context.Context) ([]models.TeamMember, error
 	var members []models.TeamMember
 	for rows.Next() {
 		var member models.TeamMember
+		var workDays, preferences, mutedNotifications string
 		var modifiedBy sql.NullString
 		var modifiedAt sql.NullTime
 
@@ -56,8 +61,13 @@ func (r *teamRepository) GetAll(ctx context.Context) ([]models.TeamMember, error
 			&member.ID,
 			&member.Name,
 			&member.SlackHandle,
+			&member.Email,
 			&member.Active,
 			&member.DateAdded,
+			&member.Participation,
+			&workDays,
+			&preferences,
+			&mutedNotifications,
 			&member.CreatedBy,
 			&modifiedBy,
 			&modifiedAt,
@@ -65,6 +75,9 @@ func (r *teamRepository) GetAll(ctx context.Context) ([]models.TeamMember, error
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan team member: %w", err)
 		}
+		if err := decodeAvailability(&member, workDays, preferences, mutedNotifications); err != nil {
+			return nil, err
+		}
 
 		// Convert NULL values to empty string/nil
 		if modifiedBy.Valid {
@@ -87,22 +100,29 @@ func (r *teamRepository) GetAll(ctx context.Context) ([]models.TeamMember, error
 // GetByID retrieves a team member by ID
 func (r *teamRepository) GetByID(ctx context.Context, id int) (*models.TeamMember, error) {
 	query := `
-		SELECT id, name, slack_handle, active, date_added,
+		SELECT id, name, slack_handle, email, active, date_added, participation, work_days, preferences,
+		       muted_notifications,
 		       created_by, modified_by, modified_at
 		FROM team_members 
-		WHERE id = ?
+		WHERE id = ? AND team_id = ?
 	`
 
 	var member models.TeamMember
+	var workDays, preferences, mutedNotifications string
 	var modifiedBy sql.NullString
 	var modifiedAt sql.NullTime
 
-	err := r.db.QueryRowContext(ctx, query, id).Scan(
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, id, teamctx.GetTeamID(ctx)).Scan(
 		&member.ID,
 		&member.Name,
 		&member.SlackHandle,
+		&member.Email,
 		&member.Active,
 		&member.DateAdded,
+		&member.Participation,
+		&workDays,
+		&preferences,
+		&mutedNotifications,
 		&member.CreatedBy,
 		&modifiedBy,
 		&modifiedAt,
@@ -114,6 +134,9 @@ func (r *teamRepository) GetByID(ctx context.Context, id int) (*models.TeamMembe
 	if err != nil {
 		return nil, fmt.Errorf("failed to get team member: %w", err)
 	}
+	if err := decodeAvailability(&member, workDays, preferences, mutedNotifications); err != nil {
+		return nil, err
+	}
 
 	// Convert NULL values to empty string/nil
 	if modifiedBy.Valid {
@@ -129,14 +152,15 @@ func (r *teamRepository) GetByID(ctx context.Context, id int) (*models.TeamMembe
 // GetActiveMembers retrieves only active team members
 func (r *teamRepository) GetActiveMembers(ctx context.Context) ([]models.TeamMember, error) {
 	query := `
-		SELECT id, name, slack_handle, active, date_added,
+		SELECT id, name, slack_handle, email, active, date_added, participation, work_days, preferences,
+		       muted_notifications,
 		       created_by, modified_by, modified_at
 		FROM team_members 
-		WHERE active = 1 
+		WHERE active = 1 AND team_id = ?
 		ORDER BY date_added ASC, name ASC
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamctx.GetTeamID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query active team members: %w", err)
 	}
@@ -145,6 +169,7 @@ func (r *teamRepository) GetActiveMembers(ctx context.Context) ([]models.TeamMem
 	var members []models.TeamMember
 	for rows.Next() {
 		var member models.TeamMember
+		var workDays, preferences, mutedNotifications string
 		var modifiedBy sql.NullString
 		var modifiedAt sql.NullTime
 
@@ -152,8 +177,13 @@ func (r *teamRepository) GetActiveMembers(ctx context.Context) ([]models.TeamMem
 			&member.ID,
 			&member.Name,
 			&member.SlackHandle,
+			&member.Email,
 			&member.Active,
 			&member.DateAdded,
+			&member.Participation,
+			&workDays,
+			&preferences,
+			&mutedNotifications,
 			&member.CreatedBy,
 			&modifiedBy,
 			&modifiedAt,
@@ -161,6 +191,9 @@ func (r *teamRepository) GetActiveMembers(ctx context.Context) ([]models.TeamMem
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan active team member: %w", err)
 		}
+		if err := decodeAvailability(&member, workDays, preferences, mutedNotifications); err != nil {
+			return nil, err
+		}
 
 		// Convert NULL values to empty string/nil
 		if modifiedBy.Valid {
@@ -183,8 +216,9 @@ func (r *teamRepository) GetActiveMembers(ctx context.Context) ([]models.TeamMem
 // Create creates a new team member
 func (r *teamRepository) Create(ctx context.Context, member *models.TeamMember) error {
 	query := `
-		INSERT INTO team_members (name, slack_handle, active, date_added, created_by) 
-		VALUES (?, ?, ?, ?, ?)
+		INSERT INTO team_members (team_id, name, slack_handle, email, active, date_added, participation, work_days, preferences,
+		                          muted_notifications, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	// Set default values
@@ -192,14 +226,25 @@ func (r *teamRepository) Create(ctx context.Context, member *models.TeamMember)
 		member.DateAdded = time.Now()
 	}
 
+	workDays, preferences, mutedNotifications, err := encodeAvailability(member)
+	if err != nil {
+		return err
+	}
+
 	// Get user from context
 	userEmail := userctx.GetUserEmail(ctx)
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		teamctx.GetTeamID(ctx),
 		member.Name,
 		member.SlackHandle,
+		member.Email,
 		member.Active,
 		member.DateAdded,
+		member.GetParticipation(),
+		workDays,
+		preferences,
+		mutedNotifications,
 		userEmail,
 	)
 	if err != nil {
@@ -221,22 +266,33 @@ func (r *teamRepository) Create(ctx context.Context, member *models.TeamMember)
 func (r *teamRepository) Update(ctx context.Context, member *models.TeamMember) error {
 	query := `
 		UPDATE team_members 
-		SET name = ?, slack_handle = ?, active = ?,
-		    modified_by = ?, modified_at = ?
-		WHERE id = ?
+		SET name = ?, slack_handle = ?, email = ?, active = ?, participation = ?, work_days = ?, preferences = ?,
+		    muted_notifications = ?, modified_by = ?, modified_at = ?
+		WHERE id = ? AND team_id = ?
 	`
 
+	workDays, preferences, mutedNotifications, err := encodeAvailability(member)
+	if err != nil {
+		return err
+	}
+
 	// Get user from context
 	userEmail := userctx.GetUserEmail(ctx)
 	now := time.Now()
 
-	result, err := r.db.ExecContext(ctx, query,
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
 		member.Name,
 		member.SlackHandle,
+		member.Email,
 		member.Active,
+		member.GetParticipation(),
+		workDays,
+		preferences,
+		mutedNotifications,
 		userEmail,
 		now,
 		member.ID,
+		teamctx.GetTeamID(ctx),
 	)
 	if err != nil {
 		return fmt.Errorf("failed to update team member: %w", err)
@@ -258,9 +314,9 @@ func (r *teamRepository) Update(ctx context.Context, member *models.TeamMember)
 
 // Delete deletes a team member by ID
 func (r *teamRepository) Delete(ctx context.Context, id int) error {
-	query := `DELETE FROM team_members WHERE id = ?`
+	query := `DELETE FROM team_members WHERE id = ? AND team_id = ?`
 
-	result, err := r.db.ExecContext(ctx, query, id)
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, teamctx.GetTeamID(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to delete team member: %w", err)
 	}
@@ -279,13 +335,73 @@ func (r *teamRepository) Delete(ctx context.Context, id int) error {
 
 // Count returns the total number of team members
 func (r *teamRepository) Count(ctx context.Context) (int, error) {
-	query := `SELECT COUNT(*) FROM team_members`
+	query := `SELECT COUNT(*) FROM team_members WHERE team_id = ?`
 
 	var count int
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := conn(ctx, r.db).QueryRowContext(ctx, query, teamctx.GetTeamID(ctx)).Scan(&count)
 	if err != nil {
 		return 0, fmt.Errorf("failed to count team members: %w", err)
 	}
 
 	return count, nil
 }
+
+// encodeAvailability encodes the work days, weekday preferences and muted notifications of a member
+// for storage
+func encodeAvailability(member *models.TeamMember) (string, string, string, error) {
+	days := member.WorkDays
+	if days == nil {
+		days = []int{}
+	}
+	workDays, err := json.Marshal(days)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode work days: %w", err)
+	}
+
+	list := member.Preferences
+	if list == nil {
+		list = []models.WeekdayPreference{}
+	}
+	preferences, err := json.Marshal(list)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode weekday preferences: %w", err)
+	}
+
+	events := member.MutedNotifications
+	if events == nil {
+		events = []string{}
+	}
+	mutedNotifications, err := json.Marshal(events)
+	if err != nil {
+		return "", "", "", fmt.Errorf("failed to encode muted notifications: %w", err)
+	}
+
+	return string(workDays), string(preferences), string(mutedNotifications), nil
+}
+
+// decodeAvailability sets the stored work days, weekday preferences and muted notifications on a
+// member, leaving them nil when empty
+func decodeAvailability(member *models.TeamMember, workDays, preferences, mutedNotifications string) error {
+	if err := json.Unmarshal([]byte(workDays), &member.WorkDays); err != nil {
+		return fmt.Errorf("failed to parse work days: %w", err)
+	}
+	if len(member.WorkDays) == 0 {
+		member.WorkDays = nil
+	}
+
+	if err := json.Unmarshal([]byte(preferences), &member.Preferences); err != nil {
+		return fmt.Errorf("failed to parse weekday preferences: %w", err)
+	}
+	if len(member.Preferences) == 0 {
+		member.Preferences = nil
+	}
+
+	if err := json.Unmarshal([]byte(mutedNotifications), &member.MutedNotifications); err != nil {
+		return fmt.Errorf("failed to parse muted notifications: %w", err)
+	}
+	if len(member.MutedNotifications) == 0 {
+		member.MutedNotifications = nil
+	}
+
+	return nil
+}
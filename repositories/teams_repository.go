@@ -0,0 +1,190 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// TeamsRepository interface defines database operations on the teams sharing the deployment
+type TeamsRepository interface {
+	GetAll(ctx context.Context) ([]models.Team, error)
+	GetByID(ctx context.Context, id int) (*models.Team, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Team, error)
+	Create(ctx context.Context, team *models.Team) error
+	Update(ctx context.Context, team *models.Team) error
+}
+
+// teamsRepository implements TeamsRepository interface
+type teamsRepository struct {
+	db *sql.DB
+}
+
+// NewTeamsRepository creates a new teams repository
+func NewTeamsRepository(db *sql.DB) TeamsRepository {
+	return &teamsRepository{db: db}
+}
+
+// GetAll retrieves all teams ordered by name
+func (r *teamsRepository) GetAll(ctx context.Context) ([]models.Team, error) {
+	query := `
+		SELECT id, name, slug, created_by, modified_by, modified_at
+		FROM teams
+		ORDER BY name ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query teams: %w", err)
+	}
+	defer rows.Close()
+
+	var teams []models.Team
+	for rows.Next() {
+		team, err := scanTeam(rows)
+		if err != nil {
+			return nil, err
+		}
+		teams = append(teams, *team)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating teams: %w", err)
+	}
+
+	return teams, nil
+}
+
+// GetByID retrieves a team by ID
+func (r *teamsRepository) GetByID(ctx context.Context, id int) (*models.Team, error) {
+	query := `
+		SELECT id, name, slug, created_by, modified_by, modified_at
+		FROM teams
+		WHERE id = ?
+	`
+
+	team, err := scanTeam(conn(ctx, r.db).QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("team with ID %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// GetBySlug retrieves a team by the slug used in its URLs
+func (r *teamsRepository) GetBySlug(ctx context.Context, slug string) (*models.Team, error) {
+	query := `
+		SELECT id, name, slug, created_by, modified_by, modified_at
+		FROM teams
+		WHERE slug = ?
+	`
+
+	team, err := scanTeam(conn(ctx, r.db).QueryRowContext(ctx, query, slug))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("team %q not found", slug)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return team, nil
+}
+
+// Create creates a new team with audit fields
+func (r *teamsRepository) Create(ctx context.Context, team *models.Team) error {
+	query := `
+		INSERT INTO teams (name, slug, created_by)
+		VALUES (?, ?, ?)
+	`
+
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, team.Name, team.Slug, userEmail)
+	if err != nil {
+		return fmt.Errorf("failed to create team: %w", err)
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get inserted ID: %w", err)
+	}
+
+	team.ID = int(id)
+	team.CreatedBy = userEmail
+	return nil
+}
+
+// Update updates the name and slug of a team with audit fields
+func (r *teamsRepository) Update(ctx context.Context, team *models.Team) error {
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+	now := time.Now()
+
+	query := `
+		UPDATE teams
+		SET name = ?, slug = ?, modified_by = ?, modified_at = ?
+		WHERE id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, team.Name, team.Slug, userEmail, now, team.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update team: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("team with ID %d not found", team.ID)
+	}
+
+	team.ModifiedBy = userEmail
+	team.ModifiedAt = &now
+	return nil
+}
+
+// scanTeam scans a team row
+func scanTeam(row rowScanner) (*models.Team, error) {
+	var team models.Team
+	var createdBy, modifiedBy sql.NullString
+	var modifiedAt sql.NullTime
+
+	err := row.Scan(
+		&team.ID,
+		&team.Name,
+		&team.Slug,
+		&createdBy,
+		&modifiedBy,
+		&modifiedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan team: %w", err)
+	}
+
+	// Convert NULL values to empty string/nil
+	if createdBy.Valid {
+		team.CreatedBy = createdBy.String
+	}
+	if modifiedBy.Valid {
+		team.ModifiedBy = modifiedBy.String
+	}
+	if modifiedAt.Valid {
+		team.ModifiedAt = &modifiedAt.Time
+	}
+
+	return &team, nil
+}
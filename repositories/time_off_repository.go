@@ -0,0 +1,198 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// TimeOffRepository interface defines time off database operations
+type TimeOffRepository interface {
+	GetByTeamMember(ctx context.Context, teamMemberID int) ([]models.TimeOff, error)
+	GetByDateRange(ctx context.Context, from, to time.Time) ([]models.TimeOff, error)
+	GetByID(ctx context.Context, id int) (*models.TimeOff, error)
+	Create(ctx context.Context, timeOff *models.TimeOff) error
+	Delete(ctx context.Context, id int) error
+}
+
+// timeOffRepository implements TimeOffRepository interface
+type timeOffRepository struct {
+	db *sql.DB
+}
+
+// NewTimeOffRepository creates a new time off repository
+func NewTimeOffRepository(db *sql.DB) TimeOffRepository {
+	return &timeOffRepository{db: db}
+}
+
+const timeOffColumns = `
+		SELECT o.id, o.team_member_id, o.start_date, o.end_date, o.reason,
+		       o.created_by, o.modified_by, o.modified_at,
+		       t.name as team_member_name
+		FROM time_off o
+		JOIN team_members t ON o.team_member_id = t.id
+`
+
+// GetByTeamMember retrieves all time off of a team member, most recent first
+func (r *timeOffRepository) GetByTeamMember(ctx context.Context, teamMemberID int) ([]models.TimeOff, error) {
+	query := timeOffColumns + `
+		WHERE o.team_member_id = ? AND t.team_id = ?
+		ORDER BY o.start_date DESC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamMemberID, teamctx.GetTeamID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time off: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTimeOffRows(rows)
+}
+
+// GetByDateRange retrieves all time off overlapping a date range (both ends inclusive)
+func (r *timeOffRepository) GetByDateRange(ctx context.Context, from, to time.Time) ([]models.TimeOff, error) {
+	query := timeOffColumns + `
+		WHERE o.start_date <= ? AND o.end_date >= ? AND t.team_id = ?
+		ORDER BY o.start_date, o.team_member_id
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, to.Format("2006-01-02"), from.Format("2006-01-02"), teamctx.GetTeamID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query time off: %w", err)
+	}
+	defer rows.Close()
+
+	return scanTimeOffRows(rows)
+}
+
+// GetByID retrieves a time off period by ID
+func (r *timeOffRepository) GetByID(ctx context.Context, id int) (*models.TimeOff, error) {
+	query := timeOffColumns + `
+		WHERE o.id = ? AND t.team_id = ?
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, id, teamctx.GetTeamID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time off: %w", err)
+	}
+	defer rows.Close()
+
+	timeOff, err := scanTimeOffRows(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(timeOff) == 0 {
+		return nil, fmt.Errorf("time off with ID %d not found", id)
+	}
+
+	return &timeOff[0], nil
+}
+
+// Create creates a new time off period with audit fields. The member is expected to belong to the
+// team of the context, the service checks that before creating.
+func (r *timeOffRepository) Create(ctx context.Context, timeOff *models.TimeOff) error {
+	query := `
+		INSERT INTO time_off (team_member_id, start_date, end_date, reason, created_by)
+		VALUES (?, ?, ?, ?, ?)
+	`
+
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		timeOff.TeamMemberID,
+		timeOff.StartDate.Format("2006-01-02"),
+		timeOff.EndDate.Format("2006-01-02"),
+		timeOff.Reason,
+		userEmail,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create time off: %w", err)
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get inserted ID: %w", err)
+	}
+
+	timeOff.ID = int(id)
+	timeOff.CreatedBy = userEmail
+	return nil
+}
+
+// Delete deletes a time off period
+func (r *timeOffRepository) Delete(ctx context.Context, id int) error {
+	query := `
+		DELETE FROM time_off
+		WHERE id = ? AND team_member_id IN (SELECT id FROM team_members WHERE team_id = ?)
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, id, teamctx.GetTeamID(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to delete time off: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("time off with ID %d not found", id)
+	}
+
+	return nil
+}
+
+// scanTimeOffRows scans time off rows selected with timeOffColumns
+func scanTimeOffRows(rows *sql.Rows) ([]models.TimeOff, error) {
+	var periods []models.TimeOff
+	for rows.Next() {
+		var timeOff models.TimeOff
+		var createdBy, modifiedBy, teamMemberName sql.NullString
+		var modifiedAt sql.NullTime
+
+		err := rows.Scan(
+			&timeOff.ID,
+			&timeOff.TeamMemberID,
+			&timeOff.StartDate,
+			&timeOff.EndDate,
+			&timeOff.Reason,
+			&createdBy,
+			&modifiedBy,
+			&modifiedAt,
+			&teamMemberName,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time off: %w", err)
+		}
+
+		// Convert NULL values to empty string/nil
+		if createdBy.Valid {
+			timeOff.CreatedBy = createdBy.String
+		}
+		if modifiedBy.Valid {
+			timeOff.ModifiedBy = modifiedBy.String
+		}
+		if modifiedAt.Valid {
+			timeOff.ModifiedAt = &modifiedAt.Time
+		}
+		if teamMemberName.Valid {
+			timeOff.TeamMemberName = teamMemberName.String
+		}
+
+		periods = append(periods, timeOff)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating time off: %w", err)
+	}
+
+	return periods, nil
+}
@@ -0,0 +1,460 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// WebhookRepository interface defines webhook subscription and delivery database operations
+type WebhookRepository interface {
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	GetSubscriptionByID(ctx context.Context, id int) (*models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id int) error
+	CreateDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error
+	GetDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error)
+	GetDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error)
+	GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error)
+	UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int, error)
+}
+
+// webhookRepository implements WebhookRepository interface
+type webhookRepository struct {
+	db *sql.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *sql.DB) WebhookRepository {
+	return &webhookRepository{db: db}
+}
+
+// GetSubscriptions retrieves all webhook subscriptions of the team ordered by name
+func (r *webhookRepository) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	query := `
+		SELECT id, name, url, secret, events, active, created_by, modified_by, modified_at
+		FROM webhook_subscriptions
+		WHERE team_id = ?
+		ORDER BY name ASC, id ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamctx.GetTeamID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subscriptions []models.WebhookSubscription
+	for rows.Next() {
+		subscription, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subscriptions = append(subscriptions, *subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook subscriptions: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscriptionByID retrieves a webhook subscription of the team by ID
+func (r *webhookRepository) GetSubscriptionByID(ctx context.Context, id int) (*models.WebhookSubscription, error) {
+	query := `
+		SELECT id, name, url, secret, events, active, created_by, modified_by, modified_at
+		FROM webhook_subscriptions
+		WHERE id = ? AND team_id = ?
+	`
+
+	subscription, err := scanWebhookSubscription(conn(ctx, r.db).QueryRowContext(ctx, query, id, teamctx.GetTeamID(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook subscription with ID %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// CreateSubscription creates a new webhook subscription with audit fields
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	query := `
+		INSERT INTO webhook_subscriptions (team_id, name, url, secret, events, active, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+
+	events, err := encodeEvents(subscription.Events)
+	if err != nil {
+		return err
+	}
+
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		teamctx.GetTeamID(ctx),
+		subscription.Name,
+		subscription.URL,
+		subscription.Secret,
+		events,
+		subscription.Active,
+		userEmail,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	// Get the inserted ID
+	id, err := result.LastInsertId()
+	if err != nil {
+		return fmt.Errorf("failed to get inserted ID: %w", err)
+	}
+
+	subscription.ID = int(id)
+	subscription.CreatedBy = userEmail
+	return nil
+}
+
+// UpdateSubscription updates an existing webhook subscription with audit fields
+func (r *webhookRepository) UpdateSubscription(ctx context.Context, subscription *models.WebhookSubscription) error {
+	events, err := encodeEvents(subscription.Events)
+	if err != nil {
+		return err
+	}
+
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+	now := time.Now()
+
+	query := `
+		UPDATE webhook_subscriptions
+		SET name = ?, url = ?, secret = ?, events = ?, active = ?, modified_by = ?, modified_at = ?
+		WHERE id = ? AND team_id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		subscription.Name,
+		subscription.URL,
+		subscription.Secret,
+		events,
+		subscription.Active,
+		userEmail,
+		now,
+		subscription.ID,
+		teamctx.GetTeamID(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook subscription with ID %d not found", subscription.ID)
+	}
+
+	subscription.ModifiedBy = userEmail
+	subscription.ModifiedAt = &now
+	return nil
+}
+
+// DeleteSubscription deletes a webhook subscription of the team together with its deliveries
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id int) error {
+	return NewTransactor(r.db).WithTx(ctx, func(ctx context.Context) error {
+		teamID := teamctx.GetTeamID(ctx)
+		deliveriesQuery := `DELETE FROM webhook_deliveries WHERE subscription_id = ? AND team_id = ?`
+		if _, err := conn(ctx, r.db).ExecContext(ctx, deliveriesQuery, id, teamID); err != nil {
+			return fmt.Errorf("failed to delete webhook deliveries: %w", err)
+		}
+
+		query := `DELETE FROM webhook_subscriptions WHERE id = ? AND team_id = ?`
+		result, err := conn(ctx, r.db).ExecContext(ctx, query, id, teamID)
+		if err != nil {
+			return fmt.Errorf("failed to delete webhook subscription: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("webhook subscription with ID %d not found", id)
+		}
+
+		return nil
+	})
+}
+
+// CreateDeliveries queues deliveries for the team in one go
+func (r *webhookRepository) CreateDeliveries(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	query := `
+		INSERT INTO webhook_deliveries (team_id, subscription_id, event_id, event, payload, status, attempts, next_attempt_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	return NewTransactor(r.db).WithTx(ctx, func(ctx context.Context) error {
+		stmt, err := conn(ctx, r.db).PrepareContext(ctx, query)
+		if err != nil {
+			return fmt.Errorf("failed to prepare webhook delivery insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, delivery := range deliveries {
+			result, err := stmt.ExecContext(ctx,
+				teamctx.GetTeamID(ctx),
+				delivery.SubscriptionID,
+				delivery.EventID,
+				delivery.Event,
+				delivery.Payload,
+				delivery.Status,
+				delivery.Attempts,
+				delivery.NextAttemptAt.UTC(),
+				delivery.CreatedAt.UTC(),
+			)
+			if err != nil {
+				return fmt.Errorf("failed to create webhook delivery: %w", err)
+			}
+
+			id, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get inserted ID: %w", err)
+			}
+			delivery.ID = int(id)
+		}
+
+		return nil
+	})
+}
+
+// selectWebhookDeliveries selects deliveries with the name of their subscription, for scanWebhookDelivery
+const selectWebhookDeliveries = `
+	SELECT d.id, d.subscription_id, s.name, d.event_id, d.event, d.payload, d.status, d.attempts,
+		   d.next_attempt_at, d.last_attempt_at, d.response_status, d.last_error, d.created_at, d.delivered_at
+	FROM webhook_deliveries d
+	JOIN webhook_subscriptions s ON s.id = d.subscription_id
+`
+
+// GetDeliveries retrieves the latest deliveries of the team, newest first
+func (r *webhookRepository) GetDeliveries(ctx context.Context, limit int) ([]models.WebhookDelivery, error) {
+	query := selectWebhookDeliveries + `
+		WHERE d.team_id = ?
+		ORDER BY d.id DESC
+		LIMIT ?
+	`
+
+	return r.queryDeliveries(ctx, query, teamctx.GetTeamID(ctx), limit)
+}
+
+// GetDeliveryByID retrieves a delivery of the team by ID
+func (r *webhookRepository) GetDeliveryByID(ctx context.Context, id int) (*models.WebhookDelivery, error) {
+	query := selectWebhookDeliveries + `
+		WHERE d.id = ? AND d.team_id = ?
+	`
+
+	delivery, err := scanWebhookDelivery(conn(ctx, r.db).QueryRowContext(ctx, query, id, teamctx.GetTeamID(ctx)))
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("webhook delivery with ID %d not found", id)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return delivery, nil
+}
+
+// GetDueDeliveries retrieves the pending deliveries of the team that are due at the given time, in
+// the order they were queued. Deliveries of paused subscriptions wait until they are active again.
+func (r *webhookRepository) GetDueDeliveries(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	query := selectWebhookDeliveries + `
+		WHERE d.team_id = ? AND d.status = ? AND d.next_attempt_at <= ? AND s.active = 1
+		ORDER BY d.id ASC
+		LIMIT ?
+	`
+
+	return r.queryDeliveries(ctx, query, teamctx.GetTeamID(ctx), models.WebhookDeliveryPending, now.UTC(), limit)
+}
+
+// queryDeliveries runs a query of selectWebhookDeliveries and scans the deliveries it returns
+func (r *webhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]models.WebhookDelivery, error) {
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	var deliveries []models.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating webhook deliveries: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// UpdateDelivery records the outcome of an attempt, or a delivery that is queued again
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, delivery *models.WebhookDelivery) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = ?, attempts = ?, next_attempt_at = ?, last_attempt_at = ?, response_status = ?, last_error = ?, delivered_at = ?
+		WHERE id = ? AND team_id = ?
+	`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query,
+		delivery.Status,
+		delivery.Attempts,
+		delivery.NextAttemptAt.UTC(),
+		utcTime(delivery.LastAttemptAt),
+		delivery.ResponseStatus,
+		delivery.LastError,
+		utcTime(delivery.DeliveredAt),
+		delivery.ID,
+		teamctx.GetTeamID(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook delivery with ID %d not found", delivery.ID)
+	}
+
+	return nil
+}
+
+// DeleteDeliveriesBefore deletes the delivered and failed deliveries of the team that were queued
+// before the given time, and returns how many there were
+func (r *webhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int, error) {
+	query := `DELETE FROM webhook_deliveries WHERE team_id = ? AND status != ? AND created_at < ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, teamctx.GetTeamID(ctx), models.WebhookDeliveryPending, before.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete webhook deliveries: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// utcTime returns an optional time in UTC, so stored times compare in order
+func utcTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
+
+// scanWebhookSubscription scans a webhook subscription row
+func scanWebhookSubscription(row rowScanner) (*models.WebhookSubscription, error) {
+	var subscription models.WebhookSubscription
+	var events string
+	var createdBy, modifiedBy sql.NullString
+	var modifiedAt sql.NullTime
+
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.Name,
+		&subscription.URL,
+		&subscription.Secret,
+		&events,
+		&subscription.Active,
+		&createdBy,
+		&modifiedBy,
+		&modifiedAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhook subscription: %w", err)
+	}
+
+	if err := json.Unmarshal([]byte(events), &subscription.Events); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook events: %w", err)
+	}
+
+	// Convert NULL values to empty string/nil
+	if createdBy.Valid {
+		subscription.CreatedBy = createdBy.String
+	}
+	if modifiedBy.Valid {
+		subscription.ModifiedBy = modifiedBy.String
+	}
+	if modifiedAt.Valid {
+		subscription.ModifiedAt = &modifiedAt.Time
+	}
+
+	return &subscription, nil
+}
+
+// scanWebhookDelivery scans a row of selectWebhookDeliveries
+func scanWebhookDelivery(row rowScanner) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	var lastAttemptAt, deliveredAt sql.NullTime
+
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.SubscriptionID,
+		&delivery.SubscriptionName,
+		&delivery.EventID,
+		&delivery.Event,
+		&delivery.Payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.NextAttemptAt,
+		&lastAttemptAt,
+		&delivery.ResponseStatus,
+		&delivery.LastError,
+		&delivery.CreatedAt,
+		&deliveredAt,
+	)
+	if err == sql.ErrNoRows {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+	}
+
+	if lastAttemptAt.Valid {
+		delivery.LastAttemptAt = &lastAttemptAt.Time
+	}
+	if deliveredAt.Valid {
+		delivery.DeliveredAt = &deliveredAt.Time
+	}
+
+	return &delivery, nil
+}
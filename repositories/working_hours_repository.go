@@ -7,16 +7,21 @@ import (
 	"time"
 
 	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/teamctx"
 	"github.com/blogem/eod-scheduler/userctx"
 )
 
 // WorkingHoursRepository interface defines working hours database operations
 type WorkingHoursRepository interface {
 	GetAll(ctx context.Context) ([]models.WorkingHours, error)
-	GetByDay(ctx context.Context, dayOfWeek int) (*models.WorkingHours, error)
+	GetByDay(ctx context.Context, dayOfWeek int, date time.Time) ([]models.WorkingHours, error)
 	GetActiveDays(ctx context.Context) ([]models.WorkingHours, error)
 	Update(ctx context.Context, hours *models.WorkingHours) error
-	UpdateByDay(ctx context.Context, dayOfWeek int, startTime, endTime string, active bool) error
+	ReplaceDay(ctx context.Context, effectiveFrom time.Time, dayOfWeek int, shifts []models.WorkingHours) error
+	DeleteChange(ctx context.Context, effectiveFrom time.Time) error
+	GetExceptions(ctx context.Context) ([]models.WorkingHoursException, error)
+	SaveException(ctx context.Context, exception *models.WorkingHoursException) error
+	DeleteException(ctx context.Context, id int) error
 }
 
 // workingHoursRepository implements WorkingHoursRepository interface
@@ -29,109 +34,74 @@ func NewWorkingHoursRepository(db *sql.DB) WorkingHoursRepository {
 	return &workingHoursRepository{db: db}
 }
 
-// GetAll retrieves all working hours configurations
+// GetAll retrieves the working hours of every version, the initial configuration first and the
+// shifts of a day ordered by start time
 func (r *workingHoursRepository) GetAll(ctx context.Context) ([]models.WorkingHours, error) {
 	query := `
-		SELECT id, day_of_week, start_time, end_time, active 
+		SELECT id, day_of_week, name, start_time, end_time, active, effective_from 
 		FROM working_hours 
-		ORDER BY day_of_week ASC
+		WHERE team_id = ?
+		ORDER BY effective_from ASC, day_of_week ASC, start_time ASC, name ASC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := conn(ctx, r.db).Query(query, teamctx.GetTeamID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query working hours: %w", err)
 	}
 	defer rows.Close()
 
-	var hours []models.WorkingHours
-	for rows.Next() {
-		var hour models.WorkingHours
-		err := rows.Scan(
-			&hour.ID,
-			&hour.DayOfWeek,
-			&hour.StartTime,
-			&hour.EndTime,
-			&hour.Active,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan working hours: %w", err)
-		}
-		hours = append(hours, hour)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating working hours: %w", err)
-	}
-
-	return hours, nil
+	return scanWorkingHoursRows(rows)
 }
 
-// GetByDay retrieves working hours configuration for a specific day
-func (r *workingHoursRepository) GetByDay(ctx context.Context, dayOfWeek int) (*models.WorkingHours, error) {
+// GetByDay retrieves the shifts of a specific day in the version in effect on the date, ordered by
+// start time
+func (r *workingHoursRepository) GetByDay(ctx context.Context, dayOfWeek int, date time.Time) ([]models.WorkingHours, error) {
 	query := `
-		SELECT id, day_of_week, start_time, end_time, active 
+		SELECT id, day_of_week, name, start_time, end_time, active, effective_from 
 		FROM working_hours 
-		WHERE day_of_week = ?
+		WHERE team_id = ? AND day_of_week = ?
+		ORDER BY start_time ASC, name ASC
 	`
 
-	var hour models.WorkingHours
-	err := r.db.QueryRow(query, dayOfWeek).Scan(
-		&hour.ID,
-		&hour.DayOfWeek,
-		&hour.StartTime,
-		&hour.EndTime,
-		&hour.Active,
-	)
-
-	if err == sql.ErrNoRows {
-		return nil, fmt.Errorf("working hours for day %d not found", dayOfWeek)
-	}
+	rows, err := conn(ctx, r.db).Query(query, teamctx.GetTeamID(ctx), dayOfWeek)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get working hours: %w", err)
 	}
+	defer rows.Close()
 
-	return &hour, nil
+	hours, err := scanWorkingHoursRows(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	hours = models.DayVersionOn(hours, dayOfWeek, date)
+	if len(hours) == 0 {
+		return nil, fmt.Errorf("working hours for day %d not found", dayOfWeek)
+	}
+
+	return hours, nil
 }
 
-// GetActiveDays retrieves all active working days
+// GetActiveDays retrieves the active shifts of every version. Days that are off from an effective
+// date on keep their inactive row, so the shifts of the version before it end on that date.
 func (r *workingHoursRepository) GetActiveDays(ctx context.Context) ([]models.WorkingHours, error) {
 	query := `
-		SELECT id, day_of_week, start_time, end_time, active 
+		SELECT id, day_of_week, name, start_time, end_time, active, effective_from 
 		FROM working_hours 
-		WHERE active = 1 
-		ORDER BY day_of_week ASC
+		WHERE team_id = ? AND (active = 1 OR effective_from IS NOT NULL) 
+		ORDER BY effective_from ASC, day_of_week ASC, start_time ASC, name ASC
 	`
 
-	rows, err := r.db.Query(query)
+	rows, err := conn(ctx, r.db).Query(query, teamctx.GetTeamID(ctx))
 	if err != nil {
 		return nil, fmt.Errorf("failed to query active working hours: %w", err)
 	}
 	defer rows.Close()
 
-	var hours []models.WorkingHours
-	for rows.Next() {
-		var hour models.WorkingHours
-		err := rows.Scan(
-			&hour.ID,
-			&hour.DayOfWeek,
-			&hour.StartTime,
-			&hour.EndTime,
-			&hour.Active,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("failed to scan working hours: %w", err)
-		}
-		hours = append(hours, hour)
-	}
-
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("error iterating active working hours: %w", err)
-	}
-
-	return hours, nil
+	return scanWorkingHoursRows(rows)
 }
 
-// Update updates existing working hours with audit fields
+// Update updates an existing shift with audit fields
 func (r *workingHoursRepository) Update(ctx context.Context, hours *models.WorkingHours) error {
 	// Get user email from context for audit
 	userEmail := userctx.GetUserEmail(ctx)
@@ -139,11 +109,11 @@ func (r *workingHoursRepository) Update(ctx context.Context, hours *models.Worki
 
 	query := `
 		UPDATE working_hours 
-		SET start_time = ?, end_time = ?, active = ?, modified_by = ?, modified_at = ? 
-		WHERE day_of_week = ?
+		SET name = ?, start_time = ?, end_time = ?, active = ?, modified_by = ?, modified_at = ? 
+		WHERE id = ? AND team_id = ?
 	`
 
-	result, err := r.db.Exec(query, hours.StartTime, hours.EndTime, hours.Active, userEmail, now, hours.DayOfWeek)
+	result, err := conn(ctx, r.db).Exec(query, hours.Name, hours.StartTime, hours.EndTime, hours.Active, userEmail, now, hours.ID, teamctx.GetTeamID(ctx))
 	if err != nil {
 		return fmt.Errorf("failed to update working hours: %w", err)
 	}
@@ -154,27 +124,173 @@ func (r *workingHoursRepository) Update(ctx context.Context, hours *models.Worki
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("working hours for day %d not found", hours.DayOfWeek)
+		return fmt.Errorf("working hours with ID %d not found", hours.ID)
 	}
 
 	return nil
 }
 
-// UpdateByDay updates working hours for a specific day with audit fields
-func (r *workingHoursRepository) UpdateByDay(ctx context.Context, dayOfWeek int, startTime, endTime string, active bool) error {
+// ReplaceDay replaces all shifts of a day in the version taking effect on the given date with the
+// given shifts in a single transaction. A zero date replaces the initial configuration.
+func (r *workingHoursRepository) ReplaceDay(ctx context.Context, effectiveFrom time.Time, dayOfWeek int, shifts []models.WorkingHours) error {
 	// Get user email from context for audit
 	userEmail := userctx.GetUserEmail(ctx)
-	now := time.Now()
+	teamID := teamctx.GetTeamID(ctx)
+	version := effectiveFromValue(effectiveFrom)
+
+	return NewTransactor(r.db).WithTx(ctx, func(ctx context.Context) error {
+		if _, err := conn(ctx, r.db).ExecContext(ctx, `DELETE FROM working_hours WHERE team_id = ? AND day_of_week = ? AND effective_from IS ?`, teamID, dayOfWeek, version); err != nil {
+			return fmt.Errorf("failed to delete working hours for day %d: %w", dayOfWeek, err)
+		}
+
+		query := `
+			INSERT INTO working_hours (team_id, day_of_week, name, start_time, end_time, active, effective_from, created_by) 
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		`
+
+		for i := range shifts {
+			shift := &shifts[i]
+			result, err := conn(ctx, r.db).ExecContext(ctx, query, teamID, dayOfWeek, shift.Name, shift.StartTime, shift.EndTime, shift.Active, version, userEmail)
+			if err != nil {
+				return fmt.Errorf("failed to create working hours for day %d: %w", dayOfWeek, err)
+			}
+
+			id, err := result.LastInsertId()
+			if err != nil {
+				return fmt.Errorf("failed to get inserted ID: %w", err)
+			}
+			shift.ID = int(id)
+			shift.DayOfWeek = dayOfWeek
+			shift.EffectiveFrom = effectiveFrom
+			shift.CreatedBy = userEmail
+		}
+
+		return nil
+	})
+}
+
+// DeleteChange deletes the versions of every day taking effect on the given date, the versions
+// before them stay in effect
+func (r *workingHoursRepository) DeleteChange(ctx context.Context, effectiveFrom time.Time) error {
+	if effectiveFrom.IsZero() {
+		return fmt.Errorf("the initial working hours can't be deleted")
+	}
+
+	query := `DELETE FROM working_hours WHERE team_id = ? AND effective_from = ?`
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, teamctx.GetTeamID(ctx), effectiveFromValue(effectiveFrom))
+	if err != nil {
+		return fmt.Errorf("failed to delete working hours change: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("no working hours change on %s", models.FormatDate(effectiveFrom))
+	}
+
+	return nil
+}
 
+// GetExceptions retrieves all working hours exceptions ordered by date
+func (r *workingHoursRepository) GetExceptions(ctx context.Context) ([]models.WorkingHoursException, error) {
 	query := `
-		UPDATE working_hours 
-		SET start_time = ?, end_time = ?, active = ?, modified_by = ?, modified_at = ? 
-		WHERE day_of_week = ?
+		SELECT id, date, kind, name, start_time, end_time, reason, created_by, modified_by, modified_at
+		FROM working_hours_exceptions
+		WHERE team_id = ?
+		ORDER BY date ASC
+	`
+
+	rows, err := conn(ctx, r.db).QueryContext(ctx, query, teamctx.GetTeamID(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query working hours exceptions: %w", err)
+	}
+	defer rows.Close()
+
+	var exceptions []models.WorkingHoursException
+	for rows.Next() {
+		var exception models.WorkingHoursException
+		var createdBy, modifiedBy sql.NullString
+		var modifiedAt sql.NullTime
+		err := rows.Scan(
+			&exception.ID,
+			&exception.Date,
+			&exception.Kind,
+			&exception.Name,
+			&exception.StartTime,
+			&exception.EndTime,
+			&exception.Reason,
+			&createdBy,
+			&modifiedBy,
+			&modifiedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan working hours exception: %w", err)
+		}
+
+		// Convert NULL values to empty string/nil
+		if createdBy.Valid {
+			exception.CreatedBy = createdBy.String
+		}
+		if modifiedBy.Valid {
+			exception.ModifiedBy = modifiedBy.String
+		}
+		if modifiedAt.Valid {
+			exception.ModifiedAt = &modifiedAt.Time
+		}
+		exceptions = append(exceptions, exception)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating working hours exceptions: %w", err)
+	}
+
+	return exceptions, nil
+}
+
+// SaveException creates the working hours exception of a date, or replaces the exception the date
+// already has
+func (r *workingHoursRepository) SaveException(ctx context.Context, exception *models.WorkingHoursException) error {
+	query := `
+		INSERT INTO working_hours_exceptions (team_id, date, kind, name, start_time, end_time, reason, created_by)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (team_id, date) DO UPDATE SET
+			kind = excluded.kind, name = excluded.name, start_time = excluded.start_time,
+			end_time = excluded.end_time, reason = excluded.reason,
+			modified_by = excluded.created_by, modified_at = CURRENT_TIMESTAMP
+		RETURNING id
 	`
 
-	result, err := r.db.Exec(query, startTime, endTime, active, userEmail, now, dayOfWeek)
+	// Get user from context
+	userEmail := userctx.GetUserEmail(ctx)
+
+	err := conn(ctx, r.db).QueryRowContext(ctx, query,
+		teamctx.GetTeamID(ctx),
+		exception.Date.Format("2006-01-02"),
+		exception.Kind,
+		exception.Name,
+		exception.StartTime,
+		exception.EndTime,
+		exception.Reason,
+		userEmail,
+	).Scan(&exception.ID)
 	if err != nil {
-		return fmt.Errorf("failed to update working hours for day %d: %w", dayOfWeek, err)
+		return fmt.Errorf("failed to save working hours exception: %w", err)
+	}
+
+	exception.CreatedBy = userEmail
+	return nil
+}
+
+// DeleteException deletes a working hours exception
+func (r *workingHoursRepository) DeleteException(ctx context.Context, id int) error {
+	query := `DELETE FROM working_hours_exceptions WHERE team_id = ? AND id = ?`
+
+	result, err := conn(ctx, r.db).ExecContext(ctx, query, teamctx.GetTeamID(ctx), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete working hours exception: %w", err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
@@ -183,8 +299,47 @@ func (r *workingHoursRepository) UpdateByDay(ctx context.Context, dayOfWeek int,
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("working hours for day %d not found", dayOfWeek)
+		return fmt.Errorf("working hours exception with ID %d not found", id)
 	}
 
 	return nil
 }
+
+// effectiveFromValue returns the value stored for an effective date, NULL for the initial configuration
+func effectiveFromValue(effectiveFrom time.Time) any {
+	if effectiveFrom.IsZero() {
+		return nil
+	}
+	return models.FormatDate(effectiveFrom)
+}
+
+// scanWorkingHoursRows scans all working hours rows
+func scanWorkingHoursRows(rows *sql.Rows) ([]models.WorkingHours, error) {
+	var hours []models.WorkingHours
+	for rows.Next() {
+		var hour models.WorkingHours
+		var effectiveFrom sql.NullTime
+		err := rows.Scan(
+			&hour.ID,
+			&hour.DayOfWeek,
+			&hour.Name,
+			&hour.StartTime,
+			&hour.EndTime,
+			&hour.Active,
+			&effectiveFrom,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan working hours: %w", err)
+		}
+		if effectiveFrom.Valid {
+			hour.EffectiveFrom = effectiveFrom.Time
+		}
+		hours = append(hours, hour)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating working hours: %w", err)
+	}
+
+	return hours, nil
+}
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// maxRuleReach limits how far the rules look for the shifts around a date
+const maxRuleReach = 366 // days
+
+// RuleViolationError is returned by manual schedule edits that break the assignment rules. The
+// edit goes through once it is confirmed.
+type RuleViolationError struct {
+	Violations []string
+}
+
+// Error lists the broken rules
+func (e *RuleViolationError) Error() string {
+	return "the change breaks the assignment rules: " + strings.Join(e.Violations, "; ")
+}
+
+// assignmentRules limit how often a member is on duty: a minimum number of working days off
+// between two shifts, and a maximum number of shifts per calendar week and month. The rules count
+// the primary shifts within working hours; backups and on-call blocks aren't bound by them.
+type assignmentRules struct {
+	minGapDays  int
+	maxPerWeek  int
+	maxPerMonth int
+	workingDays []models.WorkingHours // Shifts of every working hours version
+	holidays    *models.HolidayCalendar
+}
+
+// newAssignmentRules returns the assignment rules configured in the schedule state. Working days
+// are the dates with a shift in the working hours in effect on them, after their working hours
+// exception or holiday.
+func newAssignmentRules(state *models.ScheduleState, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) assignmentRules {
+	return assignmentRules{
+		minGapDays:  state.MinGapDays,
+		maxPerWeek:  state.MaxShiftsPerWeek,
+		maxPerMonth: state.MaxShiftsPerMonth,
+		workingDays: activeDays,
+		holidays:    holidays,
+	}
+}
+
+// isEmpty checks if none of the rules is set
+func (r assignmentRules) isEmpty() bool {
+	return r.minGapDays <= 0 && r.maxPerWeek <= 0 && r.maxPerMonth <= 0
+}
+
+// isWorkingDay checks if the date has a shift, which duty-free holidays and non-working exceptions don't
+func (r assignmentRules) isWorkingDay(date time.Time) bool {
+	return len(r.holidays.ShiftsOn(r.workingDays, date)) > 0
+}
+
+// workingDaysBetween counts the working days after the first date and before the second
+func (r assignmentRules) workingDaysBetween(first, second time.Time) int {
+	count := 0
+	for day := truncateToDate(first).AddDate(0, 0, 1); day.Before(truncateToDate(second)); day = day.AddDate(0, 0, 1) {
+		if r.isWorkingDay(day) {
+			count++
+		}
+	}
+	return count
+}
+
+// window returns the period around from and to in which shifts count towards the rules for the
+// dates in between: the calendar weeks and months they fall in, and the minimum gap around them
+func (r assignmentRules) window(from, to time.Time) (time.Time, time.Time) {
+	from, to = truncateToDate(from), truncateToDate(to)
+	first, last := from, to
+
+	if r.maxPerWeek > 0 {
+		first = from.AddDate(0, 0, -models.GetWeekdayNumber(from))
+		last = to.AddDate(0, 0, 6-models.GetWeekdayNumber(to))
+	}
+	if r.maxPerMonth > 0 {
+		if monthStart := from.AddDate(0, 0, 1-from.Day()); monthStart.Before(first) {
+			first = monthStart
+		}
+		if monthEnd := to.AddDate(0, 1, -to.Day()); monthEnd.After(last) {
+			last = monthEnd
+		}
+	}
+
+	// A shift within the minimum number of working days before from or after to is too close
+	before, after := from, to
+	for counted := 0; counted < r.minGapDays && from.Sub(before) < maxRuleReach*24*time.Hour; {
+		before = before.AddDate(0, 0, -1)
+		if r.isWorkingDay(before) {
+			counted++
+		}
+	}
+	for counted := 0; counted < r.minGapDays && after.Sub(to) < maxRuleReach*24*time.Hour; {
+		after = after.AddDate(0, 0, 1)
+		if r.isWorkingDay(after) {
+			counted++
+		}
+	}
+	if before.Before(first) {
+		first = before
+	}
+	if after.After(last) {
+		last = after
+	}
+
+	return first, last
+}
+
+// violations returns the rules a member breaks by being on duty on the date, given the dates they
+// are on duty already
+func (r assignmentRules) violations(duties dutyDates, memberID int, date time.Time) []string {
+	date = truncateToDate(date)
+	var violations []string
+
+	if r.minGapDays > 0 {
+		var previous, next time.Time
+		for _, duty := range duties[memberID] {
+			if duty.Before(date) && (previous.IsZero() || duty.After(previous)) {
+				previous = duty
+			}
+			if duty.After(date) && (next.IsZero() || duty.Before(next)) {
+				next = duty
+			}
+		}
+		if !previous.IsZero() {
+			if gap := r.workingDaysBetween(previous, date); gap < r.minGapDays {
+				violations = append(violations, r.gapViolation(gap, previous, date))
+			}
+		}
+		if !next.IsZero() {
+			if gap := r.workingDaysBetween(date, next); gap < r.minGapDays {
+				violations = append(violations, r.gapViolation(gap, date, next))
+			}
+		}
+	}
+
+	if r.maxPerWeek > 0 {
+		weekStart := date.AddDate(0, 0, -models.GetWeekdayNumber(date))
+		count := 1 + duties.count(memberID, func(duty time.Time) bool {
+			return !duty.Before(weekStart) && duty.Before(weekStart.AddDate(0, 0, 7))
+		})
+		if count > r.maxPerWeek {
+			violations = append(violations, fmt.Sprintf("would have %d shifts in the week of %s, the maximum is %d", count, weekStart.Format("Jan 2"), r.maxPerWeek))
+		}
+	}
+
+	if r.maxPerMonth > 0 {
+		count := 1 + duties.count(memberID, func(duty time.Time) bool {
+			return duty.Year() == date.Year() && duty.Month() == date.Month()
+		})
+		if count > r.maxPerMonth {
+			violations = append(violations, fmt.Sprintf("would have %d shifts in %s, the maximum is %d", count, date.Format("January 2006"), r.maxPerMonth))
+		}
+	}
+
+	return violations
+}
+
+// gapViolation describes two shifts that are too close together
+func (r assignmentRules) gapViolation(gap int, first, second time.Time) string {
+	return fmt.Sprintf("would have %d working day(s) off between %s and %s, the minimum is %d", gap, first.Format("Mon Jan 2"), second.Format("Mon Jan 2"), r.minGapDays)
+}
+
+// dutyDates holds the dates each member is on duty, by member ID
+type dutyDates map[int][]time.Time
+
+// newDutyDates collects the dates of the primary shifts within working hours
+func newDutyDates(entries []models.ScheduleEntry) dutyDates {
+	duties := make(dutyDates)
+	for _, entry := range entries {
+		if !entry.IsBackup() && !entry.IsOnCall() {
+			duties.add(entry.TeamMemberID, entry.Date)
+		}
+	}
+	return duties
+}
+
+// add records a date the member is on duty
+func (d dutyDates) add(memberID int, date time.Time) {
+	d[memberID] = append(d[memberID], truncateToDate(date))
+}
+
+// remove forgets one of the dates the member is on duty
+func (d dutyDates) remove(memberID int, date time.Time) {
+	date = truncateToDate(date)
+	for i, duty := range d[memberID] {
+		if duty.Equal(date) {
+			d[memberID] = append(d[memberID][:i], d[memberID][i+1:]...)
+			return
+		}
+	}
+}
+
+// has checks if the member is on duty on the date
+func (d dutyDates) has(memberID int, date time.Time) bool {
+	date = truncateToDate(date)
+	return d.count(memberID, date.Equal) > 0
+}
+
+// count counts the dates the member is on duty that match
+func (d dutyDates) count(memberID int, match func(time.Time) bool) int {
+	count := 0
+	for _, duty := range d[memberID] {
+		if match(duty) {
+			count++
+		}
+	}
+	return count
+}
+
+// enforceAssignmentRules goes through the assignments in chronological order and fixes every
+// assignment that breaks the rules. Preferably the member swaps with a member on duty in the same
+// shift within the next week, so nobody gets more or fewer turns; otherwise the next member in
+// rotation order takes over. Either way the other member has to be available, not on duty that day
+// yet and within the rules, and members who don't avoid the weekday go first. The existing entries
+// around the generation period count towards the rules but never change. If nobody can take over
+// the member stays on duty, and the broken rules are returned as unsatisfied constraints.
+func enforceAssignmentRules(input RotationInput, rules assignmentRules, existing []models.ScheduleEntry, assignments []Assignment) ([]Assignment, []models.UnsatisfiedConstraint) {
+	if rules.isEmpty() || len(input.Members) == 0 {
+		return assignments, nil
+	}
+
+	// Dates members are on duty up to the assignment at hand, and on every generated assignment
+	duties := newDutyDates(existing)
+	assigned := make(dutyDates)
+	for _, assignment := range assignments {
+		assigned.add(assignment.TeamMemberID, assignment.WorkingDate.Date)
+	}
+	fixer := ruleFixer{input: input, rules: rules, duties: duties, assigned: assigned}
+
+	enforced := make([]Assignment, len(assignments))
+	copy(enforced, assignments)
+
+	var unsatisfied []models.UnsatisfiedConstraint
+	for i, assignment := range enforced {
+		date := assignment.WorkingDate.Date
+		if violations := rules.violations(duties, assignment.TeamMemberID, date); len(violations) > 0 && !fixer.fix(enforced, i) {
+			if index := memberIndex(input.Members, assignment.TeamMemberID); index >= 0 {
+				for _, violation := range violations {
+					unsatisfied = append(unsatisfied, models.UnsatisfiedConstraint{
+						Date:         date,
+						Shift:        assignment.WorkingDate.WorkingHours.Name,
+						TeamMemberID: assignment.TeamMemberID,
+						Description:  input.Members[index].Name + " " + violation,
+					})
+				}
+			}
+		}
+		duties.add(enforced[i].TeamMemberID, date)
+	}
+
+	return enforced, unsatisfied
+}
+
+// ruleFixer finds another member for an assignment that breaks the assignment rules
+type ruleFixer struct {
+	input    RotationInput
+	rules    assignmentRules
+	duties   dutyDates // On duty before the assignment at hand, or in the existing entries
+	assigned dutyDates // On duty in any of the generated assignments
+}
+
+// fix swaps or hands over the assignment at index i, and reports false if nobody can take it
+func (f ruleFixer) fix(assignments []Assignment, i int) bool {
+	for _, allowAvoided := range []bool{false, true} {
+		if j, ok := f.swapPartner(assignments, i, allowAvoided); ok {
+			a, b := assignments[i], assignments[j]
+			f.assigned.remove(a.TeamMemberID, a.WorkingDate.Date)
+			f.assigned.remove(b.TeamMemberID, b.WorkingDate.Date)
+			assignments[i].TeamMemberID, assignments[j].TeamMemberID = b.TeamMemberID, a.TeamMemberID
+			f.assigned.add(b.TeamMemberID, a.WorkingDate.Date)
+			f.assigned.add(a.TeamMemberID, b.WorkingDate.Date)
+			return true
+		}
+	}
+	for _, allowAvoided := range []bool{false, true} {
+		if substituteID, ok := f.substitute(assignments[i], allowAvoided); ok {
+			f.assigned.remove(assignments[i].TeamMemberID, assignments[i].WorkingDate.Date)
+			assignments[i].TeamMemberID = substituteID
+			f.assigned.add(substituteID, assignments[i].WorkingDate.Date)
+			return true
+		}
+	}
+	return false
+}
+
+// canTake checks if a member can be on duty on the date in place of another member
+func (f ruleFixer) canTake(memberID int, date time.Time, allowAvoided bool) bool {
+	if !f.input.isAvailable(memberID, date) || f.duties.has(memberID, date) || f.assigned.has(memberID, date) {
+		return false
+	}
+	if index := memberIndex(f.input.Members, memberID); !allowAvoided && index >= 0 && f.input.Members[index].Avoids(date) {
+		return false
+	}
+	return len(f.rules.violations(f.duties, memberID, date)) == 0
+}
+
+// swapPartner returns the index of a later assignment in the same shift, at most a week later,
+// whose member can take the assignment at index i while its member takes theirs
+func (f ruleFixer) swapPartner(assignments []Assignment, i int, allowAvoided bool) (int, bool) {
+	a := assignments[i]
+	for j := i + 1; j < len(assignments); j++ {
+		b := assignments[j]
+		if b.WorkingDate.Date.Sub(a.WorkingDate.Date).Hours() > maxSwapDistance*24 {
+			break // The assignments are in chronological order
+		}
+		if b.WorkingDate.WorkingHours.Name != a.WorkingDate.WorkingHours.Name || b.TeamMemberID == a.TeamMemberID {
+			continue
+		}
+		if f.canTake(b.TeamMemberID, a.WorkingDate.Date, allowAvoided) && f.canTake(a.TeamMemberID, b.WorkingDate.Date, allowAvoided) {
+			return j, true
+		}
+	}
+	return 0, false
+}
+
+// substitute returns the next member in rotation order after the member of the assignment who can
+// take it over
+func (f ruleFixer) substitute(assignment Assignment, allowAvoided bool) (int, bool) {
+	next := memberIndex(f.input.Members, assignment.TeamMemberID) + 1
+	for i := range f.input.Members {
+		member := f.input.Members[(next+i)%len(f.input.Members)]
+		if member.ID != assignment.TeamMemberID && f.canTake(member.ID, assignment.WorkingDate.Date, allowAvoided) {
+			return member.ID, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// rulesFor returns the assignment rules for a Monday to Friday week
+func rulesFor(minGapDays, maxPerWeek, maxPerMonth int, holidays ...models.Holiday) assignmentRules {
+	state := &models.ScheduleState{MinGapDays: minGapDays, MaxShiftsPerWeek: maxPerWeek, MaxShiftsPerMonth: maxPerMonth}
+	return newAssignmentRules(state, weekdaysMonToFri(), models.NewHolidayCalendar(holidays))
+}
+
+func TestAssignmentRulesViolations(t *testing.T) {
+	date := func(value string) models.ScheduleEntry { return historyEntry(value, 1) }
+
+	testCases := []struct {
+		name     string
+		rules    assignmentRules
+		existing []models.ScheduleEntry
+		date     string
+		expected []string
+	}{
+		{
+			name:     "Friday then Monday has no working day in between",
+			rules:    rulesFor(1, 0, 0),
+			existing: []models.ScheduleEntry{date("2023-09-29")},
+			date:     "2023-10-02",
+			expected: []string{"would have 0 working day(s) off between Fri Sep 29 and Mon Oct 2, the minimum is 1"},
+		},
+		{
+			name:     "a duty-free holiday isn't a day off",
+			rules:    rulesFor(1, 0, 0, models.Holiday{Date: testMonday, Name: "Closed"}),
+			existing: []models.ScheduleEntry{date("2023-09-29")},
+			date:     "2023-10-03",
+			expected: []string{"would have 0 working day(s) off between Fri Sep 29 and Tue Oct 3, the minimum is 1"},
+		},
+		{
+			name:     "the gap to a later shift counts too",
+			rules:    rulesFor(2, 0, 0),
+			existing: []models.ScheduleEntry{date("2023-09-28"), date("2023-10-05")},
+			date:     "2023-10-03",
+			expected: []string{
+				"would have 1 working day(s) off between Tue Oct 3 and Thu Oct 5, the minimum is 2",
+			},
+		},
+		{
+			name:     "enough days off",
+			rules:    rulesFor(2, 0, 0),
+			existing: []models.ScheduleEntry{date("2023-09-27")},
+			date:     "2023-10-02",
+		},
+		{
+			name:     "backups and on-call blocks don't count",
+			rules:    rulesFor(1, 0, 0),
+			existing: []models.ScheduleEntry{backupEntry("2023-09-29", 1), {Date: testMonday.AddDate(0, 0, 1), TeamMemberID: 1, Layer: models.ScheduleLayerOnCall}},
+			date:     "2023-10-02",
+		},
+		{
+			name:     "shifts per calendar week",
+			rules:    rulesFor(0, 2, 0),
+			existing: []models.ScheduleEntry{date("2023-09-29"), date("2023-10-02"), date("2023-10-04")},
+			date:     "2023-10-06",
+			expected: []string{"would have 3 shifts in the week of Oct 2, the maximum is 2"},
+		},
+		{
+			name:     "shifts per calendar month",
+			rules:    rulesFor(0, 0, 2),
+			existing: []models.ScheduleEntry{date("2023-09-29"), date("2023-10-02"), date("2023-10-16")},
+			date:     "2023-10-31",
+			expected: []string{"would have 3 shifts in October 2023, the maximum is 2"},
+		},
+		{
+			name:     "other members don't count",
+			rules:    rulesFor(1, 1, 1),
+			existing: []models.ScheduleEntry{historyEntry("2023-09-29", 2), historyEntry("2023-10-03", 3)},
+			date:     "2023-10-02",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			parsed, _ := models.ParseDate(tc.date)
+			assert.Equal(t, tc.expected, tc.rules.violations(newDutyDates(tc.existing), 1, parsed))
+		})
+	}
+}
+
+func TestAssignmentRulesWindow(t *testing.T) {
+	testCases := []struct {
+		name     string
+		rules    assignmentRules
+		from, to string
+	}{
+		{name: "calendar week", rules: rulesFor(0, 1, 0), from: "2023-10-02", to: "2023-10-08"},
+		{name: "calendar month", rules: rulesFor(0, 0, 1), from: "2023-10-01", to: "2023-10-31"},
+		{name: "three working days around the date", rules: rulesFor(3, 0, 0), from: "2023-09-27", to: "2023-10-05"},
+		{name: "the widest of the rules", rules: rulesFor(3, 1, 0), from: "2023-09-27", to: "2023-10-08"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			from, to := tc.rules.window(testMonday, testMonday)
+			assert.Equal(t, tc.from, models.FormatDate(from))
+			assert.Equal(t, tc.to, models.FormatDate(to))
+		})
+	}
+}
+
+func TestEnforceAssignmentRules(t *testing.T) {
+	testCases := []struct {
+		name        string
+		rules       assignmentRules
+		existing    []models.ScheduleEntry
+		timeOff     []models.TimeOff
+		assigned    []int
+		expected    []int
+		unsatisfied []string
+	}{
+		{
+			name:     "rotation within the rules is kept",
+			rules:    rulesFor(1, 2, 0),
+			assigned: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+			expected: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+		},
+		{
+			name:     "Monday after Alice's Friday goes to the next member",
+			rules:    rulesFor(1, 0, 0),
+			existing: []models.ScheduleEntry{historyEntry("2023-09-29", 1)},
+			assigned: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+			expected: []int{2, 1, 3, 1, 2, 3, 1, 2, 3, 1},
+		},
+		{
+			name:     "a roster change giving Alice three shifts in a week",
+			rules:    rulesFor(0, 2, 0),
+			assigned: []int{1, 2, 1, 3, 1, 2, 3, 1, 2, 3},
+			expected: []int{1, 2, 1, 3, 2, 1, 3, 1, 2, 3},
+		},
+		{
+			name:     "swaps keep to the rules for both members",
+			rules:    rulesFor(1, 0, 0),
+			existing: []models.ScheduleEntry{historyEntry("2023-09-29", 1), historyEntry("2023-09-29", 2)},
+			assigned: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+			expected: []int{3, 2, 1, 2, 1, 3, 1, 2, 3, 1},
+		},
+		{
+			name:     "the next member takes over if nobody can swap",
+			rules:    rulesFor(1, 0, 0),
+			existing: []models.ScheduleEntry{historyEntry("2023-09-29", 1)},
+			assigned: []int{1},
+			expected: []int{2},
+		},
+		{
+			name:     "nobody else can take over",
+			rules:    rulesFor(1, 0, 0),
+			existing: []models.ScheduleEntry{historyEntry("2023-09-29", 1), historyEntry("2023-09-29", 2)},
+			timeOff:  []models.TimeOff{timeOffPeriod(3, "2023-10-02", "2023-10-02")},
+			assigned: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+			expected: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+			unsatisfied: []string{
+				"Alice would have 0 working day(s) off between Fri Sep 29 and Mon Oct 2, the minimum is 1",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dates := workingDatesFrom(testMonday, len(tc.assigned))
+			var assignments []Assignment
+			for i, workingDate := range dates {
+				assignments = append(assignments, Assignment{WorkingDate: workingDate, TeamMemberID: tc.assigned[i]})
+			}
+			input := RotationInput{
+				Start:       testMonday,
+				End:         testMonday.AddDate(0, 0, 14),
+				Dates:       dates,
+				Members:     threeMembers,
+				WorkingDays: weekdaysMonToFri(),
+				TimeOff:     tc.timeOff,
+			}
+
+			enforced, unsatisfied := enforceAssignmentRules(input, tc.rules, tc.existing, assignments)
+			assert.Equal(t, tc.expected, assignedIDs(enforced))
+
+			var descriptions []string
+			for _, constraint := range unsatisfied {
+				assert.Equal(t, 1, constraint.TeamMemberID)
+				assert.False(t, constraint.Hard)
+				descriptions = append(descriptions, constraint.Description)
+			}
+			assert.Equal(t, tc.unsatisfied, descriptions)
+		})
+	}
+}
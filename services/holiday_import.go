@@ -0,0 +1,213 @@
+package services
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// maxEventDays limits how many holidays a single multi-day calendar event expands into
+const maxEventDays = 31
+
+// icsEvent is an all-day event read from an iCalendar file
+type icsEvent struct {
+	Name      string
+	Start     time.Time
+	Days      int  // Number of days the event spans, at least 1
+	Recurring bool // Repeats every year
+}
+
+// parseICS reads the events of an iCalendar (.ics) file as used by public holiday calendars.
+// Only the date part of start and end times is used, events repeating yearly without an end
+// are reported as recurring.
+func parseICS(r io.Reader) ([]icsEvent, error) {
+	lines, err := unfoldICSLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []icsEvent
+	var current map[string]icsProperty
+	seenCalendar := false
+
+	for lineNumber, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		property, err := parseICSProperty(line)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
+		}
+
+		switch {
+		case property.name == "BEGIN" && strings.EqualFold(property.value, "VCALENDAR"):
+			seenCalendar = true
+		case property.name == "BEGIN" && strings.EqualFold(property.value, "VEVENT"):
+			current = make(map[string]icsProperty)
+		case property.name == "END" && strings.EqualFold(property.value, "VEVENT"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN:VEVENT", lineNumber+1)
+			}
+			event, err := newICSEvent(current)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNumber+1, err)
+			}
+			events = append(events, event)
+			current = nil
+		case current != nil:
+			// Keep the first occurrence, nested components like VALARM come after the event properties
+			if _, exists := current[property.name]; !exists {
+				current[property.name] = property
+			}
+		}
+	}
+
+	if !seenCalendar {
+		return nil, fmt.Errorf("not an iCalendar file: missing BEGIN:VCALENDAR")
+	}
+
+	return events, nil
+}
+
+// icsProperty is a single content line of an iCalendar file
+type icsProperty struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// unfoldICSLines splits the file in content lines, joining lines that were folded
+// onto continuation lines starting with a space or tab
+func unfoldICSLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+	return lines, nil
+}
+
+// parseICSProperty parses a content line like DTSTART;VALUE=DATE:20251225
+func parseICSProperty(line string) (icsProperty, error) {
+	// The value starts after the first colon that isn't inside a quoted parameter value
+	inQuotes := false
+	separator := -1
+	for i, c := range line {
+		if c == '"' {
+			inQuotes = !inQuotes
+		} else if c == ':' && !inQuotes {
+			separator = i
+			break
+		}
+	}
+	if separator < 0 {
+		return icsProperty{}, fmt.Errorf("invalid content line %q", line)
+	}
+
+	parts := strings.Split(line[:separator], ";")
+	property := icsProperty{
+		name:   strings.ToUpper(parts[0]),
+		params: make(map[string]string),
+		value:  line[separator+1:],
+	}
+	for _, param := range parts[1:] {
+		if key, value, ok := strings.Cut(param, "="); ok {
+			property.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+		}
+	}
+
+	return property, nil
+}
+
+// newICSEvent builds an event from its properties
+func newICSEvent(properties map[string]icsProperty) (icsEvent, error) {
+	name := unescapeICSText(properties["SUMMARY"].value)
+	if name == "" {
+		name = "Holiday"
+	}
+
+	startProperty, ok := properties["DTSTART"]
+	if !ok {
+		return icsEvent{}, fmt.Errorf("event %q has no start date", name)
+	}
+	start, _, err := parseICSDate(startProperty.value)
+	if err != nil {
+		return icsEvent{}, fmt.Errorf("event %q: invalid start date: %w", name, err)
+	}
+
+	event := icsEvent{Name: name, Start: start, Days: 1}
+
+	if endProperty, ok := properties["DTEND"]; ok {
+		end, hasTime, err := parseICSDate(endProperty.value)
+		if err != nil {
+			return icsEvent{}, fmt.Errorf("event %q: invalid end date: %w", name, err)
+		}
+
+		// All-day events end on the day after, timed events on the day itself
+		days := int(end.Sub(start).Hours() / 24)
+		if hasTime {
+			days++
+		}
+		event.Days = min(max(days, 1), maxEventDays)
+	}
+
+	if rule, ok := properties["RRULE"]; ok {
+		event.Recurring = isYearlyRule(rule.value)
+	}
+
+	return event, nil
+}
+
+// parseICSDate parses a DATE (20251225) or DATE-TIME (20251225T090000Z) value. It reports if the
+// value had a time other than midnight.
+func parseICSDate(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 8 {
+		return time.Time{}, false, fmt.Errorf("%q is not a date", value)
+	}
+
+	date, err := time.Parse("20060102", value[:8])
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("%q is not a date", value)
+	}
+
+	clock := strings.TrimSuffix(value[8:], "Z")
+	hasTime := clock != "" && clock != "T000000"
+	return date, hasTime, nil
+}
+
+// isYearlyRule checks if a recurrence rule repeats every year without ever ending
+func isYearlyRule(rule string) bool {
+	yearly := false
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "FREQ":
+			yearly = value == "YEARLY"
+		case "INTERVAL":
+			if value != "1" {
+				return false
+			}
+		case "COUNT", "UNTIL":
+			return false
+		}
+	}
+	return yearly
+}
+
+// unescapeICSText reverts the escaping of TEXT values
+func unescapeICSText(text string) string {
+	replacer := strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, " ", `\N`, " ")
+	return strings.TrimSpace(replacer.Replace(text))
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+)
+
+// HolidayService interface defines holiday business logic
+type HolidayService interface {
+	GetAllHolidays(ctx context.Context) ([]models.Holiday, error)
+	GetHolidayByID(ctx context.Context, id int) (*models.Holiday, error)
+	CreateHoliday(ctx context.Context, form *models.HolidayForm) (*models.Holiday, error)
+	UpdateHoliday(ctx context.Context, id int, form *models.HolidayForm) (*models.Holiday, error)
+	DeleteHoliday(ctx context.Context, id int) error
+	ImportCalendar(ctx context.Context, calendar io.Reader, settings *models.HolidayForm) (*HolidayImportResult, error)
+}
+
+// HolidayImportResult represents the outcome of importing a holiday calendar
+type HolidayImportResult struct {
+	Imported int `json:"imported"`
+	Skipped  int `json:"skipped"` // Holidays that already existed
+}
+
+// holidayService implements HolidayService interface
+type holidayService struct {
+	holidayRepo repositories.HolidayRepository
+}
+
+// NewHolidayService creates a new holiday service
+func NewHolidayService(holidayRepo repositories.HolidayRepository) HolidayService {
+	return &holidayService{
+		holidayRepo: holidayRepo,
+	}
+}
+
+// GetAllHolidays retrieves all holidays
+func (s *holidayService) GetAllHolidays(ctx context.Context) ([]models.Holiday, error) {
+	return s.holidayRepo.GetAll(ctx)
+}
+
+// GetHolidayByID retrieves a holiday by ID
+func (s *holidayService) GetHolidayByID(ctx context.Context, id int) (*models.Holiday, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid holiday ID: %d", id)
+	}
+	return s.holidayRepo.GetByID(ctx, id)
+}
+
+// CreateHoliday creates a new holiday
+func (s *holidayService) CreateHoliday(ctx context.Context, form *models.HolidayForm) (*models.Holiday, error) {
+	holiday, err := newHolidayFromForm(form)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.holidayRepo.Create(ctx, holiday); err != nil {
+		return nil, fmt.Errorf("failed to create holiday: %w", err)
+	}
+
+	return holiday, nil
+}
+
+// UpdateHoliday updates an existing holiday
+func (s *holidayService) UpdateHoliday(ctx context.Context, id int, form *models.HolidayForm) (*models.Holiday, error) {
+	if _, err := s.GetHolidayByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("holiday not found: %w", err)
+	}
+
+	holiday, err := newHolidayFromForm(form)
+	if err != nil {
+		return nil, err
+	}
+	holiday.ID = id
+
+	if err := s.holidayRepo.Update(ctx, holiday); err != nil {
+		return nil, fmt.Errorf("failed to update holiday: %w", err)
+	}
+
+	return holiday, nil
+}
+
+// DeleteHoliday deletes a holiday
+func (s *holidayService) DeleteHoliday(ctx context.Context, id int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid holiday ID: %d", id)
+	}
+	return s.holidayRepo.Delete(ctx, id)
+}
+
+// ImportCalendar creates a holiday for every day of the events in an iCalendar file. The behavior
+// and alternative hours come from settings. Holidays that already exist are skipped, so importing
+// the same file twice is harmless.
+func (s *holidayService) ImportCalendar(ctx context.Context, calendar io.Reader, settings *models.HolidayForm) (*HolidayImportResult, error) {
+	events, err := parseICS(calendar)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	existing, err := s.holidayRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+
+	known := make(map[string]bool)
+	for _, holiday := range existing {
+		known[holidayKey(holiday)] = true
+	}
+
+	result := &HolidayImportResult{}
+	for _, event := range events {
+		for day := 0; day < event.Days; day++ {
+			form := *settings
+			form.Date = models.FormatDate(event.Start.AddDate(0, 0, day))
+			form.Name = event.Name
+			form.Recurring = event.Recurring
+
+			holiday, err := newHolidayFromForm(&form)
+			if err != nil {
+				return result, fmt.Errorf("invalid holiday %q on %s: %w", event.Name, form.Date, err)
+			}
+
+			key := holidayKey(*holiday)
+			if known[key] {
+				result.Skipped++
+				continue
+			}
+
+			if err := s.holidayRepo.Create(ctx, holiday); err != nil {
+				return result, fmt.Errorf("failed to create holiday: %w", err)
+			}
+			known[key] = true
+			result.Imported++
+		}
+	}
+
+	return result, nil
+}
+
+// newHolidayFromForm validates the form and converts it into a holiday
+func newHolidayFromForm(form *models.HolidayForm) (*models.Holiday, error) {
+	if errors := form.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+	}
+
+	date, _ := models.ParseDate(strings.TrimSpace(form.Date))
+	holiday := &models.Holiday{
+		Date:      date,
+		Name:      strings.TrimSpace(form.Name),
+		Recurring: form.Recurring,
+		Behavior:  form.Behavior,
+	}
+
+	// Only keep the hours when they are used
+	if form.Behavior == models.HolidayBehaviorAlternativeHours {
+		holiday.StartTime = strings.TrimSpace(form.StartTime)
+		holiday.EndTime = strings.TrimSpace(form.EndTime)
+	}
+
+	return holiday, nil
+}
+
+// holidayKey identifies a holiday for duplicate detection: recurring holidays by month and day,
+// others by date
+func holidayKey(holiday models.Holiday) string {
+	date := models.FormatDate(holiday.Date)
+	if holiday.Recurring {
+		date = holiday.Date.Format("01-02")
+	}
+	return date + "|" + strings.ToLower(holiday.Name)
+}
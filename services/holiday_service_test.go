@@ -0,0 +1,252 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+// testCalendar is a holiday calendar as exported by common calendar applications
+const testCalendar = "BEGIN:VCALENDAR\r\n" +
+	"VERSION:2.0\r\n" +
+	"PRODID:-//Test//Holidays//EN\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20231225\r\n" +
+	"DTEND;VALUE=DATE:20231226\r\n" +
+	"RRULE:FREQ=YEARLY\r\n" +
+	"SUMMARY:Christmas Day\r\n" +
+	"END:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\n" +
+	"DTSTART;VALUE=DATE:20231227\r\n" +
+	"DTEND;VALUE=DATE:20231230\r\n" +
+	"SUMMARY:Office closed between Christmas\\, New Year and the\r\n" +
+	"  weekend\r\n" +
+	"END:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParseICS(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := models.ParseDate(value)
+		return parsed
+	}
+
+	testCases := []struct {
+		name          string
+		calendar      string
+		expected      []icsEvent
+		expectedError string
+	}{
+		{
+			name:     "all-day, multi-day and recurring events",
+			calendar: testCalendar,
+			expected: []icsEvent{
+				{Name: "Christmas Day", Start: date("2023-12-25"), Days: 1, Recurring: true},
+				{Name: "Office closed between Christmas, New Year and the weekend", Start: date("2023-12-27"), Days: 3},
+			},
+		},
+		{
+			name: "timed event ends on the same day",
+			calendar: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:20231224T090000Z\nDTEND:20231224T130000Z\n" +
+				"SUMMARY:Christmas Eve\nEND:VEVENT\nEND:VCALENDAR\n",
+			expected: []icsEvent{{Name: "Christmas Eve", Start: date("2023-12-24"), Days: 1}},
+		},
+		{
+			name: "yearly rules that end aren't recurring",
+			calendar: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20230501\nRRULE:FREQ=YEARLY;COUNT=3\n" +
+				"END:VEVENT\nEND:VCALENDAR\n",
+			expected: []icsEvent{{Name: "Holiday", Start: date("2023-05-01"), Days: 1}},
+		},
+		{
+			name: "nested components don't override event properties",
+			calendar: "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART;VALUE=DATE:20230501\nSUMMARY:Labour Day\n" +
+				"BEGIN:VALARM\nSUMMARY:Reminder\nEND:VALARM\nEND:VEVENT\nEND:VCALENDAR\n",
+			expected: []icsEvent{{Name: "Labour Day", Start: date("2023-05-01"), Days: 1}},
+		},
+		{
+			name:          "not a calendar",
+			calendar:      "name,date\nChristmas,2023-12-25\n",
+			expectedError: "invalid content line",
+		},
+		{
+			name:          "missing calendar",
+			calendar:      "BEGIN:VEVENT\nDTSTART:20231225\nEND:VEVENT\n",
+			expectedError: "missing BEGIN:VCALENDAR",
+		},
+		{
+			name:          "event without start",
+			calendar:      "BEGIN:VCALENDAR\nBEGIN:VEVENT\nSUMMARY:Someday\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedError: `event "Someday" has no start date`,
+		},
+		{
+			name:          "invalid start",
+			calendar:      "BEGIN:VCALENDAR\nBEGIN:VEVENT\nDTSTART:2023-12-25\nEND:VEVENT\nEND:VCALENDAR\n",
+			expectedError: "invalid start date",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			events, err := parseICS(strings.NewReader(tc.calendar))
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expected, events)
+		})
+	}
+}
+
+// HolidayServiceTestSuite is a test suite for the holiday service
+type HolidayServiceTestSuite struct {
+	suite.Suite
+	service         HolidayService
+	mockHolidayRepo *dbMocks.MockHolidayRepository
+}
+
+// SetupTest sets up the test suite before each test
+func (suite *HolidayServiceTestSuite) SetupTest() {
+	suite.mockHolidayRepo = dbMocks.NewMockHolidayRepository(suite.T())
+	suite.service = NewHolidayService(suite.mockHolidayRepo)
+}
+
+// TestCreateHoliday tests that hours are only kept for holidays with alternative hours
+func (suite *HolidayServiceTestSuite) TestCreateHoliday() {
+	ctx := context.Background()
+
+	suite.mockHolidayRepo.EXPECT().Create(ctx, mock.MatchedBy(func(holiday *models.Holiday) bool {
+		return holiday.Name == "Christmas Day" && holiday.StartTime == "" && holiday.EndTime == ""
+	})).Return(nil)
+
+	holiday, err := suite.service.CreateHoliday(ctx, &models.HolidayForm{
+		Date: "2023-12-25", Name: " Christmas Day ", Recurring: true, Behavior: models.HolidayBehaviorNoDuty,
+		StartTime: "09:00", EndTime: "12:00",
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), holiday.Recurring)
+	assert.Equal(suite.T(), "2023-12-25", holiday.GetFormattedDate())
+
+	// Invalid forms never reach the repository
+	_, err = suite.service.CreateHoliday(ctx, &models.HolidayForm{Date: "2023-12-25", Behavior: models.HolidayBehaviorNoDuty})
+	assert.ErrorContains(suite.T(), err, "Name is required")
+}
+
+// TestImportCalendar tests importing an iCalendar file
+func (suite *HolidayServiceTestSuite) TestImportCalendar() {
+	ctx := context.Background()
+
+	testCases := []struct {
+		name             string
+		calendar         string
+		settings         models.HolidayForm
+		setupMocks       func(created *[]models.Holiday)
+		expectedResult   *HolidayImportResult
+		expectedError    string
+		expectedHolidays []string
+	}{
+		{
+			name:     "multi-day events become one holiday per day",
+			calendar: testCalendar,
+			settings: models.HolidayForm{Behavior: models.HolidayBehaviorNoDuty},
+			setupMocks: func(created *[]models.Holiday) {
+				suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, nil)
+				suite.expectCreate(ctx, created)
+			},
+			expectedResult:   &HolidayImportResult{Imported: 4},
+			expectedHolidays: []string{"12-25 recurring", "2023-12-27", "2023-12-28", "2023-12-29"},
+		},
+		{
+			name:     "existing holidays are skipped",
+			calendar: testCalendar,
+			settings: models.HolidayForm{Behavior: models.HolidayBehaviorAlternativeHours, StartTime: "10:00", EndTime: "12:00"},
+			setupMocks: func(created *[]models.Holiday) {
+				suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return([]models.Holiday{
+					{ID: 1, Date: time.Date(2020, 12, 25, 0, 0, 0, 0, time.UTC), Name: "christmas day", Recurring: true},
+					{ID: 2, Date: time.Date(2023, 12, 28, 0, 0, 0, 0, time.UTC), Name: "Office closed between Christmas, New Year and the weekend"},
+				}, nil)
+				suite.expectCreate(ctx, created)
+			},
+			expectedResult:   &HolidayImportResult{Imported: 2, Skipped: 2},
+			expectedHolidays: []string{"2023-12-27", "2023-12-29"},
+		},
+		{
+			name:          "invalid settings",
+			calendar:      testCalendar,
+			settings:      models.HolidayForm{Behavior: models.HolidayBehaviorAlternativeHours},
+			setupMocks:    func(created *[]models.Holiday) { suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, nil) },
+			expectedError: "Start time must be in HH:MM format",
+		},
+		{
+			name:          "invalid calendar",
+			calendar:      "not a calendar",
+			settings:      models.HolidayForm{Behavior: models.HolidayBehaviorNoDuty},
+			setupMocks:    func(created *[]models.Holiday) {},
+			expectedError: "failed to read calendar",
+		},
+		{
+			name:     "repository error",
+			calendar: testCalendar,
+			settings: models.HolidayForm{Behavior: models.HolidayBehaviorNoDuty},
+			setupMocks: func(created *[]models.Holiday) {
+				suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, errors.New("database error"))
+			},
+			expectedError: "failed to get holidays",
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+
+			var created []models.Holiday
+			tc.setupMocks(&created)
+
+			result, err := suite.service.ImportCalendar(ctx, strings.NewReader(tc.calendar), &tc.settings)
+			if tc.expectedError != "" {
+				assert.ErrorContains(suite.T(), err, tc.expectedError)
+				return
+			}
+
+			assert.NoError(suite.T(), err)
+			assert.Equal(suite.T(), tc.expectedResult, result)
+
+			var holidays []string
+			for _, holiday := range created {
+				if holiday.Recurring {
+					holidays = append(holidays, holiday.Date.Format("01-02")+" recurring")
+				} else {
+					holidays = append(holidays, holiday.GetFormattedDate())
+				}
+				assert.Equal(suite.T(), tc.settings.Behavior, holiday.Behavior)
+				assert.Equal(suite.T(), tc.settings.StartTime, holiday.StartTime)
+			}
+			assert.Equal(suite.T(), tc.expectedHolidays, holidays)
+		})
+	}
+}
+
+// expectCreate records the holidays created in the repository
+func (suite *HolidayServiceTestSuite) expectCreate(ctx context.Context, created *[]models.Holiday) {
+	suite.mockHolidayRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.Holiday")).RunAndReturn(
+		func(ctx context.Context, holiday *models.Holiday) error {
+			*created = append(*created, *holiday)
+			return nil
+		},
+	)
+}
+
+// TestRunHolidayServiceTestSuite runs the test suite
+func TestRunHolidayServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(HolidayServiceTestSuite))
+}
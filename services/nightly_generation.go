@@ -0,0 +1,78 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// NightlyGeneration extends the schedule of every team each night, so each of them always covers
+// its full horizon
+type NightlyGeneration struct {
+	teamsRepo repositories.TeamsRepository
+	schedule  ScheduleService
+	runAt     time.Duration // Time of day the run starts, as the time since midnight
+}
+
+// NewNightlyGeneration creates the nightly generation run
+func NewNightlyGeneration(teamsRepo repositories.TeamsRepository, schedule ScheduleService, runAt time.Duration) *NightlyGeneration {
+	return &NightlyGeneration{
+		teamsRepo: teamsRepo,
+		schedule:  schedule,
+		runAt:     runAt,
+	}
+}
+
+// Start runs the generation every night until the context is done
+func (n *NightlyGeneration) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(nextNightlyRun(time.Now(), n.runAt)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			n.Run(ctx)
+		}
+	}
+}
+
+// Run extends the schedule of every team once. A team that fails doesn't stop the others, its
+// outcome shows on its dashboard.
+func (n *NightlyGeneration) Run(ctx context.Context) {
+	teams, err := n.teamsRepo.GetAll(ctx)
+	if err != nil {
+		log.Printf("Nightly generation failed to get the teams: %v", err)
+		return
+	}
+
+	// Entries created by the run are attributed to the system
+	ctx = userctx.SetUserEmail(ctx, "system")
+	for i := range teams {
+		team := &teams[i]
+		result, err := n.schedule.RunGeneration(teamctx.SetTeam(ctx, team), models.GenerationTriggerAutomatic)
+		switch {
+		case err != nil:
+			log.Printf("Nightly generation failed for team %s: %v", team.Slug, err)
+		case !result.Success:
+			log.Printf("Nightly generation skipped team %s: %s", team.Slug, result.Message)
+		default:
+			log.Printf("Nightly generation for team %s: %s", team.Slug, result.Message)
+		}
+	}
+}
+
+// nextNightlyRun returns the first time after now at the given time of day
+func nextNightlyRun(now time.Time, runAt time.Duration) time.Time {
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	next := midnight.Add(runAt)
+	if !next.After(now) {
+		next = midnight.AddDate(0, 0, 1).Add(runAt)
+	}
+	return next
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// runRecorder is a schedule service that records the generation runs it was asked for
+type runRecorder struct {
+	ScheduleService
+	runs []string
+}
+
+func (r *runRecorder) RunGeneration(ctx context.Context, trigger string) (*models.GenerationResult, error) {
+	team := teamctx.GetTeam(ctx)
+	r.runs = append(r.runs, team.Slug+" "+trigger+" by "+userctx.GetUserEmail(ctx))
+	if team.Slug == "broken" {
+		return nil, errors.New("database is locked")
+	}
+	return &models.GenerationResult{Success: true}, nil
+}
+
+func TestNightlyGenerationRun(t *testing.T) {
+	ctx := context.Background()
+	mockTeamsRepo := dbMocks.NewMockTeamsRepository(t)
+	mockTeamsRepo.EXPECT().GetAll(ctx).Return([]models.Team{
+		{ID: 1, Name: "Default", Slug: "default"},
+		{ID: 2, Name: "Broken", Slug: "broken"},
+		{ID: 3, Name: "Platform", Slug: "platform"},
+	}, nil)
+	schedule := &runRecorder{}
+
+	NewNightlyGeneration(mockTeamsRepo, schedule, 2*time.Hour).Run(ctx)
+
+	// A failing team doesn't stop the teams after it
+	assert.Equal(t, []string{
+		"default automatic by system",
+		"broken automatic by system",
+		"platform automatic by system",
+	}, schedule.runs)
+}
+
+func TestNextNightlyRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		now      time.Time
+		expected time.Time
+	}{
+		{
+			name:     "before the run time",
+			now:      time.Date(2023, 10, 2, 1, 30, 0, 0, time.UTC),
+			expected: time.Date(2023, 10, 2, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "at the run time",
+			now:      time.Date(2023, 10, 2, 2, 0, 0, 0, time.UTC),
+			expected: time.Date(2023, 10, 3, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "after the run time",
+			now:      time.Date(2023, 10, 31, 14, 0, 0, 0, time.UTC),
+			expected: time.Date(2023, 11, 1, 2, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, nextNightlyRun(tc.now, 2*time.Hour))
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+)
+
+// NotificationService interface defines the business logic of the notification channels of a team
+type NotificationService interface {
+	GetChannels(ctx context.Context) ([]models.NotificationChannel, error)
+	GetChannelByID(ctx context.Context, id int) (*models.NotificationChannel, error)
+	CreateChannel(ctx context.Context, form *models.NotificationChannelForm) (*models.NotificationChannel, error)
+	UpdateChannel(ctx context.Context, id int, form *models.NotificationChannelForm) (*models.NotificationChannel, error)
+	DeleteChannel(ctx context.Context, id int) error
+}
+
+// notificationService implements NotificationService interface
+type notificationService struct {
+	channelRepo repositories.NotificationChannelRepository
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(channelRepo repositories.NotificationChannelRepository) NotificationService {
+	return &notificationService{
+		channelRepo: channelRepo,
+	}
+}
+
+// GetChannels retrieves all notification channels of the team
+func (s *notificationService) GetChannels(ctx context.Context) ([]models.NotificationChannel, error) {
+	return s.channelRepo.GetAll(ctx)
+}
+
+// GetChannelByID retrieves a notification channel by ID
+func (s *notificationService) GetChannelByID(ctx context.Context, id int) (*models.NotificationChannel, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid notification channel ID: %d", id)
+	}
+	return s.channelRepo.GetByID(ctx, id)
+}
+
+// CreateChannel creates a new notification channel
+func (s *notificationService) CreateChannel(ctx context.Context, form *models.NotificationChannelForm) (*models.NotificationChannel, error) {
+	channel, err := newChannelFromForm(form)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.channelRepo.Create(ctx, channel); err != nil {
+		return nil, fmt.Errorf("failed to create notification channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// UpdateChannel updates an existing notification channel
+func (s *notificationService) UpdateChannel(ctx context.Context, id int, form *models.NotificationChannelForm) (*models.NotificationChannel, error) {
+	if _, err := s.GetChannelByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("notification channel not found: %w", err)
+	}
+
+	channel, err := newChannelFromForm(form)
+	if err != nil {
+		return nil, err
+	}
+	channel.ID = id
+
+	if err := s.channelRepo.Update(ctx, channel); err != nil {
+		return nil, fmt.Errorf("failed to update notification channel: %w", err)
+	}
+
+	return channel, nil
+}
+
+// DeleteChannel deletes a notification channel
+func (s *notificationService) DeleteChannel(ctx context.Context, id int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid notification channel ID: %d", id)
+	}
+	return s.channelRepo.Delete(ctx, id)
+}
+
+// newChannelFromForm validates the form and creates the notification channel it describes
+func newChannelFromForm(form *models.NotificationChannelForm) (*models.NotificationChannel, error) {
+	if errors := form.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+	}
+
+	return &models.NotificationChannel{
+		Name:   strings.TrimSpace(form.Name),
+		Kind:   form.Kind,
+		Target: strings.TrimSpace(form.Target),
+		Events: form.GetEvents(),
+		Active: form.Active,
+	}, nil
+}
@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+func TestNotificationServiceCreateChannel(t *testing.T) {
+	ctx := context.Background()
+	mockChannelRepo := dbMocks.NewMockNotificationChannelRepository(t)
+	service := NewNotificationService(mockChannelRepo)
+
+	mockChannelRepo.EXPECT().Create(ctx, mock.MatchedBy(func(channel *models.NotificationChannel) bool {
+		return channel.Name == "On-call channel" && channel.Target == "https://hooks.slack.com/services/T0/B0/x" &&
+			channel.Active && assert.ObjectsAreEqual([]string{models.NotificationTakeover}, channel.Events)
+	})).Return(nil).Once()
+
+	form := &models.NotificationChannelForm{
+		Name:   "  On-call channel ",
+		Kind:   models.ChannelKindSlack,
+		Target: " https://hooks.slack.com/services/T0/B0/x",
+		Events: []string{models.NotificationTakeover},
+		Active: true,
+	}
+	_, err := service.CreateChannel(ctx, form)
+	assert.NoError(t, err)
+
+	// Invalid forms never reach the repository
+	_, err = service.CreateChannel(ctx, &models.NotificationChannelForm{Name: "Members", Kind: models.ChannelKindEmail})
+	assert.EqualError(t, err, "validation failed: Choose at least one event")
+}
+
+func TestNotificationServiceUpdateChannel(t *testing.T) {
+	ctx := context.Background()
+	mockChannelRepo := dbMocks.NewMockNotificationChannelRepository(t)
+	service := NewNotificationService(mockChannelRepo)
+	form := &models.NotificationChannelForm{Name: "Members", Kind: models.ChannelKindEmail, Events: []string{models.NotificationRegenerated}}
+
+	mockChannelRepo.EXPECT().GetByID(ctx, 3).Return(&models.NotificationChannel{ID: 3}, nil).Once()
+	mockChannelRepo.EXPECT().Update(ctx, mock.MatchedBy(func(channel *models.NotificationChannel) bool {
+		return channel.ID == 3 && channel.Name == "Members" && !channel.Active
+	})).Return(nil).Once()
+
+	_, err := service.UpdateChannel(ctx, 3, form)
+	assert.NoError(t, err)
+
+	mockChannelRepo.EXPECT().GetByID(ctx, 4).Return(nil, errors.New("notification channel with ID 4 not found")).Once()
+	_, err = service.UpdateChannel(ctx, 4, form)
+	assert.EqualError(t, err, "notification channel not found: notification channel with ID 4 not found")
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// maxOnCallBlockDays limits the length of an on-call block. Longer stretches without working hours,
+// like the days between Christmas and New Year, are split into blocks of at most a week.
+const maxOnCallBlockDays = 7
+
+// dutyPeriod is a stretch of time covered by the working hours shifts
+type dutyPeriod struct {
+	start time.Time
+	end   time.Time
+}
+
+// onCallBlocks returns the on-call blocks starting in the generation period: every stretch of time
+// between the end of one working hours shift and the start of the next, like Friday 17:00 to Monday
+// 09:00. Duty-free holidays are covered by on-call as well, holidays with alternative hours move
+// the blocks around those hours. Together with the working hours this gives 24/7 coverage.
+func onCallBlocks(startDate, endDate time.Time, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) []WorkingDate {
+	from := truncateToDate(startDate)
+	until := truncateToDate(endDate)
+
+	// Look a day back for overnight shifts running into the period, and far enough ahead to find
+	// the end of every block starting in it
+	lookahead := until.AddDate(0, 0, maxOnCallBlockDays+1)
+	periods := dutyPeriods(from.AddDate(0, 0, -1), lookahead, activeDays, holidays)
+	if len(periods) == 0 {
+		return nil
+	}
+	periods = append(periods, dutyPeriod{start: lookahead, end: lookahead})
+
+	var blocks []WorkingDate
+	for i := 1; i < len(periods); i++ {
+		for start := periods[i-1].end; start.Before(periods[i].start); {
+			end := start.AddDate(0, 0, maxOnCallBlockDays)
+			if end.After(periods[i].start) {
+				end = periods[i].start
+			}
+
+			if date := truncateToDate(start); !date.Before(from) && date.Before(until) {
+				blocks = append(blocks, WorkingDate{
+					Date: date,
+					WorkingHours: models.WorkingHours{
+						DayOfWeek: models.GetWeekdayNumber(date),
+						StartTime: start.Format("15:04"),
+						EndTime:   end.Format("15:04"),
+						Active:    true,
+					},
+					EndDate: truncateToDate(end),
+				})
+			}
+			start = end
+		}
+	}
+
+	return blocks
+}
+
+// dutyPeriods returns the time covered by the working hours shifts between two dates, with
+// overlapping and adjacent shifts merged into one period
+func dutyPeriods(from, until time.Time, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) []dutyPeriod {
+	var periods []dutyPeriod
+	for date := from; date.Before(until); date = date.AddDate(0, 0, 1) {
+		for _, workingHours := range holidays.ShiftsOn(activeDays, date) {
+			shift := models.ScheduleEntry{Date: date, StartTime: workingHours.StartTime, EndTime: workingHours.EndTime}
+			periods = append(periods, dutyPeriod{start: shift.StartsAt(time.UTC), end: shift.EndsAt(time.UTC)})
+		}
+	}
+
+	sort.Slice(periods, func(i, j int) bool {
+		return periods[i].start.Before(periods[j].start)
+	})
+
+	var merged []dutyPeriod
+	for _, period := range periods {
+		if last := len(merged) - 1; last >= 0 && !period.start.After(merged[last].end) {
+			if period.end.After(merged[last].end) {
+				merged[last].end = period.end
+			}
+			continue
+		}
+		merged = append(merged, period)
+	}
+	return merged
+}
+
+// forOnCall narrows the input down to the on-call blocks and the on-call history. The blocks rotate
+// like a shift of their own, through the same members and with the same rotation period.
+func (in RotationInput) forOnCall(blocks []WorkingDate) RotationInput {
+	narrowed := in
+	narrowed.Dates = blocks
+
+	narrowed.History = nil
+	for _, entry := range in.History {
+		if entry.IsOnCall() && !entry.IsBackup() {
+			narrowed.History = append(narrowed.History, entry)
+		}
+	}
+
+	narrowed.BlockEnds = make(map[string]time.Time)
+	for _, block := range blocks {
+		narrowed.BlockEnds[models.FormatDate(block.Date)] = block.EndDate
+	}
+
+	return narrowed
+}
+
+// forBlock returns the input for finding someone available during a single on-call block
+func (in RotationInput) forBlock(date, endDate time.Time) RotationInput {
+	narrowed := in
+	narrowed.BlockEnds = map[string]time.Time{models.FormatDate(date): endDate}
+	return narrowed
+}
+
+// generateOnCall assigns the on-call blocks of the generation period that aren't taken yet. The
+// blocks continue the member rotation with their own cursor for queued strategies, and start a
+// member further than the last shift otherwise, so the member on duty during the day doesn't get
+// the night as well. It returns the on-call entries.
+func (s *scheduleService) generateOnCall(state *models.ScheduleState, snapshot *scheduleSnapshot, strategy RotationStrategy, input RotationInput, startDate time.Time, activeDays []models.WorkingHours) []models.ScheduleEntry {
+	existing := snapshot.between(startDate, input.End.AddDate(0, 0, -1))
+
+	// Skip blocks that kept their entry during cleanup
+	taken := make(map[string]bool)
+	for _, entry := range existing {
+		if entry.IsOnCall() && !entry.IsBackup() {
+			taken[entry.GetFormattedDate()+"|"+entry.StartTime] = true
+		}
+	}
+
+	staffed, _ := staffedDates(onCallBlocks(startDate, input.End, activeDays, input.Holidays), input.Members)
+
+	var blocks []WorkingDate
+	for _, block := range staffed {
+		if !taken[models.FormatDate(block.Date)+"|"+block.WorkingHours.StartTime] {
+			blocks = append(blocks, block)
+		}
+	}
+
+	onCallInput := input.forOnCall(blocks)
+	queued, isQueued := strategy.(queuedStrategy)
+	if isQueued {
+		onCallInput.Cursor = state.OnCallCursor % len(onCallInput.Members)
+	} else {
+		onCallInput.Members = staggered(input.Members, len(shiftNames(activeDays)))
+	}
+
+	assignments := strategy.Assign(onCallInput)
+	if isQueued {
+		state.OnCallCursor = queued.NextCursor(onCallInput, assignments)
+	}
+
+	var entries []models.ScheduleEntry
+	for _, assignment := range assignments {
+		endDate := assignment.WorkingDate.EndDate
+		entries = append(entries, models.ScheduleEntry{
+			Date:             assignment.WorkingDate.Date,
+			TeamMemberID:     assignment.TeamMemberID,
+			StartTime:        assignment.WorkingDate.WorkingHours.StartTime,
+			EndTime:          assignment.WorkingDate.WorkingHours.EndTime,
+			Layer:            models.ScheduleLayerOnCall,
+			EndDate:          &endDate,
+			IsManualOverride: false,
+		})
+	}
+
+	return entries
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+func TestOnCallBlocks(t *testing.T) {
+	type block struct {
+		date, start, end, endDate string
+	}
+
+	overnight := weekdaysMonToFri()
+	for i := range overnight {
+		overnight[i].StartTime, overnight[i].EndTime = "18:00", "02:00"
+	}
+
+	testCases := []struct {
+		name       string
+		days       int
+		activeDays []models.WorkingHours
+		holidays   []models.Holiday
+		expected   []block
+	}{
+		{
+			name:       "nights and the weekend",
+			days:       7,
+			activeDays: weekdaysMonToFri(),
+			expected: []block{
+				{"2023-10-02", "17:00", "09:00", "2023-10-03"},
+				{"2023-10-03", "17:00", "09:00", "2023-10-04"},
+				{"2023-10-04", "17:00", "09:00", "2023-10-05"},
+				{"2023-10-05", "17:00", "09:00", "2023-10-06"},
+				{"2023-10-06", "17:00", "09:00", "2023-10-09"},
+			},
+		},
+		{
+			name:       "duty-free holidays are covered by on-call",
+			days:       3,
+			activeDays: weekdaysMonToFri(),
+			holidays:   []models.Holiday{{Date: testMonday.AddDate(0, 0, 2), Name: "Day off", Behavior: models.HolidayBehaviorNoDuty}},
+			expected: []block{
+				{"2023-10-02", "17:00", "09:00", "2023-10-03"},
+				{"2023-10-03", "17:00", "09:00", "2023-10-05"},
+			},
+		},
+		{
+			name:       "holidays with alternative hours move the blocks",
+			days:       1,
+			activeDays: weekdaysMonToFri(),
+			holidays:   []models.Holiday{{Date: testMonday, Name: "Short day", Behavior: models.HolidayBehaviorAlternativeHours, StartTime: "10:00", EndTime: "13:00"}},
+			expected: []block{
+				{"2023-10-02", "13:00", "09:00", "2023-10-03"},
+			},
+		},
+		{
+			name:       "overnight shifts leave the day to on-call",
+			days:       3,
+			activeDays: overnight,
+			expected: []block{
+				// Monday is covered by the weekend block starting on Saturday at 02:00
+				{"2023-10-03", "02:00", "18:00", "2023-10-03"},
+				{"2023-10-04", "02:00", "18:00", "2023-10-04"},
+			},
+		},
+		{
+			name:       "long stretches are split into weeks",
+			days:       14,
+			activeDays: []models.WorkingHours{{DayOfWeek: 0, StartTime: "09:00", EndTime: "17:00", Active: true}},
+			holidays:   []models.Holiday{{Date: testMonday.AddDate(0, 0, 7), Name: "Day off", Behavior: models.HolidayBehaviorNoDuty}},
+			expected: []block{
+				{"2023-10-02", "17:00", "17:00", "2023-10-09"},
+				{"2023-10-09", "17:00", "09:00", "2023-10-16"},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			blocks := onCallBlocks(testMonday, testMonday.AddDate(0, 0, tc.days), tc.activeDays, models.NewHolidayCalendar(tc.holidays))
+
+			var actual []block
+			for _, b := range blocks {
+				actual = append(actual, block{models.FormatDate(b.Date), b.WorkingHours.StartTime, b.WorkingHours.EndTime, models.FormatDate(b.EndDate)})
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestRotationInputAvailableDuringBlock(t *testing.T) {
+	friday := testMonday.AddDate(0, 0, 4)
+	input := RotationInput{
+		Members: threeMembers,
+		TimeOff: []models.TimeOff{timeOffPeriod(1, "2023-10-08", "2023-10-08")},
+	}
+
+	assert.True(t, input.isAvailable(1, friday), "Only Friday itself counts for a shift")
+	assert.False(t, input.forBlock(friday, friday.AddDate(0, 0, 3)).isAvailable(1, friday), "Sunday falls in the block")
+	assert.Equal(t, 2, input.forBlock(friday, friday.AddDate(0, 0, 3)).nextAvailable(0, friday))
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"slices"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// participation returns the participation of a member in percent, full participation for members
+// that aren't in the rotation
+func (in RotationInput) participation(memberID int) int {
+	if index := memberIndex(in.Members, memberID); index >= 0 {
+		return in.Members[index].GetParticipation()
+	}
+	return models.FullParticipation
+}
+
+// worksOn checks if a member can be on duty on the weekday of the date. Members that aren't in the
+// rotation are only restricted by their time off.
+func (in RotationInput) worksOn(memberID int, date time.Time) bool {
+	if index := memberIndex(in.Members, memberID); index >= 0 {
+		return in.Members[index].WorksOn(date)
+	}
+	return true
+}
+
+// weighted returns the input with the members repeated in proportion to their participation, for
+// strategies that hand out turns by position in the roster
+func (in RotationInput) weighted() RotationInput {
+	narrowed := in
+	narrowed.Members = weightedRotation(in.Members)
+	return narrowed
+}
+
+// weightedRotation repeats the members in proportion to their participation, spread as evenly as
+// possible: with participations of 100, 100 and 50 the rotation is A, B, C, A, B. Without
+// part-time members it returns the members unchanged.
+func weightedRotation(members []models.TeamMember) []models.TeamMember {
+	divisor := 0
+	for _, member := range members {
+		divisor = gcd(divisor, member.GetParticipation())
+	}
+	if divisor == 0 || divisor == models.FullParticipation {
+		return members
+	}
+
+	weights := make([]int, len(members))
+	total := 0
+	for i, member := range members {
+		weights[i] = member.GetParticipation() / divisor
+		total += weights[i]
+	}
+
+	// Smooth weighted round robin: every step each member gains their weight, the member with the
+	// most gets the turn and pays back the total. Ties go to the member first in rotation order.
+	current := make([]int, len(members))
+	rotation := make([]models.TeamMember, 0, total)
+	for range total {
+		chosen := 0
+		for i := range members {
+			current[i] += weights[i]
+			if current[i] > current[chosen] {
+				chosen = i
+			}
+		}
+		current[chosen] -= total
+		rotation = append(rotation, members[chosen])
+	}
+	return rotation
+}
+
+// staffedDates splits the dates into those at least one of the members works on and those nobody
+// can cover
+func staffedDates(dates []WorkingDate, members []models.TeamMember) ([]WorkingDate, []WorkingDate) {
+	var staffed, unstaffed []WorkingDate
+	for _, workingDate := range dates {
+		if slices.ContainsFunc(members, func(member models.TeamMember) bool { return member.WorksOn(workingDate.Date) }) {
+			staffed = append(staffed, workingDate)
+		} else {
+			unstaffed = append(unstaffed, workingDate)
+		}
+	}
+	return staffed, unstaffed
+}
+
+// gcd returns the greatest common divisor of two numbers
+func gcd(a, b int) int {
+	for b != 0 {
+		a, b = b, a%b
+	}
+	return a
+}
@@ -0,0 +1,141 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// halfTimeCharlie returns the three members with Charlie on the team half-time, working on the
+// given weekdays
+func halfTimeCharlie(workDays ...int) []models.TeamMember {
+	members := append([]models.TeamMember{}, threeMembers...)
+	members[2].Participation = 50
+	members[2].WorkDays = workDays
+	return members
+}
+
+func TestWeightedRotation(t *testing.T) {
+	testCases := []struct {
+		name           string
+		participations []int
+		expected       []int
+	}{
+		{
+			name:           "full-time members keep the roster",
+			participations: []int{100, 100, 100},
+			expected:       []int{1, 2, 3},
+		},
+		{
+			name:           "unset participation is full-time",
+			participations: []int{0, 100, 0},
+			expected:       []int{1, 2, 3},
+		},
+		{
+			name:           "half-time member spread out",
+			participations: []int{100, 100, 50},
+			expected:       []int{1, 2, 3, 1, 2},
+		},
+		{
+			name:           "two part-time members",
+			participations: []int{100, 60, 40},
+			expected:       []int{1, 2, 3, 1, 1, 2, 1, 3, 2, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			members := append([]models.TeamMember{}, threeMembers...)
+			for i, participation := range tc.participations {
+				members[i].Participation = participation
+			}
+			assert.Equal(t, tc.expected, memberIDs(weightedRotation(members)))
+		})
+	}
+}
+
+// TestRotationStrategiesRespectParticipation tests that every strategy gives part-time members a
+// share in proportion to their participation and never schedules them on a weekday they don't work
+func TestRotationStrategiesRespectParticipation(t *testing.T) {
+	strategies := map[string]RotationStrategy{
+		"epoch modulo":          &epochModuloStrategy{},
+		"round robin":           &roundRobinStrategy{},
+		"least recently served": &leastRecentlyServedStrategy{},
+		"seeded shuffle":        &seededShuffleStrategy{seed: 42},
+		"fair share":            &fairShareStrategy{},
+	}
+
+	for name, strategy := range strategies {
+		input := RotationInput{
+			Start:       testMonday,
+			End:         testMonday.AddDate(0, 0, 84),
+			Dates:       workingDatesFrom(testMonday, 60),
+			WorkingDays: weekdaysMonToFri(),
+		}
+
+		t.Run(name+" shares", func(t *testing.T) {
+			input.Members = halfTimeCharlie()
+			assignments := strategy.Assign(input)
+			assert.Len(t, assignments, 60)
+
+			counts := make(map[int]int)
+			for _, assignment := range assignments {
+				counts[assignment.TeamMemberID]++
+			}
+
+			// Charlie is half-time, so gets a fifth of the 60 dates
+			assert.InDelta(t, 12, counts[3], 2, "Charlie's share")
+			assert.InDelta(t, counts[1], counts[2], 2, "Alice and Bob share the rest equally")
+		})
+
+		t.Run(name+" work days", func(t *testing.T) {
+			input.Members = halfTimeCharlie(0, 1, 2)
+			assignments := strategy.Assign(input)
+			assert.Len(t, assignments, 60)
+
+			charlie := 0
+			for _, assignment := range assignments {
+				if assignment.TeamMemberID == 3 {
+					charlie++
+					assert.Less(t, models.GetWeekdayNumber(assignment.WorkingDate.Date), 3, "Charlie is assigned on %s", models.FormatDate(assignment.WorkingDate.Date))
+				}
+			}
+			assert.NotZero(t, charlie, "Charlie still takes turns on Monday to Wednesday")
+		})
+	}
+}
+
+// TestRoundRobinPassesOverPartTimeMembers tests that the queue skips part-time members on some of
+// their turns and moves the cursor past the skipped positions
+func TestRoundRobinPassesOverPartTimeMembers(t *testing.T) {
+	members := append([]models.TeamMember{}, threeMembers...)
+	members[2].Participation = 50
+
+	input := RotationInput{
+		Start:       testMonday,
+		End:         testMonday.AddDate(0, 0, 14),
+		Dates:       workingDatesFrom(testMonday, 7),
+		Members:     members,
+		WorkingDays: weekdaysMonToFri(),
+		Cursor:      1,
+	}
+
+	strategy := &roundRobinStrategy{}
+	assignments := strategy.Assign(input)
+	assert.Equal(t, []int{2, 3, 1, 2, 1, 2, 3}, assignedIDs(assignments))
+	assert.Equal(t, 0, strategy.NextCursor(input, assignments), "Eight queue positions were used up")
+}
+
+func TestStaffedDates(t *testing.T) {
+	members := []models.TeamMember{
+		{ID: 1, WorkDays: []int{0, 1}},
+		{ID: 2, WorkDays: []int{1, 2}},
+	}
+
+	staffed, unstaffed := staffedDates(workingDatesFrom(testMonday, 5), members)
+	assert.Len(t, staffed, 3)
+	assert.Equal(t, "2023-10-04", models.FormatDate(staffed[2].Date))
+	assert.Len(t, unstaffed, 2, "Nobody works Thursday and Friday")
+}
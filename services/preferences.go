@@ -0,0 +1,146 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// maxSwapDistance limits how far apart two dates can be for their members to swap them in favour
+// of weekday preferences, so the rotation keeps its rhythm
+const maxSwapDistance = 7 // days
+
+// Costs of a member being on duty on a weekday they would rather not, or prefer to be on duty.
+// Avoiding outweighs preferring, nobody is moved onto an avoided day to give someone a preferred one.
+const (
+	avoidedDayCost   = 2
+	preferredDayCost = -1
+)
+
+// honourPreferences swaps the dates of members within a shift to honour soft weekday preferences.
+// A swap trades two dates of the same shift at most a week apart between their members, so nobody
+// gets more or fewer turns, and is only made if both members are available on their new date and
+// it lowers the total cost of the preferences. Hard preferences are part of availability, so swaps
+// never break them. Turns longer than a day aren't split up for a preference, so with those the
+// assignments are returned as they are.
+func honourPreferences(input RotationInput, assignments []Assignment) []Assignment {
+	if !input.Period.isDaily() || !hasSoftPreferences(input.Members) {
+		return assignments
+	}
+
+	byID := make(map[int]models.TeamMember)
+	for _, member := range input.Members {
+		byID[member.ID] = member
+	}
+
+	cost := func(memberID int, workingDate WorkingDate) int {
+		member, ok := byID[memberID]
+		switch {
+		case !ok:
+			return 0
+		case member.Avoids(workingDate.Date):
+			return avoidedDayCost
+		case member.Prefers(workingDate.Date):
+			return preferredDayCost
+		default:
+			return 0
+		}
+	}
+
+	// Members are on duty for one shift a day at most
+	onDuty := make(map[string]bool)
+	dutyKey := func(memberID int, workingDate WorkingDate) string {
+		return fmt.Sprintf("%d|%s", memberID, models.FormatDate(workingDate.Date))
+	}
+	for _, assignment := range assignments {
+		onDuty[dutyKey(assignment.TeamMemberID, assignment.WorkingDate)] = true
+	}
+
+	swapped := make([]Assignment, len(assignments))
+	copy(swapped, assignments)
+
+	// Every swap lowers the total cost, so this ends; the limit only guards against surprises
+	for pass := 0; pass < len(swapped); pass++ {
+		improved := false
+		for i := range swapped {
+			for j := i + 1; j < len(swapped); j++ {
+				a, b := swapped[i], swapped[j]
+				if a.WorkingDate.WorkingHours.Name != b.WorkingDate.WorkingHours.Name || a.TeamMemberID == b.TeamMemberID {
+					continue
+				}
+				if b.WorkingDate.Date.Sub(a.WorkingDate.Date).Hours() > maxSwapDistance*24 {
+					break // The assignments are in chronological order
+				}
+
+				before := cost(a.TeamMemberID, a.WorkingDate) + cost(b.TeamMemberID, b.WorkingDate)
+				after := cost(a.TeamMemberID, b.WorkingDate) + cost(b.TeamMemberID, a.WorkingDate)
+				if after >= before {
+					continue
+				}
+				if !input.isAvailable(a.TeamMemberID, b.WorkingDate.Date) || !input.isAvailable(b.TeamMemberID, a.WorkingDate.Date) {
+					continue
+				}
+				if onDuty[dutyKey(a.TeamMemberID, b.WorkingDate)] || onDuty[dutyKey(b.TeamMemberID, a.WorkingDate)] {
+					continue
+				}
+
+				delete(onDuty, dutyKey(a.TeamMemberID, a.WorkingDate))
+				delete(onDuty, dutyKey(b.TeamMemberID, b.WorkingDate))
+				swapped[i].TeamMemberID, swapped[j].TeamMemberID = b.TeamMemberID, a.TeamMemberID
+				onDuty[dutyKey(swapped[i].TeamMemberID, a.WorkingDate)] = true
+				onDuty[dutyKey(swapped[j].TeamMemberID, b.WorkingDate)] = true
+				improved = true
+				break // The member of date i changed, look for swaps with the new one in the next pass
+			}
+		}
+		if !improved {
+			break
+		}
+	}
+
+	return swapped
+}
+
+// hasSoftPreferences checks if any of the members has a soft weekday preference
+func hasSoftPreferences(members []models.TeamMember) bool {
+	for _, member := range members {
+		for _, preference := range member.Preferences {
+			if !preference.IsHard() {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// unsatisfiedConstraints lists the constraints a generation couldn't meet: shifts nobody could
+// cover because no member works on the weekday or every member avoids it for good, and members
+// on duty on a weekday they would rather not be
+func unsatisfiedConstraints(members []models.TeamMember, unstaffed []WorkingDate, assignments []Assignment) []models.UnsatisfiedConstraint {
+	var unsatisfied []models.UnsatisfiedConstraint
+
+	for _, workingDate := range unstaffed {
+		unsatisfied = append(unsatisfied, models.UnsatisfiedConstraint{
+			Date:        workingDate.Date,
+			Shift:       workingDate.WorkingHours.Name,
+			Hard:        true,
+			Description: fmt.Sprintf("Nobody can be on duty on %s, the shift is left open", models.DayNames[models.GetWeekdayNumber(workingDate.Date)]),
+		})
+	}
+
+	for _, assignment := range assignments {
+		index := memberIndex(members, assignment.TeamMemberID)
+		if index < 0 || !members[index].Avoids(assignment.WorkingDate.Date) {
+			continue
+		}
+		member := members[index]
+		unsatisfied = append(unsatisfied, models.UnsatisfiedConstraint{
+			Date:         assignment.WorkingDate.Date,
+			Shift:        assignment.WorkingDate.WorkingHours.Name,
+			TeamMemberID: member.ID,
+			Description:  fmt.Sprintf("%s is on duty, but %s", member.Name, member.PreferenceOn(assignment.WorkingDate.Date)),
+		})
+	}
+
+	return unsatisfied
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// preferring returns the three members with the given soft preferences for Alice, Bob and Charlie
+func preferring(kinds ...string) []models.TeamMember {
+	members := append([]models.TeamMember{}, threeMembers...)
+	for i, kind := range kinds {
+		if kind != "" {
+			// Everyone's preference is about Monday
+			members[i].Preferences = []models.WeekdayPreference{{DayOfWeek: 0, Kind: kind, Strength: models.PreferenceStrengthSoft}}
+		}
+	}
+	return members
+}
+
+func TestHonourPreferences(t *testing.T) {
+	testCases := []struct {
+		name     string
+		members  []models.TeamMember
+		timeOff  []models.TimeOff
+		expected []int
+	}{
+		{
+			name:     "no preferences keep the rotation",
+			members:  threeMembers,
+			expected: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+		},
+		{
+			name:     "avoided Monday is swapped with the nearest date",
+			members:  preferring(models.PreferenceKindAvoid, "", ""),
+			expected: []int{2, 1, 3, 1, 2, 3, 1, 2, 3, 1},
+		},
+		{
+			name:     "preferred Monday is swapped in",
+			members:  preferring("", "", models.PreferenceKindPrefer),
+			expected: []int{3, 2, 1, 1, 2, 3, 1, 2, 3, 1},
+		},
+		{
+			name:     "no swap onto a date the other member is away",
+			members:  preferring(models.PreferenceKindAvoid, "", ""),
+			timeOff:  []models.TimeOff{timeOffPeriod(2, "2023-10-02", "2023-10-06")},
+			expected: []int{3, 2, 1, 1, 2, 3, 1, 2, 3, 1},
+		},
+		{
+			name:     "everyone avoiding Monday leaves it as is",
+			members:  preferring(models.PreferenceKindAvoid, models.PreferenceKindAvoid, models.PreferenceKindAvoid),
+			expected: []int{1, 2, 3, 1, 2, 3, 1, 2, 3, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dates := workingDatesFrom(testMonday, 10)
+			var assignments []Assignment
+			for i, workingDate := range dates {
+				assignments = append(assignments, Assignment{WorkingDate: workingDate, TeamMemberID: threeMembers[i%3].ID})
+			}
+
+			input := RotationInput{
+				Start:       testMonday,
+				End:         testMonday.AddDate(0, 0, 14),
+				Dates:       dates,
+				Members:     tc.members,
+				WorkingDays: weekdaysMonToFri(),
+				TimeOff:     tc.timeOff,
+			}
+
+			honoured := honourPreferences(input, assignments)
+			assert.Equal(t, tc.expected, assignedIDs(honoured))
+
+			counts := func(assignments []Assignment) map[int]int {
+				counts := make(map[int]int)
+				for _, assignment := range assignments {
+					counts[assignment.TeamMemberID]++
+				}
+				return counts
+			}
+			assert.Equal(t, counts(assignments), counts(honoured), "Swaps don't change anyone's number of turns")
+		})
+	}
+}
+
+func TestHonourPreferencesKeepsLongerTurns(t *testing.T) {
+	var assignments []Assignment
+	for _, workingDate := range workingDatesFrom(testMonday, 5) {
+		assignments = append(assignments, Assignment{WorkingDate: workingDate, TeamMemberID: 1})
+	}
+
+	input := RotationInput{
+		Members: preferring(models.PreferenceKindAvoid, "", ""),
+		Period:  rotationPeriod{kind: models.RotationPeriodWeekly},
+	}
+	assert.Equal(t, assignedIDs(assignments), assignedIDs(honourPreferences(input, assignments)))
+}
+
+func TestUnsatisfiedConstraints(t *testing.T) {
+	members := preferring(models.PreferenceKindAvoid, "", "")
+	dates := workingDatesFrom(testMonday, 5)
+
+	assignments := []Assignment{
+		{WorkingDate: dates[0], TeamMemberID: 1},
+		{WorkingDate: dates[1], TeamMemberID: 1},
+	}
+
+	unsatisfied := unsatisfiedConstraints(members, dates[4:], assignments)
+	assert.Len(t, unsatisfied, 2)
+
+	assert.True(t, unsatisfied[0].Hard)
+	assert.Equal(t, "2023-10-06", models.FormatDate(unsatisfied[0].Date))
+	assert.Equal(t, "Nobody can be on duty on Friday, the shift is left open", unsatisfied[0].Description)
+
+	assert.False(t, unsatisfied[1].Hard)
+	assert.Equal(t, 1, unsatisfied[1].TeamMemberID)
+	assert.Equal(t, "Alice is on duty, but rather not on Monday", unsatisfied[1].Description)
+}
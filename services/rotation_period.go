@@ -0,0 +1,127 @@
+package services
+
+import (
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// rotationEpoch is the fixed Monday the deterministic rotations count from
+var rotationEpoch = time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC) // Monday, January 3, 2000
+
+// rotationPeriod decides how long a member stays on duty. The working dates are grouped into
+// turns counted from the epoch; all dates of a turn belong to the same member. With the daily
+// period every working day is a turn of its own.
+type rotationPeriod struct {
+	kind        string
+	days        int // Working days per turn, for the working days period
+	handoverDay int // Day of the week a weekly turn starts, 0=Monday
+}
+
+// newRotationPeriod returns the rotation period configured in the schedule state
+func newRotationPeriod(state *models.ScheduleState) rotationPeriod {
+	return rotationPeriod{
+		kind:        state.GetRotationPeriod(),
+		days:        state.RotationPeriodDays,
+		handoverDay: state.HandoverDay,
+	}
+}
+
+// isDaily checks if every working day is a turn of its own
+func (p rotationPeriod) isDaily() bool {
+	switch p.kind {
+	case models.RotationPeriodWeekly:
+		return false
+	case models.RotationPeriodWorkingDays:
+		return p.days <= 1
+	default:
+		return true
+	}
+}
+
+// turn returns the number of turns between the epoch and the turn the date falls in. Weekly turns
+// follow the calendar, turns of working days skip the days off: a date that isn't a working day
+// belongs to the turn of the next working day.
+func (p rotationPeriod) turn(date time.Time, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) int {
+	switch {
+	case p.isDaily():
+		return workingDaysSinceEpoch(date, activeDays, holidays)
+	case p.kind == models.RotationPeriodWeekly:
+		firstHandover := rotationEpoch.AddDate(0, 0, p.handoverDay)
+		date = truncateToDate(date)
+		if date.Before(firstHandover) {
+			return 0
+		}
+		return int(date.Sub(firstHandover).Hours()/24) / 7
+	default:
+		return workingDaysSinceEpoch(date, activeDays, holidays) / p.days
+	}
+}
+
+// turnStart returns the first date of the turn that date falls in, but never a date before
+// earliest. Generation stops there so the last turn of a period is never split.
+func (p rotationPeriod) turnStart(date, earliest time.Time, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) time.Time {
+	if p.isDaily() {
+		return date
+	}
+
+	date = truncateToDate(date)
+	turn := p.turn(date, activeDays, holidays)
+	for date.After(earliest) && p.turn(date.AddDate(0, 0, -1), activeDays, holidays) == turn {
+		date = date.AddDate(0, 0, -1)
+	}
+	return date
+}
+
+// turnHolders remembers who holds each turn while a strategy assigns the dates in chronological
+// order. Turns already under way are taken from the generated entries in the history; manual
+// overrides of a single day don't change who holds a turn. With the daily period every date is a
+// turn of its own, so nobody holds one in advance.
+type turnHolders struct {
+	input   RotationInput
+	holders map[int]int
+}
+
+// newTurnHolders creates the turn holders for a strategy input
+func newTurnHolders(input RotationInput) *turnHolders {
+	t := &turnHolders{input: input, holders: make(map[int]int)}
+	if input.Period.isDaily() {
+		return t
+	}
+
+	for _, entry := range sortedByDate(input.History) {
+		if !entry.IsManualOverride {
+			t.claim(entry.Date, entry.TeamMemberID)
+		}
+	}
+	return t
+}
+
+// holder returns the member holding the turn of the date, if they are still in the rotation and
+// available on the date
+func (t *turnHolders) holder(date time.Time) (int, bool) {
+	if t.input.Period.isDaily() {
+		return 0, false
+	}
+	memberID, ok := t.holders[t.input.turn(date)]
+	if !ok || memberIndex(t.input.Members, memberID) < 0 || !t.input.isAvailable(memberID, date) {
+		return 0, false
+	}
+	return memberID, true
+}
+
+// claim makes the member hold the turn of the date, unless someone already holds it
+func (t *turnHolders) claim(date time.Time, memberID int) {
+	if t.input.Period.isDaily() {
+		return
+	}
+	turn := t.input.turn(date)
+	if _, ok := t.holders[turn]; !ok {
+		t.holders[turn] = memberID
+	}
+}
+
+// turn returns the turn a date falls in
+func (in RotationInput) turn(date time.Time) int {
+	return in.Period.turn(date, in.WorkingDays, in.Holidays)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+func TestRotationPeriodTurn(t *testing.T) {
+	testCases := []struct {
+		name     string
+		period   rotationPeriod
+		dates    []string
+		expected []int
+	}{
+		{
+			name:     "daily counts working days",
+			period:   rotationPeriod{kind: models.RotationPeriodDaily},
+			dates:    []string{"2023-10-02", "2023-10-03", "2023-10-06", "2023-10-07", "2023-10-09"},
+			expected: []int{6195, 6196, 6199, 6200, 6200},
+		},
+		{
+			name:     "weekly hands over on Monday",
+			period:   rotationPeriod{kind: models.RotationPeriodWeekly},
+			dates:    []string{"2023-10-02", "2023-10-06", "2023-10-08", "2023-10-09"},
+			expected: []int{1239, 1239, 1239, 1240},
+		},
+		{
+			name:     "weekly hands over on the handover day",
+			period:   rotationPeriod{kind: models.RotationPeriodWeekly, handoverDay: 2},
+			dates:    []string{"2023-10-02", "2023-10-03", "2023-10-04", "2023-10-10", "2023-10-11"},
+			expected: []int{1238, 1238, 1239, 1239, 1240},
+		},
+		{
+			name:     "working days skip the weekend",
+			period:   rotationPeriod{kind: models.RotationPeriodWorkingDays, days: 3},
+			dates:    []string{"2023-10-02", "2023-10-04", "2023-10-05", "2023-10-07", "2023-10-09", "2023-10-10"},
+			expected: []int{2065, 2065, 2066, 2066, 2066, 2067},
+		},
+		{
+			name:     "a single working day is daily",
+			period:   rotationPeriod{kind: models.RotationPeriodWorkingDays, days: 1},
+			dates:    []string{"2023-10-02", "2023-10-03"},
+			expected: []int{6195, 6196},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var turns []int
+			for _, value := range tc.dates {
+				date, _ := models.ParseDate(value)
+				turns = append(turns, tc.period.turn(date, weekdaysMonToFri(), nil))
+			}
+			assert.Equal(t, tc.expected, turns)
+		})
+	}
+}
+
+func TestRotationPeriodTurnStart(t *testing.T) {
+	tuesday := testMonday.AddDate(0, 0, 1)
+	saturday := testMonday.AddDate(0, 0, 5)
+
+	weekly := rotationPeriod{kind: models.RotationPeriodWeekly, handoverDay: 2}
+	assert.Equal(t, testMonday.AddDate(0, 0, -5), weekly.turnStart(tuesday, time.Time{}, weekdaysMonToFri(), nil))
+	assert.Equal(t, testMonday, weekly.turnStart(tuesday, testMonday, weekdaysMonToFri(), nil), "Never before the earliest date")
+
+	// The weekend belongs to the turn that starts on Monday
+	workingDays := rotationPeriod{kind: models.RotationPeriodWorkingDays, days: 3}
+	assert.Equal(t, testMonday.AddDate(0, 0, 3), workingDays.turnStart(saturday, time.Time{}, weekdaysMonToFri(), nil))
+
+	daily := rotationPeriod{kind: models.RotationPeriodDaily}
+	assert.Equal(t, saturday, daily.turnStart(saturday, time.Time{}, weekdaysMonToFri(), nil))
+}
+
+// TestRotationStrategiesHandOutWholeTurns tests that every strategy keeps a member on duty for
+// their whole turn when turns last longer than a day
+func TestRotationStrategiesHandOutWholeTurns(t *testing.T) {
+	weekly := rotationPeriod{kind: models.RotationPeriodWeekly}
+
+	testCases := []struct {
+		name     string
+		strategy RotationStrategy
+		period   rotationPeriod
+		start    time.Time
+		count    int
+		history  []models.ScheduleEntry
+		timeOff  []models.TimeOff
+		skip     string // Date with a manual override, not handed to the strategy
+		expected []int
+	}{
+		{
+			name:     "deterministic rotation hands over on Monday",
+			strategy: &epochModuloStrategy{},
+			period:   weekly,
+			start:    testMonday,
+			count:    10,
+			expected: []int{1, 1, 1, 1, 1, 2, 2, 2, 2, 2},
+		},
+		{
+			name:     "deterministic rotation hands over on the handover day",
+			strategy: &epochModuloStrategy{},
+			period:   rotationPeriod{kind: models.RotationPeriodWeekly, handoverDay: 2},
+			start:    testMonday,
+			count:    10,
+			expected: []int{3, 3, 1, 1, 1, 1, 1, 2, 2, 2},
+		},
+		{
+			name:     "turns of three working days",
+			strategy: &epochModuloStrategy{},
+			period:   rotationPeriod{kind: models.RotationPeriodWorkingDays, days: 3},
+			start:    testMonday,
+			count:    7,
+			expected: []int{2, 2, 2, 3, 3, 3, 1},
+		},
+		{
+			name:     "time off within a turn is covered by the next member",
+			strategy: &epochModuloStrategy{},
+			period:   weekly,
+			start:    testMonday,
+			count:    5,
+			timeOff:  []models.TimeOff{timeOffPeriod(1, "2023-10-04", "2023-10-04")},
+			expected: []int{1, 1, 2, 1, 1},
+		},
+		{
+			name:     "round robin takeover of a single day doesn't use up a turn",
+			strategy: &roundRobinStrategy{},
+			period:   weekly,
+			start:    testMonday,
+			count:    10,
+			history:  []models.ScheduleEntry{takeoverEntry("2023-10-04", 3, 1)},
+			skip:     "2023-10-04",
+			expected: []int{1, 1, 1, 1, 2, 2, 2, 2, 2},
+		},
+		{
+			name:     "least recently served keeps the turn that is under way",
+			strategy: &leastRecentlyServedStrategy{},
+			period:   weekly,
+			start:    testMonday.AddDate(0, 0, 2),
+			count:    5,
+			history:  []models.ScheduleEntry{historyEntry("2023-10-02", 3), historyEntry("2023-10-03", 3)},
+			expected: []int{3, 3, 3, 1, 1},
+		},
+		{
+			name:     "fairness balancing hands out whole weeks",
+			strategy: &fairShareStrategy{},
+			period:   weekly,
+			start:    testMonday,
+			count:    10,
+			expected: []int{1, 1, 1, 1, 1, 2, 2, 2, 2, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dates []WorkingDate
+			for _, workingDate := range workingDatesFrom(tc.start, tc.count) {
+				if models.FormatDate(workingDate.Date) != tc.skip {
+					dates = append(dates, workingDate)
+				}
+			}
+
+			input := RotationInput{
+				Start:       tc.start,
+				End:         tc.start.AddDate(0, 0, 14),
+				Dates:       dates,
+				Members:     threeMembers,
+				WorkingDays: weekdaysMonToFri(),
+				History:     tc.history,
+				TimeOff:     tc.timeOff,
+				Period:      tc.period,
+			}
+
+			assignments := tc.strategy.Assign(input)
+			assert.Equal(t, tc.expected, assignedIDs(assignments))
+
+			if queued, ok := tc.strategy.(queuedStrategy); ok {
+				assert.Equal(t, 2, queued.NextCursor(input, assignments), "Two turns were used up")
+			}
+		})
+	}
+}
@@ -0,0 +1,245 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+)
+
+// rotationQueue keeps the persisted rotation queue and cursor in step with the published schedule.
+// The cursor points at the queue position of the member who gets the first rotation slot on or
+// after RotationCursorDate. Queued strategies keep everything published before that date, so
+// splicing members into the queue only affects dates that haven't been published yet.
+type rotationQueue struct {
+	scheduleRepo     repositories.ScheduleRepository
+	teamRepo         repositories.TeamRepository
+	workingHoursRepo repositories.WorkingHoursRepository
+	holidayRepo      repositories.HolidayRepository
+}
+
+// newRotationQueue creates a new rotation queue helper
+func newRotationQueue(
+	scheduleRepo repositories.ScheduleRepository,
+	teamRepo repositories.TeamRepository,
+	workingHoursRepo repositories.WorkingHoursRepository,
+	holidayRepo repositories.HolidayRepository,
+) *rotationQueue {
+	return &rotationQueue{
+		scheduleRepo:     scheduleRepo,
+		teamRepo:         teamRepo,
+		workingHoursRepo: workingHoursRepo,
+		holidayRepo:      holidayRepo,
+	}
+}
+
+// load retrieves the schedule state with the rotation cursor advanced to date
+func (q *rotationQueue) load(ctx context.Context, date time.Time) (*models.ScheduleState, error) {
+	state, err := q.scheduleRepo.GetState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule state: %w", err)
+	}
+
+	if err := q.advance(ctx, state, date); err != nil {
+		return nil, err
+	}
+
+	return state, nil
+}
+
+// advance moves the cursor of every shift past its rotation slots published before date. Every
+// queue change advances the cursors and saves them first, so the slots counted here were all
+// published with the current queue.
+func (q *rotationQueue) advance(ctx context.Context, state *models.ScheduleState, date time.Time) error {
+	date = truncateToDate(date)
+
+	if state.RotationCursorDate.IsZero() {
+		return q.initialize(ctx, state, date)
+	}
+
+	if !state.RotationCursorDate.Before(date) {
+		return nil
+	}
+
+	published, err := q.scheduleRepo.GetByDateRange(ctx, state.RotationCursorDate, date.AddDate(0, 0, -1))
+	if err != nil {
+		return fmt.Errorf("failed to get published entries: %w", err)
+	}
+
+	slots, err := q.countTurns(ctx, state, published)
+	if err != nil {
+		return err
+	}
+
+	if len(state.RotationQueue) > 0 {
+		for key, count := range slots {
+			if key.onCall {
+				state.OnCallCursor = (state.OnCallCursor + count) % len(state.RotationQueue)
+				continue
+			}
+			cursor, ok := state.ShiftCursor(key.shift)
+			if !ok {
+				continue // The shift joins the rotation at the next generation
+			}
+			state.SetShiftCursor(key.shift, (cursor+count)%len(state.RotationQueue))
+		}
+	}
+	state.RotationCursorDate = date
+
+	return nil
+}
+
+// rotationKey identifies a rotation with a cursor of its own: a shift, or the on-call blocks
+type rotationKey struct {
+	onCall bool
+	shift  string
+}
+
+// countTurns counts the turns of each shift and of the on-call blocks among the published rotation
+// slots. With the daily period every slot is a turn, longer turns count once however many of their
+// days were published.
+func (q *rotationQueue) countTurns(ctx context.Context, state *models.ScheduleState, published []models.ScheduleEntry) (map[rotationKey]int, error) {
+	period := newRotationPeriod(state)
+
+	var activeDays []models.WorkingHours
+	var holidays *models.HolidayCalendar
+	if !period.isDaily() && period.kind == models.RotationPeriodWorkingDays {
+		var err error
+		if activeDays, err = q.workingHoursRepo.GetActiveDays(ctx); err != nil {
+			return nil, fmt.Errorf("failed to get active working days: %w", err)
+		}
+		if holidays, err = q.holidayCalendar(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	counts := make(map[rotationKey]int)
+	seen := make(map[rotationKey]map[int]bool)
+	for _, entry := range published {
+		if !isRotationSlot(entry) {
+			continue
+		}
+		key := rotationKey{onCall: entry.IsOnCall(), shift: entry.Shift}
+		if period.isDaily() {
+			counts[key]++
+			continue
+		}
+
+		// On-call blocks follow the turns of all working days
+		workingDays, calendar := activeDays, holidays
+		if !key.onCall {
+			workingDays, calendar = shiftDays(activeDays, entry.Shift), holidays.ForShift(entry.Shift)
+		}
+		turn := period.turn(entry.Date, workingDays, calendar)
+		if seen[key] == nil {
+			seen[key] = make(map[int]bool)
+		}
+		if !seen[key][turn] {
+			seen[key][turn] = true
+			counts[key]++
+		}
+	}
+
+	return counts, nil
+}
+
+// holidayCalendar loads the holidays and the working hours exceptions into a calendar
+func (q *rotationQueue) holidayCalendar(ctx context.Context) (*models.HolidayCalendar, error) {
+	holidays, err := q.holidayRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	exceptions, err := q.workingHoursRepo.GetExceptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working hours exceptions: %w", err)
+	}
+	return models.NewHolidayCalendar(holidays).WithExceptions(exceptions), nil
+}
+
+// initialize seeds the queue with the active members. The cursor continues where the epoch based
+// rotation left off, so switching strategies doesn't restart the rotation.
+func (q *rotationQueue) initialize(ctx context.Context, state *models.ScheduleState, date time.Time) error {
+	activeMembers, err := q.teamRepo.GetActiveMembers(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active team members: %w", err)
+	}
+
+	activeDays, err := q.workingHoursRepo.GetActiveDays(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active working days: %w", err)
+	}
+
+	holidays, err := q.holidayCalendar(ctx)
+	if err != nil {
+		return err
+	}
+
+	state.RotationQueue = nil
+	for _, member := range activeMembers {
+		state.RotationQueue = append(state.RotationQueue, member.ID)
+	}
+
+	state.RotationCursor = 0
+	state.ShiftCursors = nil
+	state.OnCallCursor = 0
+	if len(state.RotationQueue) > 0 {
+		state.RotationCursor = newRotationPeriod(state).turn(date, activeDays, holidays) % len(state.RotationQueue)
+		// On-call starts a member after the last shift, like the deterministic rotation does
+		state.OnCallCursor = (state.RotationCursor + len(shiftNames(activeDays))) % len(state.RotationQueue)
+	}
+	state.RotationCursorDate = date
+
+	return nil
+}
+
+// sync makes sure the queue holds exactly the active members. Members that became active
+// outside of the team service join at the end, inactive ones are taken out.
+func (q *rotationQueue) sync(state *models.ScheduleState, activeMembers []models.TeamMember) {
+	active := make(map[int]bool)
+	for _, member := range activeMembers {
+		active[member.ID] = true
+	}
+
+	for _, memberID := range append([]int{}, state.RotationQueue...) {
+		if !active[memberID] {
+			state.RemoveFromQueue(memberID)
+		}
+	}
+
+	for _, member := range activeMembers {
+		state.InsertIntoQueue(member.ID, len(state.RotationQueue))
+	}
+}
+
+// orderByQueue returns the active members in rotation queue order
+func orderByQueue(activeMembers []models.TeamMember, queue []int) []models.TeamMember {
+	byID := make(map[int]models.TeamMember)
+	for _, member := range activeMembers {
+		byID[member.ID] = member
+	}
+
+	ordered := make([]models.TeamMember, 0, len(activeMembers))
+	for _, memberID := range queue {
+		if member, ok := byID[memberID]; ok {
+			ordered = append(ordered, member)
+		}
+	}
+	return ordered
+}
+
+// isRotationSlot checks if an entry used up a turn in the rotation. Generated entries and
+// takeovers of generated entries do, standalone manual overrides don't. Backups follow the
+// primary, they never use up a turn.
+func isRotationSlot(entry models.ScheduleEntry) bool {
+	if entry.IsBackup() {
+		return false
+	}
+	return !entry.IsManualOverride || entry.OriginalTeamMemberID != nil
+}
+
+// truncateToDate strips the time of day from a timestamp
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+func TestRotationQueueAdvance(t *testing.T) {
+	ctx := context.Background()
+	nextMonday := testMonday.AddDate(0, 0, 7)
+
+	testCases := []struct {
+		name           string
+		state          models.ScheduleState
+		date           time.Time
+		setupMocks     func(schedule *dbMocks.MockScheduleRepository, team *dbMocks.MockTeamRepository, hours *dbMocks.MockWorkingHoursRepository, holidays *dbMocks.MockHolidayRepository)
+		expectedQueue  []int
+		expectedCursor int
+		expectedShifts map[string]int
+		expectedOnCall int
+		expectedDate   time.Time
+		expectedError  string
+	}{
+		{
+			name:  "first use seeds the queue where the epoch rotation left off",
+			state: models.ScheduleState{},
+			date:  testMonday.AddDate(0, 0, 1).Add(15 * time.Hour), // Tuesday afternoon, 6196 working days since epoch
+			setupMocks: func(schedule *dbMocks.MockScheduleRepository, team *dbMocks.MockTeamRepository, hours *dbMocks.MockWorkingHoursRepository, holidays *dbMocks.MockHolidayRepository) {
+				team.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+				hours.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+				holidays.EXPECT().GetAll(ctx).Return(nil, nil)
+				hours.EXPECT().GetExceptions(ctx).Return(nil, nil)
+			},
+			expectedQueue:  []int{1, 2, 3},
+			expectedCursor: 1,
+			expectedOnCall: 2, // A member after the only shift
+			expectedDate:   testMonday.AddDate(0, 0, 1),
+		},
+		{
+			name:  "counts the rotation slots published since the cursor date",
+			state: models.ScheduleState{RotationQueue: []int{1, 2, 3}, RotationCursor: 0, RotationCursorDate: testMonday},
+			date:  nextMonday,
+			setupMocks: func(schedule *dbMocks.MockScheduleRepository, team *dbMocks.MockTeamRepository, hours *dbMocks.MockWorkingHoursRepository, holidays *dbMocks.MockHolidayRepository) {
+				schedule.EXPECT().GetByDateRange(ctx, testMonday, nextMonday.AddDate(0, 0, -1)).Return([]models.ScheduleEntry{
+					historyEntry("2023-10-02", 1),
+					historyEntry("2023-10-03", 2),
+					takeoverEntry("2023-10-04", 1, 3), // Uses up Charlie's turn
+					overrideEntry("2023-10-05", 2),    // Doesn't use up a turn
+					historyEntry("2023-10-06", 1),
+				}, nil)
+			},
+			expectedQueue:  []int{1, 2, 3},
+			expectedCursor: 1,
+			expectedDate:   nextMonday,
+		},
+		{
+			name: "advances the cursor of every shift by its own slots",
+			state: models.ScheduleState{
+				RotationQueue:      []int{1, 2, 3},
+				ShiftCursors:       map[string]int{"Morning": 0, "Afternoon": 1},
+				RotationCursorDate: testMonday,
+			},
+			date: nextMonday,
+			setupMocks: func(schedule *dbMocks.MockScheduleRepository, team *dbMocks.MockTeamRepository, hours *dbMocks.MockWorkingHoursRepository, holidays *dbMocks.MockHolidayRepository) {
+				schedule.EXPECT().GetByDateRange(ctx, testMonday, nextMonday.AddDate(0, 0, -1)).Return([]models.ScheduleEntry{
+					shiftEntry("2023-10-02", "Morning", 1),
+					shiftEntry("2023-10-02", "Afternoon", 2),
+					shiftEntry("2023-10-03", "Morning", 2),
+					shiftEntry("2023-10-04", "Evening", 3), // Shift without a cursor yet
+				}, nil)
+			},
+			expectedQueue:  []int{1, 2, 3},
+			expectedCursor: 0,
+			expectedShifts: map[string]int{"Morning": 2, "Afternoon": 2},
+			expectedDate:   nextMonday,
+		},
+		{
+			name:  "advances the on-call cursor by the on-call blocks",
+			state: models.ScheduleState{RotationQueue: []int{1, 2, 3}, RotationCursor: 0, OnCallCursor: 1, RotationCursorDate: testMonday},
+			date:  testMonday.AddDate(0, 0, 2),
+			setupMocks: func(schedule *dbMocks.MockScheduleRepository, team *dbMocks.MockTeamRepository, hours *dbMocks.MockWorkingHoursRepository, holidays *dbMocks.MockHolidayRepository) {
+				monday, tuesday := historyEntry("2023-10-02", 2), historyEntry("2023-10-03", 3)
+				monday.Layer, tuesday.Layer = models.ScheduleLayerOnCall, models.ScheduleLayerOnCall
+				schedule.EXPECT().GetByDateRange(ctx, testMonday, testMonday.AddDate(0, 0, 1)).Return([]models.ScheduleEntry{
+					historyEntry("2023-10-02", 1),
+					monday,
+					historyEntry("2023-10-03", 2),
+					tuesday,
+				}, nil)
+			},
+			expectedQueue:  []int{1, 2, 3},
+			expectedCursor: 2,
+			expectedOnCall: 0,
+			expectedDate:   testMonday.AddDate(0, 0, 2),
+		},
+		{
+			name: "weekly turns count once however many days were published",
+			state: models.ScheduleState{
+				RotationPeriod:     models.RotationPeriodWeekly,
+				RotationQueue:      []int{1, 2, 3},
+				RotationCursorDate: testMonday,
+			},
+			date: nextMonday.AddDate(0, 0, 7),
+			setupMocks: func(schedule *dbMocks.MockScheduleRepository, team *dbMocks.MockTeamRepository, hours *dbMocks.MockWorkingHoursRepository, holidays *dbMocks.MockHolidayRepository) {
+				schedule.EXPECT().GetByDateRange(ctx, testMonday, nextMonday.AddDate(0, 0, 6)).Return([]models.ScheduleEntry{
+					historyEntry("2023-10-02", 1),
+					historyEntry("2023-10-06", 1),
+					historyEntry("2023-10-09", 2),
+					takeoverEntry("2023-10-10", 3, 2), // Part of Bob's turn
+					historyEntry("2023-10-13", 2),
+				}, nil)
+			},
+			expectedQueue:  []int{1, 2, 3},
+			expectedCursor: 2,
+			expectedDate:   nextMonday.AddDate(0, 0, 7),
+		},
+		{
+			name:           "cursor date in the future is left alone",
+			state:          models.ScheduleState{RotationQueue: []int{1, 2, 3}, RotationCursor: 2, RotationCursorDate: nextMonday},
+			date:           testMonday,
+			expectedQueue:  []int{1, 2, 3},
+			expectedCursor: 2,
+			expectedDate:   nextMonday,
+		},
+		{
+			name:  "repository error",
+			state: models.ScheduleState{RotationQueue: []int{1, 2, 3}, RotationCursorDate: testMonday},
+			date:  nextMonday,
+			setupMocks: func(schedule *dbMocks.MockScheduleRepository, team *dbMocks.MockTeamRepository, hours *dbMocks.MockWorkingHoursRepository, holidays *dbMocks.MockHolidayRepository) {
+				schedule.EXPECT().GetByDateRange(ctx, testMonday, nextMonday.AddDate(0, 0, -1)).Return(nil, errors.New("range error"))
+			},
+			expectedError: "failed to get published entries",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			scheduleRepo := dbMocks.NewMockScheduleRepository(t)
+			teamRepo := dbMocks.NewMockTeamRepository(t)
+			hoursRepo := dbMocks.NewMockWorkingHoursRepository(t)
+			holidayRepo := dbMocks.NewMockHolidayRepository(t)
+			if tc.setupMocks != nil {
+				tc.setupMocks(scheduleRepo, teamRepo, hoursRepo, holidayRepo)
+			}
+
+			state := tc.state
+			err := newRotationQueue(scheduleRepo, teamRepo, hoursRepo, holidayRepo).advance(ctx, &state, tc.date)
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(t, err, tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedQueue, state.RotationQueue)
+			assert.Equal(t, tc.expectedCursor, state.RotationCursor)
+			assert.Equal(t, tc.expectedOnCall, state.OnCallCursor)
+			if tc.expectedShifts != nil {
+				assert.Equal(t, tc.expectedShifts, state.ShiftCursors)
+			}
+			assert.Equal(t, tc.expectedDate, state.RotationCursorDate)
+		})
+	}
+}
+
+func TestRotationQueueSync(t *testing.T) {
+	dana := models.TeamMember{ID: 4, Name: "Dana", Active: true, DateAdded: joinedDate}
+
+	// Bob left and Dana joined outside of the team service, Charlie is still next
+	state := &models.ScheduleState{RotationQueue: []int{1, 2, 3}, RotationCursor: 2}
+	activeMembers := []models.TeamMember{threeMembers[0], threeMembers[2], dana}
+
+	queue := newRotationQueue(nil, nil, nil, nil)
+	queue.sync(state, activeMembers)
+
+	assert.Equal(t, []int{1, 3, 4}, state.RotationQueue)
+	assert.Equal(t, 1, state.RotationCursor)
+
+	// Members come out in queue order, not in the order they were passed in
+	state.MoveInQueue(4, 0)
+	assert.Equal(t, []int{4, 1, 3}, memberIDs(orderByQueue(activeMembers, state.RotationQueue)))
+}
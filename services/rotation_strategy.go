@@ -0,0 +1,770 @@
+package services
+
+import (
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// RotationStrategy decides which team member covers each working date.
+// Members with time off on a date must not be assigned to it while someone else is available, and
+// members are never assigned to a weekday they don't work. Part-time members get a share of the
+// turns in proportion to their participation.
+type RotationStrategy interface {
+	// HistoryFrom returns the date from which the strategy needs the assignment history,
+	// or false if it doesn't look at history at all
+	HistoryFrom(input RotationInput) (time.Time, bool)
+	// Assign returns one assignment per working date, in the same order as the dates
+	Assign(input RotationInput) []Assignment
+}
+
+// RotationInput holds everything a rotation strategy can base its assignments on. Every shift
+// rotates on its own, so the generator hands the strategy the input of one shift at a time.
+type RotationInput struct {
+	Start       time.Time               // First date of the generation period, or the cursor date for queued strategies
+	End         time.Time               // First date after the generation period
+	Dates       []WorkingDate           // Working dates to assign, in chronological order
+	Members     []models.TeamMember     // Active members in rotation order
+	WorkingDays []models.WorkingHours   // Working days configuration of every version
+	History     []models.ScheduleEntry  // Existing entries, only loaded when the strategy asks for them
+	Cursor      int                     // Position in Members of the next member on duty, for queued strategies
+	TimeOff     []models.TimeOff        // Time off overlapping the generation period and the loaded history
+	Holidays    *models.HolidayCalendar // Holidays and working hours exceptions, dates without shifts don't count as working days
+	Period      rotationPeriod          // Groups the dates into turns, a member covers every date of their turn
+	BlockEnds   map[string]time.Time    // Last day of the on-call blocks by start date, a member has to be available on every day
+}
+
+// forShift narrows the input down to the dates, working days, calendar and history of a single shift.
+// Strategies rotate the primary members, so the history leaves out the backups.
+func (in RotationInput) forShift(shift string) RotationInput {
+	narrowed := in
+
+	narrowed.Dates = nil
+	for _, workingDate := range in.Dates {
+		if workingDate.WorkingHours.Name == shift {
+			narrowed.Dates = append(narrowed.Dates, workingDate)
+		}
+	}
+
+	narrowed.WorkingDays = shiftDays(in.WorkingDays, shift)
+	narrowed.Holidays = in.Holidays.ForShift(shift)
+
+	narrowed.History = nil
+	for _, entry := range in.History {
+		if entry.Shift == shift && !entry.IsBackup() && !entry.IsOnCall() {
+			narrowed.History = append(narrowed.History, entry)
+		}
+	}
+
+	return narrowed
+}
+
+// isAvailable checks if a member works on the weekday of the given date and has no time off on it,
+// or on any day of the on-call block starting on the date
+func (in RotationInput) isAvailable(memberID int, date time.Time) bool {
+	if !in.worksOn(memberID, date) {
+		return false
+	}
+
+	last := date
+	if blockEnd, ok := in.BlockEnds[models.FormatDate(date)]; ok {
+		last = blockEnd
+	}
+
+	for _, timeOff := range in.TimeOff {
+		if timeOff.TeamMemberID != memberID {
+			continue
+		}
+		for day := date; !day.After(last); day = day.AddDate(0, 0, 1) {
+			if timeOff.Covers(day) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// nextAvailable returns the first member from position index onwards in Members (wrapping around)
+// that is available on the date. If nobody is available the first member from index onwards who
+// works on the weekday stays on duty despite their time off.
+func (in RotationInput) nextAvailable(index int, date time.Time) int {
+	for i := range in.Members {
+		member := in.Members[(index+i)%len(in.Members)]
+		if in.isAvailable(member.ID, date) {
+			return member.ID
+		}
+	}
+	return in.fallback(index, date)
+}
+
+// fallback returns the first member from position index onwards in Members (wrapping around) who
+// works on the weekday of the date, ignoring time off. If nobody does, the member at index.
+func (in RotationInput) fallback(index int, date time.Time) int {
+	for i := range in.Members {
+		member := in.Members[(index+i)%len(in.Members)]
+		if member.WorksOn(date) {
+			return member.ID
+		}
+	}
+	return in.Members[index%len(in.Members)].ID
+}
+
+// nextAvailableExcept returns the first member from position index onwards in Members (wrapping
+// around) that is available on the date and isn't excluded. It reports false if there is none.
+func (in RotationInput) nextAvailableExcept(index int, date time.Time, excluded ...int) (int, bool) {
+	for i := range in.Members {
+		member := in.Members[(index+i)%len(in.Members)]
+		if in.isAvailable(member.ID, date) && !slices.Contains(excluded, member.ID) {
+			return member.ID, true
+		}
+	}
+	return 0, false
+}
+
+// backupFor returns the backup for a shift: the member next in line after the primary who is
+// available on the date. If nobody is available the next member who works on the weekday is the
+// backup anyway. It reports false if nobody but the primary works on the weekday.
+func (in RotationInput) backupFor(primaryID int, date time.Time) (int, bool) {
+	next := memberIndex(in.Members, primaryID) + 1
+	if backupID, ok := in.nextAvailableExcept(next, date, primaryID); ok {
+		return backupID, true
+	}
+	for i := range in.Members {
+		if member := in.Members[(next+i)%len(in.Members)]; member.ID != primaryID && member.WorksOn(date) {
+			return member.ID, true
+		}
+	}
+	return 0, false
+}
+
+// availableMembers returns the members that are available on the date, in rotation order.
+// If nobody is available the members who work on the weekday are returned despite their time off,
+// someone still has to be on duty.
+func (in RotationInput) availableMembers(date time.Time) []models.TeamMember {
+	var available, working []models.TeamMember
+	for _, member := range in.Members {
+		if in.isAvailable(member.ID, date) {
+			available = append(available, member)
+		}
+		if member.WorksOn(date) {
+			working = append(working, member)
+		}
+	}
+	if len(available) > 0 {
+		return available
+	}
+	if len(working) > 0 {
+		return working
+	}
+	return in.Members
+}
+
+// queuedStrategy is implemented by strategies that continue the persisted rotation queue.
+// The generator hands them the members in queue order with the cursor, keeps the entries
+// published before the cursor date and saves the cursor they end on.
+type queuedStrategy interface {
+	RotationStrategy
+	// NextCursor returns the position in input.Members of the first member on duty after input.End
+	NextCursor(input RotationInput, assignments []Assignment) int
+}
+
+// Assignment links a working date to the team member on duty
+type Assignment struct {
+	WorkingDate  WorkingDate
+	TeamMemberID int
+}
+
+// newRotationStrategy returns the rotation strategy configured in the schedule state
+func newRotationStrategy(state *models.ScheduleState) RotationStrategy {
+	switch state.GetRotationStrategy() {
+	case models.RotationStrategyFairShare:
+		return &fairShareStrategy{}
+	case models.RotationStrategyRoundRobin:
+		return &roundRobinStrategy{}
+	case models.RotationStrategyLeastRecentlyServed:
+		return &leastRecentlyServedStrategy{}
+	case models.RotationStrategySeededShuffle:
+		return &seededShuffleStrategy{seed: state.RotationSeed}
+	default:
+		return &epochModuloStrategy{}
+	}
+}
+
+// epochModuloStrategy assigns members based on the number of turns since a fixed epoch.
+// This maintains determinism (same date always gets same assignment) while avoiding consecutive assignments.
+// Part-time members appear in the rotation in proportion to their participation.
+type epochModuloStrategy struct{}
+
+// HistoryFrom implements RotationStrategy
+func (st *epochModuloStrategy) HistoryFrom(input RotationInput) (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Assign implements RotationStrategy
+func (st *epochModuloStrategy) Assign(input RotationInput) []Assignment {
+	input = input.weighted()
+
+	assignments := make([]Assignment, 0, len(input.Dates))
+	for _, workingDate := range input.Dates {
+		memberIndex := input.turn(workingDate.Date) % len(input.Members)
+		assignments = append(assignments, Assignment{
+			WorkingDate:  workingDate,
+			TeamMemberID: input.nextAvailable(memberIndex, workingDate.Date),
+		})
+	}
+	return assignments
+}
+
+// roundRobinStrategy continues the persisted rotation queue from its cursor, so members keep
+// taking turns in queue order regardless of the calendar. A takeover of a generated slot still
+// uses up the turn of the member that was originally scheduled, and so does time off: the next
+// available member covers the slot and the rotation continues after the absent member. When a
+// turn lasts several days, a takeover of some of its days leaves the rest with the member.
+// Part-time members are passed over on some of their turns in the queue, in proportion to their
+// participation.
+type roundRobinStrategy struct{}
+
+// HistoryFrom implements RotationStrategy
+func (st *roundRobinStrategy) HistoryFrom(input RotationInput) (time.Time, bool) {
+	// Only the takeovers within the generation period matter
+	return input.Start, true
+}
+
+// Assign implements RotationStrategy
+func (st *roundRobinStrategy) Assign(input RotationInput) []Assignment {
+	positions := st.turnPositions(input)
+	offsets, _ := st.queueOffsets(input, len(positions))
+
+	assignments := make([]Assignment, 0, len(input.Dates))
+	for _, workingDate := range input.Dates {
+		cursor := input.Cursor + offsets[positions[input.turn(workingDate.Date)]]
+		assignments = append(assignments, Assignment{
+			WorkingDate:  workingDate,
+			TeamMemberID: input.nextAvailable(cursor, workingDate.Date),
+		})
+	}
+	return assignments
+}
+
+// NextCursor implements queuedStrategy
+func (st *roundRobinStrategy) NextCursor(input RotationInput, assignments []Assignment) int {
+	if len(input.Members) == 0 {
+		return 0
+	}
+	_, consumed := st.queueOffsets(input, len(st.turnPositions(input)))
+	return (input.Cursor + consumed) % len(input.Members)
+}
+
+// queueOffsets returns how far from the cursor the member taking each of count turns is in the
+// queue, and how many queue positions the turns used up. Every time the queue reaches a member
+// their participation is added to their credit, and they take the turn once the credit makes up a
+// full turn; otherwise the queue moves on to the next member. Credits start at half a turn, so
+// part-time members are rounded to the nearest turn and full-time members never get passed over.
+func (st *roundRobinStrategy) queueOffsets(input RotationInput, count int) ([]int, int) {
+	if len(input.Members) == 0 {
+		return nil, 0
+	}
+
+	offsets := make([]int, 0, count)
+	credits := make(map[int]int)
+	offset := 0
+	for ; len(offsets) < count; offset++ {
+		member := input.Members[(input.Cursor+offset)%len(input.Members)]
+		credit, ok := credits[member.ID]
+		if !ok {
+			credit = models.FullParticipation / 2
+		}
+
+		credit += member.GetParticipation()
+		if credit >= models.FullParticipation {
+			credit -= models.FullParticipation
+			offsets = append(offsets, offset)
+		}
+		credits[member.ID] = credit
+	}
+	return offsets, offset
+}
+
+// turnPositions numbers the turns of the generation period in chronological order. Turns with
+// dates to assign and turns that were completely taken over both use up a place in the queue.
+func (st *roundRobinStrategy) turnPositions(input RotationInput) map[int]int {
+	turns := make(map[int]bool)
+	for _, workingDate := range input.Dates {
+		turns[input.turn(workingDate.Date)] = true
+	}
+	for _, takeover := range st.takeovers(input) {
+		turns[input.turn(takeover)] = true
+	}
+
+	ordered := make([]int, 0, len(turns))
+	for turn := range turns {
+		ordered = append(ordered, turn)
+	}
+	sort.Ints(ordered)
+
+	positions := make(map[int]int, len(ordered))
+	for position, turn := range ordered {
+		positions[turn] = position
+	}
+	return positions
+}
+
+// takeovers returns the dates of the takeovers within the generation period
+func (st *roundRobinStrategy) takeovers(input RotationInput) []time.Time {
+	start := models.FormatDate(input.Start)
+	end := models.FormatDate(input.End)
+
+	var takeovers []time.Time
+	for _, entry := range input.History {
+		date := entry.GetFormattedDate()
+		if entry.IsManualOverride && isRotationSlot(entry) && date >= start && date < end {
+			takeovers = append(takeovers, entry.Date)
+		}
+	}
+	return takeovers
+}
+
+// leastRecentlyServedStrategy gives each date to the member who has gone longest without duty.
+// Members that never served go first, ties go to the member that comes first in the roster. The
+// time without duty is weighted by participation, so a member at 50% waits twice as long as a
+// full-time member. When turns last several days the member picked for the first date keeps the
+// rest of the turn.
+type leastRecentlyServedStrategy struct{}
+
+// HistoryFrom implements RotationStrategy
+func (st *leastRecentlyServedStrategy) HistoryFrom(input RotationInput) (time.Time, bool) {
+	return earliestJoinDate(input.Members), true
+}
+
+// Assign implements RotationStrategy
+func (st *leastRecentlyServedStrategy) Assign(input RotationInput) []Assignment {
+	history := sortedByDate(input.History)
+	lastServed := make(map[int]time.Time)
+	holders := newTurnHolders(input)
+	next := 0
+
+	assignments := make([]Assignment, 0, len(input.Dates))
+	for _, workingDate := range input.Dates {
+		date := truncateToDate(workingDate.Date)
+
+		// Only entries before this date count, later overrides haven't happened yet
+		for ; next < len(history) && truncateToDate(history[next].Date).Before(date); next++ {
+			lastServed[history[next].TeamMemberID] = truncateToDate(history[next].Date)
+		}
+
+		// Time without duty in days, weighted by participation
+		waited := func(memberID int) float64 {
+			last, served := lastServed[memberID]
+			if !served {
+				return math.Inf(1)
+			}
+			return date.Sub(last).Hours() / 24 * float64(input.participation(memberID))
+		}
+
+		chosen, holdsTurn := holders.holder(workingDate.Date)
+		if !holdsTurn {
+			available := input.availableMembers(workingDate.Date)
+			chosen = available[0].ID
+			for _, member := range available[1:] {
+				if waited(member.ID) > waited(chosen) {
+					chosen = member.ID
+				}
+			}
+			holders.claim(workingDate.Date, chosen)
+		}
+
+		lastServed[chosen] = date
+		assignments = append(assignments, Assignment{
+			WorkingDate:  workingDate,
+			TeamMemberID: chosen,
+		})
+	}
+	return assignments
+}
+
+// seededShuffleStrategy rotates through a shuffled roster. Every cycle of len(members) turns
+// uses its own permutation derived from the seed, so everyone serves exactly once per cycle
+// while the order varies. The same seed and roster always produce the same schedule. Part-time
+// members take part in a cycle in proportion to their participation, so with part-time members a
+// cycle is longer and full-time members serve more than once in it.
+type seededShuffleStrategy struct {
+	seed int64
+}
+
+// HistoryFrom implements RotationStrategy
+func (st *seededShuffleStrategy) HistoryFrom(input RotationInput) (time.Time, bool) {
+	return time.Time{}, false
+}
+
+// Assign implements RotationStrategy
+func (st *seededShuffleStrategy) Assign(input RotationInput) []Assignment {
+	input = input.weighted()
+	memberCount := len(input.Members)
+	permutations := make(map[int][]int)
+
+	assignments := make([]Assignment, 0, len(input.Dates))
+	for _, workingDate := range input.Dates {
+		offset := input.turn(workingDate.Date)
+		cycle := offset / memberCount
+
+		permutation, ok := permutations[cycle]
+		if !ok {
+			permutation = st.permutation(cycle, memberCount)
+			permutations[cycle] = permutation
+		}
+
+		assignments = append(assignments, Assignment{
+			WorkingDate:  workingDate,
+			TeamMemberID: st.pick(input, permutation, offset%memberCount, workingDate.Date),
+		})
+	}
+	return assignments
+}
+
+// pick returns the member at a position of the cycle's permutation, or the next available member
+// in permutation order if that member has time off or doesn't work on the weekday
+func (st *seededShuffleStrategy) pick(input RotationInput, permutation []int, position int, date time.Time) int {
+	for i := range permutation {
+		member := input.Members[permutation[(position+i)%len(permutation)]]
+		if input.isAvailable(member.ID, date) {
+			return member.ID
+		}
+	}
+	for i := range permutation {
+		member := input.Members[permutation[(position+i)%len(permutation)]]
+		if member.WorksOn(date) {
+			return member.ID
+		}
+	}
+	return input.Members[permutation[position]].ID
+}
+
+// permutation returns the roster order for a cycle. A cycle never starts with the member that
+// ended the previous one, so nobody is on duty two working days in a row across cycles.
+func (st *seededShuffleStrategy) permutation(cycle, memberCount int) []int {
+	// With fewer than three members the only way to avoid back-to-back duty is a fixed order
+	if memberCount < 3 {
+		permutation := make([]int, memberCount)
+		for i := range permutation {
+			permutation[i] = i
+		}
+		return permutation
+	}
+
+	permutation := st.draw(cycle, memberCount)
+	previous := st.draw(cycle-1, memberCount)
+
+	// Swapping the first two positions leaves the last position untouched, so the
+	// previous cycle's last member is the same whether or not it was swapped itself
+	if permutation[0] == previous[memberCount-1] {
+		permutation[0], permutation[1] = permutation[1], permutation[0]
+	}
+	return permutation
+}
+
+// draw returns the raw seeded permutation for a cycle
+func (st *seededShuffleStrategy) draw(cycle, memberCount int) []int {
+	rng := rand.New(rand.NewPCG(uint64(st.seed), uint64(cycle)))
+	return rng.Perm(memberCount)
+}
+
+// fairShareStrategy gives each date to the member furthest behind their fair share.
+// Every slot held by an active member (generated, overridden or taken over) is shared equally
+// between the active members that had joined by that date and weren't on time off, so newcomers
+// start level instead of having to catch up on the team's whole history, and nobody has to make up
+// for their holidays. Part-time members get a share in proportion to their participation. When turns
+// last several days the member picked for the first date keeps the rest of the turn.
+type fairShareStrategy struct{}
+
+// HistoryFrom implements RotationStrategy
+func (st *fairShareStrategy) HistoryFrom(input RotationInput) (time.Time, bool) {
+	// History only matters from the moment the first active member joined
+	return earliestJoinDate(input.Members), true
+}
+
+// Assign implements RotationStrategy
+func (st *fairShareStrategy) Assign(input RotationInput) []Assignment {
+	expected := make(map[int]float64) // fair share of the slots handed out so far
+	assigned := make(map[int]int)     // slots actually held
+
+	for _, entry := range input.History {
+		if memberIndex(input.Members, entry.TeamMemberID) < 0 {
+			continue // Slots held by former members don't affect the current balance
+		}
+		assigned[entry.TeamMemberID]++
+
+		// Share the slot between the members that were part of the team and available on that date
+		var eligible []models.TeamMember
+		for _, member := range input.Members {
+			if models.FormatDate(member.DateAdded) <= entry.GetFormattedDate() && input.isAvailable(member.ID, entry.Date) {
+				eligible = append(eligible, member)
+			}
+		}
+		st.share(expected, eligible)
+	}
+
+	deficit := func(memberID int) float64 {
+		return expected[memberID] - float64(assigned[memberID])
+	}
+
+	const epsilon = 1e-9
+
+	holders := newTurnHolders(input)
+	assignments := make([]Assignment, 0, len(input.Dates))
+	for _, workingDate := range input.Dates {
+		// Ties go to the member that comes first in the rotation order. The slot is only shared
+		// between the available members, so time off doesn't have to be caught up on afterwards.
+		available := input.availableMembers(workingDate.Date)
+		chosen, holdsTurn := holders.holder(workingDate.Date)
+		if !holdsTurn {
+			chosen = available[0].ID
+			for _, member := range available[1:] {
+				if deficit(member.ID) > deficit(chosen)+epsilon {
+					chosen = member.ID
+				}
+			}
+			holders.claim(workingDate.Date, chosen)
+		}
+
+		st.share(expected, available)
+		assigned[chosen]++
+
+		assignments = append(assignments, Assignment{
+			WorkingDate:  workingDate,
+			TeamMemberID: chosen,
+		})
+	}
+	return assignments
+}
+
+// share divides a slot between the members in proportion to their participation
+func (st *fairShareStrategy) share(expected map[int]float64, members []models.TeamMember) {
+	total := 0
+	for _, member := range members {
+		total += member.GetParticipation()
+	}
+	for _, member := range members {
+		expected[member.ID] += float64(member.GetParticipation()) / float64(total)
+	}
+}
+
+// workingDaysSinceEpoch calculates how many working days have passed since a fixed epoch
+// using the actual configured working days. This ensures deterministic assignments
+// while preventing consecutive assignments due to non-working days. Duty-free holidays
+// aren't counted either, so the member who would have been on duty that day is next, and working
+// hours exceptions count for the dates they make working or non-working.
+// The days are counted per whole week between the dates the working hours change, so the cost
+// doesn't grow with the distance to the epoch. Every date counts with the working hours in effect
+// on it, so a change of the working hours doesn't shift the rotation of the dates before it.
+func workingDaysSinceEpoch(date time.Time, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) int {
+	// Use a fixed epoch date that's a Monday to make calculation easier
+	epoch := rotationEpoch
+
+	if date.Before(epoch) {
+		return 0
+	}
+
+	// The days counted are the ones starting before the date
+	elapsed := date.Sub(epoch)
+	days := int(elapsed / (24 * time.Hour))
+	if elapsed%(24*time.Hour) != 0 {
+		days++
+	}
+
+	// The days since the epoch on which the working hours change split the days into segments
+	// with the same working weekdays
+	boundaries := []int{0}
+	for _, changed := range changeDays(activeDays, epoch) {
+		if changed > 0 && changed < days {
+			boundaries = append(boundaries, changed)
+		}
+	}
+	boundaries = append(boundaries, days)
+
+	workingDays := 0
+	segments := make([][7]bool, len(boundaries)-1)
+	for i := range segments {
+		segments[i] = activeWeekdays(activeDays, epoch.AddDate(0, 0, boundaries[i]))
+		workingDays += countWeekdays(segments[i], boundaries[i], boundaries[i+1]-boundaries[i])
+	}
+
+	// weekly checks if a date is a working day by its weekday alone
+	weekly := func(date time.Time) bool {
+		day := int(truncateToDate(date).Sub(epoch) / (24 * time.Hour))
+		return segments[sort.SearchInts(boundaries[1:], day+1)][models.GetWeekdayNumber(date)]
+	}
+
+	for _, holiday := range holidays.DutyFreeDates(epoch, date) {
+		// An exception on the holiday decides on its own
+		if holidays.FindException(holiday) == nil && weekly(holiday) {
+			workingDays--
+		}
+	}
+
+	// Exceptions make working days non-working and the other way around
+	for _, exceptionDate := range holidays.ExceptionDates(epoch, date) {
+		working := len(holidays.ShiftsOn(activeDays, exceptionDate)) > 0
+		switch {
+		case weekly(exceptionDate) && !working:
+			workingDays--
+		case !weekly(exceptionDate) && working:
+			workingDays++
+		}
+	}
+
+	return workingDays
+}
+
+// changeDays returns the distinct days since the epoch on which the working hours change, in order
+func changeDays(activeDays []models.WorkingHours, epoch time.Time) []int {
+	seen := make(map[int]bool)
+	var changes []int
+	for _, workingHours := range activeDays {
+		if workingHours.EffectiveFrom.IsZero() {
+			continue
+		}
+		day := int(truncateToDate(workingHours.EffectiveFrom).Sub(epoch) / (24 * time.Hour))
+		if !seen[day] {
+			seen[day] = true
+			changes = append(changes, day)
+		}
+	}
+	sort.Ints(changes)
+	return changes
+}
+
+// activeWeekdays returns the weekdays with a shift in the working hours in effect on the date, in
+// our DayOfWeek format (0=Monday)
+func activeWeekdays(activeDays []models.WorkingHours, date time.Time) [7]bool {
+	var weekdays [7]bool
+	for day := 0; day < 7; day++ {
+		for _, workingHours := range models.DayVersionOn(activeDays, day, date) {
+			if workingHours.Active {
+				weekdays[day] = true
+			}
+		}
+	}
+	return weekdays
+}
+
+// countWeekdays counts the active weekdays among n days, starting the given number of days after
+// the epoch
+func countWeekdays(weekdays [7]bool, first, n int) int {
+	perWeek := 0
+	for _, active := range weekdays {
+		if active {
+			perWeek++
+		}
+	}
+
+	count := n / 7 * perWeek
+	for i := 0; i < n%7; i++ {
+		if weekdays[(first+i)%7] {
+			count++
+		}
+	}
+	return count
+}
+
+// shiftNames returns the names of the configured shifts, ordered by their earliest start time
+func shiftNames(activeDays []models.WorkingHours) []string {
+	sorted := make([]models.WorkingHours, len(activeDays))
+	copy(sorted, activeDays)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].StartTime != sorted[j].StartTime {
+			return sorted[i].StartTime < sorted[j].StartTime
+		}
+		return sorted[i].Name < sorted[j].Name
+	})
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, workingHours := range sorted {
+		if workingHours.Active && !seen[workingHours.Name] {
+			seen[workingHours.Name] = true
+			names = append(names, workingHours.Name)
+		}
+	}
+	return names
+}
+
+// shiftDays returns the working days configuration of a single shift. Versions of a day without
+// the shift keep an inactive row, so the shift ends on the date they take effect.
+func shiftDays(workingDays []models.WorkingHours, shift string) []models.WorkingHours {
+	type version struct {
+		day  int
+		from string
+	}
+
+	var days []models.WorkingHours
+	var versions []models.WorkingHours
+	hasShift := make(map[version]bool)
+	for _, workingHours := range workingDays {
+		key := version{workingHours.DayOfWeek, models.FormatDate(workingHours.EffectiveFrom)}
+		if _, seen := hasShift[key]; !seen {
+			hasShift[key] = false
+			versions = append(versions, workingHours)
+		}
+		if workingHours.Active && workingHours.Name == shift {
+			hasShift[key] = true
+			days = append(days, workingHours)
+		}
+	}
+
+	for _, workingHours := range versions {
+		if !hasShift[version{workingHours.DayOfWeek, models.FormatDate(workingHours.EffectiveFrom)}] {
+			days = append(days, models.WorkingHours{
+				DayOfWeek:     workingHours.DayOfWeek,
+				EffectiveFrom: workingHours.EffectiveFrom,
+				StartTime:     "00:00",
+				EndTime:       "00:00",
+			})
+		}
+	}
+	return days
+}
+
+// staggered returns the members in rotation order, starting offset members further. Shifts use
+// it to start their rotation at a different member, so one member doesn't get every shift of a day.
+func staggered(members []models.TeamMember, offset int) []models.TeamMember {
+	if len(members) == 0 {
+		return members
+	}
+	offset %= len(members)
+	return append(append([]models.TeamMember{}, members[offset:]...), members[:offset]...)
+}
+
+// earliestJoinDate returns the date the longest-serving member joined the team
+func earliestJoinDate(members []models.TeamMember) time.Time {
+	earliest := timeNow()
+	for _, member := range members {
+		if member.DateAdded.Before(earliest) {
+			earliest = member.DateAdded
+		}
+	}
+	return earliest
+}
+
+// memberIndex returns the position of a member in the roster, or -1 if not present
+func memberIndex(members []models.TeamMember, memberID int) int {
+	for i, member := range members {
+		if member.ID == memberID {
+			return i
+		}
+	}
+	return -1
+}
+
+// sortedByDate returns a copy of the entries in chronological order
+func sortedByDate(entries []models.ScheduleEntry) []models.ScheduleEntry {
+	sorted := make([]models.ScheduleEntry, len(entries))
+	copy(sorted, entries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+	return sorted
+}
@@ -0,0 +1,742 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// weekdaysMonToFri returns a Monday to Friday working hours configuration
+func weekdaysMonToFri() []models.WorkingHours {
+	var days []models.WorkingHours
+	for day := 0; day < 5; day++ {
+		days = append(days, models.WorkingHours{DayOfWeek: day, StartTime: "09:00", EndTime: "17:00", Active: true})
+	}
+	return days
+}
+
+// workingDatesFrom builds consecutive Monday to Friday working dates starting at start
+func workingDatesFrom(start time.Time, count int) []WorkingDate {
+	var dates []WorkingDate
+	for date := start; len(dates) < count; date = date.AddDate(0, 0, 1) {
+		if weekday := models.GetWeekdayNumber(date); weekday < 5 {
+			dates = append(dates, WorkingDate{
+				Date:         date,
+				WorkingHours: models.WorkingHours{DayOfWeek: weekday, StartTime: "09:00", EndTime: "17:00", Active: true},
+			})
+		}
+	}
+	return dates
+}
+
+// assignedIDs extracts the member IDs from a list of assignments
+func assignedIDs(assignments []Assignment) []int {
+	ids := make([]int, 0, len(assignments))
+	for _, assignment := range assignments {
+		ids = append(ids, assignment.TeamMemberID)
+	}
+	return ids
+}
+
+// memberIDs extracts the IDs from a list of team members
+func memberIDs(members []models.TeamMember) []int {
+	ids := make([]int, 0, len(members))
+	for _, member := range members {
+		ids = append(ids, member.ID)
+	}
+	return ids
+}
+
+// historyEntry creates a past schedule entry for a member
+func historyEntry(date string, memberID int) models.ScheduleEntry {
+	parsed, _ := models.ParseDate(date)
+	return models.ScheduleEntry{Date: parsed, TeamMemberID: memberID}
+}
+
+// shiftEntry builds a rotation slot of a named shift held by memberID
+func shiftEntry(date, shift string, memberID int) models.ScheduleEntry {
+	entry := historyEntry(date, memberID)
+	entry.Shift = shift
+	return entry
+}
+
+// morningAndAfternoon returns a morning and an afternoon shift on Monday to Friday
+func morningAndAfternoon() []models.WorkingHours {
+	var days []models.WorkingHours
+	for day := 0; day < 5; day++ {
+		days = append(days,
+			models.WorkingHours{DayOfWeek: day, Name: "Morning", StartTime: "08:00", EndTime: "13:00", Active: true},
+			models.WorkingHours{DayOfWeek: day, Name: "Afternoon", StartTime: "13:00", EndTime: "18:00", Active: true},
+		)
+	}
+	return days
+}
+
+var (
+	// 2023-10-02 was a Monday
+	testMonday   = time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	joinedDate   = time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	threeMembers = []models.TeamMember{
+		{ID: 1, Name: "Alice", Active: true, DateAdded: joinedDate},
+		{ID: 2, Name: "Bob", Active: true, DateAdded: joinedDate},
+		{ID: 3, Name: "Charlie", Active: true, DateAdded: joinedDate},
+	}
+)
+
+func TestNewRotationStrategy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy string
+		expected RotationStrategy
+	}{
+		{"empty defaults to epoch modulo", "", &epochModuloStrategy{}},
+		{"epoch modulo", models.RotationStrategyEpochModulo, &epochModuloStrategy{}},
+		{"fair share", models.RotationStrategyFairShare, &fairShareStrategy{}},
+		{"round robin", models.RotationStrategyRoundRobin, &roundRobinStrategy{}},
+		{"least recently served", models.RotationStrategyLeastRecentlyServed, &leastRecentlyServedStrategy{}},
+		{"seeded shuffle", models.RotationStrategySeededShuffle, &seededShuffleStrategy{seed: 42}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			state := &models.ScheduleState{RotationStrategy: tc.strategy, RotationSeed: 42}
+			assert.Equal(t, tc.expected, newRotationStrategy(state))
+		})
+	}
+}
+
+func TestEpochModuloStrategy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		start    time.Time
+		count    int
+		members  []models.TeamMember
+		expected []int
+	}{
+		{
+			name:     "three members rotate by working day offset",
+			start:    testMonday,
+			count:    6,
+			members:  threeMembers,
+			expected: []int{1, 2, 3, 1, 2, 3}, // 6195 working days since epoch on 2023-10-02
+		},
+		{
+			name:     "same date always maps to the same member",
+			start:    testMonday.AddDate(0, 0, 1),
+			count:    2,
+			members:  threeMembers,
+			expected: []int{2, 3},
+		},
+		{
+			name:     "single member takes every slot",
+			start:    testMonday,
+			count:    3,
+			members:  threeMembers[:1],
+			expected: []int{1, 1, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := RotationInput{
+				Dates:       workingDatesFrom(tc.start, tc.count),
+				Members:     tc.members,
+				WorkingDays: weekdaysMonToFri(),
+			}
+
+			strategy := &epochModuloStrategy{}
+			_, needsHistory := strategy.HistoryFrom(input)
+			assert.False(t, needsHistory)
+			assert.Equal(t, tc.expected, assignedIDs(strategy.Assign(input)))
+		})
+	}
+}
+
+// TestEpochModuloStrategyHonoursHolidays tests that duty-free holidays don't count as working days
+func TestEpochModuloStrategyHonoursHolidays(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := models.ParseDate(value)
+		return parsed
+	}
+
+	testCases := []struct {
+		name     string
+		dates    []string
+		holidays []models.Holiday
+		expected []int
+	}{
+		{
+			name:     "duty-free holiday in the period doesn't skip anyone",
+			dates:    []string{"2023-10-02", "2023-10-04", "2023-10-05"},
+			holidays: []models.Holiday{{Date: date("2023-10-03"), Behavior: models.HolidayBehaviorNoDuty}},
+			expected: []int{1, 2, 3},
+		},
+		{
+			name:     "duty-free holiday before the period shifts the rotation",
+			dates:    []string{"2023-10-02", "2023-10-03", "2023-10-04"},
+			holidays: []models.Holiday{{Date: date("2023-09-29"), Behavior: models.HolidayBehaviorNoDuty}},
+			expected: []int{3, 1, 2},
+		},
+		{
+			name:  "holiday with alternative hours still counts",
+			dates: []string{"2023-10-02", "2023-10-03", "2023-10-04"},
+			holidays: []models.Holiday{{
+				Date: date("2023-09-29"), Behavior: models.HolidayBehaviorAlternativeHours, StartTime: "09:00", EndTime: "12:00",
+			}},
+			expected: []int{1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dates []WorkingDate
+			for _, value := range tc.dates {
+				dates = append(dates, WorkingDate{Date: date(value)})
+			}
+
+			input := RotationInput{
+				Dates:       dates,
+				Members:     threeMembers,
+				WorkingDays: weekdaysMonToFri(),
+				Holidays:    models.NewHolidayCalendar(tc.holidays),
+			}
+
+			strategy := &epochModuloStrategy{}
+			assert.Equal(t, tc.expected, assignedIDs(strategy.Assign(input)))
+		})
+	}
+}
+
+// countWorkingDaysSinceEpoch counts the working days since the epoch one day at a time, as a
+// reference for workingDaysSinceEpoch
+func countWorkingDaysSinceEpoch(date time.Time, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) int {
+	count := 0
+	for d := rotationEpoch; d.Before(date); d = d.AddDate(0, 0, 1) {
+		if len(holidays.ShiftsOn(activeDays, d)) > 0 {
+			count++
+		}
+	}
+	return count
+}
+
+// TestWorkingDaysSinceEpoch tests that counting per week gives the same result as counting every day
+func TestWorkingDaysSinceEpoch(t *testing.T) {
+	date := func(value string) time.Time {
+		parsed, _ := models.ParseDate(value)
+		return parsed
+	}
+
+	weekend := []models.WorkingHours{{DayOfWeek: 5, Active: true}, {DayOfWeek: 6, Active: true}}
+	shifts := append(weekdaysMonToFri(), models.WorkingHours{DayOfWeek: 0, Name: "Evening", Active: true}, models.WorkingHours{DayOfWeek: 2, Active: false})
+	holidays := models.NewHolidayCalendar([]models.Holiday{
+		{Date: date("2000-12-25"), Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+		{Date: date("2004-02-29"), Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+		{Date: date("2023-09-29"), Behavior: models.HolidayBehaviorNoDuty},
+		{Date: date("2023-10-07"), Behavior: models.HolidayBehaviorNoDuty},
+		{Date: date("2023-12-25"), Behavior: models.HolidayBehaviorAlternativeHours, StartTime: "09:00", EndTime: "12:00"},
+		{Date: date("1999-12-31"), Behavior: models.HolidayBehaviorNoDuty},
+	})
+
+	// Exceptions on weekdays, weekends and holidays, before the epoch and in between the dates
+	exceptions := func() *models.HolidayCalendar {
+		return models.NewHolidayCalendar([]models.Holiday{
+			{Date: date("2000-12-25"), Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+			{Date: date("2023-12-25"), Behavior: models.HolidayBehaviorAlternativeHours, StartTime: "09:00", EndTime: "12:00"},
+		}).WithExceptions([]models.WorkingHoursException{
+			{Date: date("1999-12-30"), Kind: models.ExceptionKindNonWorking},
+			{Date: date("2000-01-08"), Kind: models.ExceptionKindWorking, StartTime: "10:00", EndTime: "16:00"},
+			{Date: date("2022-12-25"), Kind: models.ExceptionKindWorking, StartTime: "10:00", EndTime: "16:00"},
+			{Date: date("2023-10-03"), Kind: models.ExceptionKindNonWorking},
+			{Date: date("2023-10-04"), Kind: models.ExceptionKindHours, StartTime: "09:00", EndTime: "13:00"},
+			{Date: date("2023-10-07"), Kind: models.ExceptionKindHours, StartTime: "09:00", EndTime: "13:00"},
+			{Date: date("2023-12-25"), Kind: models.ExceptionKindNonWorking},
+			{Date: date("2024-12-25"), Kind: models.ExceptionKindHours, StartTime: "09:00", EndTime: "13:00"},
+		})
+	}
+
+	// Weekdays with Sunday from before the epoch, the weekend only from a Wednesday on and Friday
+	// again from the next year
+	changed := date("2023-10-04")
+	versioned := append(weekdaysMonToFri(), models.WorkingHours{DayOfWeek: 6, Active: true, EffectiveFrom: date("1999-06-01")})
+	for day := 0; day < 5; day++ {
+		versioned = append(versioned, models.WorkingHours{DayOfWeek: day, EffectiveFrom: changed})
+	}
+	versioned = append(versioned,
+		models.WorkingHours{DayOfWeek: 5, Active: true, EffectiveFrom: changed},
+		models.WorkingHours{DayOfWeek: 4, Active: true, EffectiveFrom: date("2024-01-01")})
+
+	testCases := []struct {
+		name       string
+		activeDays []models.WorkingHours
+		holidays   *models.HolidayCalendar
+	}{
+		{"weekdays", weekdaysMonToFri(), nil},
+		{"weekend", weekend, nil},
+		{"shifts on the same day", shifts, nil},
+		{"no working days", nil, nil},
+		{"weekdays with holidays", weekdaysMonToFri(), holidays},
+		{"weekend with holidays", weekend, holidays},
+		{"changed working hours", versioned, nil},
+		{"changed working hours with holidays", versioned, holidays},
+		{"weekdays with exceptions", weekdaysMonToFri(), exceptions()},
+		{"weekend with exceptions", weekend, exceptions()},
+		{"changed working hours with exceptions", versioned, exceptions()},
+	}
+
+	dates := []time.Time{
+		date("1999-06-01"), rotationEpoch, rotationEpoch.Add(time.Hour), date("2000-01-09"),
+		date("2004-03-01"), date("2023-10-02"), date("2023-10-02").Add(10 * time.Hour),
+		date("2023-10-04"), date("2023-10-05"), date("2023-10-08"), date("2023-10-09"), date("2023-12-26"), date("2024-03-01"), date("2030-07-17"),
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, day := range dates {
+				assert.Equal(t, countWorkingDaysSinceEpoch(day, tc.activeDays, tc.holidays),
+					workingDaysSinceEpoch(day, tc.activeDays, tc.holidays), "working days until %s", day)
+			}
+		})
+	}
+}
+
+// TestShiftDays tests that a shift ends on the date a version of its day without it takes effect
+func TestShiftDays(t *testing.T) {
+	changed := time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC)
+	workingHours := append(morningAndAfternoon(),
+		models.WorkingHours{DayOfWeek: 0, Name: "Morning", StartTime: "07:00", EndTime: "13:00", Active: true, EffectiveFrom: changed})
+
+	morning := shiftDays(workingHours, "Morning")
+	afternoon := shiftDays(workingHours, "Afternoon")
+
+	assert.Equal(t, "08:00", models.ShiftsOn(morning, testMonday)[0].StartTime)
+	assert.Equal(t, "07:00", models.ShiftsOn(morning, changed.AddDate(0, 0, 5))[0].StartTime)
+	assert.Len(t, models.ShiftsOn(afternoon, testMonday), 1)
+	assert.Empty(t, models.ShiftsOn(afternoon, changed.AddDate(0, 0, 5)), "Monday has no afternoon shift from %s", changed)
+	assert.Len(t, models.ShiftsOn(afternoon, changed.AddDate(0, 0, 6)), 1, "The other days keep their afternoon shift")
+}
+
+func BenchmarkWorkingDaysSinceEpoch(b *testing.B) {
+	holidays := models.NewHolidayCalendar([]models.Holiday{
+		{Date: time.Date(2000, 12, 25, 0, 0, 0, 0, time.UTC), Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+		{Date: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+	})
+	date := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	for b.Loop() {
+		workingDaysSinceEpoch(date, weekdaysMonToFri(), holidays)
+	}
+}
+
+// takeoverEntry creates a takeover of a generated slot, which still uses up a turn
+func takeoverEntry(date string, memberID, originalMemberID int) models.ScheduleEntry {
+	entry := historyEntry(date, memberID)
+	entry.IsManualOverride = true
+	entry.OriginalTeamMemberID = &originalMemberID
+	return entry
+}
+
+// overrideEntry creates a standalone manual override, which doesn't use up a turn
+func overrideEntry(date string, memberID int) models.ScheduleEntry {
+	entry := historyEntry(date, memberID)
+	entry.IsManualOverride = true
+	return entry
+}
+
+// backupEntry creates a generated backup entry, which never uses up a turn
+func backupEntry(date string, memberID int) models.ScheduleEntry {
+	entry := historyEntry(date, memberID)
+	entry.Role = models.ScheduleRoleBackup
+	return entry
+}
+
+// timeOffPeriod creates a time off period for a member, both dates inclusive
+func timeOffPeriod(memberID int, from, to string) models.TimeOff {
+	start, _ := models.ParseDate(from)
+	end, _ := models.ParseDate(to)
+	return models.TimeOff{TeamMemberID: memberID, StartDate: start, EndDate: end}
+}
+
+func TestRoundRobinStrategy(t *testing.T) {
+	testCases := []struct {
+		name       string
+		cursor     int
+		history    []models.ScheduleEntry
+		timeOff    []models.TimeOff
+		skip       string // Date with a manual override, not handed to the strategy
+		expected   []int
+		nextCursor int
+	}{
+		{
+			name:       "starts at the cursor",
+			cursor:     0,
+			expected:   []int{1, 2, 3, 1, 2},
+			nextCursor: 2,
+		},
+		{
+			name:       "continues from a saved cursor",
+			cursor:     2,
+			expected:   []int{3, 1, 2, 3, 1},
+			nextCursor: 1,
+		},
+		{
+			name:       "takeover after the start uses up a turn",
+			cursor:     0,
+			history:    []models.ScheduleEntry{takeoverEntry("2023-10-03", 3, 2)},
+			skip:       "2023-10-03",
+			expected:   []int{1, 3, 1, 2, 3},
+			nextCursor: 0,
+		},
+		{
+			name:       "standalone override doesn't move the cursor",
+			cursor:     0,
+			history:    []models.ScheduleEntry{overrideEntry("2023-10-03", 3)},
+			skip:       "2023-10-03",
+			expected:   []int{1, 2, 3, 1, 2},
+			nextCursor: 2,
+		},
+		{
+			name:   "takeovers before the start and after the end are ignored",
+			cursor: 1,
+			history: []models.ScheduleEntry{
+				takeoverEntry("2023-09-29", 1, 3),
+				historyEntry("2023-10-03", 2),
+				takeoverEntry("2023-10-20", 1, 2),
+			},
+			expected:   []int{2, 3, 1, 2, 3},
+			nextCursor: 0,
+		},
+		{
+			name:       "time off is covered by the next member and uses up the turn",
+			cursor:     0,
+			timeOff:    []models.TimeOff{timeOffPeriod(2, "2023-10-03", "2023-10-03")},
+			expected:   []int{1, 3, 3, 1, 2},
+			nextCursor: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dates []WorkingDate
+			for _, workingDate := range workingDatesFrom(testMonday, 6) {
+				if models.FormatDate(workingDate.Date) != tc.skip {
+					dates = append(dates, workingDate)
+				}
+			}
+
+			input := RotationInput{
+				Start:       testMonday,
+				End:         testMonday.AddDate(0, 0, 7),
+				Dates:       dates[:5],
+				Members:     threeMembers,
+				WorkingDays: weekdaysMonToFri(),
+				History:     tc.history,
+				Cursor:      tc.cursor,
+				TimeOff:     tc.timeOff,
+			}
+
+			strategy := &roundRobinStrategy{}
+			from, needsHistory := strategy.HistoryFrom(input)
+			assert.True(t, needsHistory)
+			assert.Equal(t, testMonday, from)
+
+			assignments := strategy.Assign(input)
+			assert.Equal(t, tc.expected, assignedIDs(assignments))
+			assert.Equal(t, tc.nextCursor, strategy.NextCursor(input, assignments))
+		})
+	}
+}
+
+func TestLeastRecentlyServedStrategy(t *testing.T) {
+	testCases := []struct {
+		name     string
+		history  []models.ScheduleEntry
+		skip     string // Date with a manual override, not handed to the strategy
+		expected []int
+	}{
+		{
+			name:     "no history follows roster order",
+			history:  nil,
+			expected: []int{1, 2, 3, 1, 2},
+		},
+		{
+			name:     "member that never served goes first",
+			history:  []models.ScheduleEntry{historyEntry("2023-09-28", 1), historyEntry("2023-09-29", 2)},
+			expected: []int{3, 1, 2, 3, 1},
+		},
+		{
+			name:     "longest waiting member goes first",
+			history:  []models.ScheduleEntry{historyEntry("2023-09-27", 2), historyEntry("2023-09-28", 3), historyEntry("2023-09-29", 1)},
+			expected: []int{2, 3, 1, 2, 3},
+		},
+		{
+			name: "override counts from its own date",
+			history: []models.ScheduleEntry{
+				historyEntry("2023-09-27", 1), historyEntry("2023-09-28", 2), historyEntry("2023-09-29", 3),
+				historyEntry("2023-10-03", 2),
+			},
+			skip: "2023-10-03",
+			// Bob's Tuesday override puts Charlie ahead of him on Wednesday
+			expected: []int{1, 3, 1, 2, 3},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var dates []WorkingDate
+			for _, workingDate := range workingDatesFrom(testMonday, 6) {
+				if models.FormatDate(workingDate.Date) != tc.skip {
+					dates = append(dates, workingDate)
+				}
+			}
+
+			input := RotationInput{
+				Dates:       dates[:5],
+				Members:     threeMembers,
+				WorkingDays: weekdaysMonToFri(),
+				History:     tc.history,
+			}
+
+			strategy := &leastRecentlyServedStrategy{}
+			from, needsHistory := strategy.HistoryFrom(input)
+			assert.True(t, needsHistory)
+			assert.Equal(t, joinedDate, from)
+			assert.Equal(t, tc.expected, assignedIDs(strategy.Assign(input)))
+		})
+	}
+}
+
+func TestSeededShuffleStrategy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		seed    int64
+		members []models.TeamMember
+		count   int
+	}{
+		{"three members", 1, threeMembers, 12},
+		{"different seed", 7, threeMembers, 12},
+		{"two members", 5, threeMembers[:2], 8},
+		{"single member", 3, threeMembers[:1], 4},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Start at a cycle boundary so every cycle is complete
+			start := testMonday
+			for workingDaysSinceEpoch(start, weekdaysMonToFri(), nil)%len(tc.members) != 0 {
+				start = start.AddDate(0, 0, 1)
+			}
+
+			input := RotationInput{
+				Dates:       workingDatesFrom(start, tc.count),
+				Members:     tc.members,
+				WorkingDays: weekdaysMonToFri(),
+			}
+
+			strategy := &seededShuffleStrategy{seed: tc.seed}
+			_, needsHistory := strategy.HistoryFrom(input)
+			assert.False(t, needsHistory)
+
+			first := assignedIDs(strategy.Assign(input))
+			assert.Equal(t, first, assignedIDs(strategy.Assign(input)), "same seed must give the same schedule")
+
+			// Everyone serves exactly once per cycle
+			memberCount := len(tc.members)
+			for cycle := 0; cycle+memberCount <= len(first); cycle += memberCount {
+				assert.ElementsMatch(t, memberIDs(tc.members), first[cycle:cycle+memberCount])
+			}
+		})
+	}
+}
+
+func TestSeededShuffleStrategyAvoidsBackToBackDuty(t *testing.T) {
+	members := append(append([]models.TeamMember{}, threeMembers...), models.TeamMember{ID: 4, Name: "Dana", Active: true, DateAdded: joinedDate})
+
+	for _, roster := range [][]models.TeamMember{threeMembers, members} {
+		for seed := int64(0); seed < 20; seed++ {
+			input := RotationInput{
+				Dates:       workingDatesFrom(testMonday, 200),
+				Members:     roster,
+				WorkingDays: weekdaysMonToFri(),
+			}
+
+			ids := assignedIDs((&seededShuffleStrategy{seed: seed}).Assign(input))
+			for i := 1; i < len(ids); i++ {
+				assert.NotEqual(t, ids[i-1], ids[i], "seed %d with %d members: member %d on duty twice in a row on day %d", seed, len(roster), ids[i], i)
+			}
+		}
+	}
+}
+
+func TestFairShareStrategy(t *testing.T) {
+	newcomer := models.TeamMember{ID: 4, Name: "Dana", Active: true, DateAdded: time.Date(2023, 9, 28, 0, 0, 0, 0, time.UTC)}
+
+	testCases := []struct {
+		name     string
+		members  []models.TeamMember
+		history  []models.ScheduleEntry
+		timeOff  []models.TimeOff
+		expected []int
+	}{
+		{
+			name:     "balanced history follows roster order",
+			members:  threeMembers,
+			history:  []models.ScheduleEntry{historyEntry("2023-09-27", 1), historyEntry("2023-09-28", 2), historyEntry("2023-09-29", 3)},
+			expected: []int{1, 2, 3, 1, 2},
+		},
+		{
+			name:    "member behind their share catches up first",
+			members: threeMembers,
+			history: []models.ScheduleEntry{
+				historyEntry("2023-09-26", 1), historyEntry("2023-09-27", 2),
+				historyEntry("2023-09-28", 1), historyEntry("2023-09-29", 2),
+			},
+			expected: []int{3, 3, 1, 2, 3},
+		},
+		{
+			name:    "newcomer only shares slots after joining",
+			members: append(append([]models.TeamMember{}, threeMembers...), newcomer),
+			history: []models.ScheduleEntry{
+				historyEntry("2023-09-25", 1), historyEntry("2023-09-26", 2), historyEntry("2023-09-27", 3),
+				historyEntry("2023-09-28", 1), historyEntry("2023-09-29", 2),
+			},
+			expected: []int{3, 4, 1, 2, 3},
+		},
+		{
+			name:     "slots held by former members are ignored",
+			members:  threeMembers,
+			history:  []models.ScheduleEntry{historyEntry("2023-09-28", 99), historyEntry("2023-09-29", 1)},
+			expected: []int{2, 3, 1, 2, 3},
+		},
+		{
+			name:     "time off isn't caught up on afterwards",
+			members:  threeMembers,
+			history:  []models.ScheduleEntry{historyEntry("2023-09-27", 1), historyEntry("2023-09-28", 2), historyEntry("2023-09-29", 3)},
+			timeOff:  []models.TimeOff{timeOffPeriod(1, "2023-10-02", "2023-10-03")},
+			expected: []int{2, 3, 1, 2, 3},
+		},
+		{
+			name:     "history slots during time off aren't shared with the absent member",
+			members:  threeMembers,
+			history:  []models.ScheduleEntry{historyEntry("2023-09-28", 1), historyEntry("2023-09-29", 2)},
+			timeOff:  []models.TimeOff{timeOffPeriod(3, "2023-09-28", "2023-09-29")},
+			expected: []int{1, 2, 3, 1, 2},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			input := RotationInput{
+				Dates:       workingDatesFrom(testMonday, 5),
+				Members:     tc.members,
+				WorkingDays: weekdaysMonToFri(),
+				History:     tc.history,
+				TimeOff:     tc.timeOff,
+			}
+
+			strategy := &fairShareStrategy{}
+			from, needsHistory := strategy.HistoryFrom(input)
+			assert.True(t, needsHistory)
+			assert.Equal(t, joinedDate, from)
+			assert.Equal(t, tc.expected, assignedIDs(strategy.Assign(input)))
+		})
+	}
+}
+
+func TestRotationStrategiesSkipMembersOnTimeOff(t *testing.T) {
+	strategies := map[string]RotationStrategy{
+		"epoch modulo":          &epochModuloStrategy{},
+		"round robin":           &roundRobinStrategy{},
+		"least recently served": &leastRecentlyServedStrategy{},
+		"seeded shuffle":        &seededShuffleStrategy{seed: 42},
+		"fair share":            &fairShareStrategy{},
+	}
+
+	for name, strategy := range strategies {
+		t.Run(name, func(t *testing.T) {
+			input := RotationInput{
+				Start:       testMonday,
+				End:         testMonday.AddDate(0, 0, 14),
+				Dates:       workingDatesFrom(testMonday, 10),
+				Members:     threeMembers,
+				WorkingDays: weekdaysMonToFri(),
+				TimeOff: []models.TimeOff{
+					timeOffPeriod(2, "2023-10-02", "2023-10-06"), // Bob is away the whole first week
+					timeOffPeriod(1, "2023-10-11", "2023-10-11"), // Everyone is away on Wednesday the 11th
+					timeOffPeriod(2, "2023-10-11", "2023-10-11"),
+					timeOffPeriod(3, "2023-10-11", "2023-10-11"),
+				},
+			}
+
+			assignments := strategy.Assign(input)
+			assert.Len(t, assignments, 10)
+			for _, assignment := range assignments[:5] {
+				assert.NotEqual(t, 2, assignment.TeamMemberID, "Bob is assigned on %s", models.FormatDate(assignment.WorkingDate.Date))
+			}
+
+			// Somebody still has to be on duty when the whole team is away
+			assert.Contains(t, memberIDs(threeMembers), assignments[7].TeamMemberID)
+		})
+	}
+}
+
+// TestBackupFor tests that the backup is the next available member after the primary
+func TestBackupFor(t *testing.T) {
+	wednesday := testMonday.AddDate(0, 0, 2)
+	input := RotationInput{
+		Members: threeMembers,
+		TimeOff: []models.TimeOff{
+			timeOffPeriod(3, "2023-10-04", "2023-10-04"),
+			timeOffPeriod(1, "2023-10-06", "2023-10-06"),
+			timeOffPeriod(3, "2023-10-06", "2023-10-06"),
+		},
+	}
+
+	testCases := []struct {
+		name      string
+		primaryID int
+		date      time.Time
+		expected  int
+	}{
+		{name: "next member in rotation order", primaryID: 1, date: testMonday, expected: 2},
+		{name: "wraps around", primaryID: 3, date: testMonday, expected: 1},
+		{name: "skips members on time off", primaryID: 2, date: wednesday, expected: 1},
+		{name: "nobody available keeps the next member", primaryID: 2, date: testMonday.AddDate(0, 0, 4), expected: 3},
+		{name: "primary outside the rotation", primaryID: 9, date: testMonday, expected: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			backupID, ok := input.backupFor(tc.primaryID, tc.date)
+			assert.True(t, ok)
+			assert.Equal(t, tc.expected, backupID)
+		})
+	}
+
+	_, ok := RotationInput{Members: threeMembers[:1]}.backupFor(1, testMonday)
+	assert.False(t, ok, "A single member can't back themselves up")
+}
+
+// TestStrategiesIgnoreBackups tests that backups in the history don't count as turns
+func TestStrategiesIgnoreBackups(t *testing.T) {
+	input := RotationInput{
+		Start:       testMonday,
+		End:         testMonday.AddDate(0, 0, 7),
+		Dates:       workingDatesFrom(testMonday, 3),
+		Members:     threeMembers,
+		WorkingDays: weekdaysMonToFri(),
+		History: []models.ScheduleEntry{
+			historyEntry("2023-09-27", 1),
+			historyEntry("2023-09-28", 2),
+			historyEntry("2023-09-29", 3),
+			backupEntry("2023-09-29", 1),
+		},
+	}
+
+	// Alice was only the backup on Friday, so she is still the least recently on duty
+	assignments := (&leastRecentlyServedStrategy{}).Assign(input.forShift(""))
+	assert.Equal(t, 1, assignments[0].TeamMemberID)
+	assert.False(t, isRotationSlot(backupEntry("2023-09-29", 1)))
+}
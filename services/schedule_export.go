@@ -0,0 +1,116 @@
+package services
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// Formats the schedule can be exported in
+const (
+	ExportFormatCSV = "csv"
+	ExportFormatICS = "ics"
+)
+
+// FilterByLayer returns the entries of a single layer of the schedule, or all entries when the
+// layer is empty
+func FilterByLayer(entries []models.ScheduleEntry, layer string) []models.ScheduleEntry {
+	if layer == "" {
+		return entries
+	}
+
+	var filtered []models.ScheduleEntry
+	for _, entry := range entries {
+		if entry.GetLayer() == layer {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// WriteScheduleCSV writes the entries as CSV with a header row. The layer column tells working
+// hours entries and on-call blocks apart.
+func WriteScheduleCSV(w io.Writer, entries []models.ScheduleEntry) error {
+	writer := csv.NewWriter(w)
+
+	header := []string{"date", "layer", "shift", "role", "member", "slack_handle", "start_time", "end_date", "end_time", "manual_override"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, entry := range entries {
+		record := []string{
+			entry.GetFormattedDate(),
+			entry.GetLayer(),
+			entry.Shift,
+			entry.GetRole(),
+			entry.TeamMemberName,
+			entry.TeamMemberSlackHandle,
+			entry.StartTime,
+			models.FormatDate(entry.EndsAt(time.UTC)),
+			entry.EndTime,
+			strconv.FormatBool(entry.IsManualOverride),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV record: %w", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// WriteScheduleICS writes the entries as an iCalendar file with one event per entry. The times are
+// floating local times, calendar apps show them in the time zone of the reader. The layer is the
+// category of the event.
+func WriteScheduleICS(w io.Writer, entries []models.ScheduleEntry, calendarName string) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//EOD Scheduler//Schedule Export//EN",
+		"CALSCALE:GREGORIAN",
+		"X-WR-CALNAME:" + escapeICSText(calendarName),
+	}
+
+	stamp := timeNow().UTC().Format("20060102T150405Z")
+	for _, entry := range entries {
+		summary := entry.TeamMemberName
+		if entry.Shift != "" {
+			summary += " (" + entry.Shift + ")"
+		}
+		if entry.IsBackup() {
+			summary += " - " + entry.GetRoleName()
+		}
+
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			fmt.Sprintf("UID:schedule-entry-%d@eod-scheduler", entry.ID),
+			"DTSTAMP:"+stamp,
+			"DTSTART:"+entry.StartsAt(time.UTC).Format("20060102T150405"),
+			"DTEND:"+entry.EndsAt(time.UTC).Format("20060102T150405"),
+			"SUMMARY:"+escapeICSText(entry.GetLayerName()+": "+summary),
+			"CATEGORIES:"+escapeICSText(entry.GetLayerName()),
+			"END:VEVENT",
+		)
+	}
+	lines = append(lines, "END:VCALENDAR")
+
+	if _, err := io.WriteString(w, strings.Join(lines, "\r\n")+"\r\n"); err != nil {
+		return fmt.Errorf("failed to write calendar: %w", err)
+	}
+	return nil
+}
+
+// escapeICSText escapes a TEXT value, the reverse of unescapeICSText
+func escapeICSText(text string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `;`, `\;`, `,`, `\,`, "\n", `\n`)
+	return replacer.Replace(text)
+}
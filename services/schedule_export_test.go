@@ -0,0 +1,55 @@
+package services
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// exportEntries returns a working hours entry and the on-call block after it
+func exportEntries() []models.ScheduleEntry {
+	tuesday := testMonday.AddDate(0, 0, 1)
+	return []models.ScheduleEntry{
+		{ID: 1, Date: testMonday, TeamMemberName: "Alice", TeamMemberSlackHandle: "@alice", StartTime: "09:00", EndTime: "17:00", Shift: "Day"},
+		{ID: 2, Date: testMonday, TeamMemberName: "Bob", StartTime: "17:00", EndTime: "09:00", Layer: models.ScheduleLayerOnCall, EndDate: &tuesday, IsManualOverride: true},
+	}
+}
+
+func TestFilterByLayer(t *testing.T) {
+	entries := exportEntries()
+
+	assert.Len(t, FilterByLayer(entries, ""), 2)
+	assert.Equal(t, entries[:1], FilterByLayer(entries, models.ScheduleLayerWorkingHours))
+	assert.Equal(t, entries[1:], FilterByLayer(entries, models.ScheduleLayerOnCall))
+}
+
+func TestWriteScheduleCSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteScheduleCSV(&buf, exportEntries()))
+
+	assert.Equal(t, strings.Join([]string{
+		"date,layer,shift,role,member,slack_handle,start_time,end_date,end_time,manual_override",
+		"2023-10-02,working_hours,Day,primary,Alice,@alice,09:00,2023-10-02,17:00,false",
+		"2023-10-02,on_call,,primary,Bob,,17:00,2023-10-03,09:00,true",
+	}, "\n")+"\n", buf.String())
+}
+
+func TestWriteScheduleICS(t *testing.T) {
+	var buf bytes.Buffer
+	assert.NoError(t, WriteScheduleICS(&buf, exportEntries(), "Platform, on duty"))
+
+	calendar := buf.String()
+	assert.Contains(t, calendar, "X-WR-CALNAME:Platform\\, on duty\r\n")
+	assert.Contains(t, calendar, "DTSTART:20231002T090000\r\nDTEND:20231002T170000\r\nSUMMARY:Working hours: Alice (Day)\r\nCATEGORIES:Working hours\r\n")
+	assert.Contains(t, calendar, "DTSTART:20231002T170000\r\nDTEND:20231003T090000\r\nSUMMARY:On-call: Bob\r\nCATEGORIES:On-call\r\n")
+
+	// The export reads back with the holiday calendar import
+	events, err := parseICS(strings.NewReader(calendar))
+	assert.NoError(t, err)
+	assert.Len(t, events, 2)
+	assert.Equal(t, "On-call: Bob", events[1].Name)
+}
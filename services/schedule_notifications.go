@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/notifications"
+)
+
+// shiftStartsSoon returns the reminder of a shift for its member
+func shiftStartsSoon(entry *models.ScheduleEntry) *notifications.Notification {
+	return &notifications.Notification{
+		Event:     models.NotificationShiftStartsSoon,
+		Subject:   "Shift starts soon",
+		Text:      entry.TeamMemberName + " is on duty for " + dutyDescription(entry) + ".",
+		Entry:     entry,
+		MemberIDs: []int{entry.TeamMemberID},
+	}
+}
+
+// notifyTakeover tells the member who took over a shift, the entry is the manual override
+func (s *scheduleService) notifyTakeover(ctx context.Context, entry *models.ScheduleEntry, previousName string) {
+	s.notifier.Dispatch(ctx, &notifications.Notification{
+		Event:     models.NotificationTakeover,
+		Subject:   "Shift taken over",
+		Text:      entry.TeamMemberName + " takes over " + dutyDescription(entry) + " from " + previousName + ".",
+		Entry:     entry,
+		MemberIDs: []int{entry.TeamMemberID},
+	})
+}
+
+// notifyOverrideRemoved tells the member of a manual override that was removed
+func (s *scheduleService) notifyOverrideRemoved(ctx context.Context, override *models.ScheduleEntry) {
+	s.notifier.Dispatch(ctx, &notifications.Notification{
+		Event:     models.NotificationOverrideRemoved,
+		Subject:   "Takeover removed",
+		Text:      override.TeamMemberName + " no longer takes over " + dutyDescription(override) + ", the original assignment is back.",
+		Entry:     override,
+		MemberIDs: []int{override.TeamMemberID},
+	})
+}
+
+// notifyRegenerated tells every active member the schedule was generated again
+func (s *scheduleService) notifyRegenerated(ctx context.Context) {
+	s.notifier.Dispatch(ctx, &notifications.Notification{
+		Event:   models.NotificationRegenerated,
+		Subject: "Schedule regenerated",
+		Text:    "The schedule was generated again, check your upcoming shifts.",
+	})
+}
+
+// dutyDescription describes the duty of a schedule entry, like "the EOD of Tue 2023-10-03 (09:00–17:00)"
+// or "the backup Morning shift of Tue 2023-10-03 (08:00–13:00)"
+func dutyDescription(entry *models.ScheduleEntry) string {
+	duty := "EOD"
+	switch {
+	case entry.IsOnCall():
+		duty = "on-call"
+	case entry.Shift != "":
+		duty = entry.Shift + " shift"
+	}
+	if entry.IsBackup() {
+		duty = "backup " + duty
+	}
+	return "the " + duty + " of " + slackDate(entry.Date) + " (" + hoursText(entry) + ")"
+}
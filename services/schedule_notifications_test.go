@@ -0,0 +1,127 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/notifications"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+// notificationRecorder is a dispatcher that keeps the notifications instead of sending them
+type notificationRecorder struct {
+	mu   sync.Mutex
+	sent []*notifications.Notification
+}
+
+// Dispatch records the notification
+func (r *notificationRecorder) Dispatch(ctx context.Context, n *notifications.Notification) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sent = append(r.sent, n)
+}
+
+// events returns the events of the recorded notifications in the order they were dispatched
+func (r *notificationRecorder) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []string
+	for _, n := range r.sent {
+		events = append(events, n.Event)
+	}
+	return events
+}
+
+// TestCreateManualOverride_NotifiesTakeover tests that the member taking over a shift is notified
+func TestCreateManualOverride_NotifiesTakeover(t *testing.T) {
+	ctx := context.Background()
+	mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+	mockTeamRepo := dbMocks.NewMockTeamRepository(t)
+	notifier := &notificationRecorder{}
+	webhooks := &webhookRecorder{}
+	service := NewScheduleService(
+		mockScheduleRepo,
+		mockTeamRepo,
+		dbMocks.NewMockWorkingHoursRepository(t),
+		dbMocks.NewMockTimeOffRepository(t),
+		dbMocks.NewMockHolidayRepository(t),
+		passThroughTx(t),
+		notifier,
+		webhooks,
+	)
+
+	generated := historyEntry("2023-10-02", 1)
+	generated.ID = 10
+	generated.TeamMemberName = "Alice"
+	created := historyEntry("2023-10-02", 2)
+	created.ID = 11
+	created.TeamMemberName = "Bob"
+	created.StartTime, created.EndTime = "09:00", "17:00"
+	created.IsManualOverride = true
+	mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&threeMembers[1], nil)
+	mockScheduleRepo.EXPECT().GetByID(ctx, 10).Return(&generated, nil)
+	mockScheduleRepo.EXPECT().GetByDate(ctx, generated.Date).Return([]models.ScheduleEntry{generated}, nil)
+	mockScheduleRepo.EXPECT().Delete(ctx, 10).Return(nil)
+	mockScheduleRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.ScheduleEntry")).RunAndReturn(
+		func(ctx context.Context, entry *models.ScheduleEntry) error {
+			entry.ID = 11
+			return nil
+		},
+	)
+	mockScheduleRepo.EXPECT().GetByID(ctx, 11).Return(&created, nil)
+
+	form := &models.ScheduleEntryForm{Date: "2023-10-02", TeamMemberID: 2, StartTime: "09:00", EndTime: "17:00", Confirmed: true}
+	_, err := service.CreateManualOverride(ctx, 10, form)
+	assert.NoError(t, err)
+
+	if assert.Len(t, notifier.sent, 1) {
+		n := notifier.sent[0]
+		assert.Equal(t, models.NotificationTakeover, n.Event)
+		assert.Equal(t, []int{2}, n.MemberIDs)
+		assert.Equal(t, "Bob takes over the EOD of Mon 2023-10-02 (09:00–17:00) from Alice.", n.Text)
+	}
+
+	// The webhooks hear about the replaced entry, the override that replaces it and the takeover
+	assert.Equal(t, []string{models.WebhookEventEntryDeleted, models.WebhookEventEntryCreated, models.WebhookEventOverrideCreated}, webhooks.events())
+	if assert.Len(t, webhooks.published, 3) {
+		assert.Equal(t, &webhookOverride{Override: &created, Replaced: &generated}, webhooks.published[2].data)
+	}
+}
+
+// TestDutyDescription tests the description of the duty of an entry in notifications
+func TestDutyDescription(t *testing.T) {
+	endDate := time.Date(2023, 10, 9, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name     string
+		entry    models.ScheduleEntry
+		expected string
+	}{
+		{
+			name:     "EOD",
+			entry:    models.ScheduleEntry{Date: testMonday, StartTime: "09:00", EndTime: "17:00"},
+			expected: "the EOD of Mon 2023-10-02 (09:00–17:00)",
+		},
+		{
+			name:     "backup shift",
+			entry:    models.ScheduleEntry{Date: testMonday, Shift: "Morning", Role: models.ScheduleRoleBackup, StartTime: "08:00", EndTime: "13:00"},
+			expected: "the backup Morning shift of Mon 2023-10-02 (08:00–13:00)",
+		},
+		{
+			name:     "on-call block",
+			entry:    models.ScheduleEntry{Date: testMonday, Layer: models.ScheduleLayerOnCall, StartTime: "17:00", EndTime: "09:00", EndDate: &endDate},
+			expected: "the on-call of Mon 2023-10-02 (17:00–Mon 09:00)",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, dutyDescription(&tc.entry))
+		})
+	}
+}
@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+)
+
+// PreviewSchedule computes what generating the schedule again would change, without writing
+// anything. The generator runs as it would for the Generate button, against a repository that
+// keeps its changes in memory, and the result is compared with the current schedule slot by slot.
+func (s *scheduleService) PreviewSchedule(ctx context.Context) (*models.SchedulePreview, error) {
+	state, err := s.scheduleRepo.GetState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule state: %w", err)
+	}
+
+	// Everything from today up to the end of the schedule can change
+	from := truncateToDate(timeNow())
+	to := scheduleEnd(state, from)
+
+	overlay := newPreviewRepository(s.scheduleRepo)
+	preview := *s
+	preview.scheduleRepo = overlay
+	preview.rotation = newRotationQueue(overlay, s.teamRepo, s.workingHoursRepo, s.holidayRepo)
+
+	result, err := preview.GenerateSchedule(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+	if !result.Success {
+		return &models.SchedulePreview{From: from, To: to, Result: result}, nil
+	}
+
+	before, err := s.scheduleRepo.GetByDateRange(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current schedule: %w", err)
+	}
+	after, err := overlay.GetByDateRange(ctx, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get previewed schedule: %w", err)
+	}
+
+	members, err := s.teamRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	names := make(map[int]string)
+	for _, member := range members {
+		names[member.ID] = member.Name
+	}
+
+	days, changes := diffSchedules(before, after, names)
+	return &models.SchedulePreview{
+		From:    from,
+		To:      to,
+		Result:  result,
+		Days:    days,
+		Members: changes,
+	}, nil
+}
+
+// previewSlotKey identifies the slot of an entry: its shift or on-call block and its role
+func previewSlotKey(entry models.ScheduleEntry) string {
+	return slotKey(entry) + "|" + entry.GetRole()
+}
+
+// diffSchedules compares two versions of the schedule. It returns the slots that change member
+// grouped by day, and per member the number of entries in both versions and the dates they gain
+// or lose.
+func diffSchedules(before, after []models.ScheduleEntry, names map[int]string) ([]models.PreviewDay, []models.MemberChanges) {
+	type slot struct {
+		before, after *models.ScheduleEntry
+	}
+	slots := make(map[string]*slot)
+	var keys []string
+	slotFor := func(entry models.ScheduleEntry) *slot {
+		key := previewSlotKey(entry)
+		if slots[key] == nil {
+			slots[key] = &slot{}
+			keys = append(keys, key)
+		}
+		return slots[key]
+	}
+
+	totals := make(map[int]*models.MemberChanges)
+	memberFor := func(memberID int) *models.MemberChanges {
+		if totals[memberID] == nil {
+			totals[memberID] = &models.MemberChanges{TeamMemberID: memberID, Name: names[memberID]}
+		}
+		return totals[memberID]
+	}
+
+	for i := range before {
+		slotFor(before[i]).before = &before[i]
+		memberFor(before[i].TeamMemberID).Before++
+	}
+	for i := range after {
+		slotFor(after[i]).after = &after[i]
+		memberFor(after[i].TeamMemberID).After++
+	}
+
+	// Changes in schedule order: by date, start time and role
+	var changed []*models.ScheduleEntry
+	changeOf := make(map[*models.ScheduleEntry]models.SlotChange)
+	for _, key := range keys {
+		current, next := slots[key].before, slots[key].after
+		if current != nil && next != nil && current.TeamMemberID == next.TeamMemberID {
+			continue
+		}
+
+		entry := next
+		if entry == nil {
+			entry = current
+		}
+		change := models.SlotChange{
+			Shift:     entry.Shift,
+			StartTime: entry.StartTime,
+			EndTime:   entry.EndTime,
+			Role:      entry.GetRole(),
+			Layer:     entry.GetLayer(),
+		}
+		if current != nil {
+			change.Before = &models.PreviewMember{TeamMemberID: current.TeamMemberID, Name: names[current.TeamMemberID]}
+			lost := memberFor(current.TeamMemberID)
+			lost.Lost = append(lost.Lost, current.Date)
+		}
+		if next != nil {
+			change.After = &models.PreviewMember{TeamMemberID: next.TeamMemberID, Name: names[next.TeamMemberID]}
+			gained := memberFor(next.TeamMemberID)
+			gained.Gained = append(gained.Gained, next.Date)
+		}
+		changed = append(changed, entry)
+		changeOf[entry] = change
+	}
+	sort.SliceStable(changed, func(i, j int) bool { return inScheduleOrder(changed[i], changed[j]) })
+
+	var days []models.PreviewDay
+	for _, entry := range changed {
+		date := truncateToDate(entry.Date)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, models.PreviewDay{Date: date})
+		}
+		days[len(days)-1].Changes = append(days[len(days)-1].Changes, changeOf[entry])
+	}
+
+	var changes []models.MemberChanges
+	for _, member := range totals {
+		changes = append(changes, *member)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Name != changes[j].Name {
+			return changes[i].Name < changes[j].Name
+		}
+		return changes[i].TeamMemberID < changes[j].TeamMemberID
+	})
+
+	return days, changes
+}
+
+// inScheduleOrder checks if an entry comes before another in the schedule: by date, start time,
+// and the primary before the backup
+func inScheduleOrder(a, b *models.ScheduleEntry) bool {
+	if a.GetFormattedDate() != b.GetFormattedDate() {
+		return a.GetFormattedDate() < b.GetFormattedDate()
+	}
+	if a.StartTime != b.StartTime {
+		return a.StartTime < b.StartTime
+	}
+	return !a.IsBackup() && b.IsBackup()
+}
+
+// errNotInPreview is returned by the repository operations the generator doesn't use
+var errNotInPreview = errors.New("not available in a preview")
+
+// previewRepository is a schedule repository that keeps the changes of a generation in memory on
+// top of the stored schedule, so the generator can run without writing anything
+type previewRepository struct {
+	repositories.ScheduleRepository
+	created []models.ScheduleEntry
+	deleted map[int]bool
+	state   *models.ScheduleState
+}
+
+// newPreviewRepository creates a preview repository on top of the stored schedule
+func newPreviewRepository(scheduleRepo repositories.ScheduleRepository) *previewRepository {
+	return &previewRepository{
+		ScheduleRepository: scheduleRepo,
+		deleted:            make(map[int]bool),
+	}
+}
+
+// GetByDateRange returns the stored entries that weren't deleted and the created ones, in the
+// order of the stored schedule
+func (p *previewRepository) GetByDateRange(ctx context.Context, from, to time.Time) ([]models.ScheduleEntry, error) {
+	stored, err := p.ScheduleRepository.GetByDateRange(ctx, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []models.ScheduleEntry
+	for _, entry := range stored {
+		if !p.deleted[entry.ID] {
+			entries = append(entries, entry)
+		}
+	}
+	for _, entry := range p.created {
+		if date := entry.GetFormattedDate(); !p.deleted[entry.ID] && date >= models.FormatDate(from) && date <= models.FormatDate(to) {
+			entries = append(entries, entry)
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool { return inScheduleOrder(&entries[i], &entries[j]) })
+	return entries, nil
+}
+
+// GetByDate returns the entries of a single date
+func (p *previewRepository) GetByDate(ctx context.Context, date time.Time) ([]models.ScheduleEntry, error) {
+	return p.GetByDateRange(ctx, date, date)
+}
+
+// GetByID returns an entry unless it was deleted
+func (p *previewRepository) GetByID(ctx context.Context, id int) (*models.ScheduleEntry, error) {
+	if p.deleted[id] {
+		return nil, fmt.Errorf("schedule entry %d was deleted", id)
+	}
+	for i := range p.created {
+		if p.created[i].ID == id {
+			return &p.created[i], nil
+		}
+	}
+	return p.ScheduleRepository.GetByID(ctx, id)
+}
+
+// Create keeps the entry in memory. Created entries get negative IDs, which stored entries never have.
+func (p *previewRepository) Create(ctx context.Context, entry *models.ScheduleEntry) error {
+	entry.ID = -(len(p.created) + 1)
+	p.created = append(p.created, *entry)
+	return nil
+}
+
+// CreateBatch keeps the entries in memory
+func (p *previewRepository) CreateBatch(ctx context.Context, entries []*models.ScheduleEntry) error {
+	for _, entry := range entries {
+		if err := p.Create(ctx, entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete hides the entry from later reads
+func (p *previewRepository) Delete(ctx context.Context, id int) error {
+	p.deleted[id] = true
+	return nil
+}
+
+// DeleteBatch hides the entries from later reads
+func (p *previewRepository) DeleteBatch(ctx context.Context, ids []int) error {
+	for _, id := range ids {
+		p.deleted[id] = true
+	}
+	return nil
+}
+
+// Update isn't used by the generator
+func (p *previewRepository) Update(ctx context.Context, entry *models.ScheduleEntry) error {
+	return errNotInPreview
+}
+
+// DeleteByDateRange isn't used by the generator
+func (p *previewRepository) DeleteByDateRange(ctx context.Context, from, to time.Time) error {
+	return errNotInPreview
+}
+
+// GetState returns the state as the generator last saved it, or the stored state
+func (p *previewRepository) GetState(ctx context.Context) (*models.ScheduleState, error) {
+	if p.state != nil {
+		return p.state, nil
+	}
+	return p.ScheduleRepository.GetState(ctx)
+}
+
+// UpdateState keeps the state in memory
+func (p *previewRepository) UpdateState(ctx context.Context, state *models.ScheduleState) error {
+	p.state = state
+	return nil
+}
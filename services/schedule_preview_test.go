@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+var previewNames = map[int]string{1: "Alice", 2: "Bob", 3: "Charlie"}
+
+func TestDiffSchedules(t *testing.T) {
+	tuesdayBackup := backupEntry("2023-10-03", 3)
+	before := []models.ScheduleEntry{
+		historyEntry("2023-10-02", 1),
+		historyEntry("2023-10-03", 2),
+		tuesdayBackup,
+		historyEntry("2023-10-04", 3),
+	}
+	after := []models.ScheduleEntry{
+		historyEntry("2023-10-02", 1),
+		historyEntry("2023-10-03", 1),
+		backupEntry("2023-10-03", 3),
+		historyEntry("2023-10-05", 3),
+	}
+
+	days, members := diffSchedules(before, after, previewNames)
+
+	// Monday and Tuesday's backup stay as they are
+	assert.Equal(t, []models.PreviewDay{
+		{Date: testMonday.AddDate(0, 0, 1), Changes: []models.SlotChange{{
+			Role:   models.ScheduleRolePrimary,
+			Layer:  models.ScheduleLayerWorkingHours,
+			Before: &models.PreviewMember{TeamMemberID: 2, Name: "Bob"},
+			After:  &models.PreviewMember{TeamMemberID: 1, Name: "Alice"},
+		}}},
+		{Date: testMonday.AddDate(0, 0, 2), Changes: []models.SlotChange{{
+			Role:   models.ScheduleRolePrimary,
+			Layer:  models.ScheduleLayerWorkingHours,
+			Before: &models.PreviewMember{TeamMemberID: 3, Name: "Charlie"},
+		}}},
+		{Date: testMonday.AddDate(0, 0, 3), Changes: []models.SlotChange{{
+			Role:  models.ScheduleRolePrimary,
+			Layer: models.ScheduleLayerWorkingHours,
+			After: &models.PreviewMember{TeamMemberID: 3, Name: "Charlie"},
+		}}},
+	}, days)
+
+	assert.Equal(t, []models.MemberChanges{
+		{TeamMemberID: 1, Name: "Alice", Before: 1, After: 2, Gained: []time.Time{testMonday.AddDate(0, 0, 1)}},
+		{TeamMemberID: 2, Name: "Bob", Before: 1, After: 0, Lost: []time.Time{testMonday.AddDate(0, 0, 1)}},
+		{TeamMemberID: 3, Name: "Charlie", Before: 2, After: 2,
+			Gained: []time.Time{testMonday.AddDate(0, 0, 3)}, Lost: []time.Time{testMonday.AddDate(0, 0, 2)}},
+	}, members)
+}
+
+func TestPreviewRepository(t *testing.T) {
+	ctx := context.Background()
+	mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+	monday := historyEntry("2023-10-02", 1)
+	monday.ID = 10
+	tuesday := historyEntry("2023-10-03", 2)
+	tuesday.ID = 11
+	mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{monday, tuesday}, nil)
+	mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil).Once()
+
+	overlay := newPreviewRepository(mockScheduleRepo)
+
+	// Writes stay in memory, the mock fails the test on any write
+	assert.NoError(t, overlay.Delete(ctx, 11))
+	created := historyEntry("2023-10-03", 3)
+	assert.NoError(t, overlay.Create(ctx, &created))
+	assert.Equal(t, -1, created.ID)
+	outside := historyEntry("2023-10-09", 1)
+	assert.NoError(t, overlay.Create(ctx, &outside))
+
+	entries, err := overlay.GetByDateRange(ctx, testMonday, testMonday.AddDate(0, 0, 6))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{10, -1}, []int{entries[0].ID, entries[1].ID})
+
+	state, err := overlay.GetState(ctx)
+	assert.NoError(t, err)
+	state.RotationCursor = 2
+	assert.NoError(t, overlay.UpdateState(ctx, state))
+	saved, err := overlay.GetState(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, saved.RotationCursor)
+
+	assert.ErrorIs(t, overlay.Update(ctx, &created), errNotInPreview)
+}
+
+// TestPreviewSchedule tests that a preview shows the changes of generating again without writing anything
+func (suite *GenerateScheduleTestSuite) TestPreviewSchedule() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	// Bob left after this week was generated
+	var stored []models.ScheduleEntry
+	for i, memberID := range []int{1, 2, 3, 1, 2} {
+		entry := historyEntry(models.FormatDate(testMonday.AddDate(0, 0, i)), memberID)
+		entry.ID = 10 + i
+		stored = append(stored, entry)
+	}
+	activeMembers := []models.TeamMember{threeMembers[0], threeMembers[2]}
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(activeMembers, nil)
+	suite.mockTeamRepo.EXPECT().GetAll(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).RunAndReturn(func(ctx context.Context) (*models.ScheduleState, error) {
+		return &models.ScheduleState{
+			TeamID:             1,
+			LastGenerationDate: testMonday.AddDate(0, 0, -3),
+			GeneratedUntil:     testMonday.AddDate(0, 0, 7),
+			HorizonLength:      1,
+			HorizonUnit:        models.HorizonUnitWeeks,
+		}, nil
+	})
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, from, to time.Time) ([]models.ScheduleEntry, error) {
+			var entries []models.ScheduleEntry
+			for _, entry := range stored {
+				if !entry.Date.Before(truncateToDate(from)) && !entry.Date.After(to) {
+					entries = append(entries, entry)
+				}
+			}
+			return entries, nil
+		},
+	)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+
+	// Act
+	preview, err := suite.service.PreviewSchedule(ctx)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), preview.Result.Success)
+	assert.Equal(suite.T(), testMonday, preview.From)
+	assert.Equal(suite.T(), testMonday.AddDate(0, 0, 7), preview.To)
+
+	// Today stays as it is, Bob's days go to Alice and Charlie
+	assert.True(suite.T(), preview.HasChanges())
+	var lostByBob []string
+	for _, day := range preview.Days {
+		assert.True(suite.T(), day.Date.After(testMonday))
+		for _, change := range day.Changes {
+			assert.NotNil(suite.T(), change.After)
+			assert.NotEqual(suite.T(), 2, change.After.TeamMemberID)
+			if change.Before != nil && change.Before.TeamMemberID == 2 {
+				lostByBob = append(lostByBob, models.FormatDate(day.Date))
+			}
+		}
+	}
+	assert.Equal(suite.T(), []string{"2023-10-03", "2023-10-06"}, lostByBob)
+
+	total := 0
+	for _, member := range preview.Members {
+		total += member.After
+		if member.TeamMemberID == 2 {
+			assert.Equal(suite.T(), -2, member.Difference())
+		}
+	}
+	assert.Equal(suite.T(), 5, total)
+}
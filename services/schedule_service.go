@@ -3,10 +3,12 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/notifications"
 	"github.com/blogem/eod-scheduler/repositories"
 )
 
@@ -20,19 +22,27 @@ type ScheduleService interface {
 	GetDashboardData(ctx context.Context) (*DashboardData, error)
 	GetWeeklySchedule(ctx context.Context, startDate time.Time) (*models.WeekView, error)
 	GenerateSchedule(ctx context.Context, force bool) (*models.GenerationResult, error)
+	RunGeneration(ctx context.Context, trigger string) (*models.GenerationResult, error)
+	PreviewSchedule(ctx context.Context) (*models.SchedulePreview, error)
 	CreateManualOverride(ctx context.Context, entryID int, form *models.ScheduleEntryForm) (*models.ScheduleEntry, error)
 	UpdateScheduleEntry(ctx context.Context, id int, form *models.ScheduleEntryForm) (*models.ScheduleEntry, error)
 	RemoveManualOverride(ctx context.Context, id int) error
 	GetScheduleEntry(ctx context.Context, id int) (*models.ScheduleEntry, error)
+	GetSettings(ctx context.Context) (*models.ScheduleState, error)
+	UpdateSettings(ctx context.Context, form *models.ScheduleSettingsForm) (*models.ScheduleState, error)
 }
 
 // DashboardData represents data for the dashboard view
 type DashboardData struct {
-	CurrentWeek   []models.ScheduleEntry `json:"current_week"`
-	NextWeeks     []models.ScheduleEntry `json:"next_weeks"`
-	TeamCount     int                    `json:"team_count"`
-	ActiveDays    int                    `json:"active_days"`
-	LastGenerated time.Time              `json:"last_generated"`
+	CurrentWeek    []models.ScheduleEntry `json:"current_week"`
+	OnDuty         []models.ScheduleEntry `json:"on_duty"` // Shifts under way right now
+	NextWeeks      []models.ScheduleEntry `json:"next_weeks"`
+	TeamCount      int                    `json:"team_count"`
+	ActiveDays     int                    `json:"active_days"`
+	LastGenerated  time.Time              `json:"last_generated"`
+	LastRun        models.GenerationRun   `json:"last_run"`        // Outcome of the last manual or nightly run
+	Horizon        string                 `json:"horizon"`         // How far ahead the schedule is generated, like "3 months"
+	ScheduledUntil time.Time              `json:"scheduled_until"` // Last day the schedule is filled up to, zero if unknown
 }
 
 // scheduleService implements ScheduleService interface
@@ -40,6 +50,12 @@ type scheduleService struct {
 	scheduleRepo     repositories.ScheduleRepository
 	teamRepo         repositories.TeamRepository
 	workingHoursRepo repositories.WorkingHoursRepository
+	timeOffRepo      repositories.TimeOffRepository
+	holidayRepo      repositories.HolidayRepository
+	transactor       repositories.Transactor
+	notifier         notifications.Dispatcher
+	webhooks         WebhookPublisher
+	rotation         *rotationQueue
 }
 
 // NewScheduleService creates a new schedule service
@@ -47,11 +63,22 @@ func NewScheduleService(
 	scheduleRepo repositories.ScheduleRepository,
 	teamRepo repositories.TeamRepository,
 	workingHoursRepo repositories.WorkingHoursRepository,
+	timeOffRepo repositories.TimeOffRepository,
+	holidayRepo repositories.HolidayRepository,
+	transactor repositories.Transactor,
+	notifier notifications.Dispatcher,
+	webhooks WebhookPublisher,
 ) ScheduleService {
 	return &scheduleService{
 		scheduleRepo:     scheduleRepo,
 		teamRepo:         teamRepo,
 		workingHoursRepo: workingHoursRepo,
+		timeOffRepo:      timeOffRepo,
+		holidayRepo:      holidayRepo,
+		transactor:       transactor,
+		notifier:         notifier,
+		webhooks:         webhooks,
+		rotation:         newRotationQueue(scheduleRepo, teamRepo, workingHoursRepo, holidayRepo),
 	}
 }
 
@@ -69,6 +96,13 @@ func (s *scheduleService) GetDashboardData(ctx context.Context) (*DashboardData,
 		return nil, fmt.Errorf("failed to get current week entries: %w", err)
 	}
 
+	// Get the shifts under way, yesterday's overnight shifts and on-call blocks that started days ago
+	// may still be under way
+	onDuty, err := s.getOnDuty(ctx, timeNow())
+	if err != nil {
+		return nil, err
+	}
+
 	// Get next 2 weeks
 	nextWeekStart := currentWeek.End.AddDate(0, 0, 1)
 	nextWeekEnd := nextWeekStart.AddDate(0, 0, 13) // 2 weeks
@@ -95,15 +129,51 @@ func (s *scheduleService) GetDashboardData(ctx context.Context) (*DashboardData,
 		return nil, fmt.Errorf("failed to get schedule state: %w", err)
 	}
 
+	// The filled period ends the day before the generation end
+	var scheduledUntil time.Time
+	if !state.GeneratedUntil.IsZero() {
+		scheduledUntil = state.GeneratedUntil.AddDate(0, 0, -1)
+	}
+
 	return &DashboardData{
-		CurrentWeek:   currentWeekEntries,
-		NextWeeks:     nextWeeksEntries,
-		TeamCount:     teamCount,
-		ActiveDays:    len(activeDays),
-		LastGenerated: state.LastGenerationDate,
+		CurrentWeek:    currentWeekEntries,
+		OnDuty:         onDuty,
+		NextWeeks:      nextWeeksEntries,
+		TeamCount:      teamCount,
+		ActiveDays:     countWorkingDays(models.ShiftsInEffect(activeDays, timeNow())),
+		LastGenerated:  state.LastGenerationDate,
+		LastRun:        state.LastRun,
+		Horizon:        state.DescribeHorizon(),
+		ScheduledUntil: scheduledUntil,
 	}, nil
 }
 
+// getOnDuty returns the schedule entries of the shifts under way at the given time
+func (s *scheduleService) getOnDuty(ctx context.Context, now time.Time) ([]models.ScheduleEntry, error) {
+	today := truncateToDate(now)
+	entries, err := s.scheduleRepo.GetByDateRange(ctx, today.AddDate(0, 0, -maxOnCallBlockDays), today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entries on duty: %w", err)
+	}
+
+	var onDuty []models.ScheduleEntry
+	for _, entry := range entries {
+		if entry.IsOnDutyAt(now) {
+			onDuty = append(onDuty, entry)
+		}
+	}
+	return onDuty, nil
+}
+
+// countWorkingDays counts the weekdays that have at least one active shift
+func countWorkingDays(activeDays []models.WorkingHours) int {
+	weekdays := make(map[int]bool)
+	for _, workingHours := range activeDays {
+		weekdays[workingHours.DayOfWeek] = true
+	}
+	return len(weekdays)
+}
+
 // GetWeeklySchedule retrieves schedule entries for a specific week
 func (s *scheduleService) GetWeeklySchedule(ctx context.Context, startDate time.Time) (*models.WeekView, error) {
 	weekRange := models.GetWeekStartingFrom(startDate)
@@ -141,7 +211,7 @@ func (s *scheduleService) GetWeeklySchedule(ctx context.Context, startDate time.
 	}, nil
 }
 
-// GenerateSchedule generates schedule for the next 3 months
+// GenerateSchedule generates the schedule up to the configured horizon
 func (s *scheduleService) GenerateSchedule(ctx context.Context, force bool) (*models.GenerationResult, error) {
 	// Validate that generation is possible
 	if err := s.validateScheduleGeneration(ctx); err != nil {
@@ -162,31 +232,147 @@ func (s *scheduleService) GenerateSchedule(ctx context.Context, force bool) (*mo
 		return s.createUpToDateResult(state), nil
 	}
 
+	return s.generate(ctx, state, false)
+}
+
+// RunGeneration runs a generation and records its outcome for the dashboard. A manual run
+// generates the schedule again. The nightly run extends it to the horizon, and generates it again
+// once it is no longer up to date.
+func (s *scheduleService) RunGeneration(ctx context.Context, trigger string) (*models.GenerationResult, error) {
+	var result *models.GenerationResult
+	var err error
+	if trigger == models.GenerationTriggerAutomatic {
+		result, err = s.extendSchedule(ctx)
+	} else {
+		result, err = s.GenerateSchedule(ctx, true)
+	}
+
+	run := models.GenerationRun{At: timeNow(), Trigger: trigger}
+	if err != nil {
+		run.Message = err.Error()
+	} else {
+		run.Success = result.Success
+		run.Message = result.Message
+	}
+	if recordErr := s.recordRun(ctx, run); recordErr != nil && err == nil {
+		return nil, recordErr
+	}
+
+	// Members only hear about full generations, not about the nightly extensions. Webhooks hear about
+	// both, the entries of a generation aren't published one by one.
+	if err == nil && result.Success {
+		if !result.Extended {
+			s.notifyRegenerated(ctx)
+		}
+		s.webhooks.Publish(ctx, models.WebhookEventGenerationCompleted, &webhookGeneration{Trigger: trigger, Result: result})
+	}
+
+	return result, err
+}
+
+// extendSchedule keeps the schedule covering the full horizon. An up-to-date schedule keeps what was
+// generated so far and only gets the dates that came within the horizon since, otherwise the
+// schedule is generated again.
+func (s *scheduleService) extendSchedule(ctx context.Context) (*models.GenerationResult, error) {
+	if err := s.validateScheduleGeneration(ctx); err != nil {
+		return &models.GenerationResult{
+			Success: false,
+			Message: err.Error(),
+		}, nil
+	}
+
+	state, err := s.scheduleRepo.GetState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule state: %w", err)
+	}
+
+	extend := s.isScheduleUpToDate(state) && !state.GeneratedUntil.IsZero()
+	return s.generate(ctx, state, extend)
+}
+
+// recordRun stores the outcome of a generation run in the schedule state
+func (s *scheduleService) recordRun(ctx context.Context, run models.GenerationRun) error {
+	state, err := s.scheduleRepo.GetState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get schedule state: %w", err)
+	}
+
+	state.LastRun = run
+	if err := s.scheduleRepo.UpdateState(ctx, state); err != nil {
+		return fmt.Errorf("failed to record generation run: %w", err)
+	}
+	return nil
+}
+
+// generate fills the schedule up to the horizon. With extend, everything generated so far is kept
+// and only the later dates are filled; otherwise the future entries are generated again. It runs in a
+// single transaction, so a failing generation leaves the schedule as it was.
+func (s *scheduleService) generate(ctx context.Context, state *models.ScheduleState, extend bool) (*models.GenerationResult, error) {
+	var result *models.GenerationResult
+	err := s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		result, err = s.generateInTx(ctx, state, extend)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// generateInTx does the work of generate within its transaction
+func (s *scheduleService) generateInTx(ctx context.Context, state *models.ScheduleState, extend bool) (*models.GenerationResult, error) {
 	// Get generation data (active members and working days)
 	activeMembers, activeDays, err := s.getGenerationData(ctx)
 	if err != nil {
 		return nil, err
 	}
 
+	strategy := newRotationStrategy(state)
+
+	// The schedule of the generation period is loaded once, the generation works on the snapshot
+	snapshot, err := s.loadSnapshot(ctx, state)
+	if err != nil {
+		return nil, err
+	}
+	startDate := startDateAfter(snapshot.onDate(timeNow()))
+
+	// Queued strategies continue from the persisted cursor and keep what is already published
+	publishedUntil, err := s.prepareRotationQueue(ctx, state, strategy, startDate, activeMembers)
+	if err != nil {
+		return nil, err
+	}
+
+	// An extension keeps everything up to the end of the period filled so far
+	generatedUntil := state.GeneratedUntil
+	if extend && publishedUntil.Before(generatedUntil) {
+		publishedUntil = generatedUntil
+	}
+
 	// Clean up existing entries and prepare for new generation
-	if err := s.cleanupExistingEntries(ctx); err != nil {
+	if err := s.cleanupExistingEntries(ctx, state, snapshot, publishedUntil, activeMembers); err != nil {
 		return nil, err
 	}
 
 	// Generate new schedule entries
-	entriesCreated, err := s.generateScheduleEntries(ctx, activeMembers, activeDays)
+	entriesCreated, unsatisfied, err := s.generateScheduleEntries(ctx, state, snapshot, strategy, startDate, publishedUntil, activeMembers, activeDays)
 	if err != nil {
 		return nil, err
 	}
 
+	// An extension never shortens the schedule, a shorter horizon applies from the next full generation
+	if extend && generatedUntil.After(state.GeneratedUntil) {
+		state.GeneratedUntil = generatedUntil
+	}
+
 	// Update state and return result
-	return s.finalizeGeneration(ctx, state, entriesCreated)
+	return s.finalizeGeneration(ctx, state, extend, entriesCreated, unsatisfied)
 }
 
-// isScheduleUpToDate checks if the schedule was generated recently
+// isScheduleUpToDate checks if the schedule was generated within the up-to-date interval, it isn't
+// generated again before then unless forced
 func (s *scheduleService) isScheduleUpToDate(state *models.ScheduleState) bool {
-	daysSinceLastGen := int(time.Since(state.LastGenerationDate).Hours() / 24)
-	return daysSinceLastGen < 7 // Don't regenerate more than once per week unless forced
+	return timeNow().Before(state.NextGenerationDue())
 }
 
 // createUpToDateResult creates a result indicating the schedule is current
@@ -195,7 +381,7 @@ func (s *scheduleService) createUpToDateResult(state *models.ScheduleState) *mod
 		Success:           true,
 		Message:           "Schedule is up to date",
 		GenerationDate:    state.LastGenerationDate,
-		NextGenerationDue: state.LastGenerationDate.AddDate(0, 0, 7),
+		NextGenerationDue: state.NextGenerationDue(),
 	}
 }
 
@@ -214,210 +400,434 @@ func (s *scheduleService) getGenerationData(ctx context.Context) ([]models.TeamM
 	return activeMembers, activeDays, nil
 }
 
-// cleanupExistingEntries removes non-override entries from the future period
-func (s *scheduleService) cleanupExistingEntries(ctx context.Context) error {
+// prepareRotationQueue moves the rotation cursor up to the generation start and brings the queue in
+// line with the active members. It returns the date up to which published entries are kept, which
+// is zero for strategies that don't use the queue.
+func (s *scheduleService) prepareRotationQueue(ctx context.Context, state *models.ScheduleState, strategy RotationStrategy, startDate time.Time, activeMembers []models.TeamMember) (time.Time, error) {
+	if _, ok := strategy.(queuedStrategy); !ok {
+		return time.Time{}, nil
+	}
+
+	if err := s.rotation.advance(ctx, state, startDate); err != nil {
+		return time.Time{}, err
+	}
+	s.rotation.sync(state, activeMembers)
+
+	return state.RotationCursorDate, nil
+}
+
+// cleanupExistingEntries removes non-override entries from the future period. Entries published
+// before publishedUntil are kept, unless their member is no longer active or can no longer be on
+// duty on that weekday. The entries are deleted in one batch and removed from the snapshot.
+func (s *scheduleService) cleanupExistingEntries(ctx context.Context, state *models.ScheduleState, snapshot *scheduleSnapshot, publishedUntil time.Time, activeMembers []models.TeamMember) error {
 	today := timeNow()
 	// Always start cleanup from tomorrow to never delete today's entry
 	startDate := today.AddDate(0, 0, 1)
-	futureEnd := today.AddDate(0, 3, 0) // 3 months ahead
+	// Entries past the horizon remain from a generation with a longer horizon
+	existingEntries := snapshot.between(startDate, scheduleEnd(state, today))
 
-	existingEntries, err := s.scheduleRepo.GetByDateRange(ctx, startDate, futureEnd)
-	if err != nil {
-		return fmt.Errorf("failed to get existing entries: %w", err)
-	}
+	keepUntil := models.FormatDate(publishedUntil)
 
 	// Delete only non-override entries to preserve manual changes
+	var ids []int
+	deleted := make(map[int]bool)
 	for _, entry := range existingEntries {
-		if !entry.IsManualOverride {
-			if err := s.scheduleRepo.Delete(ctx, entry.ID); err != nil {
-				return fmt.Errorf("failed to delete existing entry: %w", err)
-			}
+		if entry.IsManualOverride {
+			continue
 		}
+		if index := memberIndex(activeMembers, entry.TeamMemberID); entry.GetFormattedDate() < keepUntil && index >= 0 && activeMembers[index].WorksOn(entry.Date) {
+			continue
+		}
+		ids = append(ids, entry.ID)
+		deleted[entry.ID] = true
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	if err := s.scheduleRepo.DeleteBatch(ctx, ids); err != nil {
+		return fmt.Errorf("failed to delete existing entries: %w", err)
 	}
+	snapshot.remove(deleted)
 
 	return nil
 }
 
-// generateScheduleEntries creates new schedule entries using deterministic assignment
-func (s *scheduleService) generateScheduleEntries(ctx context.Context, activeMembers []models.TeamMember, activeDays []models.WorkingHours) (int, error) {
-	workingDates, err := s.collectWorkingDates(ctx, activeDays)
+// generateScheduleEntries creates new schedule entries using the configured rotation strategy. Hard
+// weekday preferences are always satisfied, soft ones as far as possible without giving anyone
+// more or fewer turns, and the assignment rules unless nobody else can take the shift. It returns
+// the number of entries created and the constraints it couldn't meet.
+func (s *scheduleService) generateScheduleEntries(
+	ctx context.Context,
+	state *models.ScheduleState,
+	snapshot *scheduleSnapshot,
+	strategy RotationStrategy,
+	startDate time.Time,
+	publishedUntil time.Time,
+	activeMembers []models.TeamMember,
+	activeDays []models.WorkingHours,
+) (int, []models.UnsatisfiedConstraint, error) {
+	holidays, err := s.getHolidayCalendar(ctx)
 	if err != nil {
-		return 0, err
+		return 0, nil, err
+	}
+
+	// Generation stops at the start of the turn running at the horizon, so a turn is never split
+	// between two generations
+	period := newRotationPeriod(state)
+	horizon := state.HorizonEnd(timeNow())
+	endDate := period.turnStart(horizon, startDate, activeDays, holidays)
+
+	workingDates := s.collectWorkingDates(snapshot, startDate, endDate, activeDays, holidays)
+	// Members are never scheduled on a weekday they don't work, so nobody can cover such dates
+	workingDates, unstaffed := staffedDates(workingDates, activeMembers)
+
+	input := RotationInput{
+		Start:       startDate,
+		End:         truncateToDate(endDate),
+		Dates:       workingDates,
+		Members:     activeMembers,
+		WorkingDays: activeDays,
+		Holidays:    holidays,
+		Period:      period,
+	}
+
+	_, isQueued := strategy.(queuedStrategy)
+	if isQueued {
+		// Dates before the cursor date are slots vacated by former members, they take the next turns
+		input.Start = publishedUntil
+		input.Members = orderByQueue(activeMembers, state.RotationQueue)
+		input.Cursor = state.RotationCursor
+	}
+
+	// Only load the assignment history when the strategy needs it
+	timeOffFrom := input.Start
+	if historyFrom, ok := strategy.HistoryFrom(input); ok {
+		input.History, err = s.scheduleRepo.GetByDateRange(ctx, historyFrom, horizon)
+		if err != nil {
+			return 0, nil, fmt.Errorf("failed to get assignment history: %w", err)
+		}
+		if historyFrom.Before(timeOffFrom) {
+			timeOffFrom = historyFrom
+		}
 	}
 
-	entriesCreated := 0
-	for _, workingDate := range workingDates {
-		entry := s.createScheduleEntry(workingDate, activeMembers, activeDays)
+	// Backups are also given to the shifts kept from earlier generations
+	if state.BackupEnabled && startDate.Before(timeOffFrom) {
+		timeOffFrom = startDate
+	}
 
-		if err := s.scheduleRepo.Create(ctx, entry); err != nil {
-			return 0, fmt.Errorf("failed to create schedule entry: %w", err)
-		}
+	// Members on time off are skipped in favour of the next available member. On-call blocks
+	// starting in the period can run up to a week past it.
+	timeOffUntil := input.End
+	if state.OnCallEnabled {
+		timeOffUntil = input.End.AddDate(0, 0, maxOnCallBlockDays)
+	}
+	input.TimeOff, err = s.timeOffRepo.GetByDateRange(ctx, timeOffFrom, timeOffUntil)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get time off: %w", err)
+	}
 
-		entriesCreated++
+	assignments := s.assignShifts(state, strategy, input, activeDays)
+	assignments, broken, err := s.applyAssignmentRules(ctx, state, input, assignments)
+	if err != nil {
+		return 0, nil, err
+	}
+	unsatisfied := append(unsatisfiedConstraints(activeMembers, unstaffed, assignments), broken...)
+
+	var created []models.ScheduleEntry
+	for _, assignment := range assignments {
+		created = append(created, models.ScheduleEntry{
+			Date:             assignment.WorkingDate.Date,
+			TeamMemberID:     assignment.TeamMemberID,
+			StartTime:        assignment.WorkingDate.WorkingHours.StartTime,
+			EndTime:          assignment.WorkingDate.WorkingHours.EndTime,
+			Shift:            assignment.WorkingDate.WorkingHours.Name,
+			IsManualOverride: false,
+		})
 	}
+	snapshot.add(created...)
 
-	return entriesCreated, nil
-}
+	if state.OnCallEnabled {
+		onCall := s.generateOnCall(state, snapshot, strategy, input, startDate, activeDays)
+		snapshot.add(onCall...)
+		created = append(created, onCall...)
+	}
 
-// WorkingDate represents a date with its associated working hours
-type WorkingDate struct {
-	Date         time.Time
-	WorkingHours models.WorkingHours
+	if state.BackupEnabled {
+		created = append(created, s.assignBackups(snapshot, input, startDate)...)
+	}
+
+	// All new entries are stored at once
+	entries := make([]*models.ScheduleEntry, len(created))
+	for i := range created {
+		entries[i] = &created[i]
+	}
+	if err := s.scheduleRepo.CreateBatch(ctx, entries); err != nil {
+		return 0, nil, fmt.Errorf("failed to create schedule entries: %w", err)
+	}
+	entriesCreated := len(created)
+
+	// Everything up to the end of the period is published now, later roster changes start after it
+	if isQueued {
+		state.RotationCursorDate = input.End
+	}
+	state.GeneratedUntil = input.End
+
+	return entriesCreated, unsatisfied, nil
 }
 
-// collectWorkingDates finds all working dates in the generation period that don't have overrides
-func (s *scheduleService) collectWorkingDates(ctx context.Context, activeDays []models.WorkingHours) ([]WorkingDate, error) {
-	today := timeNow()
+// assignShifts runs the strategy for every shift on its own and returns the assignments in
+// chronological order. Each shift starts its rotation a member further than the previous one, so
+// the shifts of a day go to different members. Queued strategies keep a cursor per shift instead;
+// a shift without one starts from the main cursor, offset the same way. Afterwards members swap
+// dates to honour soft weekday preferences.
+func (s *scheduleService) assignShifts(state *models.ScheduleState, strategy RotationStrategy, input RotationInput, activeDays []models.WorkingHours) []Assignment {
+	queued, isQueued := strategy.(queuedStrategy)
+	cursors := make(map[string]int)
+
+	var assignments []Assignment
+	for index, shift := range shiftNames(activeDays) {
+		shiftInput := input.forShift(shift)
+		if isQueued {
+			cursor, ok := state.ShiftCursor(shift)
+			if !ok {
+				cursor = state.RotationCursor + index
+			}
+			shiftInput.Cursor = cursor % len(shiftInput.Members)
+		} else {
+			shiftInput.Members = staggered(input.Members, index)
+		}
 
-	// Check if today has any schedule entries
-	todayEntries, err := s.scheduleRepo.GetByDate(ctx, today)
-	if err != nil {
-		return nil, fmt.Errorf("failed to check today's entries: %w", err)
+		shiftAssignments := strategy.Assign(shiftInput)
+		if isQueued {
+			cursors[shift] = queued.NextCursor(shiftInput, shiftAssignments)
+		}
+		assignments = append(assignments, shiftAssignments...)
 	}
 
-	// Start from tomorrow if today has entries, otherwise from today
-	startDate := today.AddDate(0, 0, 1)
-	if len(todayEntries) == 0 {
-		startDate = today
+	// Shifts that no longer exist drop their cursor
+	if isQueued {
+		state.ShiftCursors = nil
+		for shift, cursor := range cursors {
+			state.SetShiftCursor(shift, cursor)
+		}
 	}
 
-	futureEnd := today.AddDate(0, 3, 0) // 3 months ahead
-	var workingDates []WorkingDate
+	sort.SliceStable(assignments, func(i, j int) bool {
+		a, b := assignments[i].WorkingDate, assignments[j].WorkingDate
+		if !a.Date.Equal(b.Date) {
+			return a.Date.Before(b.Date)
+		}
+		return a.WorkingHours.StartTime < b.WorkingHours.StartTime
+	})
 
-	for date := startDate; date.Before(futureEnd); date = date.AddDate(0, 0, 1) {
-		weekday := models.GetWeekdayNumber(date)
+	return honourPreferences(input, assignments)
+}
 
-		// Find working hours for this day of week
-		workingHours := s.findWorkingHoursForDay(activeDays, weekday)
-		if workingHours == nil {
-			continue // Skip non-working days
+// applyAssignmentRules hands the assignments that break the assignment rules to other members. The
+// entries around the generation period that are already in the schedule count towards the rules.
+// It returns the assignments and the rules that couldn't be kept.
+func (s *scheduleService) applyAssignmentRules(ctx context.Context, state *models.ScheduleState, input RotationInput, assignments []Assignment) ([]Assignment, []models.UnsatisfiedConstraint, error) {
+	rules := newAssignmentRules(state, input.WorkingDays, input.Holidays)
+	if rules.isEmpty() || len(assignments) == 0 {
+		return assignments, nil, nil
+	}
+
+	from, to := rules.window(assignments[0].WorkingDate.Date, assignments[len(assignments)-1].WorkingDate.Date)
+	existing, err := s.scheduleRepo.GetByDateRange(ctx, from, to)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get entries for the assignment rules: %w", err)
+	}
+
+	enforced, unsatisfied := enforceAssignmentRules(input, rules, existing, assignments)
+	return enforced, unsatisfied, nil
+}
+
+// assignBackups gives every shift from the start date to the end of the generation period that has
+// a primary but no backup yet a backup: the member next in line after the primary. This includes
+// shifts kept from earlier generations and manual overrides. It returns the backup entries.
+func (s *scheduleService) assignBackups(snapshot *scheduleSnapshot, input RotationInput, startDate time.Time) []models.ScheduleEntry {
+	entries := snapshot.between(startDate, input.End.AddDate(0, 0, -1))
+
+	hasBackup := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsBackup() {
+			hasBackup[slotKey(entry)] = true
 		}
+	}
 
-		// Skip dates that already have manual overrides
-		if hasOverride, err := s.hasManualOverride(ctx, date); err != nil {
-			return nil, fmt.Errorf("failed to check existing entries for date: %w", err)
-		} else if hasOverride {
+	var backups []models.ScheduleEntry
+	for _, primary := range entries {
+		if primary.IsBackup() || hasBackup[slotKey(primary)] {
 			continue
 		}
 
-		workingDates = append(workingDates, WorkingDate{
-			Date:         date,
-			WorkingHours: *workingHours,
+		backupInput := input
+		if primary.EndDate != nil {
+			backupInput = input.forBlock(primary.Date, *primary.EndDate)
+		}
+		backupID, ok := backupInput.backupFor(primary.TeamMemberID, primary.Date)
+		if !ok {
+			continue // Nobody to back the primary up
+		}
+
+		backups = append(backups, models.ScheduleEntry{
+			Date:             primary.Date,
+			TeamMemberID:     backupID,
+			StartTime:        primary.StartTime,
+			EndTime:          primary.EndTime,
+			Shift:            primary.Shift,
+			Role:             models.ScheduleRoleBackup,
+			Layer:            primary.Layer,
+			EndDate:          primary.EndDate,
+			IsManualOverride: false,
 		})
+		hasBackup[slotKey(primary)] = true
 	}
+	snapshot.add(backups...)
 
-	return workingDates, nil
+	return backups
 }
 
-// findWorkingHoursForDay finds the working hours configuration for a specific weekday
-func (s *scheduleService) findWorkingHoursForDay(activeDays []models.WorkingHours, weekday int) *models.WorkingHours {
-	for _, wh := range activeDays {
-		if wh.DayOfWeek == weekday {
-			return &wh
-		}
+// slotKey identifies the shift or on-call block of a date an entry belongs to
+func slotKey(entry models.ScheduleEntry) string {
+	if entry.IsOnCall() {
+		return entry.GetFormattedDate() + "|" + models.ScheduleLayerOnCall + "|" + entry.StartTime
 	}
-	return nil
+	return entry.GetFormattedDate() + "|" + entry.Shift
+}
+
+// WorkingDate represents a date with the working hours of one of its shifts, or an on-call block
+// starting on the date
+type WorkingDate struct {
+	Date         time.Time
+	WorkingHours models.WorkingHours
+	EndDate      time.Time // Last day of an on-call block, zero for working hours shifts
 }
 
-// hasManualOverride checks if a date already has a manual override entry
-func (s *scheduleService) hasManualOverride(ctx context.Context, date time.Time) (bool, error) {
-	existingForDay, err := s.scheduleRepo.GetByDate(ctx, date)
+// generationStartDate returns the first date that generation may (re)assign:
+// tomorrow if today already has entries, otherwise today
+func generationStartDate(ctx context.Context, scheduleRepo repositories.ScheduleRepository) (time.Time, error) {
+	// Check if today has any schedule entries
+	todayEntries, err := scheduleRepo.GetByDate(ctx, timeNow())
 	if err != nil {
-		return false, err
+		return time.Time{}, fmt.Errorf("failed to check today's entries: %w", err)
 	}
+	return startDateAfter(todayEntries), nil
+}
 
-	for _, entry := range existingForDay {
-		if entry.IsManualOverride {
-			return true, nil
-		}
+// startDateAfter returns tomorrow if today has entries, otherwise today
+func startDateAfter(todayEntries []models.ScheduleEntry) time.Time {
+	today := timeNow()
+	if len(todayEntries) > 0 {
+		return today.AddDate(0, 0, 1)
 	}
-
-	return false, nil
+	return today
 }
 
-// createScheduleEntry creates a schedule entry with deterministic team member assignment based on working day sequence
-func (s *scheduleService) createScheduleEntry(workingDate WorkingDate, activeMembers []models.TeamMember, activeDays []models.WorkingHours) *models.ScheduleEntry {
-	// Calculate deterministic assignment based on working days since epoch for this specific date
-	// This maintains determinism (same date always gets same assignment) while avoiding consecutive assignments
-	workingDaysSinceEpoch := s.calculateWorkingDaysSinceEpoch(workingDate.Date, activeDays)
-	memberIndex := workingDaysSinceEpoch % len(activeMembers)
-
-	return &models.ScheduleEntry{
-		Date:             workingDate.Date,
-		TeamMemberID:     activeMembers[memberIndex].ID,
-		StartTime:        workingDate.WorkingHours.StartTime,
-		EndTime:          workingDate.WorkingHours.EndTime,
-		IsManualOverride: false,
+// getHolidayCalendar loads all holidays and the working hours exceptions of the team into a calendar
+func (s *scheduleService) getHolidayCalendar(ctx context.Context) (*models.HolidayCalendar, error) {
+	holidays, err := s.holidayRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get holidays: %w", err)
+	}
+	exceptions, err := s.workingHoursRepo.GetExceptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working hours exceptions: %w", err)
 	}
+	return models.NewHolidayCalendar(holidays).WithExceptions(exceptions), nil
 }
 
-// calculateWorkingDaysSinceEpoch calculates how many working days have passed since a fixed epoch
-// using the actual configured working days. This ensures deterministic assignments
-// while preventing consecutive assignments due to non-working days.
-func (s *scheduleService) calculateWorkingDaysSinceEpoch(date time.Time, activeDays []models.WorkingHours) int {
-	// Use a fixed epoch date that's a Monday to make calculation easier
-	epoch := time.Date(2000, 1, 3, 0, 0, 0, 0, time.UTC) // Monday, January 3, 2000
+// collectWorkingDates finds the shifts of all working dates in the generation period that aren't
+// taken yet. Working hours exceptions and holidays change the shifts of their date: duty-free
+// holidays are skipped, holidays with alternative hours use those instead.
+func (s *scheduleService) collectWorkingDates(snapshot *scheduleSnapshot, startDate, endDate time.Time, activeDays []models.WorkingHours, holidays *models.HolidayCalendar) []WorkingDate {
+	var workingDates []WorkingDate
 
-	if date.Before(epoch) {
-		return 0
-	}
+	for date := startDate; date.Before(endDate); date = date.AddDate(0, 0, 1) {
+		// Find the shifts of the working hours in effect on this date
+		shifts := holidays.ShiftsOn(activeDays, date)
+		if len(shifts) == 0 {
+			continue // Skip non-working days
+		}
 
-	// Create a map of active days for fast lookup
-	// Convert from our DayOfWeek format (0=Monday) to Go's time.Weekday format (1=Monday, 0=Sunday)
-	activeWeekdays := make(map[time.Weekday]bool)
-	for _, workingHours := range activeDays {
-		if workingHours.Active {
-			// Convert from our format (0=Monday, 1=Tuesday, ..., 6=Sunday)
-			// to Go's format (0=Sunday, 1=Monday, ..., 6=Saturday)
-			goWeekday := time.Weekday((workingHours.DayOfWeek + 1) % 7)
-			activeWeekdays[goWeekday] = true
+		// Skip shifts that kept their entry during cleanup
+		taken := takenShifts(snapshot.onDate(date))
+
+		for _, workingHours := range shifts {
+			if taken[workingHours.Name] {
+				continue
+			}
+
+			workingDates = append(workingDates, WorkingDate{
+				Date:         date,
+				WorkingHours: workingHours,
+			})
 		}
 	}
 
-	// Count working days by iterating through each day since epoch
-	workingDays := 0
-	for d := epoch; d.Before(date); d = d.AddDate(0, 0, 1) {
-		if activeWeekdays[d.Weekday()] {
-			workingDays++
+	return workingDates
+}
+
+// takenShifts returns the shifts of a date whose primary still has an entry after cleanup: a
+// manual override, or an entry published by a queued strategy
+func takenShifts(existingForDay []models.ScheduleEntry) map[string]bool {
+	taken := make(map[string]bool)
+	for _, entry := range existingForDay {
+		if !entry.IsBackup() && !entry.IsOnCall() {
+			taken[entry.Shift] = true
 		}
 	}
-
-	return workingDays
+	return taken
 }
 
-// finalizeGeneration updates the state and creates the final result
-func (s *scheduleService) finalizeGeneration(ctx context.Context, state *models.ScheduleState, entriesCreated int) (*models.GenerationResult, error) {
+// finalizeGeneration updates the state and creates the final result. An extension doesn't count as
+// a full generation for the up-to-date interval.
+func (s *scheduleService) finalizeGeneration(ctx context.Context, state *models.ScheduleState, extend bool, entriesCreated int, unsatisfied []models.UnsatisfiedConstraint) (*models.GenerationResult, error) {
 	// Update state
-	state.LastGenerationDate = timeNow()
+	if !extend {
+		state.LastGenerationDate = timeNow()
+	}
 	if err := s.scheduleRepo.UpdateState(ctx, state); err != nil {
 		return nil, fmt.Errorf("failed to update schedule state: %w", err)
 	}
 
+	message := fmt.Sprintf("Successfully generated schedule with %d entries", entriesCreated)
+	if extend {
+		message = fmt.Sprintf("Extended the schedule with %d entries", entriesCreated)
+	}
+	if len(unsatisfied) > 0 {
+		message += fmt.Sprintf(", %d constraint(s) could not be satisfied", len(unsatisfied))
+	}
+
 	return &models.GenerationResult{
 		Success:           true,
-		Message:           fmt.Sprintf("Successfully generated schedule with %d entries", entriesCreated),
+		Message:           message,
+		Extended:          extend,
 		EntriesCreated:    entriesCreated,
 		GenerationDate:    state.LastGenerationDate,
-		NextGenerationDue: state.LastGenerationDate.AddDate(0, 0, 7),
+		NextGenerationDue: state.NextGenerationDue(),
+		Unsatisfied:       unsatisfied,
 	}, nil
 }
 
 // Helper functions for shared logic between CreateManualOverride and UpdateScheduleEntry
 
 // validateFormAndTeamMember validates the form and checks if the team member exists
-func (s *scheduleService) validateFormAndTeamMember(ctx context.Context, form *models.ScheduleEntryForm) error {
+func (s *scheduleService) validateFormAndTeamMember(ctx context.Context, form *models.ScheduleEntryForm) (*models.TeamMember, error) {
 	// Validate form
 	if errors := form.Validate(); len(errors) > 0 {
-		return fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
 	}
 
 	// Validate team member exists
-	_, err := s.teamRepo.GetByID(ctx, form.TeamMemberID)
+	member, err := s.teamRepo.GetByID(ctx, form.TeamMemberID)
 	if err != nil {
-		return fmt.Errorf("team member not found: %w", err)
+		return nil, fmt.Errorf("team member not found: %w", err)
 	}
 
-	return nil
+	return member, nil
 }
 
 // parseDateFromForm parses and validates the date from the form
@@ -443,9 +853,83 @@ func (s *scheduleService) getExistingEntryWithValidation(ctx context.Context, id
 	return entry, nil
 }
 
+// checkRoleConflict makes sure a member doesn't become both the primary and the backup of a shift
+func (s *scheduleService) checkRoleConflict(ctx context.Context, entry *models.ScheduleEntry, date time.Time, memberID int) error {
+	entries, err := s.scheduleRepo.GetByDate(ctx, date)
+	if err != nil {
+		return fmt.Errorf("failed to check existing entries: %w", err)
+	}
+
+	for _, other := range entries {
+		if other.ID == entry.ID || other.Shift != entry.Shift || other.GetLayer() != entry.GetLayer() || other.GetRole() == entry.GetRole() {
+			continue
+		}
+		if other.TeamMemberID == memberID {
+			return fmt.Errorf("%s is already the %s of this shift", other.TeamMemberName, strings.ToLower(other.GetRoleName()))
+		}
+	}
+
+	return nil
+}
+
+// checkAssignmentRules makes sure putting a member on duty in the entry's shift on the date keeps
+// the assignment rules. If it doesn't, a RuleViolationError asks for confirmation; confirmed edits
+// go through. Backups and on-call blocks aren't bound by the rules, and edits that keep the member
+// and the date don't change what the rules count.
+func (s *scheduleService) checkAssignmentRules(ctx context.Context, entry *models.ScheduleEntry, date time.Time, member *models.TeamMember, confirmed bool) error {
+	if confirmed || entry.IsBackup() || entry.IsOnCall() {
+		return nil
+	}
+	if entry.TeamMemberID == member.ID && entry.GetFormattedDate() == models.FormatDate(date) {
+		return nil
+	}
+
+	state, err := s.scheduleRepo.GetState(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get schedule state: %w", err)
+	}
+	if !state.HasAssignmentRules() {
+		return nil
+	}
+
+	activeDays, err := s.workingHoursRepo.GetActiveDays(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get active working days: %w", err)
+	}
+	holidays, err := s.getHolidayCalendar(ctx)
+	if err != nil {
+		return err
+	}
+	rules := newAssignmentRules(state, activeDays, holidays)
+
+	from, to := rules.window(date, date)
+	entries, err := s.scheduleRepo.GetByDateRange(ctx, from, to)
+	if err != nil {
+		return fmt.Errorf("failed to get entries for the assignment rules: %w", err)
+	}
+
+	// The entry being changed no longer counts
+	var others []models.ScheduleEntry
+	for _, other := range entries {
+		if other.ID != entry.ID {
+			others = append(others, other)
+		}
+	}
+
+	violations := rules.violations(newDutyDates(others), member.ID, date)
+	if len(violations) == 0 {
+		return nil
+	}
+	for i, violation := range violations {
+		violations[i] = member.Name + " " + violation
+	}
+	return &RuleViolationError{Violations: violations}
+}
+
 // CreateManualOverride creates a manual schedule override
 func (s *scheduleService) CreateManualOverride(ctx context.Context, entryID int, form *models.ScheduleEntryForm) (*models.ScheduleEntry, error) {
-	if err := s.validateFormAndTeamMember(ctx, form); err != nil {
+	member, err := s.validateFormAndTeamMember(ctx, form)
+	if err != nil {
 		return nil, err
 	}
 
@@ -459,40 +943,73 @@ func (s *scheduleService) CreateManualOverride(ctx context.Context, entryID int,
 		return nil, fmt.Errorf("failed to check existing entries: %w", err)
 	}
 
+	if err := s.checkRoleConflict(ctx, existingEntry, date, form.TeamMemberID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAssignmentRules(ctx, existingEntry, date, member, form.Confirmed); err != nil {
+		return nil, err
+	}
+
 	// Find the original entry (non-override) to store its team member ID
 	var originalTeamMemberID *int
 	if !existingEntry.IsManualOverride {
 		originalTeamMemberID = &existingEntry.TeamMemberID
 	}
 
-	// Delete existing non-override entries for this date
-	if !existingEntry.IsManualOverride {
-		if err := s.scheduleRepo.Delete(ctx, existingEntry.ID); err != nil {
-			return nil, fmt.Errorf("failed to delete existing entry: %w", err)
-		}
-	}
-
 	// Create manual override entry
 	entry := &models.ScheduleEntry{
 		Date:                 date,
 		TeamMemberID:         form.TeamMemberID,
 		StartTime:            strings.TrimSpace(form.StartTime),
 		EndTime:              strings.TrimSpace(form.EndTime),
+		Shift:                existingEntry.Shift,
+		Role:                 existingEntry.Role,
+		Layer:                existingEntry.Layer,
+		EndDate:              existingEntry.EndDate,
 		IsManualOverride:     true,
 		OriginalTeamMemberID: originalTeamMemberID,
 	}
 
-	if err := s.scheduleRepo.Create(ctx, entry); err != nil {
-		return nil, fmt.Errorf("failed to create manual override: %w", err)
+	// The override replaces a generated entry, both happen or neither does
+	err = s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if !existingEntry.IsManualOverride {
+			if err := s.scheduleRepo.Delete(ctx, existingEntry.ID); err != nil {
+				return fmt.Errorf("failed to delete existing entry: %w", err)
+			}
+		}
+
+		if err := s.scheduleRepo.Create(ctx, entry); err != nil {
+			return fmt.Errorf("failed to create manual override: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Get the created entry with team member info
-	return s.scheduleRepo.GetByID(ctx, entry.ID)
+	created, err := s.scheduleRepo.GetByID(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyTakeover(ctx, created, existingEntry.TeamMemberName)
+
+	override := &webhookOverride{Override: created}
+	if !existingEntry.IsManualOverride {
+		s.webhooks.Publish(ctx, models.WebhookEventEntryDeleted, existingEntry)
+		override.Replaced = existingEntry
+	}
+	s.webhooks.Publish(ctx, models.WebhookEventEntryCreated, created)
+	s.webhooks.Publish(ctx, models.WebhookEventOverrideCreated, override)
+	return created, nil
 }
 
 // UpdateScheduleEntry updates an existing schedule entry
 func (s *scheduleService) UpdateScheduleEntry(ctx context.Context, id int, form *models.ScheduleEntryForm) (*models.ScheduleEntry, error) {
-	if err := s.validateFormAndTeamMember(ctx, form); err != nil {
+	member, err := s.validateFormAndTeamMember(ctx, form)
+	if err != nil {
 		return nil, err
 	}
 
@@ -506,6 +1023,14 @@ func (s *scheduleService) UpdateScheduleEntry(ctx context.Context, id int, form
 		return nil, err
 	}
 
+	if err := s.checkRoleConflict(ctx, entry, date, form.TeamMemberID); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkAssignmentRules(ctx, entry, date, member, form.Confirmed); err != nil {
+		return nil, err
+	}
+
 	isManualOverride := entry.TeamMemberID != form.TeamMemberID
 
 	// Update entry fields
@@ -524,7 +1049,13 @@ func (s *scheduleService) UpdateScheduleEntry(ctx context.Context, id int, form
 	}
 
 	// Get the updated entry with team member info
-	return s.scheduleRepo.GetByID(ctx, entry.ID)
+	updated, err := s.scheduleRepo.GetByID(ctx, entry.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	s.webhooks.Publish(ctx, models.WebhookEventEntryUpdated, updated)
+	return updated, nil
 }
 
 // RemoveManualOverride removes a manual override and restores the original assignment
@@ -547,38 +1078,87 @@ func (s *scheduleService) RemoveManualOverride(ctx context.Context, id int) erro
 		return fmt.Errorf("missing original team member ID")
 	}
 
-	// Delete the override
-	if err := s.scheduleRepo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete manual override: %w", err)
+	restoredEntry, err := s.restoredEntry(ctx, entry)
+	if err != nil {
+		return err
 	}
 
-	// Get working hours for this date to determine start/end times
-	dayOfWeek := int(entry.Date.Weekday())
-	if dayOfWeek == 0 { // Sunday is 0 in Go, but we use 6
-		dayOfWeek = 6
-	} else {
-		dayOfWeek-- // Convert to our 0=Monday system
+	// The original assignment replaces the override, both happen or neither does
+	err = s.transactor.WithTx(ctx, func(ctx context.Context) error {
+		if err := s.scheduleRepo.Delete(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete manual override: %w", err)
+		}
+
+		if err := s.scheduleRepo.Create(ctx, restoredEntry); err != nil {
+			return fmt.Errorf("failed to restore original assignment: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.notifyOverrideRemoved(ctx, entry)
+
+	s.webhooks.Publish(ctx, models.WebhookEventEntryDeleted, entry)
+	s.webhooks.Publish(ctx, models.WebhookEventEntryCreated, restoredEntry)
+	s.webhooks.Publish(ctx, models.WebhookEventOverrideRemoved, &webhookOverride{Override: entry, Restored: restoredEntry})
+	return nil
+}
+
+// restoredEntry returns the generated entry a manual override replaced
+func (s *scheduleService) restoredEntry(ctx context.Context, entry *models.ScheduleEntry) (*models.ScheduleEntry, error) {
+	// On-call blocks lie outside the working hours, the block keeps its times
+	if entry.IsOnCall() {
+		return &models.ScheduleEntry{
+			Date:             entry.Date,
+			TeamMemberID:     *entry.OriginalTeamMemberID,
+			StartTime:        entry.StartTime,
+			EndTime:          entry.EndTime,
+			Role:             entry.Role,
+			Layer:            entry.Layer,
+			EndDate:          entry.EndDate,
+			IsManualOverride: false,
+		}, nil
 	}
 
-	workingHours, err := s.workingHoursRepo.GetByDay(ctx, dayOfWeek)
+	// Get the working hours in effect on this date to determine start/end times
+	weekly, err := s.workingHoursRepo.GetByDay(ctx, models.GetWeekdayNumber(entry.Date), entry.Date)
 	if err != nil {
-		return fmt.Errorf("failed to get working hours: %w", err)
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
 	}
 
-	// Create the restored entry
-	restoredEntry := &models.ScheduleEntry{
+	// The exception or holiday of the date changes its hours
+	holidays, err := s.getHolidayCalendar(ctx)
+	if err != nil {
+		return nil, err
+	}
+	shifts := holidays.ShiftsOn(weekly, entry.Date)
+	if len(shifts) == 0 {
+		// The date is no longer a working day, the original assignment keeps the override's hours
+		shifts = []models.WorkingHours{{Name: entry.Shift, StartTime: entry.StartTime, EndTime: entry.EndTime}}
+	}
+	workingHours := findShift(shifts, entry.Shift)
+
+	return &models.ScheduleEntry{
 		Date:             entry.Date,
 		TeamMemberID:     *entry.OriginalTeamMemberID,
 		StartTime:        workingHours.StartTime,
 		EndTime:          workingHours.EndTime,
+		Shift:            entry.Shift,
+		Role:             entry.Role,
 		IsManualOverride: false,
-	}
+	}, nil
+}
 
-	if err := s.scheduleRepo.Create(ctx, restoredEntry); err != nil {
-		return fmt.Errorf("failed to restore original assignment: %w", err)
+// findShift returns the shift with the given name, or the first shift of the day if it no longer exists
+func findShift(shifts []models.WorkingHours, name string) *models.WorkingHours {
+	for i := range shifts {
+		if shifts[i].Name == name {
+			return &shifts[i]
+		}
 	}
-
-	return nil
+	return &shifts[0]
 }
 
 // GetScheduleEntry retrieves a schedule entry by ID
@@ -589,6 +1169,42 @@ func (s *scheduleService) GetScheduleEntry(ctx context.Context, id int) (*models
 	return s.scheduleRepo.GetByID(ctx, id)
 }
 
+// GetSettings retrieves the schedule generation settings
+func (s *scheduleService) GetSettings(ctx context.Context) (*models.ScheduleState, error) {
+	return s.scheduleRepo.GetState(ctx)
+}
+
+// UpdateSettings updates the schedule generation settings
+func (s *scheduleService) UpdateSettings(ctx context.Context, form *models.ScheduleSettingsForm) (*models.ScheduleState, error) {
+	if errors := form.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+	}
+
+	state, err := s.scheduleRepo.GetState(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule state: %w", err)
+	}
+
+	state.RotationStrategy = form.RotationStrategy
+	state.RotationSeed = form.GetRotationSeed()
+	state.RotationPeriod = form.RotationPeriod
+	state.RotationPeriodDays = form.GetRotationPeriodDays()
+	state.HandoverDay = form.GetHandoverDay()
+	state.BackupEnabled = form.BackupEnabled
+	state.OnCallEnabled = form.OnCallEnabled
+	state.MinGapDays = form.GetMinGapDays()
+	state.MaxShiftsPerWeek = form.GetMaxShiftsPerWeek()
+	state.MaxShiftsPerMonth = form.GetMaxShiftsPerMonth()
+	state.HorizonLength = form.GetHorizonLength()
+	state.HorizonUnit = form.GetHorizonUnit()
+	state.UpToDateDays = form.GetUpToDateDays()
+	if err := s.scheduleRepo.UpdateState(ctx, state); err != nil {
+		return nil, fmt.Errorf("failed to update schedule settings: %w", err)
+	}
+
+	return state, nil
+}
+
 // validateScheduleGeneration checks if schedule generation is possible
 func (s *scheduleService) validateScheduleGeneration(ctx context.Context) error {
 	// Check if there are active team members
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"sort"
 	"testing"
 	"time"
@@ -22,6 +23,11 @@ type GenerateScheduleTestSuite struct {
 	mockScheduleRepo *dbMocks.MockScheduleRepository
 	mockTeamRepo     *dbMocks.MockTeamRepository
 	mockWorkingRepo  *dbMocks.MockWorkingHoursRepository
+	mockTimeOffRepo  *dbMocks.MockTimeOffRepository
+	mockHolidayRepo  *dbMocks.MockHolidayRepository
+	mockTransactor   *dbMocks.MockTransactor
+	notifier         *notificationRecorder
+	webhooks         *webhookRecorder
 }
 
 // SetupTest sets up the test suite before each test
@@ -29,14 +35,46 @@ func (suite *GenerateScheduleTestSuite) SetupTest() {
 	suite.mockScheduleRepo = dbMocks.NewMockScheduleRepository(suite.T())
 	suite.mockTeamRepo = dbMocks.NewMockTeamRepository(suite.T())
 	suite.mockWorkingRepo = dbMocks.NewMockWorkingHoursRepository(suite.T())
+	suite.mockTimeOffRepo = dbMocks.NewMockTimeOffRepository(suite.T())
+	suite.mockHolidayRepo = dbMocks.NewMockHolidayRepository(suite.T())
+	suite.mockTransactor = passThroughTx(suite.T())
+	suite.notifier = &notificationRecorder{}
+	suite.webhooks = &webhookRecorder{}
 
 	suite.service = NewScheduleService(
 		suite.mockScheduleRepo,
 		suite.mockTeamRepo,
 		suite.mockWorkingRepo,
+		suite.mockTimeOffRepo,
+		suite.mockHolidayRepo,
+		suite.mockTransactor,
+		suite.notifier,
+		suite.webhooks,
 	)
 }
 
+// passThroughTx returns a transactor that runs the work with the context it gets
+func passThroughTx(t *testing.T) *dbMocks.MockTransactor {
+	transactor := dbMocks.NewMockTransactor(t)
+	transactor.EXPECT().WithTx(mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(ctx)
+		},
+	).Maybe()
+	return transactor
+}
+
+// expectHolidays sets up the holidays the generator finds
+func (suite *GenerateScheduleTestSuite) expectHolidays(ctx context.Context, holidays ...models.Holiday) {
+	suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return(holidays, nil)
+	suite.mockWorkingRepo.EXPECT().GetExceptions(ctx).Return(nil, nil)
+}
+
+// expectTimeOff sets up the time off the generator finds for the generation period
+func (suite *GenerateScheduleTestSuite) expectTimeOff(ctx context.Context, timeOff ...models.TimeOff) {
+	suite.mockTimeOffRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return(timeOff, nil)
+}
+
 // TestGenerateSchedule_ValidationFailure_NoActiveMembers tests validation failure when no active team members exist
 func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_ValidationFailure_NoActiveMembers() {
 	// Setup: No active team members
@@ -94,7 +132,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_UpToDate_RecentGene
 	// Setup: Recent generation (3 days ago, less than 7)
 	recentDate := time.Now().AddDate(0, 0, -3)
 	scheduleState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: recentDate,
 	}
 
@@ -130,7 +168,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_ForceGeneration() {
 	// Setup: Recent generation but force=true
 	recentDate := time.Now().AddDate(0, 0, -3)
 	scheduleState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: recentDate,
 	}
 
@@ -151,30 +189,30 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_ForceGeneration() {
 
 	// Mock getting existing entries and deleting non-overrides
 	today := time.Now()
-	tomorrow := today.AddDate(0, 0, 1)
 	futureEnd := today.AddDate(0, 3, 0)
 	existingEntries := []models.ScheduleEntry{
 		{ID: 1, Date: today.AddDate(0, 0, 1), IsManualOverride: false},
 		{ID: 2, Date: today.AddDate(0, 0, 2), IsManualOverride: true}, // Should not be deleted
 	}
 	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.MatchedBy(func(from time.Time) bool {
-		// Cleanup always starts from tomorrow
-		return from.Year() == tomorrow.Year() && from.Month() == tomorrow.Month() && from.Day() == tomorrow.Day()
+		// The generation loads the schedule from today, cleanup starts from tomorrow
+		return from.Year() == today.Year() && from.Month() == today.Month() && from.Day() == today.Day()
 	}), mock.MatchedBy(func(to time.Time) bool {
 		return to.Year() == futureEnd.Year() && to.Month() == futureEnd.Month()
-	})).Return(existingEntries, nil)
+	})).Return(existingEntries, nil).Once()
 
-	// Expect deletion of non-override entries only
-	suite.mockScheduleRepo.EXPECT().Delete(ctx, 1).Return(nil) // Delete non-override entry
+	// Expect deletion of non-override entries only, in one batch
+	suite.mockScheduleRepo.EXPECT().DeleteBatch(ctx, []int{1}).Return(nil).Once()
 	// ID 2 (override) should NOT be deleted
 
 	// Mock creation of new entries - we'll expect at least one Monday in the next 3 months
-	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil).Maybe()
-	suite.mockScheduleRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.ScheduleEntry")).Return(nil).Maybe()
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).Return(nil).Maybe()
 
 	// Mock state update
 	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.MatchedBy(func(state *models.ScheduleState) bool {
-		return state.ID == 1
+		return state.TeamID == 1
 	})).Return(nil)
 
 	// Act
@@ -192,7 +230,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_SuccessfulGeneratio
 	// Setup: Old generation (8 days ago, more than 7)
 	oldDate := time.Now().AddDate(0, 0, -8)
 	scheduleState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: oldDate,
 	}
 
@@ -218,12 +256,13 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_SuccessfulGeneratio
 	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil)
 
 	// Mock entry creation - expect multiple calls for different days
-	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil).Maybe()
-	suite.mockScheduleRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.ScheduleEntry")).Return(nil).Maybe()
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).Return(nil).Maybe()
 
 	// Mock state update
 	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.MatchedBy(func(state *models.ScheduleState) bool {
-		return state.ID == 1
+		return state.TeamID == 1
 	})).Return(nil)
 
 	// Act
@@ -242,7 +281,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_SuccessfulGeneratio
 func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_RoundRobinLogic() {
 	// Setup: Old generation to force regeneration
 	scheduleState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: time.Now().AddDate(0, 0, -8),
 	}
 
@@ -264,10 +303,16 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_RoundRobinLogic() {
 
 	// Track the order of team member assignments
 	var assignedMembers []int
-	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil).Maybe()
-	suite.mockScheduleRepo.EXPECT().Create(ctx, mock.MatchedBy(func(entry *models.ScheduleEntry) bool {
-		assignedMembers = append(assignedMembers, entry.TeamMemberID)
-		return entry.TeamMemberID == 20 || entry.TeamMemberID == 30 || entry.TeamMemberID == 10 // Expect round-robin
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.MatchedBy(func(entries []*models.ScheduleEntry) bool {
+		for _, entry := range entries {
+			assignedMembers = append(assignedMembers, entry.TeamMemberID)
+			if entry.TeamMemberID != 20 && entry.TeamMemberID != 30 && entry.TeamMemberID != 10 { // Expect round-robin
+				return false
+			}
+		}
+		return true
 	})).Return(nil).Maybe()
 
 	// Mock state update - expect the index to wrap around correctly
@@ -301,7 +346,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_RoundRobinLogic() {
 // TestGenerateSchedule_SkipManualOverrides tests that manual overrides are preserved
 func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_SkipManualOverrides() {
 	scheduleState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: time.Now().AddDate(0, 0, -8),
 	}
 
@@ -317,23 +362,26 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_SkipManualOverrides
 	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(activeMembers, nil)
 	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(activeDays, nil)
 	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(scheduleState, nil)
-	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil)
 
-	// Mock GetByDate to return a manual override for a specific Monday
+	// The schedule holds a manual override for a specific Monday
 	nextMonday := getNextMonday()
 	manualOverride := []models.ScheduleEntry{
 		{ID: 100, Date: nextMonday, TeamMemberID: 999, IsManualOverride: true},
 	}
-
-	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, nextMonday).Return(manualOverride, nil).Maybe()
-	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.MatchedBy(func(date time.Time) bool {
-		return !date.Equal(nextMonday)
-	})).Return([]models.ScheduleEntry{}, nil).Maybe()
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return(manualOverride, nil)
 
 	// Expect creation of entries but NOT for the day with manual override
-	suite.mockScheduleRepo.EXPECT().Create(ctx, mock.MatchedBy(func(entry *models.ScheduleEntry) bool {
-		return !entry.Date.Equal(nextMonday) // Should not create entry for manual override day
-	})).Return(nil).Maybe()
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	var createdDates []string
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdDates = append(createdDates, entry.GetFormattedDate())
+			}
+			return nil
+		},
+	)
 
 	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.AnythingOfType("*models.ScheduleState")).Return(nil)
 
@@ -344,6 +392,8 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_SkipManualOverrides
 	assert.NoError(suite.T(), err)
 	assert.NotNil(suite.T(), result)
 	assert.True(suite.T(), result.Success)
+	assert.NotEmpty(suite.T(), createdDates)
+	assert.NotContains(suite.T(), createdDates, models.FormatDate(nextMonday), "Should not create entry for manual override day")
 }
 
 // TestGenerateSchedule_ErrorHandling tests various error scenarios
@@ -379,8 +429,9 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_ErrorHandling() {
 				suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return([]models.WorkingHours{{DayOfWeek: 0}}, nil)
 				suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{LastGenerationDate: time.Now().AddDate(0, 0, -8)}, nil)
 				suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil)
-				suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil).Maybe()
-				suite.mockScheduleRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.ScheduleEntry")).Return(nil).Maybe()
+				suite.expectHolidays(ctx)
+				suite.expectTimeOff(ctx)
+				suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).Return(nil).Maybe()
 				suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.AnythingOfType("*models.ScheduleState")).Return(errors.New("update error"))
 			},
 			expectedError: "failed to update schedule state",
@@ -408,7 +459,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_ErrorHandling() {
 // TestGenerateSchedule_DeterministicGenerationWithTwoMembers tests deterministic assignment works correctly with small team
 func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_DeterministicGenerationWithTwoMembers() {
 	scheduleState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: time.Now().AddDate(0, 0, -8),
 	}
 
@@ -428,8 +479,9 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_DeterministicGenera
 	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil)
 
 	// Expect entries to be created based on deterministic date-based assignment
-	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil).Maybe()
-	suite.mockScheduleRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.ScheduleEntry")).Return(nil).Maybe()
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).Return(nil).Maybe()
 
 	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.AnythingOfType("*models.ScheduleState")).Return(nil)
 
@@ -472,7 +524,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_DeterministicAssign
 	}
 
 	scheduleState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: time.Now().AddDate(0, 0, -8), // Force regeneration
 	}
 
@@ -520,14 +572,17 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_DeterministicAssign
 				memberID int
 			}
 
-			suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.AnythingOfType("time.Time")).Return([]models.ScheduleEntry{}, nil).Maybe()
-			suite.mockScheduleRepo.EXPECT().Create(ctx, mock.MatchedBy(func(entry *models.ScheduleEntry) bool {
-				weekday := entry.Date.Weekday()
-				assignments[weekday] = append(assignments[weekday], entry.TeamMemberID)
-				chronologicalAssignments = append(chronologicalAssignments, struct {
-					date     time.Time
-					memberID int
-				}{entry.Date, entry.TeamMemberID})
+			suite.expectHolidays(ctx)
+			suite.expectTimeOff(ctx)
+			suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.MatchedBy(func(entries []*models.ScheduleEntry) bool {
+				for _, entry := range entries {
+					weekday := entry.Date.Weekday()
+					assignments[weekday] = append(assignments[weekday], entry.TeamMemberID)
+					chronologicalAssignments = append(chronologicalAssignments, struct {
+						date     time.Time
+						memberID int
+					}{entry.Date, entry.TeamMemberID})
+				}
 				return true
 			})).Return(nil).Maybe()
 
@@ -625,7 +680,7 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_UnfairRoundRobin()
 
 	// Mock schedule state
 	initialState := &models.ScheduleState{
-		ID:                 1,
+		TeamID:             1,
 		LastGenerationDate: testStartDate.AddDate(0, 0, -30), // 30 days ago
 	}
 	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(initialState, nil)
@@ -635,14 +690,17 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_UnfairRoundRobin()
 
 	// Mock GetByDate calls for override checks - no overrides
 	// Need to be more generous with the number of calls since it generates for 3 months
-	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, mock.Anything).Return([]models.ScheduleEntry{}, nil).Maybe()
 
 	// Track created entries
 	var createdEntries []models.ScheduleEntry
-	suite.mockScheduleRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.ScheduleEntry")).RunAndReturn(
-		func(ctx context.Context, entry *models.ScheduleEntry) error {
-			entry.ID = len(createdEntries) + 1
-			createdEntries = append(createdEntries, *entry)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				entry.ID = len(createdEntries) + 1
+				createdEntries = append(createdEntries, *entry)
+			}
 			return nil
 		},
 	).Maybe() // Let it create as many as needed
@@ -684,7 +742,1512 @@ func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_UnfairRoundRobin()
 	}
 }
 
-// TestRunGenerateScheduleTestSuite runs the test suite
-func TestRunGenerateScheduleTestSuite(t *testing.T) {
-	suite.Run(t, new(GenerateScheduleTestSuite))
+// TestGenerateSchedule_FairShareStrategy tests that the fairness strategy hands the next slots
+// to whoever is furthest behind, counting past shifts and manual overrides
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_FairShareStrategy() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	// Start on a Monday (2023-10-02 was a Monday)
+	testStartDate := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return testStartDate }
+
+	joined := time.Date(2023, 9, 1, 0, 0, 0, 0, time.UTC)
+	activeMembers := []models.TeamMember{
+		{ID: 1, Name: "Alice", Active: true, DateAdded: joined},
+		{ID: 2, Name: "Bob", Active: true, DateAdded: joined},
+		{ID: 3, Name: "Charlie", Active: true, DateAdded: joined},
+	}
+
+	activeDays := []models.WorkingHours{
+		{DayOfWeek: 0, StartTime: "09:00", EndTime: "17:00", Active: true},
+		{DayOfWeek: 1, StartTime: "09:00", EndTime: "17:00", Active: true},
+		{DayOfWeek: 2, StartTime: "09:00", EndTime: "17:00", Active: true},
+		{DayOfWeek: 3, StartTime: "09:00", EndTime: "17:00", Active: true},
+		{DayOfWeek: 4, StartTime: "09:00", EndTime: "17:00", Active: true},
+	}
+
+	// Alice and Bob each did three shifts (one of Bob's as a takeover), Charlie none yet
+	history := []models.ScheduleEntry{
+		{ID: 1, Date: time.Date(2023, 9, 25, 0, 0, 0, 0, time.UTC), TeamMemberID: 1},
+		{ID: 2, Date: time.Date(2023, 9, 26, 0, 0, 0, 0, time.UTC), TeamMemberID: 2},
+		{ID: 3, Date: time.Date(2023, 9, 27, 0, 0, 0, 0, time.UTC), TeamMemberID: 1},
+		{ID: 4, Date: time.Date(2023, 9, 28, 0, 0, 0, 0, time.UTC), TeamMemberID: 2, IsManualOverride: true},
+		{ID: 5, Date: time.Date(2023, 9, 29, 0, 0, 0, 0, time.UTC), TeamMemberID: 1},
+		{ID: 6, Date: time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC), TeamMemberID: 2},
+	}
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(activeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(activeDays, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{
+		TeamID:             1,
+		LastGenerationDate: testStartDate.AddDate(0, 0, -30),
+		RotationStrategy:   models.RotationStrategyFairShare,
+	}, nil)
+
+	// First range lookup is the cleanup of future entries, the second one loads the history
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil).Once()
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, joined, mock.Anything).Return(history, nil).Once()
+
+	var createdEntries []models.ScheduleEntry
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 6)
+
+	// Charlie catches up first, then the rotation continues in roster order
+	expected := []int{3, 3, 3, 1, 2, 3}
+	for i, memberID := range expected {
+		assert.Equal(suite.T(), memberID, createdEntries[i].TeamMemberID, "slot %d", i)
+	}
+
+	// Over the whole horizon nobody ends up more than one shift apart
+	totals := map[int]int{}
+	for _, entry := range append(history, createdEntries...) {
+		totals[entry.TeamMemberID]++
+	}
+	minCount, maxCount := totals[1], totals[1]
+	for _, count := range totals {
+		minCount = min(minCount, count)
+		maxCount = max(maxCount, count)
+	}
+	assert.LessOrEqual(suite.T(), maxCount-minCount, 1)
+}
+
+// TestGenerateSchedule_RoundRobinKeepsPublishedWeeks tests that the round robin continues from the
+// saved cursor, keeps the published entries and only reassigns slots of members that left
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_RoundRobinKeepsPublishedWeeks() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	// Start on a Monday (2023-10-02 was a Monday)
+	testStartDate := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return testStartDate }
+	publishedUntil := time.Date(2023, 10, 9, 0, 0, 0, 0, time.UTC)
+
+	// Bob was deactivated after this week was published
+	activeMembers := []models.TeamMember{
+		{ID: 1, Name: "Alice", Active: true},
+		{ID: 3, Name: "Charlie", Active: true},
+		{ID: 4, Name: "Dana", Active: true},
+	}
+
+	published := map[string][]models.ScheduleEntry{
+		"2023-10-02": {{ID: 10, Date: testStartDate, TeamMemberID: 1}},
+		"2023-10-03": {{ID: 11, Date: testStartDate.AddDate(0, 0, 1), TeamMemberID: 2}},
+		"2023-10-04": {{ID: 12, Date: testStartDate.AddDate(0, 0, 2), TeamMemberID: 3}},
+		"2023-10-05": {{ID: 13, Date: testStartDate.AddDate(0, 0, 3), TeamMemberID: 4}},
+		"2023-10-06": {{ID: 14, Date: testStartDate.AddDate(0, 0, 4), TeamMemberID: 1}},
+	}
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(activeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{
+		TeamID:             1,
+		LastGenerationDate: testStartDate.AddDate(0, 0, -30),
+		RotationStrategy:   models.RotationStrategyRoundRobin,
+		RotationQueue:      []int{1, 2, 3, 4},
+		RotationCursor:     1, // Bob would have been next after the published week
+		RotationCursorDate: publishedUntil,
+	}, nil)
+
+	// The generation loads the schedule from today, the strategy only looks at takeovers after the published weeks
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, testStartDate, mock.Anything).Return([]models.ScheduleEntry{
+		published["2023-10-02"][0], published["2023-10-03"][0], published["2023-10-04"][0], published["2023-10-05"][0], published["2023-10-06"][0],
+	}, nil).Once()
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, publishedUntil, mock.Anything).Return([]models.ScheduleEntry{}, nil).Once()
+
+	// Only Bob's published slot is removed
+	suite.mockScheduleRepo.EXPECT().DeleteBatch(ctx, []int{11}).Return(nil).Once()
+
+	var createdEntries []models.ScheduleEntry
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+
+	var savedState *models.ScheduleState
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, state *models.ScheduleState) error {
+			savedState = state
+			return nil
+		},
+	)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 3)
+
+	// Bob's vacated slot goes to whoever is next in the queue, then the queue continues after the published week
+	assert.Equal(suite.T(), "2023-10-03", createdEntries[0].GetFormattedDate())
+	assert.Equal(suite.T(), "2023-10-09", createdEntries[1].GetFormattedDate())
+	assert.Equal(suite.T(), []int{3, 4, 1, 3}, []int{
+		createdEntries[0].TeamMemberID, createdEntries[1].TeamMemberID,
+		createdEntries[2].TeamMemberID, createdEntries[3].TeamMemberID,
+	})
+
+	// The cursor is saved at the end of the generated period, ready for the next roster change
+	assert.Equal(suite.T(), []int{1, 3, 4}, savedState.RotationQueue)
+	assert.Equal(suite.T(), (1+len(createdEntries))%3, savedState.RotationCursor)
+	assert.Equal(suite.T(), time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC), savedState.RotationCursorDate)
+}
+
+// TestGenerateSchedule_SkipsMembersOnTimeOff tests that members on time off are replaced by the next available member
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_SkipsMembersOnTimeOff() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	// Start on a Monday (2023-10-02 was a Monday)
+	testStartDate := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return testStartDate }
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+
+	suite.expectHolidays(ctx)
+
+	// Bob is away the whole first week, the lookup covers the generation period
+	suite.mockTimeOffRepo.EXPECT().GetByDateRange(ctx, testStartDate, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)).Return([]models.TimeOff{
+		timeOffPeriod(2, "2023-10-02", "2023-10-06"),
+	}, nil)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 10)
+
+	bobAssigned := false
+	for _, entry := range createdEntries[:5] {
+		assert.NotEqual(suite.T(), 2, entry.TeamMemberID, "Bob is on time off on %s", entry.GetFormattedDate())
+	}
+	for _, entry := range createdEntries[5:] {
+		bobAssigned = bobAssigned || entry.TeamMemberID == 2
+	}
+	assert.True(suite.T(), bobAssigned, "Bob is back in the rotation after the time off")
+}
+
+// TestGenerateSchedule_TimeOffError tests that a failing time off lookup aborts the generation
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_TimeOffError() {
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.expectHolidays(ctx)
+	suite.mockTimeOffRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return(nil, errors.New("time off error"))
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.Error(suite.T(), err)
+	assert.Nil(suite.T(), result)
+	assert.Contains(suite.T(), err.Error(), "failed to get time off")
+}
+
+// TestGenerateSchedule_HonoursHolidays tests that duty-free holidays are skipped and alternative hours are used
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_HonoursHolidays() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	// Start on a Monday (2023-10-02 was a Monday)
+	testStartDate := time.Date(2023, 10, 2, 0, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return testStartDate }
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+
+	// Wednesday is a recurring duty-free holiday, Thursday has shorter hours
+	suite.expectHolidays(ctx,
+		models.Holiday{Date: time.Date(2020, 10, 4, 0, 0, 0, 0, time.UTC), Name: "Founders Day", Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+		models.Holiday{Date: time.Date(2023, 10, 5, 0, 0, 0, 0, time.UTC), Name: "Team Outing", Behavior: models.HolidayBehaviorAlternativeHours, StartTime: "09:00", EndTime: "12:00"},
+	)
+	suite.expectTimeOff(ctx)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 10)
+
+	for _, entry := range createdEntries {
+		assert.NotEqual(suite.T(), "2023-10-04", entry.GetFormattedDate(), "Nobody is on duty on a duty-free holiday")
+	}
+
+	assert.Equal(suite.T(), "2023-10-05", createdEntries[2].GetFormattedDate())
+	assert.Equal(suite.T(), "09:00", createdEntries[2].StartTime)
+	assert.Equal(suite.T(), "12:00", createdEntries[2].EndTime)
+	assert.Equal(suite.T(), "17:00", createdEntries[3].EndTime, "Regular hours apply after the holiday")
+
+	// The holiday doesn't make the rotation skip anyone
+	for i := 1; i < len(createdEntries); i++ {
+		expected := createdEntries[i-1].TeamMemberID%3 + 1
+		assert.Equal(suite.T(), expected, createdEntries[i].TeamMemberID, "Member on %s", createdEntries[i].GetFormattedDate())
+	}
+}
+
+// TestGenerateSchedule_WorkingHoursChange tests that every date uses the working hours in effect
+// on it, and that the rotation continues across the change
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_WorkingHoursChange() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	// From Wednesday 2023-10-11 on, Friday is off and Saturday has shorter hours
+	changed := time.Date(2023, 10, 11, 0, 0, 0, 0, time.UTC)
+	workingHours := append(weekdaysMonToFri(),
+		models.WorkingHours{DayOfWeek: 4, StartTime: "00:00", EndTime: "00:00", EffectiveFrom: changed},
+		models.WorkingHours{DayOfWeek: 5, StartTime: "10:00", EndTime: "14:00", Active: true, EffectiveFrom: changed},
+	)
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(workingHours, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 10)
+
+	weekdays := make(map[time.Weekday]bool)
+	for _, entry := range createdEntries {
+		switch {
+		case entry.Date.Before(changed):
+			assert.NotEqual(suite.T(), time.Saturday, entry.Date.Weekday(), "No Saturday before the change")
+			assert.Equal(suite.T(), "09:00", entry.StartTime)
+		case entry.Date.Weekday() == time.Saturday:
+			assert.Equal(suite.T(), "10:00", entry.StartTime)
+			assert.Equal(suite.T(), "14:00", entry.EndTime)
+		default:
+			assert.NotEqual(suite.T(), time.Friday, entry.Date.Weekday(), "Friday is off from %s", changed)
+		}
+		weekdays[entry.Date.Weekday()] = true
+	}
+	assert.True(suite.T(), weekdays[time.Friday], "Friday before the change keeps its shift")
+	assert.True(suite.T(), weekdays[time.Saturday], "Saturday is a working day after the change")
+
+	// The change doesn't make the rotation skip anyone
+	for i := 1; i < len(createdEntries); i++ {
+		expected := createdEntries[i-1].TeamMemberID%3 + 1
+		assert.Equal(suite.T(), expected, createdEntries[i].TeamMemberID, "Member on %s", createdEntries[i].GetFormattedDate())
+	}
+}
+
+// TestGenerateSchedule_WorkingHoursExceptions tests that exceptions change the hours of a date,
+// make it a non-working day or an extra working day, and take precedence over holidays
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_WorkingHoursExceptions() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.expectTimeOff(ctx)
+
+	// Tuesday ends early, Wednesday is off, Thursday is a holiday with duty after all and Saturday
+	// is an extra release day
+	suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return([]models.Holiday{
+		{Date: time.Date(2023, 10, 5, 0, 0, 0, 0, time.UTC), Name: "Team Outing", Behavior: models.HolidayBehaviorNoDuty},
+	}, nil)
+	suite.mockWorkingRepo.EXPECT().GetExceptions(ctx).Return([]models.WorkingHoursException{
+		{Date: time.Date(2023, 10, 3, 0, 0, 0, 0, time.UTC), Kind: models.ExceptionKindHours, StartTime: "09:00", EndTime: "13:00"},
+		{Date: time.Date(2023, 10, 4, 0, 0, 0, 0, time.UTC), Kind: models.ExceptionKindNonWorking},
+		{Date: time.Date(2023, 10, 5, 0, 0, 0, 0, time.UTC), Kind: models.ExceptionKindHours, StartTime: "12:00", EndTime: "17:00"},
+		{Date: time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC), Kind: models.ExceptionKindWorking, StartTime: "10:00", EndTime: "16:00"},
+	}, nil)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 10)
+
+	var firstWeek []string
+	for _, entry := range createdEntries[:6] {
+		firstWeek = append(firstWeek, entry.GetFormattedDate()+" "+entry.StartTime+"-"+entry.EndTime)
+	}
+	assert.Equal(suite.T(), []string{
+		"2023-10-02 09:00-17:00",
+		"2023-10-03 09:00-13:00",
+		"2023-10-05 12:00-17:00",
+		"2023-10-06 09:00-17:00",
+		"2023-10-07 10:00-16:00",
+		"2023-10-09 09:00-17:00",
+	}, firstWeek)
+
+	// The exceptions don't make the rotation skip anyone
+	for i := 1; i < len(createdEntries); i++ {
+		expected := createdEntries[i-1].TeamMemberID%3 + 1
+		assert.Equal(suite.T(), expected, createdEntries[i].TeamMemberID, "Member on %s", createdEntries[i].GetFormattedDate())
+	}
+}
+
+// TestGenerateSchedule_ExtraWorkingDayOfShift tests that an extra working day only gets the shift
+// of the exception, and that both shifts keep rotating through the team
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_ExtraWorkingDayOfShift() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	saturday := time.Date(2023, 10, 7, 0, 0, 0, 0, time.UTC)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(morningAndAfternoon(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, nil)
+	suite.mockWorkingRepo.EXPECT().GetExceptions(ctx).Return([]models.WorkingHoursException{
+		{Date: saturday, Kind: models.ExceptionKindWorking, Name: "Morning", StartTime: "10:00", EndTime: "14:00"},
+	}, nil)
+	suite.expectTimeOff(ctx)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+
+	previous := make(map[string]int)
+	var onSaturday []models.ScheduleEntry
+	for _, entry := range createdEntries {
+		if entry.Date.Equal(saturday) {
+			onSaturday = append(onSaturday, entry)
+		}
+		if last, ok := previous[entry.Shift]; ok {
+			assert.Equal(suite.T(), last%3+1, entry.TeamMemberID, "%s on %s", entry.Shift, entry.GetFormattedDate())
+		}
+		previous[entry.Shift] = entry.TeamMemberID
+	}
+	if assert.Len(suite.T(), onSaturday, 1) {
+		assert.Equal(suite.T(), "Morning", onSaturday[0].Shift)
+		assert.Equal(suite.T(), "10:00", onSaturday[0].StartTime)
+		assert.Equal(suite.T(), "14:00", onSaturday[0].EndTime)
+	}
+}
+
+// TestGenerateSchedule_IndependentShifts tests that every shift gets its own entry and rotation,
+// starting at a different member so nobody gets both shifts of a day
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_IndependentShifts() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(morningAndAfternoon(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Equal(suite.T(), result.EntriesCreated, len(createdEntries))
+	assert.Greater(suite.T(), len(createdEntries), 20)
+	assert.Equal(suite.T(), 0, len(createdEntries)%2, "Every date has both shifts")
+
+	var previous map[string]int
+	for i := 0; i < len(createdEntries); i += 2 {
+		morning, afternoon := createdEntries[i], createdEntries[i+1]
+		assert.Equal(suite.T(), morning.GetFormattedDate(), afternoon.GetFormattedDate())
+		assert.Equal(suite.T(), "Morning", morning.Shift)
+		assert.Equal(suite.T(), "08:00", morning.StartTime)
+		assert.Equal(suite.T(), "Afternoon", afternoon.Shift)
+		assert.Equal(suite.T(), "18:00", afternoon.EndTime)
+		assert.NotEqual(suite.T(), morning.TeamMemberID, afternoon.TeamMemberID, "Shifts on %s", morning.GetFormattedDate())
+
+		// Each shift rotates through the team on its own
+		if previous != nil {
+			assert.Equal(suite.T(), previous["Morning"]%3+1, morning.TeamMemberID, "Morning on %s", morning.GetFormattedDate())
+			assert.Equal(suite.T(), previous["Afternoon"]%3+1, afternoon.TeamMemberID, "Afternoon on %s", afternoon.GetFormattedDate())
+		}
+		previous = map[string]int{"Morning": morning.TeamMemberID, "Afternoon": afternoon.TeamMemberID}
+	}
+}
+
+// TestGenerateSchedule_RoundRobinShiftCursors tests that the round robin keeps a cursor per shift
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_RoundRobinShiftCursors() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(morningAndAfternoon(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{
+		TeamID:             1,
+		RotationStrategy:   models.RotationStrategyRoundRobin,
+		RotationQueue:      []int{1, 2, 3},
+		RotationCursor:     0,
+		RotationCursorDate: testMonday,
+		ShiftCursors:       map[string]int{"Afternoon": 2, "Evening": 1}, // The morning shift is new
+	}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+
+	var savedState *models.ScheduleState
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, state *models.ScheduleState) error {
+			savedState = state
+			return nil
+		},
+	)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 4)
+
+	// The morning shift starts at the rotation cursor, the afternoon shift at its own cursor
+	assert.Equal(suite.T(), []int{1, 3, 2, 1}, []int{
+		createdEntries[0].TeamMemberID, createdEntries[1].TeamMemberID,
+		createdEntries[2].TeamMemberID, createdEntries[3].TeamMemberID,
+	})
+
+	// Both cursors are saved at the end of the generated period, the removed shift's cursor is dropped
+	shiftDays := len(createdEntries) / 2
+	assert.Equal(suite.T(), map[string]int{
+		"Morning":   shiftDays % 3,
+		"Afternoon": (2 + shiftDays) % 3,
+	}, savedState.ShiftCursors)
+}
+
+// TestGenerateSchedule_WeeklyRotation tests that a weekly rotation keeps one member on duty from
+// handover to handover and stops generating at the last handover before the horizon
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_WeeklyRotation() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{
+		TeamID:             1,
+		RotationStrategy:   models.RotationStrategyRoundRobin,
+		RotationPeriod:     models.RotationPeriodWeekly,
+		HandoverDay:        2, // Wednesday
+		RotationQueue:      []int{1, 2, 3},
+		RotationCursor:     0,
+		RotationCursorDate: testMonday,
+	}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Maybe()
+
+	var savedState *models.ScheduleState
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, state *models.ScheduleState) error {
+			savedState = state
+			return nil
+		},
+	)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Greater(suite.T(), len(createdEntries), 10)
+
+	// Monday and Tuesday finish the turn that started last Wednesday, then Bob takes over
+	assert.Equal(suite.T(), []int{1, 1, 2, 2, 2, 2, 2, 3}, assignedEntryIDs(createdEntries[:8]))
+
+	// The turn running at the three month horizon is left for the next generation
+	lastEntry := createdEntries[len(createdEntries)-1]
+	assert.Equal(suite.T(), "2023-12-26", lastEntry.GetFormattedDate())
+	assert.Equal(suite.T(), time.Date(2023, 12, 27, 0, 0, 0, 0, time.UTC), savedState.RotationCursorDate)
+
+	// 13 turns from the partial first week up to the horizon
+	assert.Equal(suite.T(), 13%3, savedState.RotationCursor)
+}
+
+// TestGenerateSchedule_BackupRole tests that every shift gets a backup: the next available member
+// after the primary, never the primary themselves
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_BackupRole() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	// Thursday was overridden by hand, its backup has to be filled in as well
+	thursday := overrideEntry("2023-10-05", 2)
+	thursday.StartTime, thursday.EndTime = "09:00", "17:00"
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1, BackupEnabled: true}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, from, to time.Time) ([]models.ScheduleEntry, error) {
+			return append([]models.ScheduleEntry{thursday}, createdEntries...), nil
+		},
+	)
+	suite.expectHolidays(ctx)
+
+	// Charlie is away on Wednesday
+	suite.expectTimeOff(ctx, timeOffPeriod(3, "2023-10-04", "2023-10-04"))
+
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Equal(suite.T(), result.EntriesCreated, len(createdEntries))
+
+	primaries := make(map[string]int)
+	backups := make(map[string]models.ScheduleEntry)
+	primaries[thursday.GetFormattedDate()] = thursday.TeamMemberID
+	for _, entry := range createdEntries {
+		if entry.IsBackup() {
+			assert.NotContains(suite.T(), backups, entry.GetFormattedDate(), "One backup per shift")
+			backups[entry.GetFormattedDate()] = entry
+		} else {
+			assert.Equal(suite.T(), models.ScheduleRolePrimary, entry.GetRole())
+			primaries[entry.GetFormattedDate()] = entry.TeamMemberID
+		}
+	}
+	assert.Len(suite.T(), backups, len(primaries), "Every shift has a backup")
+
+	for date, primaryID := range primaries {
+		backup := backups[date]
+		assert.NotEqual(suite.T(), primaryID, backup.TeamMemberID, "Backup on %s", date)
+		if date == "2023-10-04" {
+			assert.NotEqual(suite.T(), 3, backup.TeamMemberID, "Charlie is on time off")
+		} else {
+			assert.Equal(suite.T(), primaryID%3+1, backup.TeamMemberID, "Backup on %s is next in line", date)
+		}
+	}
+
+	assert.Equal(suite.T(), 3, backups["2023-10-05"].TeamMemberID, "The override gets a backup too")
+	assert.Equal(suite.T(), "09:00", backups["2023-10-05"].StartTime)
+	assert.False(suite.T(), backups["2023-10-05"].IsManualOverride)
+}
+
+// TestGenerateSchedule_OnCallCoverage tests that on-call blocks cover the time between the working
+// hours, rotating separately from the working hours shifts
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_OnCallCoverage() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1, OnCallEnabled: true}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, from, to time.Time) ([]models.ScheduleEntry, error) {
+			return createdEntries, nil
+		},
+	)
+	suite.expectHolidays(ctx)
+
+	// Charlie is away on Saturday, so they can't take the weekend
+	suite.expectTimeOff(ctx, timeOffPeriod(3, "2023-10-07", "2023-10-07"))
+
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+
+	daytime := make(map[string]int)
+	onCall := make(map[string]models.ScheduleEntry)
+	for _, entry := range createdEntries {
+		if entry.IsOnCall() {
+			onCall[entry.GetFormattedDate()] = entry
+		} else {
+			assert.Equal(suite.T(), models.ScheduleLayerWorkingHours, entry.GetLayer())
+			daytime[entry.GetFormattedDate()] = entry.TeamMemberID
+		}
+	}
+	assert.Len(suite.T(), onCall, len(daytime), "Every working day ends with an on-call block")
+
+	monday := onCall["2023-10-02"]
+	assert.Equal(suite.T(), "17:00", monday.StartTime)
+	assert.Equal(suite.T(), "09:00", monday.EndTime)
+	assert.Equal(suite.T(), "2023-10-03", models.FormatDate(*monday.EndDate))
+
+	weekend := onCall["2023-10-06"]
+	assert.Equal(suite.T(), "2023-10-09", models.FormatDate(*weekend.EndDate), "The weekend block runs until Monday")
+	assert.NotEqual(suite.T(), 3, weekend.TeamMemberID, "Charlie is on time off during the weekend")
+	assert.NotContains(suite.T(), onCall, "2023-10-07", "No block starts on Saturday")
+
+	for date, entry := range onCall {
+		if date != "2023-10-06" {
+			assert.NotEqual(suite.T(), daytime[date], entry.TeamMemberID, "The night of %s goes to someone else", date)
+		}
+	}
+}
+
+// TestGenerateSchedule_WeekdayPreferences tests that hard preferences are always satisfied, soft ones
+// where possible, and that the result reports what couldn't be satisfied
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_WeekdayPreferences() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	avoid := func(day int, strength string) models.WeekdayPreference {
+		return models.WeekdayPreference{DayOfWeek: day, Kind: models.PreferenceKindAvoid, Strength: strength}
+	}
+	members := append([]models.TeamMember{}, threeMembers...)
+	members[0].Preferences = []models.WeekdayPreference{avoid(0, models.PreferenceStrengthSoft)} // Alice would rather not do Mondays
+	members[1].Preferences = []models.WeekdayPreference{avoid(0, models.PreferenceStrengthSoft)} // Neither would Bob
+	members[2].Preferences = []models.WeekdayPreference{                                         // Charlie never does Mondays or Fridays
+		avoid(0, models.PreferenceStrengthHard),
+		avoid(4, models.PreferenceStrengthHard),
+	}
+	members[0].WorkDays = []int{0, 1, 2, 3} // Nobody works Fridays
+	members[1].WorkDays = []int{0, 1, 2, 3}
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(members, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.NotEmpty(suite.T(), createdEntries)
+
+	mondays := 0
+	for _, entry := range createdEntries {
+		weekday := models.GetWeekdayNumber(entry.Date)
+		assert.NotEqual(suite.T(), 4, weekday, "Nobody can be on duty on Friday %s", entry.GetFormattedDate())
+		if weekday == 0 {
+			mondays++
+			assert.NotEqual(suite.T(), 3, entry.TeamMemberID, "Charlie is on duty on Monday %s", entry.GetFormattedDate())
+		}
+	}
+
+	// Every open Friday and every Monday someone had to take against their wish is reported
+	var open, avoided int
+	for _, constraint := range result.Unsatisfied {
+		if constraint.Hard {
+			open++
+			assert.Equal(suite.T(), 4, models.GetWeekdayNumber(constraint.Date))
+		} else {
+			avoided++
+			assert.Equal(suite.T(), 0, models.GetWeekdayNumber(constraint.Date))
+		}
+	}
+	assert.Equal(suite.T(), mondays, avoided)
+	assert.NotZero(suite.T(), open)
+	assert.Contains(suite.T(), result.Message, "could not be satisfied")
+}
+
+// TestGenerateSchedule_AssignmentRules tests that the generator keeps the minimum gap to the shifts already in the schedule
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_AssignmentRules() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	// Alice was on duty on Friday and would be again on Monday
+	friday := historyEntry("2023-09-29", 1)
+	existing := []models.ScheduleEntry{friday}
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1, MinGapDays: 1, MaxShiftsPerWeek: 2}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, from, to time.Time) ([]models.ScheduleEntry, error) {
+			var entries []models.ScheduleEntry
+			for _, entry := range existing {
+				if !entry.Date.Before(truncateToDate(from)) && !entry.Date.After(to) {
+					entries = append(entries, entry)
+				}
+			}
+			return entries, nil
+		},
+	)
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Empty(suite.T(), result.Unsatisfied)
+
+	// Alice swaps Monday for Bob's Tuesday, the rotation carries on after that
+	assert.Equal(suite.T(), []int{2, 1, 3, 1, 2, 3}, assignedEntryIDs(createdEntries[:6]))
+
+	// Nobody is on duty two working days in a row or more than twice a week
+	rules := newAssignmentRules(&models.ScheduleState{MinGapDays: 1, MaxShiftsPerWeek: 2}, weekdaysMonToFri(), nil)
+	duties := newDutyDates(existing)
+	for _, entry := range createdEntries {
+		assert.Empty(suite.T(), rules.violations(duties, entry.TeamMemberID, entry.Date), "%s on %s", entry.TeamMemberName, entry.GetFormattedDate())
+		duties.add(entry.TeamMemberID, entry.Date)
+	}
+}
+
+// TestRunGeneration_NightlyExtension tests that the nightly run keeps an up-to-date schedule and only fills the dates that came within the horizon
+func (suite *GenerateScheduleTestSuite) TestRunGeneration_NightlyExtension() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	// This week was generated yesterday with a horizon of one week, the horizon is two weeks now
+	state := &models.ScheduleState{
+		TeamID:             1,
+		LastGenerationDate: testMonday.AddDate(0, 0, -1),
+		GeneratedUntil:     testMonday.AddDate(0, 0, 7),
+		HorizonLength:      2,
+		HorizonUnit:        models.HorizonUnitWeeks,
+	}
+	var generated []models.ScheduleEntry
+	for i, memberID := range []int{2, 3, 1, 2, 3} {
+		entry := historyEntry(models.FormatDate(testMonday.AddDate(0, 0, i)), memberID)
+		entry.ID = 10 + i
+		generated = append(generated, entry)
+	}
+
+	var createdEntries []models.ScheduleEntry
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(state, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, testMonday, testMonday.AddDate(0, 0, 14)).Return(generated, nil).Once()
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, state).Return(nil).Times(2)
+
+	// Act
+	result, err := suite.service.RunGeneration(ctx, models.GenerationTriggerAutomatic)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Equal(suite.T(), "Extended the schedule with 5 entries", result.Message)
+
+	// The generated week stays as it is, the rotation fills the week after it
+	assert.Equal(suite.T(), "2023-10-09", createdEntries[0].GetFormattedDate())
+	assert.Equal(suite.T(), []int{3, 1, 2, 3, 1}, assignedEntryIDs(createdEntries))
+
+	// An extension doesn't restart the up-to-date interval, and the run is recorded for the dashboard
+	assert.Equal(suite.T(), testMonday.AddDate(0, 0, -1), state.LastGenerationDate)
+	assert.Equal(suite.T(), testMonday.AddDate(0, 0, 14), state.GeneratedUntil)
+	assert.Equal(suite.T(), models.GenerationRun{
+		At:      testMonday,
+		Trigger: models.GenerationTriggerAutomatic,
+		Success: true,
+		Message: "Extended the schedule with 5 entries",
+	}, state.LastRun)
+
+	// Only the dates that came within the horizon changed, nobody is notified but the webhooks hear about it
+	assert.Empty(suite.T(), suite.notifier.events())
+	assert.Equal(suite.T(), []string{models.WebhookEventGenerationCompleted}, suite.webhooks.events())
+}
+
+// TestRunGeneration_NightlyRegeneration tests that the nightly run generates a schedule again once it is no longer up to date
+func (suite *GenerateScheduleTestSuite) TestRunGeneration_NightlyRegeneration() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	// Up to date for three days, generated four days ago
+	state := &models.ScheduleState{
+		TeamID:             1,
+		LastGenerationDate: testMonday.AddDate(0, 0, -4),
+		GeneratedUntil:     testMonday.AddDate(0, 0, 7),
+		HorizonLength:      1,
+		HorizonUnit:        models.HorizonUnitWeeks,
+		UpToDateDays:       3,
+	}
+	tuesday := historyEntry("2023-10-03", 1)
+	tuesday.ID = 10
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(state, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, testMonday, testMonday.AddDate(0, 0, 7)).Return([]models.ScheduleEntry{tuesday}, nil).Once()
+	suite.mockScheduleRepo.EXPECT().DeleteBatch(ctx, []int{10}).Return(nil).Once()
+	suite.expectHolidays(ctx)
+	suite.expectTimeOff(ctx)
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.MatchedBy(func(entries []*models.ScheduleEntry) bool {
+		return len(entries) == 5
+	})).Return(nil).Once()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, state).Return(nil).Times(2)
+
+	// Act
+	result, err := suite.service.RunGeneration(ctx, models.GenerationTriggerAutomatic)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Successfully generated schedule with 5 entries", result.Message)
+	assert.Equal(suite.T(), testMonday.AddDate(0, 0, 3), result.NextGenerationDue)
+	assert.Equal(suite.T(), testMonday, state.LastGenerationDate)
+	assert.True(suite.T(), state.LastRun.Success)
+	assert.Equal(suite.T(), []string{models.NotificationRegenerated}, suite.notifier.events())
+	assert.Equal(suite.T(), []string{models.WebhookEventGenerationCompleted}, suite.webhooks.events())
+}
+
+// TestGenerateSchedule_LongHorizonBatches tests that a long horizon loads, deletes and creates the schedule in one go
+func (suite *GenerateScheduleTestSuite) TestGenerateSchedule_LongHorizonBatches() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+
+	// The first year was generated before, with a manual override on every fifth working day
+	existing := generatedYear(testMonday)
+	var generatedIDs []int
+	for _, entry := range existing {
+		if !entry.IsManualOverride && entry.Date.After(testMonday) {
+			generatedIDs = append(generatedIDs, entry.ID)
+		}
+	}
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{
+		TeamID:           1,
+		RotationStrategy: models.RotationStrategyEpochModulo,
+		HorizonLength:    24,
+		HorizonUnit:      models.HorizonUnitMonths,
+	}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, testMonday, testMonday.AddDate(0, 24, 0)).Return(existing, nil).Once()
+	suite.expectHolidays(ctx, models.Holiday{Date: testMonday.AddDate(0, 2, 23), Recurring: true, Behavior: models.HolidayBehaviorNoDuty})
+	suite.expectTimeOff(ctx)
+
+	// Every generated entry after today is deleted and every working date filled in a single batch
+	suite.mockScheduleRepo.EXPECT().DeleteBatch(ctx, generatedIDs).Return(nil).Once()
+	var createdEntries []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, entries []*models.ScheduleEntry) error {
+			for _, entry := range entries {
+				createdEntries = append(createdEntries, *entry)
+			}
+			return nil
+		},
+	).Once()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+
+	// Act
+	result, err := suite.service.GenerateSchedule(ctx, true)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), result.Success)
+	assert.Equal(suite.T(), len(createdEntries), result.EntriesCreated)
+	assert.Greater(suite.T(), len(createdEntries), 400)
+
+	// Overrides and today's entry stay, nothing is scheduled twice
+	taken := make(map[string]bool)
+	for _, entry := range existing {
+		if entry.IsManualOverride || !entry.Date.After(testMonday) {
+			taken[entry.GetFormattedDate()] = true
+		}
+	}
+	for _, entry := range createdEntries {
+		assert.False(suite.T(), taken[entry.GetFormattedDate()], "%s is scheduled twice", entry.GetFormattedDate())
+		taken[entry.GetFormattedDate()] = true
+	}
+}
+
+// generatedYear returns a generated year of weekday entries from the given date, with a manual override
+// on every fifth working day
+func generatedYear(from time.Time) []models.ScheduleEntry {
+	var entries []models.ScheduleEntry
+	for date := from; date.Before(from.AddDate(1, 0, 0)); date = date.AddDate(0, 0, 1) {
+		if models.GetWeekdayNumber(date) > 4 {
+			continue
+		}
+		entry := historyEntry(models.FormatDate(date), threeMembers[len(entries)%3].ID)
+		entry.ID = len(entries) + 1
+		entry.IsManualOverride = len(entries)%5 == 4
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// BenchmarkGenerateSchedule measures a full generation of a team with a year of schedule in place
+func BenchmarkGenerateSchedule(b *testing.B) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+	existing := generatedYear(testMonday)
+	holidays := []models.Holiday{
+		{Date: time.Date(2000, 12, 25, 0, 0, 0, 0, time.UTC), Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+		{Date: time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC), Recurring: true, Behavior: models.HolidayBehaviorNoDuty},
+	}
+
+	for _, strategy := range []string{models.RotationStrategyEpochModulo, models.RotationStrategyFairShare} {
+		for _, months := range []int{3, 12, 24} {
+			b.Run(fmt.Sprintf("%s/%d_months", strategy, months), func(b *testing.B) {
+				scheduleRepo := dbMocks.NewMockScheduleRepository(b)
+				teamRepo := dbMocks.NewMockTeamRepository(b)
+				workingRepo := dbMocks.NewMockWorkingHoursRepository(b)
+				timeOffRepo := dbMocks.NewMockTimeOffRepository(b)
+				holidayRepo := dbMocks.NewMockHolidayRepository(b)
+				transactor := dbMocks.NewMockTransactor(b)
+
+				teamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+				workingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+				scheduleRepo.EXPECT().GetState(ctx).RunAndReturn(func(ctx context.Context) (*models.ScheduleState, error) {
+					return &models.ScheduleState{TeamID: 1, RotationStrategy: strategy, HorizonLength: months, HorizonUnit: models.HorizonUnitMonths}, nil
+				})
+				scheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return(existing, nil)
+				scheduleRepo.EXPECT().DeleteBatch(ctx, mock.Anything).Return(nil)
+				scheduleRepo.EXPECT().CreateBatch(ctx, mock.Anything).Return(nil)
+				scheduleRepo.EXPECT().UpdateState(ctx, mock.Anything).Return(nil)
+				holidayRepo.EXPECT().GetAll(ctx).Return(holidays, nil)
+				workingRepo.EXPECT().GetExceptions(ctx).Return(nil, nil)
+				timeOffRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return(nil, nil)
+				transactor.EXPECT().WithTx(mock.Anything, mock.Anything).RunAndReturn(
+					func(ctx context.Context, fn func(ctx context.Context) error) error {
+						return fn(ctx)
+					},
+				)
+
+				service := NewScheduleService(scheduleRepo, teamRepo, workingRepo, timeOffRepo, holidayRepo, transactor, &notificationRecorder{}, &webhookRecorder{})
+				for b.Loop() {
+					if _, err := service.GenerateSchedule(ctx, true); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+// TestRunGeneration_RecordsFailure tests that a run that can't generate the schedule is recorded as failed
+func (suite *GenerateScheduleTestSuite) TestRunGeneration_RecordsFailure() {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+	state := &models.ScheduleState{TeamID: 1}
+
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return([]models.TeamMember{}, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(state, nil)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, state).Return(nil)
+
+	// Act
+	result, err := suite.service.RunGeneration(ctx, models.GenerationTriggerManual)
+
+	// Assert
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), result.Success)
+	assert.Equal(suite.T(), models.GenerationRun{
+		At:      testMonday,
+		Trigger: models.GenerationTriggerManual,
+		Message: result.Message,
+	}, state.LastRun)
+	assert.Empty(suite.T(), suite.notifier.events())
+	assert.Empty(suite.T(), suite.webhooks.events())
+}
+
+// TestGetDashboardData_OnDutyOvernight tests that the early-morning hours belong to the shift of the previous day
+func (suite *GenerateScheduleTestSuite) TestGetDashboardData_OnDutyOvernight() {
+	ctx := context.Background()
+	tuesday := testMonday.AddDate(0, 0, 1)
+	originalTimeNow := timeNow
+	timeNow = func() time.Time { return time.Date(2023, 10, 3, 1, 30, 0, 0, time.Local) }
+	defer func() { timeNow = originalTimeNow }()
+
+	evening := models.ScheduleEntry{ID: 1, Date: testMonday, TeamMemberID: 1, StartTime: "18:00", EndTime: "02:00"}
+	day := models.ScheduleEntry{ID: 2, Date: testMonday, TeamMemberID: 2, StartTime: "09:00", EndTime: "17:00"}
+	next := models.ScheduleEntry{ID: 3, Date: tuesday, TeamMemberID: 3, StartTime: "18:00", EndTime: "02:00"}
+
+	// On-call blocks can start days before
+	friday, monday := testMonday.AddDate(0, 0, -3), testMonday
+	weekend := models.ScheduleEntry{ID: 4, Date: friday, TeamMemberID: 2, StartTime: "17:00", EndTime: "09:00", Layer: models.ScheduleLayerOnCall, EndDate: &monday}
+	night := models.ScheduleEntry{ID: 5, Date: testMonday, TeamMemberID: 3, StartTime: "17:00", EndTime: "09:00", Layer: models.ScheduleLayerOnCall, EndDate: &tuesday}
+
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, tuesday.AddDate(0, 0, -7), tuesday).Return([]models.ScheduleEntry{weekend, evening, day, night, next}, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{}, nil)
+	suite.mockTeamRepo.EXPECT().Count(ctx).Return(3, nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+
+	data, err := suite.service.GetDashboardData(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []models.ScheduleEntry{evening, night}, data.OnDuty)
+}
+
+// assignedEntryIDs returns the member IDs of the entries, in order
+func assignedEntryIDs(entries []models.ScheduleEntry) []int {
+	ids := make([]int, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.TeamMemberID
+	}
+	return ids
+}
+
+// TestRunGenerateScheduleTestSuite runs the test suite
+func TestRunGenerateScheduleTestSuite(t *testing.T) {
+	suite.Run(t, new(GenerateScheduleTestSuite))
+}
+
+// TestManualOverrideRoleConflict tests that a member can't become both primary and backup of a shift
+func TestManualOverrideRoleConflict(t *testing.T) {
+	ctx := context.Background()
+	mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+	mockTeamRepo := dbMocks.NewMockTeamRepository(t)
+	service := NewScheduleService(
+		mockScheduleRepo,
+		mockTeamRepo,
+		dbMocks.NewMockWorkingHoursRepository(t),
+		dbMocks.NewMockTimeOffRepository(t),
+		dbMocks.NewMockHolidayRepository(t),
+		passThroughTx(t),
+		&notificationRecorder{},
+		&webhookRecorder{},
+	)
+
+	primary := historyEntry("2023-10-02", 1)
+	primary.ID = 10
+	backup := backupEntry("2023-10-02", 2)
+	backup.ID = 11
+	backup.TeamMemberName = "Bob"
+
+	mockTeamRepo.EXPECT().GetByID(ctx, mock.Anything).Return(&threeMembers[1], nil)
+	mockScheduleRepo.EXPECT().GetByID(ctx, 10).Return(&primary, nil)
+	mockScheduleRepo.EXPECT().GetByDate(ctx, primary.Date).Return([]models.ScheduleEntry{primary, backup}, nil)
+
+	form := &models.ScheduleEntryForm{Date: "2023-10-02", TeamMemberID: 2, StartTime: "09:00", EndTime: "17:00"}
+
+	_, err := service.CreateManualOverride(ctx, 10, form)
+	assert.EqualError(t, err, "Bob is already the backup of this shift")
+
+	_, err = service.UpdateScheduleEntry(ctx, 10, form)
+	assert.EqualError(t, err, "Bob is already the backup of this shift")
+}
+
+// TestManualEditAssignmentRules tests that manual edits breaking the assignment rules need confirmation
+func TestManualEditAssignmentRules(t *testing.T) {
+	ctx := context.Background()
+	mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+	mockTeamRepo := dbMocks.NewMockTeamRepository(t)
+	mockWorkingRepo := dbMocks.NewMockWorkingHoursRepository(t)
+	mockHolidayRepo := dbMocks.NewMockHolidayRepository(t)
+	service := NewScheduleService(
+		mockScheduleRepo,
+		mockTeamRepo,
+		mockWorkingRepo,
+		dbMocks.NewMockTimeOffRepository(t),
+		mockHolidayRepo,
+		passThroughTx(t),
+		&notificationRecorder{},
+		&webhookRecorder{},
+	)
+
+	// Alice is on duty on Monday, Bob on Tuesday
+	monday := historyEntry("2023-10-02", 1)
+	monday.ID = 9
+	tuesday := historyEntry("2023-10-03", 2)
+	tuesday.ID = 10
+
+	mockTeamRepo.EXPECT().GetByID(ctx, 1).Return(&threeMembers[0], nil)
+	mockScheduleRepo.EXPECT().GetByID(ctx, 10).Return(&tuesday, nil)
+	mockScheduleRepo.EXPECT().GetByDate(ctx, tuesday.Date).Return([]models.ScheduleEntry{tuesday}, nil)
+	mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1, MinGapDays: 1}, nil)
+	mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, nil)
+	mockWorkingRepo.EXPECT().GetExceptions(ctx).Return(nil, nil)
+	mockScheduleRepo.EXPECT().GetByDateRange(ctx, mock.Anything, mock.Anything).Return([]models.ScheduleEntry{monday, tuesday}, nil)
+
+	// Alice taking Tuesday as well needs confirmation
+	form := &models.ScheduleEntryForm{Date: "2023-10-03", TeamMemberID: 1, StartTime: "09:00", EndTime: "17:00"}
+	expected := &RuleViolationError{Violations: []string{"Alice would have 0 working day(s) off between Mon Oct 2 and Tue Oct 3, the minimum is 1"}}
+
+	_, err := service.CreateManualOverride(ctx, 10, form)
+	assert.Equal(t, expected, err)
+
+	_, err = service.UpdateScheduleEntry(ctx, 10, form)
+	assert.Equal(t, expected, err)
+
+	// Confirmed, the edit goes through
+	mockScheduleRepo.EXPECT().Update(ctx, mock.AnythingOfType("*models.ScheduleEntry")).Return(nil)
+	form.Confirmed = true
+	updated, err := service.UpdateScheduleEntry(ctx, 10, form)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, updated.TeamMemberID)
+	assert.True(t, updated.IsManualOverride)
+}
+
+// txMarker marks the context a transactor hands to the work it runs
+type txMarker struct{}
+
+// TestManualOverrideTransaction tests that an override and the entry it replaces change in one transaction
+func TestManualOverrideTransaction(t *testing.T) {
+	ctx := context.Background()
+	txCtx := context.WithValue(ctx, txMarker{}, true)
+	mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+	mockTeamRepo := dbMocks.NewMockTeamRepository(t)
+	mockWorkingRepo := dbMocks.NewMockWorkingHoursRepository(t)
+	mockHolidayRepo := dbMocks.NewMockHolidayRepository(t)
+	mockTransactor := dbMocks.NewMockTransactor(t)
+	notifier := &notificationRecorder{}
+	webhooks := &webhookRecorder{}
+	service := NewScheduleService(
+		mockScheduleRepo,
+		mockTeamRepo,
+		mockWorkingRepo,
+		dbMocks.NewMockTimeOffRepository(t),
+		mockHolidayRepo,
+		mockTransactor,
+		notifier,
+		webhooks,
+	)
+	mockTransactor.EXPECT().WithTx(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, fn func(ctx context.Context) error) error {
+			return fn(txCtx)
+		},
+	).Times(2)
+
+	// The generated entry is only deleted within the transaction, which fails as a whole
+	generated := historyEntry("2023-10-02", 1)
+	generated.ID = 10
+	mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&threeMembers[1], nil)
+	mockScheduleRepo.EXPECT().GetByID(ctx, 10).Return(&generated, nil)
+	mockScheduleRepo.EXPECT().GetByDate(ctx, generated.Date).Return([]models.ScheduleEntry{generated}, nil)
+	mockScheduleRepo.EXPECT().Delete(txCtx, 10).Return(nil).Once()
+	mockScheduleRepo.EXPECT().Create(txCtx, mock.AnythingOfType("*models.ScheduleEntry")).Return(errors.New("disk I/O error")).Once()
+
+	form := &models.ScheduleEntryForm{Date: "2023-10-02", TeamMemberID: 2, StartTime: "09:00", EndTime: "17:00", Confirmed: true}
+	_, err := service.CreateManualOverride(ctx, 10, form)
+	assert.EqualError(t, err, "failed to create manual override: disk I/O error")
+	assert.Empty(t, notifier.events())
+	assert.Empty(t, webhooks.events())
+
+	// Removing an override deletes it and restores the original assignment together
+	originalMemberID := 1
+	override := historyEntry("2023-10-03", 2)
+	override.ID = 12
+	override.IsManualOverride = true
+	override.OriginalTeamMemberID = &originalMemberID
+	mockScheduleRepo.EXPECT().GetByID(ctx, 12).Return(&override, nil)
+	mockWorkingRepo.EXPECT().GetByDay(ctx, 1, override.Date).Return(weekdaysMonToFri()[1:2], nil)
+	mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, nil)
+	mockWorkingRepo.EXPECT().GetExceptions(ctx).Return(nil, nil)
+	mockScheduleRepo.EXPECT().Delete(txCtx, 12).Return(nil).Once()
+	mockScheduleRepo.EXPECT().Create(txCtx, mock.MatchedBy(func(entry *models.ScheduleEntry) bool {
+		return entry.TeamMemberID == 1 && !entry.IsManualOverride
+	})).Return(nil).Once()
+
+	assert.NoError(t, service.RemoveManualOverride(ctx, 12))
+	assert.Equal(t, []string{models.NotificationOverrideRemoved}, notifier.events())
+	assert.Equal(t, []int{2}, notifier.sent[0].MemberIDs)
+	assert.Equal(t, []string{models.WebhookEventEntryDeleted, models.WebhookEventEntryCreated, models.WebhookEventOverrideRemoved}, webhooks.events())
+}
+
+// TestRemoveManualOverride_WorkingHoursExceptions tests that the restored assignment gets the hours
+// of the working hours exception of its date
+func TestRemoveManualOverride_WorkingHoursExceptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		date          string
+		weekly        []models.WorkingHours
+		exception     models.WorkingHoursException
+		expectedStart string
+		expectedEnd   string
+	}{
+		{
+			name:          "different hours",
+			date:          "2023-10-03",
+			weekly:        weekdaysMonToFri()[1:2],
+			exception:     models.WorkingHoursException{Kind: models.ExceptionKindHours, StartTime: "09:00", EndTime: "13:00"},
+			expectedStart: "09:00",
+			expectedEnd:   "13:00",
+		},
+		{
+			name:          "extra working day",
+			date:          "2023-10-07",
+			exception:     models.WorkingHoursException{Kind: models.ExceptionKindWorking, StartTime: "10:00", EndTime: "16:00"},
+			expectedStart: "10:00",
+			expectedEnd:   "16:00",
+		},
+		{
+			name:          "non-working day keeps the hours of the override",
+			date:          "2023-10-03",
+			weekly:        weekdaysMonToFri()[1:2],
+			exception:     models.WorkingHoursException{Kind: models.ExceptionKindNonWorking},
+			expectedStart: "08:00",
+			expectedEnd:   "12:00",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+			mockWorkingRepo := dbMocks.NewMockWorkingHoursRepository(t)
+			mockHolidayRepo := dbMocks.NewMockHolidayRepository(t)
+			mockTransactor := dbMocks.NewMockTransactor(t)
+			service := NewScheduleService(
+				mockScheduleRepo,
+				dbMocks.NewMockTeamRepository(t),
+				mockWorkingRepo,
+				dbMocks.NewMockTimeOffRepository(t),
+				mockHolidayRepo,
+				mockTransactor,
+				&notificationRecorder{},
+				&webhookRecorder{},
+			)
+
+			originalMemberID := 1
+			override := historyEntry(tc.date, 2)
+			override.ID = 12
+			override.StartTime = "08:00"
+			override.EndTime = "12:00"
+			override.IsManualOverride = true
+			override.OriginalTeamMemberID = &originalMemberID
+			exception := tc.exception
+			exception.Date = override.Date
+
+			mockScheduleRepo.EXPECT().GetByID(ctx, 12).Return(&override, nil)
+			mockWorkingRepo.EXPECT().GetByDay(ctx, models.GetWeekdayNumber(override.Date), override.Date).Return(tc.weekly, nil)
+			mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, nil)
+			mockWorkingRepo.EXPECT().GetExceptions(ctx).Return([]models.WorkingHoursException{exception}, nil)
+			mockTransactor.EXPECT().WithTx(ctx, mock.Anything).RunAndReturn(
+				func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			)
+			mockScheduleRepo.EXPECT().Delete(ctx, 12).Return(nil)
+			mockScheduleRepo.EXPECT().Create(ctx, mock.MatchedBy(func(entry *models.ScheduleEntry) bool {
+				return entry.TeamMemberID == 1 && entry.StartTime == tc.expectedStart && entry.EndTime == tc.expectedEnd
+			})).Return(nil)
+
+			assert.NoError(t, service.RemoveManualOverride(ctx, 12))
+		})
+	}
 }
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// scheduleSnapshot holds the schedule entries of the generation period in memory. It is loaded with
+// a single query and follows the entries the generation deletes and creates, so generating doesn't
+// query the schedule day by day.
+type scheduleSnapshot struct {
+	byDate map[string][]models.ScheduleEntry
+}
+
+// loadSnapshot loads the entries from today up to the end of the schedule
+func (s *scheduleService) loadSnapshot(ctx context.Context, state *models.ScheduleState) (*scheduleSnapshot, error) {
+	today := timeNow()
+	entries, err := s.scheduleRepo.GetByDateRange(ctx, today, scheduleEnd(state, today))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing entries: %w", err)
+	}
+	return newScheduleSnapshot(entries), nil
+}
+
+// scheduleEnd returns the last date the schedule may hold generated entries for: the horizon, or
+// the end of an earlier generation with a longer horizon
+func scheduleEnd(state *models.ScheduleState, today time.Time) time.Time {
+	end := state.HorizonEnd(today)
+	if state.GeneratedUntil.After(end) {
+		return state.GeneratedUntil
+	}
+	return end
+}
+
+// newScheduleSnapshot creates a snapshot of the given entries
+func newScheduleSnapshot(entries []models.ScheduleEntry) *scheduleSnapshot {
+	snapshot := &scheduleSnapshot{byDate: make(map[string][]models.ScheduleEntry)}
+	snapshot.add(entries...)
+	return snapshot
+}
+
+// onDate returns the entries of a date
+func (sn *scheduleSnapshot) onDate(date time.Time) []models.ScheduleEntry {
+	return sn.byDate[models.FormatDate(date)]
+}
+
+// between returns the entries from one date up to and including another, in schedule order
+func (sn *scheduleSnapshot) between(from, to time.Time) []models.ScheduleEntry {
+	var entries []models.ScheduleEntry
+	last := models.FormatDate(to)
+	for date := truncateToDate(from); models.FormatDate(date) <= last; date = date.AddDate(0, 0, 1) {
+		entries = append(entries, sn.onDate(date)...)
+	}
+	sort.SliceStable(entries, func(i, j int) bool { return inScheduleOrder(&entries[i], &entries[j]) })
+	return entries
+}
+
+// add adds entries to the snapshot
+func (sn *scheduleSnapshot) add(entries ...models.ScheduleEntry) {
+	for _, entry := range entries {
+		date := entry.GetFormattedDate()
+		sn.byDate[date] = append(sn.byDate[date], entry)
+	}
+}
+
+// remove removes the entries with the given IDs from the snapshot
+func (sn *scheduleSnapshot) remove(ids map[int]bool) {
+	for date, entries := range sn.byDate {
+		var kept []models.ScheduleEntry
+		for _, entry := range entries {
+			if !ids[entry.ID] {
+				kept = append(kept, entry)
+			}
+		}
+		sn.byDate[date] = kept
+	}
+}
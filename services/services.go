@@ -1,21 +1,36 @@
 package services
 
 import (
+	"github.com/blogem/eod-scheduler/notifications"
 	"github.com/blogem/eod-scheduler/repositories"
 )
 
 // Services holds all service instances
 type Services struct {
-	Team         TeamService
-	WorkingHours WorkingHoursService
-	Schedule     ScheduleService
+	Team          TeamService
+	WorkingHours  WorkingHoursService
+	Schedule      ScheduleService
+	TimeOff       TimeOffService
+	Holiday       HolidayService
+	Teams         TeamsService
+	SlackCommands SlackCommandService
+	Notifications NotificationService
+	Webhooks      WebhookService
 }
 
-// NewServices creates and initializes all service instances
-func NewServices(repos *repositories.Repositories) *Services {
-	return &Services{
-		Team:         NewTeamService(repos.Team, repos.Schedule),
-		WorkingHours: NewWorkingHoursService(repos.WorkingHours),
-		Schedule:     NewScheduleService(repos.Schedule, repos.Team, repos.WorkingHours),
+// NewServices creates and initializes all service instances. The notifier sends the notifications of
+// schedule changes, the webhooks get the events of schedule and team changes.
+func NewServices(repos *repositories.Repositories, notifier notifications.Dispatcher, webhooks WebhookPublisher) *Services {
+	srvs := &Services{
+		Team:          NewTeamService(repos.Team, repos.Schedule, repos.WorkingHours, repos.Holiday, webhooks),
+		WorkingHours:  NewWorkingHoursService(repos.WorkingHours),
+		Schedule:      NewScheduleService(repos.Schedule, repos.Team, repos.WorkingHours, repos.TimeOff, repos.Holiday, repos.Transactor, notifier, webhooks),
+		TimeOff:       NewTimeOffService(repos.TimeOff, repos.Team, repos.Schedule),
+		Holiday:       NewHolidayService(repos.Holiday),
+		Teams:         NewTeamsService(repos.Teams, repos.WorkingHours),
+		Notifications: NewNotificationService(repos.NotificationChannels),
+		Webhooks:      NewWebhookService(repos.Webhooks),
 	}
+	srvs.SlackCommands = NewSlackCommandService(repos.Teams, repos.Team, srvs.Schedule)
+	return srvs
 }
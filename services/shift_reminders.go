@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blogem/eod-scheduler/notifications"
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// reminderRetryDelay is the delay before the reminders are tried again when the teams can't be loaded
+const reminderRetryDelay = 5 * time.Minute
+
+// ShiftReminders notifies the members of every team shortly before their shifts start
+type ShiftReminders struct {
+	teamsRepo    repositories.TeamsRepository
+	scheduleRepo repositories.ScheduleRepository
+	notifier     notifications.Dispatcher
+	lead         time.Duration // How long before the start of a shift its member is reminded
+	lastRun      time.Time     // Reminders due up to this time were sent
+}
+
+// NewShiftReminders creates the shift reminders
+func NewShiftReminders(teamsRepo repositories.TeamsRepository, scheduleRepo repositories.ScheduleRepository, notifier notifications.Dispatcher, lead time.Duration) *ShiftReminders {
+	return &ShiftReminders{
+		teamsRepo:    teamsRepo,
+		scheduleRepo: scheduleRepo,
+		notifier:     notifier,
+		lead:         lead,
+	}
+}
+
+// Start sends the reminders as they come due, until the context is done
+func (r *ShiftReminders) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(r.Run(ctx)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Run sends the reminders that came due since the last run for shifts that haven't started yet,
+// primary, backup and on-call alike. It returns when the next reminder is due, or midnight when
+// none is due before then.
+func (r *ShiftReminders) Run(ctx context.Context) time.Time {
+	now := timeNow()
+	next := nextNightlyRun(now, 0)
+
+	teams, err := r.teamsRepo.GetAll(ctx)
+	if err != nil {
+		log.Printf("Shift reminders failed to get the teams: %v", err)
+		return now.Add(reminderRetryDelay)
+	}
+
+	// Shifts starting early tomorrow are due for a reminder today
+	today := truncateToDate(now)
+	for i := range teams {
+		teamCtx := teamctx.SetTeam(ctx, &teams[i])
+		entries, err := r.scheduleRepo.GetByDateRange(teamCtx, today, today.AddDate(0, 0, 1))
+		if err != nil {
+			log.Printf("Shift reminders failed to get the schedule of team %s: %v", teams[i].Slug, err)
+			continue
+		}
+
+		for j := range entries {
+			entry := &entries[j]
+			start := entry.StartsAt(now.Location())
+			remindAt := start.Add(-r.lead)
+			switch {
+			case remindAt.After(now):
+				if remindAt.Before(next) {
+					next = remindAt
+				}
+			case remindAt.After(r.lastRun) && start.After(now):
+				r.notifier.Dispatch(teamCtx, shiftStartsSoon(entry))
+			}
+		}
+	}
+
+	r.lastRun = now
+	return next
+}
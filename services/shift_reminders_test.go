@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+func TestShiftRemindersRun(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	ctx := context.Background()
+	tuesday := testMonday.AddDate(0, 0, 1)
+	teamEntries := map[int][]models.ScheduleEntry{
+		1: {
+			{Date: testMonday, StartTime: "09:00", EndTime: "17:00", TeamMemberID: 1, TeamMemberName: "Alice"},
+			{Date: testMonday, StartTime: "09:00", EndTime: "17:00", Role: models.ScheduleRoleBackup, TeamMemberID: 2, TeamMemberName: "Bob"},
+			{Date: tuesday, StartTime: "00:10", EndTime: "06:00", TeamMemberID: 3, TeamMemberName: "Carol"},
+		},
+		2: {
+			{Date: testMonday, Shift: "Morning", StartTime: "08:00", EndTime: "13:00", TeamMemberID: 5, TeamMemberName: "Erin"},
+			{Date: testMonday, Shift: "Afternoon", StartTime: "13:00", EndTime: "18:00", TeamMemberID: 4, TeamMemberName: "Dana"},
+		},
+	}
+
+	mockTeamsRepo := dbMocks.NewMockTeamsRepository(t)
+	mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+	teams := []models.Team{{ID: 1, Name: "Default", Slug: "default"}, {ID: 2, Name: "Platform", Slug: "platform"}}
+	mockTeamsRepo.EXPECT().GetAll(ctx).Return(teams, nil).Times(3)
+	for _, team := range teams {
+		onTeam := mock.MatchedBy(func(ctx context.Context) bool { return teamctx.GetTeamID(ctx) == team.ID })
+		mockScheduleRepo.EXPECT().GetByDateRange(onTeam, testMonday, tuesday).Return(teamEntries[team.ID], nil)
+	}
+
+	notifier := &notificationRecorder{}
+	reminders := NewShiftReminders(mockTeamsRepo, mockScheduleRepo, notifier, 15*time.Minute)
+	run := func(at time.Duration) ([]int, time.Time) {
+		notifier.sent = nil
+		timeNow = func() time.Time { return testMonday.Add(at) }
+		next := reminders.Run(ctx)
+		var memberIDs []int
+		for _, n := range notifier.sent {
+			assert.Equal(t, models.NotificationShiftStartsSoon, n.Event)
+			memberIDs = append(memberIDs, n.MemberIDs...)
+		}
+		return memberIDs, next
+	}
+
+	// Primary and backup are reminded, a shift that already started isn't
+	memberIDs, next := run(8*time.Hour + 50*time.Minute)
+	assert.Equal(t, []int{1, 2}, memberIDs)
+	assert.Equal(t, testMonday.Add(12*time.Hour+45*time.Minute), next)
+
+	// Reminders are sent once
+	memberIDs, next = run(12*time.Hour + 45*time.Minute)
+	assert.Equal(t, []int{4}, memberIDs)
+	assert.Equal(t, testMonday.Add(23*time.Hour+55*time.Minute), next)
+
+	// A shift early tomorrow is reminded today
+	memberIDs, next = run(23*time.Hour + 55*time.Minute)
+	assert.Equal(t, []int{3}, memberIDs)
+	assert.Equal(t, tuesday, next)
+
+	// The reminders are retried when the teams can't be loaded
+	failingTeamsRepo := dbMocks.NewMockTeamsRepository(t)
+	failingTeamsRepo.EXPECT().GetAll(ctx).Return(nil, errors.New("database is locked"))
+	timeNow = func() time.Time { return testMonday }
+	next = NewShiftReminders(failingTeamsRepo, mockScheduleRepo, notifier, 15*time.Minute).Run(ctx)
+	assert.Equal(t, testMonday.Add(reminderRetryDelay), next)
+}
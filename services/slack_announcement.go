@@ -0,0 +1,229 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// SlackWebhook posts messages to a Slack incoming webhook. Failed posts are retried with a growing
+// delay, as long as another attempt can succeed.
+type SlackWebhook struct {
+	url        string
+	client     *http.Client
+	attempts   int           // Attempts per message, including the first
+	retryDelay time.Duration // Delay before the first retry, doubled for every retry after it
+}
+
+// NewSlackWebhook creates a client for the incoming webhook at the given URL
+func NewSlackWebhook(url string) *SlackWebhook {
+	return &SlackWebhook{
+		url:        url,
+		client:     &http.Client{Timeout: 10 * time.Second},
+		attempts:   3,
+		retryDelay: 5 * time.Second,
+	}
+}
+
+// slackMessage is the payload of an incoming webhook
+type slackMessage struct {
+	Text string `json:"text"`
+}
+
+// Post posts a message, retrying failed attempts. It returns the error of the last attempt when
+// none succeeded.
+func (s *SlackWebhook) Post(ctx context.Context, text string) error {
+	body, err := json.Marshal(slackMessage{Text: text})
+	if err != nil {
+		return fmt.Errorf("failed to encode slack message: %w", err)
+	}
+
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		retry, err := s.post(ctx, body)
+		if err == nil {
+			return nil
+		}
+		if !retry {
+			return fmt.Errorf("failed to post slack message: %w", err)
+		}
+		if attempt >= s.attempts {
+			return fmt.Errorf("failed to post slack message after %d attempts: %w", attempt, err)
+		}
+		log.Printf("Slack post attempt %d of %d failed, retrying in %s: %v", attempt, s.attempts, delay, err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("failed to post slack message: %w", ctx.Err())
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// post makes a single attempt, and tells if another attempt can succeed when it fails. Slack
+// rejects malformed messages and revoked webhooks for good, but not rate limits and outages.
+func (s *SlackWebhook) post(ctx context.Context, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusOK {
+		return false, nil
+	}
+	reason, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+	err = fmt.Errorf("slack responded with %s: %s", resp.Status, strings.TrimSpace(string(reason)))
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+// DailyAnnouncement posts who is on duty today to Slack every working day
+type DailyAnnouncement struct {
+	teamsRepo    repositories.TeamsRepository
+	scheduleRepo repositories.ScheduleRepository
+	webhook      *SlackWebhook
+	runAt        time.Duration // Time of day the announcement is posted, as the time since midnight
+}
+
+// NewDailyAnnouncement creates the daily announcement
+func NewDailyAnnouncement(teamsRepo repositories.TeamsRepository, scheduleRepo repositories.ScheduleRepository, webhook *SlackWebhook, runAt time.Duration) *DailyAnnouncement {
+	return &DailyAnnouncement{
+		teamsRepo:    teamsRepo,
+		scheduleRepo: scheduleRepo,
+		webhook:      webhook,
+		runAt:        runAt,
+	}
+}
+
+// Start posts the announcement every day until the context is done
+func (a *DailyAnnouncement) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(nextNightlyRun(time.Now(), a.runAt)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			a.Run(ctx)
+		}
+	}
+}
+
+// Run posts today's announcement of every team with someone on duty today. A team that fails
+// doesn't stop the others. With several teams the announcement names the team.
+func (a *DailyAnnouncement) Run(ctx context.Context) {
+	teams, err := a.teamsRepo.GetAll(ctx)
+	if err != nil {
+		log.Printf("Daily announcement failed to get the teams: %v", err)
+		return
+	}
+
+	today := timeNow()
+	for i := range teams {
+		team := &teams[i]
+		entries, err := a.scheduleRepo.GetByDate(teamctx.SetTeam(ctx, team), today)
+		if err != nil {
+			log.Printf("Daily announcement failed to get today's schedule of team %s: %v", team.Slug, err)
+			continue
+		}
+
+		teamName := ""
+		if len(teams) > 1 {
+			teamName = team.Name
+		}
+		text := announcementText(teamName, entries)
+		if text == "" {
+			continue // Not a working day
+		}
+
+		if err := a.webhook.Post(ctx, text); err != nil {
+			log.Printf("Daily announcement failed for team %s: %v", team.Slug, err)
+		}
+	}
+}
+
+// announcementText returns the announcement of the members on duty in today's shifts, like
+// "Today's EOD: @alice (09:00–17:00)", or an empty string when nobody is on duty. A team name
+// makes it "Today's EOD for Platform: ...".
+func announcementText(teamName string, entries []models.ScheduleEntry) string {
+	duties := dutiesText(entries)
+	if duties == "" {
+		return ""
+	}
+
+	title := "Today's EOD"
+	if teamName != "" {
+		title += " for " + teamName
+	}
+	return title + ": " + duties
+}
+
+// dutiesText lists the primary members of the shifts of a day in schedule order, like
+// "Morning @alice (08:00–13:00), Afternoon @bob (13:00–18:00)", or returns an empty string when
+// nobody is on duty. Backups and on-call blocks aren't listed.
+func dutiesText(entries []models.ScheduleEntry) string {
+	var primaries []models.ScheduleEntry
+	for _, entry := range entries {
+		if !entry.IsBackup() && !entry.IsOnCall() {
+			primaries = append(primaries, entry)
+		}
+	}
+	sort.SliceStable(primaries, func(i, j int) bool { return inScheduleOrder(&primaries[i], &primaries[j]) })
+
+	var duties []string
+	for _, entry := range primaries {
+		duty := slackMention(&entry) + " (" + hoursText(&entry) + ")"
+		if entry.Shift != "" {
+			duty = entry.Shift + " " + duty
+		}
+		duties = append(duties, duty)
+	}
+	return strings.Join(duties, ", ")
+}
+
+// hoursText returns the hours of a shift like "09:00–17:00", marking hours that end the next day.
+// On-call blocks name the day they end, like "17:00–Mon 09:00".
+func hoursText(entry *models.ScheduleEntry) string {
+	if entry.EndDate != nil {
+		return entry.StartTime + "–" + entry.GetEndDay() + " " + entry.EndTime
+	}
+	hours := entry.StartTime + "–" + entry.EndTime
+	if entry.EndsNextDay() {
+		hours += " +1"
+	}
+	return hours
+}
+
+// slackMention returns the Slack handle of the member on duty, or their name when they have none
+func slackMention(entry *models.ScheduleEntry) string {
+	return memberMention(entry.TeamMemberName, entry.TeamMemberSlackHandle)
+}
+
+// memberMention returns a Slack handle like @alice, or the name of a member without one
+func memberMention(name, handle string) string {
+	handle = normalizeSlackHandle(handle)
+	if handle == "" {
+		return name
+	}
+	return "@" + handle
+}
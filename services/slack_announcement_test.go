@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// slackStandIn is a local stand-in for a Slack incoming webhook that answers with the given
+// statuses in turn and records the messages it receives
+type slackStandIn struct {
+	*httptest.Server
+	mu       sync.Mutex
+	statuses []int
+	messages []string
+}
+
+func newSlackStandIn(t *testing.T, statuses ...int) *slackStandIn {
+	standIn := &slackStandIn{statuses: statuses}
+	standIn.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var message slackMessage
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&message))
+
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		standIn.messages = append(standIn.messages, message.Text)
+		status := http.StatusOK
+		if len(standIn.statuses) > 0 {
+			status, standIn.statuses = standIn.statuses[0], standIn.statuses[1:]
+		}
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(http.StatusText(status)))
+	}))
+	t.Cleanup(standIn.Close)
+	return standIn
+}
+
+// webhook returns a client for the stand-in that retries without waiting
+func (s *slackStandIn) webhook() *SlackWebhook {
+	webhook := NewSlackWebhook(s.URL)
+	webhook.retryDelay = time.Millisecond
+	return webhook
+}
+
+func TestSlackWebhookPost(t *testing.T) {
+	testCases := []struct {
+		name          string
+		statuses      []int
+		expectedPosts int
+		expectedError string
+	}{
+		{
+			name:          "posted at once",
+			expectedPosts: 1,
+		},
+		{
+			name:          "retried after an outage and a rate limit",
+			statuses:      []int{http.StatusServiceUnavailable, http.StatusTooManyRequests},
+			expectedPosts: 3,
+		},
+		{
+			name:          "gives up after the last attempt",
+			statuses:      []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusBadGateway},
+			expectedPosts: 3,
+			expectedError: "failed to post slack message after 3 attempts: slack responded with 502 Bad Gateway: Bad Gateway",
+		},
+		{
+			name:          "revoked webhooks aren't retried",
+			statuses:      []int{http.StatusNotFound},
+			expectedPosts: 1,
+			expectedError: "failed to post slack message: slack responded with 404 Not Found: Not Found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			standIn := newSlackStandIn(t, tc.statuses...)
+
+			err := standIn.webhook().Post(context.Background(), "Today's EOD: @alice (09:00–17:00)")
+
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Len(t, standIn.messages, tc.expectedPosts)
+			for _, message := range standIn.messages {
+				assert.Equal(t, "Today's EOD: @alice (09:00–17:00)", message)
+			}
+		})
+	}
+}
+
+func TestSlackWebhookPost_Unreachable(t *testing.T) {
+	standIn := newSlackStandIn(t)
+	webhook := standIn.webhook()
+	standIn.Close()
+
+	err := webhook.Post(context.Background(), "Today's EOD: @alice (09:00–17:00)")
+	assert.ErrorContains(t, err, "failed to post slack message after 3 attempts")
+}
+
+func TestDailyAnnouncementRun(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday.Add(9 * time.Hour) }
+
+	ctx := context.Background()
+	mockTeamsRepo := dbMocks.NewMockTeamsRepository(t)
+	mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+	standIn := newSlackStandIn(t)
+
+	teams := []models.Team{
+		{ID: 1, Name: "Default", Slug: "default"},
+		{ID: 2, Name: "Platform", Slug: "platform"},
+		{ID: 3, Name: "Weekend", Slug: "weekend"},
+	}
+	mockTeamsRepo.EXPECT().GetAll(ctx).Return(teams, nil)
+
+	// onTeam matches the context of a team
+	onTeam := func(id int) interface{} {
+		return mock.MatchedBy(func(ctx context.Context) bool { return teamctx.GetTeamID(ctx) == id })
+	}
+	mockScheduleRepo.EXPECT().GetByDate(onTeam(1), timeNow()).Return([]models.ScheduleEntry{
+		{StartTime: "09:00", EndTime: "17:00", TeamMemberName: "Alice", TeamMemberSlackHandle: "alice"},
+		{StartTime: "09:00", EndTime: "17:00", Role: models.ScheduleRoleBackup, TeamMemberName: "Bob", TeamMemberSlackHandle: "bob"},
+		{StartTime: "17:00", EndTime: "09:00", Layer: models.ScheduleLayerOnCall, TeamMemberName: "Carol", TeamMemberSlackHandle: "carol"},
+	}, nil)
+	mockScheduleRepo.EXPECT().GetByDate(onTeam(2), timeNow()).Return([]models.ScheduleEntry{
+		{Shift: "Evening", StartTime: "18:00", EndTime: "02:00", TeamMemberName: "Dana"},
+		{Shift: "Morning", StartTime: "08:00", EndTime: "13:00", TeamMemberName: "Erin", TeamMemberSlackHandle: "@erin"},
+	}, nil)
+	mockScheduleRepo.EXPECT().GetByDate(onTeam(3), timeNow()).Return(nil, nil)
+
+	NewDailyAnnouncement(mockTeamsRepo, mockScheduleRepo, standIn.webhook(), 9*time.Hour).Run(ctx)
+
+	// Teams without anyone on duty today get no announcement
+	assert.Equal(t, []string{
+		"Today's EOD for Default: @alice (09:00–17:00)",
+		"Today's EOD for Platform: Morning @erin (08:00–13:00), Evening Dana (18:00–02:00 +1)",
+	}, standIn.messages)
+}
+
+func TestAnnouncementText(t *testing.T) {
+	entries := []models.ScheduleEntry{{StartTime: "09:00", EndTime: "17:00", TeamMemberName: "Alice", TeamMemberSlackHandle: "alice"}}
+	assert.Equal(t, "Today's EOD: @alice (09:00–17:00)", announcementText("", entries))
+	assert.Empty(t, announcementText("", nil))
+}
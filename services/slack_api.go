@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SlackAPI calls the Slack Web API with the token of a Slack app. It caches the user IDs of the
+// Slack handles it resolves.
+type SlackAPI struct {
+	token   string
+	baseURL string
+	client  *http.Client
+
+	mu      sync.Mutex
+	userIDs map[string]string // User IDs by lowercase handle
+}
+
+// NewSlackAPI creates a client for the Slack Web API
+func NewSlackAPI(token string) *SlackAPI {
+	return &SlackAPI{
+		token:   token,
+		baseURL: "https://slack.com/api",
+		client:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// slackResponse is the envelope of every Web API response
+type slackResponse struct {
+	OK               bool   `json:"ok"`
+	Error            string `json:"error"`
+	ResponseMetadata struct {
+		NextCursor string `json:"next_cursor"`
+	} `json:"response_metadata"`
+}
+
+// slackUser is a user in the users.list response
+type slackUser struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Deleted bool   `json:"deleted"`
+	IsBot   bool   `json:"is_bot"`
+	Profile struct {
+		DisplayName string `json:"display_name"`
+	} `json:"profile"`
+}
+
+// UserIDs resolves Slack handles to user IDs. A handle matches the username or the display name of
+// a user. Handles missing from the cache refresh it once; handles that still don't match a user
+// are returned as unknown.
+func (s *SlackAPI) UserIDs(ctx context.Context, handles []string) ([]string, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids, unknown := s.cachedUserIDs(handles)
+	if len(unknown) == 0 {
+		return ids, nil, nil
+	}
+
+	userIDs, err := s.listUsers(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	s.userIDs = userIDs
+
+	ids, unknown = s.cachedUserIDs(handles)
+	return ids, unknown, nil
+}
+
+// cachedUserIDs resolves handles from the cache
+func (s *SlackAPI) cachedUserIDs(handles []string) ([]string, []string) {
+	var ids, unknown []string
+	for _, handle := range handles {
+		if id, ok := s.userIDs[strings.ToLower(normalizeSlackHandle(handle))]; ok {
+			ids = append(ids, id)
+		} else {
+			unknown = append(unknown, handle)
+		}
+	}
+	return ids, unknown
+}
+
+// listUsers returns the user IDs of all active users by lowercase username and display name
+func (s *SlackAPI) listUsers(ctx context.Context) (map[string]string, error) {
+	userIDs := make(map[string]string)
+	params := url.Values{"limit": {"200"}}
+	for {
+		var page struct {
+			slackResponse
+			Members []slackUser `json:"members"`
+		}
+		if err := s.call(ctx, "users.list", params, &page); err != nil {
+			return nil, fmt.Errorf("failed to list slack users: %w", err)
+		}
+
+		for _, user := range page.Members {
+			if user.Deleted || user.IsBot {
+				continue
+			}
+			userIDs[strings.ToLower(user.Name)] = user.ID
+			if user.Profile.DisplayName != "" {
+				userIDs[strings.ToLower(user.Profile.DisplayName)] = user.ID
+			}
+		}
+
+		if page.ResponseMetadata.NextCursor == "" {
+			return userIDs, nil
+		}
+		params.Set("cursor", page.ResponseMetadata.NextCursor)
+	}
+}
+
+// SetUserGroupUsers replaces the members of a user group with the given users
+func (s *SlackAPI) SetUserGroupUsers(ctx context.Context, userGroupID string, userIDs []string) error {
+	params := url.Values{
+		"usergroup": {userGroupID},
+		"users":     {strings.Join(userIDs, ",")},
+	}
+	var response slackResponse
+	if err := s.call(ctx, "usergroups.users.update", params, &response); err != nil {
+		return fmt.Errorf("failed to update slack user group: %w", err)
+	}
+	return nil
+}
+
+// call calls a Web API method and decodes its response into result, which embeds slackResponse
+func (s *SlackAPI) call(ctx context.Context, method string, params url.Values, result interface{ ok() (bool, string) }) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.baseURL+"/"+method, strings.NewReader(params.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("slack responded with %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("failed to decode slack response: %w", err)
+	}
+	if ok, reason := result.ok(); !ok {
+		return fmt.Errorf("slack %s failed: %s", method, reason)
+	}
+	return nil
+}
+
+// ok tells if the call succeeded, and why not when it didn't
+func (r *slackResponse) ok() (bool, string) {
+	return r.OK, r.Error
+}
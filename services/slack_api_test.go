@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// slackAPIStandIn is a local stand-in for the Slack Web API. It lists its users two per page and
+// records the calls it receives and the members it sets on user groups.
+type slackAPIStandIn struct {
+	*httptest.Server
+	mu         sync.Mutex
+	users      []slackUser
+	calls      []string
+	userGroups map[string][]string
+	failWith   string // Error of every call, like "invalid_auth"
+}
+
+func newSlackAPIStandIn(t *testing.T, users ...slackUser) *slackAPIStandIn {
+	standIn := &slackAPIStandIn{users: users, userGroups: make(map[string][]string)}
+	standIn.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer xoxb-test", r.Header.Get("Authorization"))
+		assert.NoError(t, r.ParseForm())
+
+		standIn.mu.Lock()
+		defer standIn.mu.Unlock()
+		method := strings.TrimPrefix(r.URL.Path, "/")
+		standIn.calls = append(standIn.calls, method)
+
+		response := map[string]interface{}{"ok": true}
+		switch {
+		case standIn.failWith != "":
+			response = map[string]interface{}{"ok": false, "error": standIn.failWith}
+		case method == "users.list":
+			start := 0
+			if cursor := r.Form.Get("cursor"); cursor != "" {
+				start = len(cursor)
+			}
+			end := min(start+2, len(standIn.users))
+			response["members"] = standIn.users[start:end]
+			if end < len(standIn.users) {
+				response["response_metadata"] = map[string]string{"next_cursor": strings.Repeat("x", end)}
+			}
+		case method == "usergroups.users.update":
+			standIn.userGroups[r.Form.Get("usergroup")] = strings.Split(r.Form.Get("users"), ",")
+		default:
+			response = map[string]interface{}{"ok": false, "error": "unknown_method"}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(standIn.Close)
+	return standIn
+}
+
+// api returns a client for the stand-in
+func (s *slackAPIStandIn) api() *SlackAPI {
+	api := NewSlackAPI("xoxb-test")
+	api.baseURL = s.URL
+	return api
+}
+
+// slackTestUser returns a Slack user with the given username and display name
+func slackTestUser(id, name, displayName string) slackUser {
+	user := slackUser{ID: id, Name: name}
+	user.Profile.DisplayName = displayName
+	return user
+}
+
+func TestSlackAPIUserIDs(t *testing.T) {
+	ctx := context.Background()
+	standIn := newSlackAPIStandIn(t,
+		slackTestUser("U1", "alice", ""),
+		slackTestUser("U2", "bob.jones", "Bob"),
+		slackTestUser("U3", "carol", ""),
+		slackUser{ID: "U4", Name: "dana", Deleted: true},
+		slackUser{ID: "B1", Name: "deploybot", IsBot: true},
+	)
+	api := standIn.api()
+
+	// Handles match usernames and display names over all pages
+	ids, unknown, err := api.UserIDs(ctx, []string{"@alice", "bob", "Carol"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U1", "U2", "U3"}, ids)
+	assert.Empty(t, unknown)
+	assert.Equal(t, []string{"users.list", "users.list", "users.list"}, standIn.calls)
+
+	// Cached handles don't list the users again
+	ids, _, err = api.UserIDs(ctx, []string{"bob.jones", "alice"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U2", "U1"}, ids)
+	assert.Len(t, standIn.calls, 3)
+
+	// An unknown handle refreshes the cache, deleted users and bots are unknown
+	standIn.users = append(standIn.users, slackTestUser("U5", "erin", ""))
+	ids, unknown, err = api.UserIDs(ctx, []string{"erin", "dana", "deploybot"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"U5"}, ids)
+	assert.Equal(t, []string{"dana", "deploybot"}, unknown)
+	assert.Len(t, standIn.calls, 6)
+}
+
+func TestSlackAPIErrors(t *testing.T) {
+	ctx := context.Background()
+	standIn := newSlackAPIStandIn(t)
+	standIn.failWith = "invalid_auth"
+
+	_, _, err := standIn.api().UserIDs(ctx, []string{"alice"})
+	assert.EqualError(t, err, "failed to list slack users: slack users.list failed: invalid_auth")
+
+	err = standIn.api().SetUserGroupUsers(ctx, "S1", []string{"U1"})
+	assert.EqualError(t, err, "failed to update slack user group: slack usergroups.users.update failed: invalid_auth")
+}
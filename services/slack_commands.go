@@ -0,0 +1,382 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// slackRequestMaxAge is how old a signed Slack request may be, so a captured request can't be
+// replayed later
+const slackRequestMaxAge = 5 * time.Minute
+
+// slackUpcomingDays limits how far ahead the commands look for upcoming shifts
+const slackUpcomingDays = 90
+
+// slackMaxShifts is the number of upcoming shifts /eod me lists
+const slackMaxShifts = 5
+
+// Response types of slash command replies
+const (
+	SlackReplyEphemeral = "ephemeral"  // Only shown to the user who sent the command
+	SlackReplyInChannel = "in_channel" // Shown to everyone in the channel
+)
+
+// slackUsage explains the commands, for /eod help and commands that aren't understood
+const slackUsage = "Usage:\n" +
+	"• `/eod who` - who is on duty today\n" +
+	"• `/eod next` - who is on duty on the next working day\n" +
+	"• `/eod me` - your upcoming shifts\n" +
+	"• `/eod week` - this week's schedule\n" +
+	"• `/eod takeover 2026-11-03 @alice reason` - let someone take over the shift on a date"
+
+// SlackCommand is a slash command sent by a Slack user
+type SlackCommand struct {
+	Command  string // Like "/eod"
+	Text     string // Everything after the command, like "takeover 2026-11-03 @alice"
+	UserID   string
+	UserName string // Slack handle of the user who sent the command
+}
+
+// SlackReply is the reply to a slash command
+type SlackReply struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// SlackCommandService answers the slash commands of Slack users about the schedule of their team
+type SlackCommandService interface {
+	Handle(ctx context.Context, command *SlackCommand) (*SlackReply, error)
+}
+
+// slackCommandService implements SlackCommandService interface
+type slackCommandService struct {
+	teamsRepo repositories.TeamsRepository
+	teamRepo  repositories.TeamRepository
+	schedule  ScheduleService
+}
+
+// NewSlackCommandService creates a new slack command service
+func NewSlackCommandService(teamsRepo repositories.TeamsRepository, teamRepo repositories.TeamRepository, schedule ScheduleService) SlackCommandService {
+	return &slackCommandService{
+		teamsRepo: teamsRepo,
+		teamRepo:  teamRepo,
+		schedule:  schedule,
+	}
+}
+
+// VerifySlackSignature checks that a request was signed by Slack with the signing secret of the
+// app, and that it isn't older than a few minutes
+func VerifySlackSignature(signingSecret, timestamp, signature string, body []byte) error {
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid slack request timestamp: %q", timestamp)
+	}
+	age := timeNow().Sub(time.Unix(seconds, 0))
+	if age > slackRequestMaxAge || age < -slackRequestMaxAge {
+		return fmt.Errorf("slack request timestamp is too far off: %s", age.Round(time.Second))
+	}
+
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	expected := "v0=" + hex.EncodeToString(mac.Sum(nil))
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid slack request signature")
+	}
+	return nil
+}
+
+// Handle answers a slash command. The user has to be a team member with their Slack handle, the
+// command works on the team of that member. Problems with the command are explained in the reply,
+// errors are only returned when the schedule can't be read.
+func (s *slackCommandService) Handle(ctx context.Context, command *SlackCommand) (*SlackReply, error) {
+	fields := strings.Fields(command.Text)
+	if len(fields) == 0 || strings.EqualFold(fields[0], "help") {
+		return ephemeralReply(slackUsage), nil
+	}
+
+	team, member, err := s.findMember(ctx, command.UserName)
+	if err != nil {
+		return nil, err
+	}
+	if member == nil {
+		return ephemeralReply(fmt.Sprintf("No team member has the Slack handle @%s. Add it to your team member profile first.", normalizeSlackHandle(command.UserName))), nil
+	}
+
+	// Changes made through Slack are attributed to the Slack user
+	ctx = userctx.SetUserEmail(teamctx.SetTeam(ctx, team), "slack:"+normalizeSlackHandle(command.UserName))
+
+	switch strings.ToLower(fields[0]) {
+	case "who":
+		return s.who(ctx)
+	case "next":
+		return s.next(ctx)
+	case "me":
+		return s.me(ctx, member)
+	case "week":
+		return s.week(ctx)
+	case "takeover":
+		return s.takeover(ctx, member, fields[1:])
+	default:
+		return ephemeralReply(fmt.Sprintf("Unknown command `%s`.\n%s", fields[0], slackUsage)), nil
+	}
+}
+
+// who answers who is on duty today
+func (s *slackCommandService) who(ctx context.Context) (*SlackReply, error) {
+	today := truncateToDate(timeNow())
+	entries, err := s.schedule.GetScheduleByDateRange(ctx, today, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get today's schedule: %w", err)
+	}
+
+	text := announcementText("", entries)
+	if text == "" {
+		return ephemeralReply("Nobody is on duty today."), nil
+	}
+	return ephemeralReply(text), nil
+}
+
+// next answers who is on duty on the first working day after today
+func (s *slackCommandService) next(ctx context.Context) (*SlackReply, error) {
+	tomorrow := truncateToDate(timeNow()).AddDate(0, 0, 1)
+	entries, err := s.schedule.GetScheduleByDateRange(ctx, tomorrow, tomorrow.AddDate(0, 0, slackUpcomingDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the upcoming schedule: %w", err)
+	}
+
+	for _, day := range groupByDate(entries) {
+		if duties := dutiesText(day); duties != "" {
+			return ephemeralReply(fmt.Sprintf("Next EOD on %s: %s", slackDate(day[0].Date), duties)), nil
+		}
+	}
+	return ephemeralReply("Nobody is scheduled yet after today."), nil
+}
+
+// me lists the upcoming shifts of the user
+func (s *slackCommandService) me(ctx context.Context, member *models.TeamMember) (*SlackReply, error) {
+	today := truncateToDate(timeNow())
+	entries, err := s.schedule.GetScheduleByDateRange(ctx, today, today.AddDate(0, 0, slackUpcomingDays))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the upcoming schedule: %w", err)
+	}
+
+	var shifts []string
+	for _, entry := range entries {
+		if entry.TeamMemberID != member.ID || len(shifts) == slackMaxShifts {
+			continue
+		}
+		shift := "• " + slackDate(entry.Date)
+		if entry.Shift != "" {
+			shift += " " + entry.Shift
+		}
+		shift += " (" + hoursText(&entry) + ")"
+		switch {
+		case entry.IsOnCall():
+			shift += " on call"
+		case entry.IsBackup():
+			shift += " as backup"
+		}
+		shifts = append(shifts, shift)
+	}
+
+	if len(shifts) == 0 {
+		return ephemeralReply("You have no upcoming shifts."), nil
+	}
+	return ephemeralReply("Your upcoming shifts:\n" + strings.Join(shifts, "\n")), nil
+}
+
+// week lists who is on duty on the days of this week
+func (s *slackCommandService) week(ctx context.Context) (*SlackReply, error) {
+	today := truncateToDate(timeNow())
+	monday := today.AddDate(0, 0, -models.GetWeekdayNumber(today))
+	entries, err := s.schedule.GetScheduleByDateRange(ctx, monday, monday.AddDate(0, 0, 6))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get this week's schedule: %w", err)
+	}
+
+	var days []string
+	for _, day := range groupByDate(entries) {
+		if duties := dutiesText(day); duties != "" {
+			days = append(days, "• "+slackDate(day[0].Date)+": "+duties)
+		}
+	}
+
+	if len(days) == 0 {
+		return ephemeralReply("Nobody is scheduled this week."), nil
+	}
+	return ephemeralReply("This week's EOD:\n" + strings.Join(days, "\n")), nil
+}
+
+// takeover lets a member take over the shift on a date, like "2026-11-03 @alice reason". On a date
+// with several shifts it's the shift of the user.
+func (s *slackCommandService) takeover(ctx context.Context, member *models.TeamMember, args []string) (*SlackReply, error) {
+	if len(args) < 2 {
+		return ephemeralReply("Usage: `/eod takeover 2026-11-03 @alice reason`"), nil
+	}
+
+	date, err := models.ParseDate(args[0])
+	if err != nil {
+		return ephemeralReply(fmt.Sprintf("Invalid date `%s`, use YYYY-MM-DD.", args[0])), nil
+	}
+	if date.Before(truncateToDate(timeNow())) {
+		return ephemeralReply("Past shifts can't be taken over."), nil
+	}
+	reason := strings.Join(args[2:], " ")
+
+	members, err := s.teamRepo.GetActiveMembers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get team members: %w", err)
+	}
+	taker := findMemberByHandle(members, parseSlackMention(args[1]))
+	if taker == nil {
+		return ephemeralReply(fmt.Sprintf("No active team member has the Slack handle %s.", args[1])), nil
+	}
+
+	entries, err := s.schedule.GetScheduleByDateRange(ctx, date, date)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the schedule of %s: %w", models.FormatDate(date), err)
+	}
+	entry, problem := shiftToTakeOver(entries, member.ID, date)
+	if entry == nil {
+		return ephemeralReply(problem), nil
+	}
+	if entry.TeamMemberID == taker.ID {
+		return ephemeralReply(fmt.Sprintf("%s is already on duty on %s.", slackMention(entry), slackDate(date))), nil
+	}
+
+	form := &models.ScheduleEntryForm{
+		Date:         models.FormatDate(date),
+		TeamMemberID: taker.ID,
+		StartTime:    entry.StartTime,
+		EndTime:      entry.EndTime,
+	}
+	_, err = s.schedule.CreateManualOverride(ctx, entry.ID, form)
+	var violation *RuleViolationError
+	if errors.As(err, &violation) {
+		return ephemeralReply("The takeover breaks the assignment rules:\n• " + strings.Join(violation.Violations, "\n• ") +
+			"\nTake over the shift on the schedule page to confirm it anyway."), nil
+	}
+	if err != nil {
+		log.Printf("Slack takeover on %s failed: %v", models.FormatDate(date), err)
+		return ephemeralReply("The takeover failed: " + err.Error()), nil
+	}
+
+	text := fmt.Sprintf("%s takes over the EOD of %s (%s) from %s",
+		memberMention(taker.Name, taker.SlackHandle), slackDate(date), hoursText(entry), slackMention(entry))
+	if reason != "" {
+		text += ": " + reason
+	}
+	return &SlackReply{ResponseType: SlackReplyInChannel, Text: text}, nil
+}
+
+// shiftToTakeOver picks the primary working hours shift of a date to take over: the only one, or
+// the one of the given member on a date with several shifts. Otherwise it explains the problem.
+func shiftToTakeOver(entries []models.ScheduleEntry, memberID int, date time.Time) (*models.ScheduleEntry, string) {
+	var shifts []*models.ScheduleEntry
+	for i := range entries {
+		if !entries[i].IsBackup() && !entries[i].IsOnCall() {
+			shifts = append(shifts, &entries[i])
+		}
+	}
+
+	switch len(shifts) {
+	case 0:
+		return nil, "Nobody is on duty on " + slackDate(date) + "."
+	case 1:
+		return shifts[0], ""
+	}
+	for _, shift := range shifts {
+		if shift.TeamMemberID == memberID {
+			return shift, ""
+		}
+	}
+	return nil, slackDate(date) + " has several shifts, only the members on duty can hand over theirs."
+}
+
+// findMember finds the team member with a Slack handle, and their team. It returns nil when no
+// team has the member.
+func (s *slackCommandService) findMember(ctx context.Context, handle string) (*models.Team, *models.TeamMember, error) {
+	teams, err := s.teamsRepo.GetAll(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+
+	for i := range teams {
+		members, err := s.teamRepo.GetActiveMembers(teamctx.SetTeam(ctx, &teams[i]))
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get team members: %w", err)
+		}
+		if member := findMemberByHandle(members, handle); member != nil {
+			return &teams[i], member, nil
+		}
+	}
+	return nil, nil, nil
+}
+
+// findMemberByHandle returns the member with a Slack handle, ignoring case and a leading @, or nil
+func findMemberByHandle(members []models.TeamMember, handle string) *models.TeamMember {
+	handle = normalizeSlackHandle(handle)
+	if handle == "" {
+		return nil
+	}
+	for i := range members {
+		if strings.EqualFold(normalizeSlackHandle(members[i].SlackHandle), handle) {
+			return &members[i]
+		}
+	}
+	return nil
+}
+
+// normalizeSlackHandle returns a Slack handle without surrounding spaces and leading @
+func normalizeSlackHandle(handle string) string {
+	return strings.TrimPrefix(strings.TrimSpace(handle), "@")
+}
+
+// parseSlackMention returns the handle of a mention, either typed as @alice or escaped by Slack as
+// <@U024BE7LH|alice>
+func parseSlackMention(mention string) string {
+	if strings.HasPrefix(mention, "<@") && strings.HasSuffix(mention, ">") {
+		if _, handle, ok := strings.Cut(strings.TrimSuffix(mention, ">"), "|"); ok {
+			return handle
+		}
+	}
+	return mention
+}
+
+// groupByDate groups schedule entries by date, in date order
+func groupByDate(entries []models.ScheduleEntry) [][]models.ScheduleEntry {
+	var days [][]models.ScheduleEntry
+	for _, entry := range entries {
+		last := len(days) - 1
+		if last >= 0 && days[last][0].GetFormattedDate() == entry.GetFormattedDate() {
+			days[last] = append(days[last], entry)
+		} else {
+			days = append(days, []models.ScheduleEntry{entry})
+		}
+	}
+	return days
+}
+
+// slackDate formats a date for replies, like "Tue 2026-11-03"
+func slackDate(date time.Time) string {
+	return date.Format("Mon 2006-01-02")
+}
+
+// ephemeralReply returns a reply only the user who sent the command sees
+func ephemeralReply(text string) *SlackReply {
+	return &SlackReply{ResponseType: SlackReplyEphemeral, Text: text}
+}
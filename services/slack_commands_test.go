@@ -0,0 +1,321 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+	"github.com/blogem/eod-scheduler/teamctx"
+	"github.com/blogem/eod-scheduler/userctx"
+)
+
+// scheduleStub is a schedule service with a fixed schedule that records the manual overrides it
+// was asked for
+type scheduleStub struct {
+	ScheduleService
+	entries     []models.ScheduleEntry
+	overrideErr error
+	overrides   []string
+}
+
+func (s *scheduleStub) GetScheduleByDateRange(ctx context.Context, from, to time.Time) ([]models.ScheduleEntry, error) {
+	var entries []models.ScheduleEntry
+	for _, entry := range s.entries {
+		if !entry.Date.Before(from) && !entry.Date.After(to) {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (s *scheduleStub) CreateManualOverride(ctx context.Context, entryID int, form *models.ScheduleEntryForm) (*models.ScheduleEntry, error) {
+	s.overrides = append(s.overrides, teamctx.GetTeam(ctx).Slug+" entry "+strconv.Itoa(entryID)+" to member "+strconv.Itoa(form.TeamMemberID)+
+		" "+form.Date+" "+form.StartTime+"-"+form.EndTime+" by "+userctx.GetUserEmail(ctx))
+	return nil, s.overrideErr
+}
+
+// slackEntry builds a schedule entry held by a member of the platform team
+func slackEntry(id int, date string, member models.TeamMember, start, end string) models.ScheduleEntry {
+	entry := historyEntry(date, member.ID)
+	entry.ID = id
+	entry.StartTime = start
+	entry.EndTime = end
+	entry.TeamMemberName = member.Name
+	entry.TeamMemberSlackHandle = member.SlackHandle
+	return entry
+}
+
+// signSlackRequest signs a request body like Slack does
+func signSlackRequest(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifySlackSignature(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return time.Unix(1700000000, 0) }
+
+	body := []byte("command=%2Feod&text=who&user_name=alice")
+	timestamp := "1699999900"
+	signature := signSlackRequest("8f742231b10e8888abcd99yyyzzz85a5", timestamp, body)
+
+	testCases := []struct {
+		name          string
+		timestamp     string
+		signature     string
+		body          []byte
+		expectedError string
+	}{
+		{name: "signed by slack", timestamp: timestamp, signature: signature, body: body},
+		{
+			name:          "other secret",
+			timestamp:     timestamp,
+			signature:     signSlackRequest("another secret", timestamp, body),
+			body:          body,
+			expectedError: "invalid slack request signature",
+		},
+		{
+			name:          "changed body",
+			timestamp:     timestamp,
+			signature:     signature,
+			body:          []byte("command=%2Feod&text=takeover&user_name=alice"),
+			expectedError: "invalid slack request signature",
+		},
+		{
+			name:          "replayed later",
+			timestamp:     "1699999600",
+			signature:     signSlackRequest("8f742231b10e8888abcd99yyyzzz85a5", "1699999600", body),
+			body:          body,
+			expectedError: "slack request timestamp is too far off: 6m40s",
+		},
+		{
+			name:          "missing signature",
+			timestamp:     "",
+			body:          body,
+			expectedError: `invalid slack request timestamp: ""`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := VerifySlackSignature("8f742231b10e8888abcd99yyyzzz85a5", tc.timestamp, tc.signature, tc.body)
+			if tc.expectedError != "" {
+				assert.EqualError(t, err, tc.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSlackCommands(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday.Add(10 * time.Hour) }
+
+	alice := models.TeamMember{ID: 1, Name: "Alice", SlackHandle: "alice", Active: true}
+	bob := models.TeamMember{ID: 2, Name: "Bob", SlackHandle: "@Bob", Active: true}
+	carol := models.TeamMember{ID: 3, Name: "Carol", Active: true}
+	platform := models.Team{ID: 2, Name: "Platform", Slug: "platform"}
+
+	backup := slackEntry(4, "2023-10-03", carol, "09:00", "17:00")
+	backup.Role = models.ScheduleRoleBackup
+	onCall := slackEntry(5, "2023-10-06", alice, "17:00", "09:00")
+	onCall.Layer = models.ScheduleLayerOnCall
+	onCallEnd := time.Date(2023, 10, 9, 0, 0, 0, 0, time.UTC)
+	onCall.EndDate = &onCallEnd
+	morning := slackEntry(7, "2023-10-05", bob, "08:00", "13:00")
+	morning.Shift = "Morning"
+	afternoon := slackEntry(8, "2023-10-05", carol, "13:00", "18:00")
+	afternoon.Shift = "Afternoon"
+	entries := []models.ScheduleEntry{
+		slackEntry(1, "2023-09-29", alice, "09:00", "17:00"),
+		slackEntry(2, "2023-10-02", alice, "09:00", "17:00"),
+		slackEntry(3, "2023-10-03", bob, "09:00", "17:00"),
+		backup,
+		morning,
+		afternoon,
+		onCall,
+		slackEntry(6, "2023-10-10", alice, "09:00", "17:00"),
+	}
+
+	testCases := []struct {
+		name              string
+		user              string
+		text              string
+		overrideErr       error
+		expectedType      string
+		expectedText      string
+		expectedOverrides []string
+	}{
+		{
+			name:         "help",
+			user:         "alice",
+			text:         "help",
+			expectedType: SlackReplyEphemeral,
+			expectedText: slackUsage,
+		},
+		{
+			name:         "unknown user",
+			user:         "mallory",
+			text:         "who",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "No team member has the Slack handle @mallory. Add it to your team member profile first.",
+		},
+		{
+			name:         "unknown command",
+			user:         "alice",
+			text:         "swap",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Unknown command `swap`.\n" + slackUsage,
+		},
+		{
+			name:         "who",
+			user:         "alice",
+			text:         "who",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Today's EOD: @alice (09:00–17:00)",
+		},
+		{
+			name:         "next",
+			user:         "bob",
+			text:         "NEXT",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Next EOD on Tue 2023-10-03: @Bob (09:00–17:00)",
+		},
+		{
+			name:         "me",
+			user:         "alice",
+			text:         "me",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Your upcoming shifts:\n• Mon 2023-10-02 (09:00–17:00)\n• Fri 2023-10-06 (17:00–Mon 09:00) on call\n• Tue 2023-10-10 (09:00–17:00)",
+		},
+		{
+			name:         "member without a handle",
+			user:         "carol",
+			text:         "week",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "No team member has the Slack handle @carol. Add it to your team member profile first.",
+		},
+		{
+			name:         "week",
+			user:         "@Alice",
+			text:         "week",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "This week's EOD:\n• Mon 2023-10-02: @alice (09:00–17:00)\n• Tue 2023-10-03: @Bob (09:00–17:00)\n" +
+				"• Thu 2023-10-05: Morning @Bob (08:00–13:00), Afternoon Carol (13:00–18:00)",
+		},
+		{
+			name:              "takeover",
+			user:              "bob",
+			text:              "takeover 2023-10-03 @alice dentist appointment",
+			expectedType:      SlackReplyInChannel,
+			expectedText:      "@alice takes over the EOD of Tue 2023-10-03 (09:00–17:00) from @Bob: dentist appointment",
+			expectedOverrides: []string{"platform entry 3 to member 1 2023-10-03 09:00-17:00 by slack:bob"},
+		},
+		{
+			name:              "takeover with an escaped mention on a date with several shifts",
+			user:              "bob",
+			text:              "takeover 2023-10-05 <@U024BE7LH|alice>",
+			expectedType:      SlackReplyInChannel,
+			expectedText:      "@alice takes over the EOD of Thu 2023-10-05 (08:00–13:00) from @Bob",
+			expectedOverrides: []string{"platform entry 7 to member 1 2023-10-05 08:00-13:00 by slack:bob"},
+		},
+		{
+			name:         "takeover of someone else's shift on a date with several shifts",
+			user:         "alice",
+			text:         "takeover 2023-10-05 @alice",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Thu 2023-10-05 has several shifts, only the members on duty can hand over theirs.",
+		},
+		{
+			name:              "takeover breaking the assignment rules",
+			user:              "alice",
+			text:              "takeover 2023-10-03 alice",
+			overrideErr:       &RuleViolationError{Violations: []string{"Alice is on duty on Mon 2023-10-02"}},
+			expectedType:      SlackReplyEphemeral,
+			expectedText:      "The takeover breaks the assignment rules:\n• Alice is on duty on Mon 2023-10-02\nTake over the shift on the schedule page to confirm it anyway.",
+			expectedOverrides: []string{"platform entry 3 to member 1 2023-10-03 09:00-17:00 by slack:alice"},
+		},
+		{
+			name:         "takeover by the member on duty",
+			user:         "alice",
+			text:         "takeover 2023-10-02 @alice",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "@alice is already on duty on Mon 2023-10-02.",
+		},
+		{
+			name:         "takeover in the past",
+			user:         "alice",
+			text:         "takeover 2023-09-29 @bob",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Past shifts can't be taken over.",
+		},
+		{
+			name:         "takeover without duty",
+			user:         "alice",
+			text:         "takeover 2023-10-07 @bob",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Nobody is on duty on Sat 2023-10-07.",
+		},
+		{
+			name:         "takeover by someone outside the team",
+			user:         "alice",
+			text:         "takeover 2023-10-03 @mallory",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "No active team member has the Slack handle @mallory.",
+		},
+		{
+			name:         "takeover with an invalid date",
+			user:         "alice",
+			text:         "takeover 03-10-2023 @bob",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Invalid date `03-10-2023`, use YYYY-MM-DD.",
+		},
+		{
+			name:         "takeover without a member",
+			user:         "alice",
+			text:         "takeover 2023-10-03",
+			expectedType: SlackReplyEphemeral,
+			expectedText: "Usage: `/eod takeover 2026-11-03 @alice reason`",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockTeamsRepo := dbMocks.NewMockTeamsRepository(t)
+			mockTeamRepo := dbMocks.NewMockTeamRepository(t)
+			schedule := &scheduleStub{entries: entries, overrideErr: tc.overrideErr}
+
+			// The members with a Slack handle are in the platform team
+			mockTeamsRepo.EXPECT().GetAll(ctx).Return([]models.Team{{ID: 1, Name: "Default", Slug: "default"}, platform}, nil).Maybe()
+			mockTeamRepo.EXPECT().GetActiveMembers(mock.MatchedBy(func(ctx context.Context) bool {
+				return teamctx.GetTeamID(ctx) == 1
+			})).Return(nil, nil).Maybe()
+			mockTeamRepo.EXPECT().GetActiveMembers(mock.MatchedBy(func(ctx context.Context) bool {
+				return teamctx.GetTeamID(ctx) == platform.ID
+			})).Return([]models.TeamMember{alice, bob, carol}, nil).Maybe()
+
+			service := NewSlackCommandService(mockTeamsRepo, mockTeamRepo, schedule)
+			reply, err := service.Handle(ctx, &SlackCommand{Command: "/eod", Text: tc.text, UserName: tc.user})
+
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedType, reply.ResponseType)
+			assert.Equal(t, tc.expectedText, reply.Text)
+			assert.Equal(t, tc.expectedOverrides, schedule.overrides)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// userGroupRetryDelay is the delay before a failed user group sync is tried again
+const userGroupRetryDelay = 5 * time.Minute
+
+// UserGroupSync keeps the members of a Slack user group, like @eod, set to the members on duty.
+// It syncs at the start of every shift, so alerts pinging the group reach whoever is on duty.
+type UserGroupSync struct {
+	teamsRepo    repositories.TeamsRepository
+	scheduleRepo repositories.ScheduleRepository
+	slack        *SlackAPI
+	userGroupID  string
+}
+
+// NewUserGroupSync creates the sync of the given user group
+func NewUserGroupSync(teamsRepo repositories.TeamsRepository, scheduleRepo repositories.ScheduleRepository, slack *SlackAPI, userGroupID string) *UserGroupSync {
+	return &UserGroupSync{
+		teamsRepo:    teamsRepo,
+		scheduleRepo: scheduleRepo,
+		slack:        slack,
+		userGroupID:  userGroupID,
+	}
+}
+
+// Start syncs the user group right away and at every shift start after that, until the context is done
+func (s *UserGroupSync) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(s.Run(ctx)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// Run sets the members of the user group to the primary members on duty in every team, and
+// returns when it has to run again: at the next shift start, at midnight when no shift starts
+// before then, or soon when the sync failed. Outside the shifts the group keeps its members, as
+// Slack doesn't allow a user group without members.
+func (s *UserGroupSync) Run(ctx context.Context) time.Time {
+	now := timeNow()
+	retry := now.Add(userGroupRetryDelay)
+
+	teams, err := s.teamsRepo.GetAll(ctx)
+	if err != nil {
+		log.Printf("User group sync failed to get the teams: %v", err)
+		return retry
+	}
+
+	// The on-call block under way can have started a week ago
+	today := truncateToDate(now)
+	next := nextNightlyRun(now, 0)
+	var handles []string
+	for i := range teams {
+		team := &teams[i]
+		entries, err := s.scheduleRepo.GetByDateRange(teamctx.SetTeam(ctx, team), today.AddDate(0, 0, -maxOnCallBlockDays), today)
+		if err != nil {
+			log.Printf("User group sync failed to get the schedule of team %s: %v", team.Slug, err)
+			return retry
+		}
+
+		for _, entry := range entries {
+			if start := entry.StartsAt(now.Location()); start.After(now) && start.Before(next) {
+				next = start
+			}
+			if entry.IsBackup() || !entry.IsOnDutyAt(now) {
+				continue
+			}
+			if entry.TeamMemberSlackHandle == "" {
+				log.Printf("User group sync skipped %s of team %s, who has no Slack handle", entry.TeamMemberName, team.Slug)
+				continue
+			}
+			handles = append(handles, entry.TeamMemberSlackHandle)
+		}
+	}
+
+	if err := s.sync(ctx, handles); err != nil {
+		log.Printf("User group sync failed: %v", err)
+		return retry
+	}
+	return next
+}
+
+// sync sets the members of the user group to the users with the given handles
+func (s *UserGroupSync) sync(ctx context.Context, handles []string) error {
+	userIDs, unknown, err := s.slack.UserIDs(ctx, handles)
+	if err != nil {
+		return err
+	}
+	for _, handle := range unknown {
+		log.Printf("User group sync skipped @%s, who isn't a Slack user", normalizeSlackHandle(handle))
+	}
+
+	userIDs = uniqueStrings(userIDs)
+	if len(userIDs) == 0 {
+		log.Printf("User group sync found nobody on duty, the user group keeps its members")
+		return nil
+	}
+	return s.slack.SetUserGroupUsers(ctx, s.userGroupID, userIDs)
+}
+
+// uniqueStrings returns the strings without duplicates, in their original order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool)
+	var unique []string
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
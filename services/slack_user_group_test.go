@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+func TestUserGroupSyncRun(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	friday := testMonday.AddDate(0, 0, -3)
+	tuesday := testMonday.AddDate(0, 0, 1)
+	teamEntries := map[int][]models.ScheduleEntry{
+		1: {
+			{Date: friday, StartTime: "17:00", EndTime: "09:00", EndDate: &testMonday, Layer: models.ScheduleLayerOnCall, TeamMemberName: "Carol", TeamMemberSlackHandle: "carol"},
+			{Date: testMonday, StartTime: "09:00", EndTime: "17:00", TeamMemberName: "Alice", TeamMemberSlackHandle: "alice"},
+			{Date: testMonday, StartTime: "09:00", EndTime: "17:00", Role: models.ScheduleRoleBackup, TeamMemberName: "Bob", TeamMemberSlackHandle: "bob"},
+			{Date: testMonday, StartTime: "17:00", EndTime: "09:00", EndDate: &tuesday, Layer: models.ScheduleLayerOnCall, TeamMemberName: "Frank", TeamMemberSlackHandle: "frank"},
+		},
+		2: {
+			{Date: testMonday, Shift: "Morning", StartTime: "08:00", EndTime: "13:00", TeamMemberName: "Erin", TeamMemberSlackHandle: "@erin"},
+			{Date: testMonday, Shift: "Afternoon", StartTime: "13:00", EndTime: "18:00", TeamMemberName: "Dana"},
+		},
+	}
+
+	testCases := []struct {
+		name          string
+		now           time.Duration // Time of day on the test Monday
+		entries       map[int][]models.ScheduleEntry
+		failWith      string
+		expectedUsers []string // Members of the user group after the sync, nil when it wasn't updated
+		expectedNext  time.Time
+	}{
+		{
+			name:          "weekend on-call block until the first shift",
+			now:           7 * time.Hour,
+			entries:       teamEntries,
+			expectedUsers: []string{"U3"},
+			expectedNext:  testMonday.Add(8 * time.Hour),
+		},
+		{
+			name:          "members on duty in every team",
+			now:           10 * time.Hour,
+			entries:       teamEntries,
+			expectedUsers: []string{"U1", "U5"},
+			expectedNext:  testMonday.Add(13 * time.Hour),
+		},
+		{
+			name:          "members without a Slack handle are skipped",
+			now:           13*time.Hour + 30*time.Minute,
+			entries:       teamEntries,
+			expectedUsers: []string{"U1"},
+			expectedNext:  testMonday.Add(17 * time.Hour),
+		},
+		{
+			name:          "no shift starts before midnight",
+			now:           18 * time.Hour,
+			entries:       teamEntries,
+			expectedUsers: []string{"U6"},
+			expectedNext:  tuesday,
+		},
+		{
+			name:         "nobody on duty keeps the members",
+			now:          13*time.Hour + 30*time.Minute,
+			entries:      map[int][]models.ScheduleEntry{2: teamEntries[2][1:]},
+			expectedNext: tuesday,
+		},
+		{
+			name:         "failed syncs are retried",
+			now:          10 * time.Hour,
+			entries:      teamEntries,
+			failWith:     "invalid_auth",
+			expectedNext: testMonday.Add(10*time.Hour + userGroupRetryDelay),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			now := testMonday.Add(tc.now)
+			timeNow = func() time.Time { return now }
+
+			ctx := context.Background()
+			mockTeamsRepo := dbMocks.NewMockTeamsRepository(t)
+			mockScheduleRepo := dbMocks.NewMockScheduleRepository(t)
+			standIn := newSlackAPIStandIn(t,
+				slackTestUser("U1", "alice", ""),
+				slackTestUser("U2", "bob", ""),
+				slackTestUser("U3", "carol", ""),
+				slackTestUser("U5", "erin", ""),
+				slackTestUser("U6", "frank", ""),
+			)
+			standIn.failWith = tc.failWith
+
+			teams := []models.Team{{ID: 1, Name: "Default", Slug: "default"}, {ID: 2, Name: "Platform", Slug: "platform"}}
+			mockTeamsRepo.EXPECT().GetAll(ctx).Return(teams, nil)
+			for _, team := range teams {
+				onTeam := mock.MatchedBy(func(ctx context.Context) bool { return teamctx.GetTeamID(ctx) == team.ID })
+				mockScheduleRepo.EXPECT().GetByDateRange(onTeam, testMonday.AddDate(0, 0, -maxOnCallBlockDays), testMonday).
+					Return(tc.entries[team.ID], nil)
+			}
+
+			next := NewUserGroupSync(mockTeamsRepo, mockScheduleRepo, standIn.api(), "S1").Run(ctx)
+
+			assert.Equal(t, tc.expectedNext, next)
+			assert.Equal(t, tc.expectedUsers, standIn.userGroups["S1"])
+		})
+	}
+}
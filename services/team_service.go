@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"fmt"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/blogem/eod-scheduler/models"
 	"github.com/blogem/eod-scheduler/repositories"
@@ -21,19 +23,38 @@ type TeamService interface {
 	ActivateMember(ctx context.Context, id int) error
 	GetMemberCount(ctx context.Context) (int, error)
 	ValidateDeleteMember(ctx context.Context, id int) error
+	GetRotationOrder(ctx context.Context) (*RotationOrder, error)
+	MoveMember(ctx context.Context, id int, offset int) error
+}
+
+// RotationOrder represents the active members in the order they take turns
+type RotationOrder struct {
+	Members      []models.TeamMember `json:"members"`
+	NextMemberID int                 `json:"next_member_id"` // First member on duty from the From date
+	From         time.Time           `json:"from"`           // First date that follows the current queue
 }
 
 // teamService implements TeamService interface
 type teamService struct {
 	teamRepo     repositories.TeamRepository
 	scheduleRepo repositories.ScheduleRepository
+	webhooks     WebhookPublisher
+	rotation     *rotationQueue
 }
 
 // NewTeamService creates a new team service
-func NewTeamService(teamRepo repositories.TeamRepository, scheduleRepo repositories.ScheduleRepository) TeamService {
+func NewTeamService(
+	teamRepo repositories.TeamRepository,
+	scheduleRepo repositories.ScheduleRepository,
+	workingHoursRepo repositories.WorkingHoursRepository,
+	holidayRepo repositories.HolidayRepository,
+	webhooks WebhookPublisher,
+) TeamService {
 	return &teamService{
 		teamRepo:     teamRepo,
 		scheduleRepo: scheduleRepo,
+		webhooks:     webhooks,
+		rotation:     newRotationQueue(scheduleRepo, teamRepo, workingHoursRepo, holidayRepo),
 	}
 }
 
@@ -72,15 +93,25 @@ func (s *teamService) CreateMember(ctx context.Context, form *models.TeamMemberF
 
 	// Create new member
 	member := &models.TeamMember{
-		Name:        strings.TrimSpace(form.Name),
-		SlackHandle: strings.TrimSpace(form.SlackHandle),
-		Active:      form.Active,
+		Name:               strings.TrimSpace(form.Name),
+		SlackHandle:        strings.TrimSpace(form.SlackHandle),
+		Email:              strings.TrimSpace(form.Email),
+		Active:             form.Active,
+		Participation:      form.GetParticipation(),
+		WorkDays:           form.GetWorkDays(),
+		Preferences:        form.GetPreferences(),
+		MutedNotifications: form.MutedNotifications,
 	}
 
 	if err := s.teamRepo.Create(ctx, member); err != nil {
 		return nil, fmt.Errorf("failed to create team member: %w", err)
 	}
 
+	if member.Active {
+		s.syncRotation(ctx)
+		s.publishMember(ctx, member)
+	}
+
 	return member, nil
 }
 
@@ -110,14 +141,25 @@ func (s *teamService) UpdateMember(ctx context.Context, id int, form *models.Tea
 	}
 
 	// Update member fields
+	wasActive := member.Active
 	member.Name = strings.TrimSpace(form.Name)
 	member.SlackHandle = strings.TrimSpace(form.SlackHandle)
+	member.Email = strings.TrimSpace(form.Email)
 	member.Active = form.Active
+	member.Participation = form.GetParticipation()
+	member.WorkDays = form.GetWorkDays()
+	member.Preferences = form.GetPreferences()
+	member.MutedNotifications = form.MutedNotifications
 
 	if err := s.teamRepo.Update(ctx, member); err != nil {
 		return nil, fmt.Errorf("failed to update team member: %w", err)
 	}
 
+	if member.Active != wasActive {
+		s.syncRotation(ctx)
+		s.publishMember(ctx, member)
+	}
+
 	return member, nil
 }
 
@@ -136,6 +178,7 @@ func (s *teamService) DeleteMember(ctx context.Context, id int) error {
 		return fmt.Errorf("failed to delete team member: %w", err)
 	}
 
+	s.syncRotation(ctx)
 	return nil
 }
 
@@ -159,6 +202,8 @@ func (s *teamService) DeactivateMember(ctx context.Context, id int) error {
 		return fmt.Errorf("failed to deactivate team member: %w", err)
 	}
 
+	s.syncRotation(ctx)
+	s.publishMember(ctx, member)
 	return nil
 }
 
@@ -182,6 +227,8 @@ func (s *teamService) ActivateMember(ctx context.Context, id int) error {
 		return fmt.Errorf("failed to activate team member: %w", err)
 	}
 
+	s.syncRotation(ctx)
+	s.publishMember(ctx, member)
 	return nil
 }
 
@@ -229,6 +276,103 @@ func (s *teamService) ValidateDeleteMember(ctx context.Context, id int) error {
 	return nil
 }
 
+// GetRotationOrder retrieves the active members in rotation queue order
+func (s *teamService) GetRotationOrder(ctx context.Context) (*RotationOrder, error) {
+	state, activeMembers, err := s.loadRotation(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order := &RotationOrder{
+		Members: orderByQueue(activeMembers, state.RotationQueue),
+		From:    state.RotationCursorDate,
+	}
+	if len(state.RotationQueue) > 0 {
+		order.NextMemberID = state.RotationQueue[state.RotationCursor]
+	}
+
+	return order, nil
+}
+
+// MoveMember moves an active member up (negative offset) or down the rotation queue
+func (s *teamService) MoveMember(ctx context.Context, id int, offset int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid team member ID: %d", id)
+	}
+
+	member, err := s.teamRepo.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("team member not found: %w", err)
+	}
+
+	if !member.Active {
+		return fmt.Errorf("only active team members are part of the rotation")
+	}
+
+	return s.updateRotation(ctx, func(state *models.ScheduleState) {
+		if position := state.QueuePosition(id); position >= 0 {
+			state.MoveInQueue(id, max(0, min(position+offset, len(state.RotationQueue)-1)))
+		}
+	})
+}
+
+// publishMember tells the webhooks a member joined or left the rotation
+func (s *teamService) publishMember(ctx context.Context, member *models.TeamMember) {
+	event := models.WebhookEventMemberDeactivated
+	if member.Active {
+		event = models.WebhookEventMemberActivated
+	}
+	s.webhooks.Publish(ctx, event, member)
+}
+
+// syncRotation splices a roster change into the rotation queue: newly active members join at the
+// end, inactive and deleted members leave. Failures are only logged, the generator syncs the queue
+// with the active members as well.
+func (s *teamService) syncRotation(ctx context.Context) {
+	if err := s.updateRotation(ctx, func(*models.ScheduleState) {}); err != nil {
+		log.Printf("Failed to update rotation queue: %v", err)
+	}
+}
+
+// updateRotation applies a change to the rotation queue and saves it together with the cursor
+func (s *teamService) updateRotation(ctx context.Context, change func(state *models.ScheduleState)) error {
+	state, _, err := s.loadRotation(ctx)
+	if err != nil {
+		return err
+	}
+
+	change(state)
+
+	if err := s.scheduleRepo.UpdateState(ctx, state); err != nil {
+		return fmt.Errorf("failed to update rotation queue: %w", err)
+	}
+
+	return nil
+}
+
+// loadRotation retrieves the schedule state with the cursor moved past everything already published,
+// so queue changes only affect dates that are still to be generated. The queue is brought in line
+// with the returned active members first.
+func (s *teamService) loadRotation(ctx context.Context) (*models.ScheduleState, []models.TeamMember, error) {
+	startDate, err := generationStartDate(ctx, s.scheduleRepo)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	state, err := s.rotation.load(ctx, startDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activeMembers, err := s.teamRepo.GetActiveMembers(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get active team members: %w", err)
+	}
+	s.rotation.sync(state, activeMembers)
+
+	return state, activeMembers, nil
+}
+
 // findMemberBySlackHandle finds a team member by slack handle (helper function)
 func (s *teamService) findMemberBySlackHandle(ctx context.Context, slackHandle string) (*models.TeamMember, error) {
 	if slackHandle == "" {
@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+// TeamServiceTestSuite is a test suite for the rotation queue hooks of the team service
+type TeamServiceTestSuite struct {
+	suite.Suite
+	service          TeamService
+	mockScheduleRepo *dbMocks.MockScheduleRepository
+	mockTeamRepo     *dbMocks.MockTeamRepository
+	mockWorkingRepo  *dbMocks.MockWorkingHoursRepository
+	mockHolidayRepo  *dbMocks.MockHolidayRepository
+	webhooks         *webhookRecorder
+	originalTimeNow  func() time.Time
+}
+
+// SetupTest sets up the test suite before each test
+func (suite *TeamServiceTestSuite) SetupTest() {
+	suite.mockScheduleRepo = dbMocks.NewMockScheduleRepository(suite.T())
+	suite.mockTeamRepo = dbMocks.NewMockTeamRepository(suite.T())
+	suite.mockWorkingRepo = dbMocks.NewMockWorkingHoursRepository(suite.T())
+	suite.mockHolidayRepo = dbMocks.NewMockHolidayRepository(suite.T())
+	suite.webhooks = &webhookRecorder{}
+
+	suite.service = NewTeamService(
+		suite.mockTeamRepo,
+		suite.mockScheduleRepo,
+		suite.mockWorkingRepo,
+		suite.mockHolidayRepo,
+		suite.webhooks,
+	)
+}
+
+// SetupSuite pins the clock to a Monday to make the tests deterministic
+func (suite *TeamServiceTestSuite) SetupSuite() {
+	suite.originalTimeNow = timeNow
+	timeNow = func() time.Time { return testMonday }
+}
+
+// TearDownSuite restores the clock
+func (suite *TeamServiceTestSuite) TearDownSuite() {
+	timeNow = suite.originalTimeNow
+}
+
+// publishedState returns a state with Bob next in line once the published weeks are over
+func publishedState() *models.ScheduleState {
+	return &models.ScheduleState{
+		TeamID:             1,
+		RotationStrategy:   models.RotationStrategyRoundRobin,
+		RotationQueue:      []int{1, 2, 3},
+		RotationCursor:     1,
+		RotationCursorDate: testMonday.AddDate(0, 3, 0),
+	}
+}
+
+// expectQueueUpdate sets up loading the rotation queue with the given active members
+// and expects it to be saved with the given queue and cursor
+func (suite *TeamServiceTestSuite) expectQueueUpdate(ctx context.Context, activeMembers []models.TeamMember, queue []int, cursor int) {
+	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, testMonday).Return([]models.ScheduleEntry{historyEntry("2023-10-02", 1)}, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(publishedState(), nil)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(activeMembers, nil).Once()
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.MatchedBy(func(state *models.ScheduleState) bool {
+		// The cursor date stays after the published weeks, so they keep their assignments
+		return assert.Equal(suite.T(), queue, state.RotationQueue) &&
+			assert.Equal(suite.T(), cursor, state.RotationCursor) &&
+			assert.Equal(suite.T(), testMonday.AddDate(0, 3, 0), state.RotationCursorDate)
+	})).Return(nil)
+}
+
+// TestCreateMember_JoinsRotationAtTheEnd tests that a new active member is added to the end of the queue
+func (suite *TeamServiceTestSuite) TestCreateMember_JoinsRotationAtTheEnd() {
+	ctx := context.Background()
+	dana := models.TeamMember{ID: 4, Name: "Dana", Active: true}
+
+	suite.mockTeamRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.TeamMember")).RunAndReturn(
+		func(ctx context.Context, member *models.TeamMember) error {
+			member.ID = dana.ID
+			return nil
+		},
+	)
+	suite.expectQueueUpdate(ctx, append(append([]models.TeamMember{}, threeMembers...), dana), []int{1, 2, 3, 4}, 1)
+
+	member, err := suite.service.CreateMember(ctx, &models.TeamMemberForm{Name: "Dana", Active: true})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 4, member.ID)
+	assert.Equal(suite.T(), []string{models.WebhookEventMemberActivated}, suite.webhooks.events())
+}
+
+// TestCreateMember_InactiveLeavesRotationAlone tests that an inactive member doesn't touch the queue
+func (suite *TeamServiceTestSuite) TestCreateMember_InactiveLeavesRotationAlone() {
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().Create(ctx, mock.AnythingOfType("*models.TeamMember")).Return(nil)
+
+	_, err := suite.service.CreateMember(ctx, &models.TeamMemberForm{Name: "Dana", Active: false})
+
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), suite.webhooks.events())
+}
+
+// TestActivateMember_JoinsRotation tests that a reactivated member rejoins at the end of the queue
+func (suite *TeamServiceTestSuite) TestActivateMember_JoinsRotation() {
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&models.TeamMember{ID: 2, Name: "Bob", Active: false}, nil)
+	suite.mockTeamRepo.EXPECT().Update(ctx, mock.MatchedBy(func(member *models.TeamMember) bool {
+		return member.ID == 2 && member.Active
+	})).Return(nil)
+
+	// Bob was taken out earlier, Charlie is next
+	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, testMonday).Return(nil, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{
+		RotationQueue:      []int{1, 3},
+		RotationCursor:     1,
+		RotationCursorDate: testMonday.AddDate(0, 3, 0),
+	}, nil)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.MatchedBy(func(state *models.ScheduleState) bool {
+		return assert.Equal(suite.T(), []int{1, 3, 2}, state.RotationQueue) && assert.Equal(suite.T(), 1, state.RotationCursor)
+	})).Return(nil)
+
+	assert.NoError(suite.T(), suite.service.ActivateMember(ctx, 2))
+	assert.Equal(suite.T(), []string{models.WebhookEventMemberActivated}, suite.webhooks.events())
+}
+
+// TestDeactivateMember_LeavesRotation tests that a deactivated member is taken out of the queue
+// and whoever followed them becomes next
+func (suite *TeamServiceTestSuite) TestDeactivateMember_LeavesRotation() {
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&models.TeamMember{ID: 2, Name: "Bob", Active: true}, nil)
+	suite.mockTeamRepo.EXPECT().Update(ctx, mock.MatchedBy(func(member *models.TeamMember) bool {
+		return member.ID == 2 && !member.Active
+	})).Return(nil)
+	suite.expectQueueUpdate(ctx, []models.TeamMember{threeMembers[0], threeMembers[2]}, []int{1, 3}, 1)
+
+	assert.NoError(suite.T(), suite.service.DeactivateMember(ctx, 2))
+	if assert.Len(suite.T(), suite.webhooks.published, 1) {
+		assert.Equal(suite.T(), models.WebhookEventMemberDeactivated, suite.webhooks.published[0].event)
+		assert.Equal(suite.T(), 2, suite.webhooks.published[0].data.(*models.TeamMember).ID)
+	}
+}
+
+// TestDeactivateMember_QueueFailureIsOnlyLogged tests that a failing queue update doesn't fail the roster change
+func (suite *TeamServiceTestSuite) TestDeactivateMember_QueueFailureIsOnlyLogged() {
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&models.TeamMember{ID: 2, Name: "Bob", Active: true}, nil)
+	suite.mockTeamRepo.EXPECT().Update(ctx, mock.AnythingOfType("*models.TeamMember")).Return(nil)
+	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, testMonday).Return(nil, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(nil, errors.New("state error"))
+
+	assert.NoError(suite.T(), suite.service.DeactivateMember(ctx, 2))
+}
+
+// TestDeleteMember_LeavesRotation tests that a deleted member is taken out of the queue
+func (suite *TeamServiceTestSuite) TestDeleteMember_LeavesRotation() {
+	ctx := context.Background()
+
+	// Validation
+	suite.mockTeamRepo.EXPECT().GetByID(ctx, 3).Return(&models.TeamMember{ID: 3, Name: "Charlie", Active: true}, nil)
+	suite.mockScheduleRepo.EXPECT().HasFutureEntries(ctx, 3).Return(false, nil)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil).Once()
+
+	suite.mockTeamRepo.EXPECT().Delete(ctx, 3).Return(nil)
+	suite.expectQueueUpdate(ctx, threeMembers[:2], []int{1, 2}, 1)
+
+	assert.NoError(suite.T(), suite.service.DeleteMember(ctx, 3))
+}
+
+// TestFirstQueueChange_SeedsFromEpochRotation tests that the queue is seeded from the active members
+// on first use, continuing where the epoch based rotation left off
+func (suite *TeamServiceTestSuite) TestFirstQueueChange_SeedsFromEpochRotation() {
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&models.TeamMember{ID: 2, Name: "Bob", Active: false}, nil)
+	suite.mockTeamRepo.EXPECT().Update(ctx, mock.AnythingOfType("*models.TeamMember")).Return(nil)
+	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, testMonday).Return(nil, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{TeamID: 1}, nil)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(append(append([]models.TeamMember{}, threeMembers...), models.TeamMember{ID: 4, Name: "Dana", Active: true}), nil)
+	suite.mockWorkingRepo.EXPECT().GetActiveDays(ctx).Return(weekdaysMonToFri(), nil)
+	suite.mockHolidayRepo.EXPECT().GetAll(ctx).Return(nil, nil)
+	suite.mockWorkingRepo.EXPECT().GetExceptions(ctx).Return(nil, nil)
+	suite.mockScheduleRepo.EXPECT().UpdateState(ctx, mock.MatchedBy(func(state *models.ScheduleState) bool {
+		// 6195 working days since the epoch on 2023-10-02, so the epoch rotation had Dana on duty
+		return assert.Equal(suite.T(), []int{1, 2, 3, 4}, state.RotationQueue) &&
+			assert.Equal(suite.T(), 3, state.RotationCursor) &&
+			assert.Equal(suite.T(), testMonday, state.RotationCursorDate)
+	})).Return(nil)
+
+	assert.NoError(suite.T(), suite.service.ActivateMember(ctx, 2))
+}
+
+// TestMoveMember tests moving members up and down the rotation queue
+func (suite *TeamServiceTestSuite) TestMoveMember() {
+	testCases := []struct {
+		name           string
+		id             int
+		offset         int
+		expectedQueue  []int
+		expectedCursor int
+	}{
+		{"move up", 3, -1, []int{1, 3, 2}, 2},
+		{"next member keeps their turn", 2, -1, []int{2, 1, 3}, 0},
+		{"top stays on top", 1, -1, []int{1, 2, 3}, 1},
+		{"bottom stays at the bottom", 3, 1, []int{1, 2, 3}, 1},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+			ctx := context.Background()
+
+			suite.mockTeamRepo.EXPECT().GetByID(ctx, tc.id).Return(&models.TeamMember{ID: tc.id, Active: true}, nil)
+			suite.expectQueueUpdate(ctx, threeMembers, tc.expectedQueue, tc.expectedCursor)
+
+			assert.NoError(suite.T(), suite.service.MoveMember(ctx, tc.id, tc.offset))
+		})
+	}
+}
+
+// TestMoveMember_InactiveMember tests that inactive members can't be moved
+func (suite *TeamServiceTestSuite) TestMoveMember_InactiveMember() {
+	ctx := context.Background()
+
+	suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&models.TeamMember{ID: 2, Active: false}, nil)
+
+	err := suite.service.MoveMember(ctx, 2, -1)
+
+	assert.ErrorContains(suite.T(), err, "only active team members")
+}
+
+// TestGetRotationOrder tests that the members are returned in queue order with the next member
+func (suite *TeamServiceTestSuite) TestGetRotationOrder() {
+	ctx := context.Background()
+
+	suite.mockScheduleRepo.EXPECT().GetByDate(ctx, testMonday).Return(nil, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{
+		RotationQueue:      []int{3, 1, 2},
+		RotationCursor:     1,
+		RotationCursorDate: testMonday.AddDate(0, 3, 0),
+	}, nil)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+
+	order, err := suite.service.GetRotationOrder(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), []int{3, 1, 2}, memberIDs(order.Members))
+	assert.Equal(suite.T(), 1, order.NextMemberID)
+	assert.Equal(suite.T(), testMonday.AddDate(0, 3, 0), order.From)
+}
+
+func TestRunTeamServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamServiceTestSuite))
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// TeamsService interface defines business logic for the teams sharing the deployment
+type TeamsService interface {
+	GetAllTeams(ctx context.Context) ([]models.Team, error)
+	GetTeamBySlug(ctx context.Context, slug string) (*models.Team, error)
+	CreateTeam(ctx context.Context, form *models.TeamForm) (*models.Team, error)
+	UpdateTeam(ctx context.Context, id int, form *models.TeamForm) (*models.Team, error)
+}
+
+// teamsService implements TeamsService interface
+type teamsService struct {
+	teamsRepo        repositories.TeamsRepository
+	workingHoursRepo repositories.WorkingHoursRepository
+}
+
+// NewTeamsService creates a new teams service
+func NewTeamsService(teamsRepo repositories.TeamsRepository, workingHoursRepo repositories.WorkingHoursRepository) TeamsService {
+	return &teamsService{
+		teamsRepo:        teamsRepo,
+		workingHoursRepo: workingHoursRepo,
+	}
+}
+
+// GetAllTeams retrieves all teams
+func (s *teamsService) GetAllTeams(ctx context.Context) ([]models.Team, error) {
+	return s.teamsRepo.GetAll(ctx)
+}
+
+// GetTeamBySlug retrieves a team by the slug used in its URLs
+func (s *teamsService) GetTeamBySlug(ctx context.Context, slug string) (*models.Team, error) {
+	return s.teamsRepo.GetBySlug(ctx, slug)
+}
+
+// CreateTeam creates a new team. The team starts with the same working hours a new deployment
+// starts with: Monday to Friday, 09:00 to 17:00.
+func (s *teamsService) CreateTeam(ctx context.Context, form *models.TeamForm) (*models.Team, error) {
+	team, err := s.newTeamFromForm(ctx, 0, form)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.teamsRepo.Create(ctx, team); err != nil {
+		return nil, fmt.Errorf("failed to create team: %w", err)
+	}
+
+	teamCtx := teamctx.SetTeam(ctx, team)
+	for day := 0; day < 7; day++ {
+		shift := models.WorkingHours{StartTime: "09:00", EndTime: "17:00", Active: day < 5}
+		if !shift.Active {
+			shift.StartTime, shift.EndTime = "00:00", "00:00"
+		}
+		if err := s.workingHoursRepo.ReplaceDay(teamCtx, time.Time{}, day, []models.WorkingHours{shift}); err != nil {
+			return nil, fmt.Errorf("failed to create working hours of team: %w", err)
+		}
+	}
+
+	return team, nil
+}
+
+// UpdateTeam renames a team
+func (s *teamsService) UpdateTeam(ctx context.Context, id int, form *models.TeamForm) (*models.Team, error) {
+	if _, err := s.teamsRepo.GetByID(ctx, id); err != nil {
+		return nil, fmt.Errorf("team not found: %w", err)
+	}
+
+	team, err := s.newTeamFromForm(ctx, id, form)
+	if err != nil {
+		return nil, err
+	}
+	team.ID = id
+
+	if err := s.teamsRepo.Update(ctx, team); err != nil {
+		return nil, fmt.Errorf("failed to update team: %w", err)
+	}
+
+	return team, nil
+}
+
+// newTeamFromForm validates the form and converts it into a team. The slug may not be in use by
+// another team than the one with the given ID.
+func (s *teamsService) newTeamFromForm(ctx context.Context, id int, form *models.TeamForm) (*models.Team, error) {
+	if errors := form.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+	}
+
+	team := &models.Team{
+		Name: strings.TrimSpace(form.Name),
+		Slug: form.GetSlug(),
+	}
+
+	teams, err := s.teamsRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get teams: %w", err)
+	}
+	for _, other := range teams {
+		if other.ID != id && other.Slug == team.Slug {
+			return nil, fmt.Errorf("the URL name %q is already used by team %s", team.Slug, other.Name)
+		}
+	}
+
+	return team, nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// TeamsServiceTestSuite is a test suite for the teams service
+type TeamsServiceTestSuite struct {
+	suite.Suite
+	service         TeamsService
+	mockTeamsRepo   *dbMocks.MockTeamsRepository
+	mockWorkingRepo *dbMocks.MockWorkingHoursRepository
+}
+
+// SetupTest sets up the test suite before each test
+func (suite *TeamsServiceTestSuite) SetupTest() {
+	suite.mockTeamsRepo = dbMocks.NewMockTeamsRepository(suite.T())
+	suite.mockWorkingRepo = dbMocks.NewMockWorkingHoursRepository(suite.T())
+	suite.service = NewTeamsService(suite.mockTeamsRepo, suite.mockWorkingRepo)
+}
+
+// TestCreateTeam tests that a new team gets working hours of its own
+func (suite *TeamsServiceTestSuite) TestCreateTeam() {
+	ctx := context.Background()
+
+	suite.mockTeamsRepo.EXPECT().GetAll(ctx).Return([]models.Team{{ID: 1, Name: "Default", Slug: "default"}}, nil)
+	suite.mockTeamsRepo.EXPECT().Create(ctx, mock.MatchedBy(func(team *models.Team) bool {
+		return team.Name == "Platform Team" && team.Slug == "platform-team"
+	})).Run(func(_ context.Context, team *models.Team) {
+		team.ID = 2
+	}).Return(nil)
+
+	// The working hours are created for the new team, not the team of the request
+	forNewTeam := mock.MatchedBy(func(ctx context.Context) bool { return teamctx.GetTeamID(ctx) == 2 })
+	for day := 0; day < 7; day++ {
+		active := day < 5
+		suite.mockWorkingRepo.EXPECT().ReplaceDay(forNewTeam, time.Time{}, day, mock.MatchedBy(func(shifts []models.WorkingHours) bool {
+			return len(shifts) == 1 && shifts[0].Active == active
+		})).Return(nil)
+	}
+
+	team, err := suite.service.CreateTeam(ctx, &models.TeamForm{Name: " Platform Team "})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "/teams/platform-team", team.Path())
+}
+
+// TestCreateTeamSlugInUse tests that two teams can't share a slug
+func (suite *TeamsServiceTestSuite) TestCreateTeamSlugInUse() {
+	ctx := context.Background()
+
+	suite.mockTeamsRepo.EXPECT().GetAll(ctx).Return([]models.Team{{ID: 1, Name: "Default", Slug: "default"}}, nil)
+
+	_, err := suite.service.CreateTeam(ctx, &models.TeamForm{Name: "Second Default", Slug: "Default"})
+
+	assert.ErrorContains(suite.T(), err, `"default" is already used by team Default`)
+	suite.mockTeamsRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+// TestUpdateTeam tests that a team keeps its own slug when renamed
+func (suite *TeamsServiceTestSuite) TestUpdateTeam() {
+	ctx := context.Background()
+	teams := []models.Team{{ID: 1, Name: "Default", Slug: "default"}, {ID: 2, Name: "Platform", Slug: "platform"}}
+
+	suite.mockTeamsRepo.EXPECT().GetByID(ctx, 2).Return(&teams[1], nil)
+	suite.mockTeamsRepo.EXPECT().GetAll(ctx).Return(teams, nil)
+	suite.mockTeamsRepo.EXPECT().Update(ctx, mock.MatchedBy(func(team *models.Team) bool {
+		return team.ID == 2 && team.Name == "Platform Engineering" && team.Slug == "platform"
+	})).Return(nil)
+
+	_, err := suite.service.UpdateTeam(ctx, 2, &models.TeamForm{Name: "Platform Engineering", Slug: "platform"})
+
+	assert.NoError(suite.T(), err)
+}
+
+// TestRunTeamsServiceTestSuite runs the test suite
+func TestRunTeamsServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamsServiceTestSuite))
+}
@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+)
+
+// TimeOffService interface defines time off business logic
+type TimeOffService interface {
+	GetMemberTimeOff(ctx context.Context, memberID int) ([]TimeOffPeriod, error)
+	AddTimeOff(ctx context.Context, memberID int, form *models.TimeOffForm) (*TimeOffPeriod, error)
+	DeleteTimeOff(ctx context.Context, memberID, id int) error
+	ReassignConflicts(ctx context.Context, memberID, id int) (int, error)
+}
+
+// TimeOffPeriod represents a member's time off together with the schedule entries it clashes with
+type TimeOffPeriod struct {
+	models.TimeOff
+	Conflicts []models.ScheduleEntry `json:"conflicts"` // Entries from today onwards that still have the member on duty
+}
+
+// timeOffService implements TimeOffService interface
+type timeOffService struct {
+	timeOffRepo  repositories.TimeOffRepository
+	teamRepo     repositories.TeamRepository
+	scheduleRepo repositories.ScheduleRepository
+}
+
+// NewTimeOffService creates a new time off service
+func NewTimeOffService(
+	timeOffRepo repositories.TimeOffRepository,
+	teamRepo repositories.TeamRepository,
+	scheduleRepo repositories.ScheduleRepository,
+) TimeOffService {
+	return &timeOffService{
+		timeOffRepo:  timeOffRepo,
+		teamRepo:     teamRepo,
+		scheduleRepo: scheduleRepo,
+	}
+}
+
+// GetMemberTimeOff retrieves all time off of a member with the entries each period clashes with
+func (s *timeOffService) GetMemberTimeOff(ctx context.Context, memberID int) ([]TimeOffPeriod, error) {
+	if _, err := s.teamRepo.GetByID(ctx, memberID); err != nil {
+		return nil, fmt.Errorf("team member not found: %w", err)
+	}
+
+	timeOff, err := s.timeOffRepo.GetByTeamMember(ctx, memberID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get time off: %w", err)
+	}
+
+	periods := make([]TimeOffPeriod, 0, len(timeOff))
+	for _, t := range timeOff {
+		conflicts, err := s.findConflicts(ctx, &t)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, TimeOffPeriod{TimeOff: t, Conflicts: conflicts})
+	}
+
+	return periods, nil
+}
+
+// AddTimeOff registers time off for a member. The returned period lists the entries that
+// still have the member on duty, so the caller can offer to reassign them.
+func (s *timeOffService) AddTimeOff(ctx context.Context, memberID int, form *models.TimeOffForm) (*TimeOffPeriod, error) {
+	if errors := form.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+	}
+
+	if _, err := s.teamRepo.GetByID(ctx, memberID); err != nil {
+		return nil, fmt.Errorf("team member not found: %w", err)
+	}
+
+	startDate, _ := models.ParseDate(strings.TrimSpace(form.StartDate))
+	endDate, _ := models.ParseDate(strings.TrimSpace(form.EndDate))
+
+	timeOff := &models.TimeOff{
+		TeamMemberID: memberID,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Reason:       strings.TrimSpace(form.Reason),
+	}
+
+	if err := s.timeOffRepo.Create(ctx, timeOff); err != nil {
+		return nil, fmt.Errorf("failed to create time off: %w", err)
+	}
+
+	conflicts, err := s.findConflicts(ctx, timeOff)
+	if err != nil {
+		return nil, err
+	}
+
+	return &TimeOffPeriod{TimeOff: *timeOff, Conflicts: conflicts}, nil
+}
+
+// DeleteTimeOff removes a time off period of a member. Entries reassigned because of it stay as they are.
+func (s *timeOffService) DeleteTimeOff(ctx context.Context, memberID, id int) error {
+	if _, err := s.getMemberTimeOff(ctx, memberID, id); err != nil {
+		return err
+	}
+
+	if err := s.timeOffRepo.Delete(ctx, id); err != nil {
+		return fmt.Errorf("failed to delete time off: %w", err)
+	}
+
+	return nil
+}
+
+// ReassignConflicts hands the days a member is scheduled during their time off to the next
+// available member in the rotation. The entries become takeovers, so regeneration keeps them.
+// It returns the number of days reassigned; days on which nobody else is available are left alone.
+func (s *timeOffService) ReassignConflicts(ctx context.Context, memberID, id int) (int, error) {
+	timeOff, err := s.getMemberTimeOff(ctx, memberID, id)
+	if err != nil {
+		return 0, err
+	}
+
+	entries, err := s.entriesDuring(ctx, timeOff)
+	if err != nil {
+		return 0, err
+	}
+	conflicts := memberEntries(entries, memberID)
+	if len(conflicts) == 0 {
+		return 0, nil
+	}
+
+	input, err := s.reassignmentInput(ctx, timeOff)
+	if err != nil {
+		return 0, err
+	}
+	absentIndex := memberIndex(input.Members, memberID)
+
+	reassigned := 0
+	for _, entry := range conflicts {
+		// The member in the other role of the shift can't take it over as well
+		substituteID, ok := input.nextAvailableExcept(absentIndex+1, entry.Date, memberID, partnerOf(entries, entry))
+		if !ok {
+			continue // Nobody else is available that day
+		}
+
+		// Keep the member that was originally scheduled, so the takeover can be undone
+		if !entry.IsManualOverride {
+			entry.OriginalTeamMemberID = &memberID
+			entry.IsManualOverride = true
+		}
+		entry.TeamMemberID = substituteID
+
+		if err := s.scheduleRepo.Update(ctx, &entry); err != nil {
+			return reassigned, fmt.Errorf("failed to reassign schedule entry: %w", err)
+		}
+		reassigned++
+	}
+
+	return reassigned, nil
+}
+
+// reassignmentInput collects the active members in rotation order and everyone's time off
+// during the given period
+func (s *timeOffService) reassignmentInput(ctx context.Context, timeOff *models.TimeOff) (RotationInput, error) {
+	activeMembers, err := s.teamRepo.GetActiveMembers(ctx)
+	if err != nil {
+		return RotationInput{}, fmt.Errorf("failed to get active team members: %w", err)
+	}
+
+	state, err := s.scheduleRepo.GetState(ctx)
+	if err != nil {
+		return RotationInput{}, fmt.Errorf("failed to get schedule state: %w", err)
+	}
+
+	// Follow the rotation queue when it covers the whole team
+	members := activeMembers
+	if ordered := orderByQueue(activeMembers, state.RotationQueue); len(ordered) == len(activeMembers) {
+		members = ordered
+	}
+	if len(members) == 0 {
+		return RotationInput{}, fmt.Errorf("no active team members to reassign to")
+	}
+
+	allTimeOff, err := s.timeOffRepo.GetByDateRange(ctx, timeOff.StartDate, timeOff.EndDate)
+	if err != nil {
+		return RotationInput{}, fmt.Errorf("failed to get time off: %w", err)
+	}
+
+	return RotationInput{Members: members, TimeOff: allTimeOff}, nil
+}
+
+// getMemberTimeOff retrieves a time off period and checks it belongs to the member
+func (s *timeOffService) getMemberTimeOff(ctx context.Context, memberID, id int) (*models.TimeOff, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid time off ID: %d", id)
+	}
+
+	timeOff, err := s.timeOffRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("time off not found: %w", err)
+	}
+	if timeOff.TeamMemberID != memberID {
+		return nil, fmt.Errorf("time off not found: time off with ID %d not found", id)
+	}
+
+	return timeOff, nil
+}
+
+// findConflicts returns the entries from today onwards during the time off that have the member on
+// duty, as primary or as backup
+func (s *timeOffService) findConflicts(ctx context.Context, timeOff *models.TimeOff) ([]models.ScheduleEntry, error) {
+	entries, err := s.entriesDuring(ctx, timeOff)
+	if err != nil {
+		return nil, err
+	}
+	return memberEntries(entries, timeOff.TeamMemberID), nil
+}
+
+// entriesDuring returns all entries from today onwards during the time off
+func (s *timeOffService) entriesDuring(ctx context.Context, timeOff *models.TimeOff) ([]models.ScheduleEntry, error) {
+	from := timeOff.StartDate
+	if today := truncateToDate(timeNow()); from.Before(today) {
+		from = today
+	}
+	if from.After(timeOff.EndDate) {
+		return nil, nil // Already over
+	}
+
+	entries, err := s.scheduleRepo.GetByDateRange(ctx, from, timeOff.EndDate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get schedule entries: %w", err)
+	}
+	return entries, nil
+}
+
+// memberEntries returns the entries that have the member on duty
+func memberEntries(entries []models.ScheduleEntry, memberID int) []models.ScheduleEntry {
+	var result []models.ScheduleEntry
+	for _, entry := range entries {
+		if entry.TeamMemberID == memberID {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
+
+// partnerOf returns the member in the other role of the entry's shift, or 0 if there is none
+func partnerOf(entries []models.ScheduleEntry, entry models.ScheduleEntry) int {
+	for _, other := range entries {
+		if slotKey(other) == slotKey(entry) && other.GetRole() != entry.GetRole() {
+			return other.TeamMemberID
+		}
+	}
+	return 0
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+// TimeOffServiceTestSuite is a test suite for the time off service
+type TimeOffServiceTestSuite struct {
+	suite.Suite
+	service          TimeOffService
+	mockTimeOffRepo  *dbMocks.MockTimeOffRepository
+	mockTeamRepo     *dbMocks.MockTeamRepository
+	mockScheduleRepo *dbMocks.MockScheduleRepository
+	originalTimeNow  func() time.Time
+}
+
+// SetupTest sets up the test suite before each test
+func (suite *TimeOffServiceTestSuite) SetupTest() {
+	suite.mockTimeOffRepo = dbMocks.NewMockTimeOffRepository(suite.T())
+	suite.mockTeamRepo = dbMocks.NewMockTeamRepository(suite.T())
+	suite.mockScheduleRepo = dbMocks.NewMockScheduleRepository(suite.T())
+
+	suite.service = NewTimeOffService(
+		suite.mockTimeOffRepo,
+		suite.mockTeamRepo,
+		suite.mockScheduleRepo,
+	)
+}
+
+// SetupSuite pins the clock to a Monday afternoon to make the tests deterministic
+func (suite *TimeOffServiceTestSuite) SetupSuite() {
+	suite.originalTimeNow = timeNow
+	timeNow = func() time.Time { return testMonday.Add(15 * time.Hour) }
+}
+
+// TearDownSuite restores the clock
+func (suite *TimeOffServiceTestSuite) TearDownSuite() {
+	timeNow = suite.originalTimeNow
+}
+
+// TestAddTimeOff tests registering time off and reporting the days it clashes with
+func (suite *TimeOffServiceTestSuite) TestAddTimeOff() {
+	ctx := context.Background()
+	wednesday := testMonday.AddDate(0, 0, 2)
+	friday := testMonday.AddDate(0, 0, 4)
+
+	testCases := []struct {
+		name              string
+		form              models.TimeOffForm
+		setupMocks        func()
+		expectedConflicts []int
+		expectedError     string
+	}{
+		{
+			name:          "invalid form",
+			form:          models.TimeOffForm{StartDate: "2023-10-06", EndDate: "2023-10-04"},
+			expectedError: "End date can't be before the start date",
+		},
+		{
+			name: "unknown member",
+			form: models.TimeOffForm{StartDate: "2023-10-04", EndDate: "2023-10-06"},
+			setupMocks: func() {
+				suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(nil, errors.New("team member with ID 2 not found"))
+			},
+			expectedError: "team member not found",
+		},
+		{
+			name: "reports the days the member is still on duty",
+			form: models.TimeOffForm{StartDate: "2023-10-04", EndDate: "2023-10-06", Reason: " Holiday "},
+			setupMocks: func() {
+				suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&threeMembers[1], nil)
+				suite.mockTimeOffRepo.EXPECT().Create(ctx, mock.MatchedBy(func(timeOff *models.TimeOff) bool {
+					return timeOff.TeamMemberID == 2 && timeOff.StartDate.Equal(wednesday) &&
+						timeOff.EndDate.Equal(friday) && timeOff.Reason == "Holiday"
+				})).RunAndReturn(func(ctx context.Context, timeOff *models.TimeOff) error {
+					timeOff.ID = 7
+					return nil
+				})
+				suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, wednesday, friday).Return([]models.ScheduleEntry{
+					{ID: 11, Date: wednesday, TeamMemberID: 2},
+					{ID: 12, Date: wednesday.AddDate(0, 0, 1), TeamMemberID: 3},
+					{ID: 13, Date: friday, TeamMemberID: 2},
+				}, nil)
+			},
+			expectedConflicts: []int{11, 13},
+		},
+		{
+			name: "past time off has no conflicts",
+			form: models.TimeOffForm{StartDate: "2023-09-25", EndDate: "2023-09-29"},
+			setupMocks: func() {
+				suite.mockTeamRepo.EXPECT().GetByID(ctx, 2).Return(&threeMembers[1], nil)
+				suite.mockTimeOffRepo.EXPECT().Create(ctx, mock.Anything).Return(nil)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		suite.Run(tc.name, func() {
+			suite.SetupTest()
+			if tc.setupMocks != nil {
+				tc.setupMocks()
+			}
+
+			period, err := suite.service.AddTimeOff(ctx, 2, &tc.form)
+
+			if tc.expectedError != "" {
+				assert.ErrorContains(suite.T(), err, tc.expectedError)
+				return
+			}
+			assert.NoError(suite.T(), err)
+
+			var conflicts []int
+			for _, entry := range period.Conflicts {
+				conflicts = append(conflicts, entry.ID)
+			}
+			assert.Equal(suite.T(), tc.expectedConflicts, conflicts)
+		})
+	}
+}
+
+// TestReassignConflicts tests handing the days of a member on time off to the next available member
+func (suite *TimeOffServiceTestSuite) TestReassignConflicts() {
+	ctx := context.Background()
+	bobOff := timeOffPeriod(2, "2023-10-02", "2023-10-06")
+	bobOff.ID = 7
+
+	suite.mockTimeOffRepo.EXPECT().GetByID(ctx, 7).Return(&bobOff, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, testMonday, testMonday.AddDate(0, 0, 4)).Return([]models.ScheduleEntry{
+		historyEntry("2023-10-02", 2),
+		historyEntry("2023-10-03", 1),
+		historyEntry("2023-10-04", 2),
+		overrideEntry("2023-10-05", 2),
+		historyEntry("2023-10-06", 2),
+	}, nil)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{}, nil)
+
+	// Charlie is next after Bob, but is away on Wednesday; on Friday nobody else is around
+	suite.mockTimeOffRepo.EXPECT().GetByDateRange(ctx, bobOff.StartDate, bobOff.EndDate).Return([]models.TimeOff{
+		bobOff,
+		timeOffPeriod(3, "2023-10-04", "2023-10-04"),
+		timeOffPeriod(1, "2023-10-06", "2023-10-06"),
+		timeOffPeriod(3, "2023-10-06", "2023-10-06"),
+	}, nil)
+
+	var updated []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().Update(ctx, mock.Anything).RunAndReturn(func(ctx context.Context, entry *models.ScheduleEntry) error {
+		updated = append(updated, *entry)
+		return nil
+	})
+
+	reassigned, err := suite.service.ReassignConflicts(ctx, 2, 7)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, reassigned)
+	if assert.Len(suite.T(), updated, 3) {
+		// Generated entries become takeovers that remember Bob
+		assert.Equal(suite.T(), 3, updated[0].TeamMemberID)
+		assert.True(suite.T(), updated[0].IsManualOverride)
+		assert.Equal(suite.T(), 2, *updated[0].OriginalTeamMemberID)
+		assert.Equal(suite.T(), 1, updated[1].TeamMemberID)
+
+		// A standalone override is handed over as it is
+		assert.Equal(suite.T(), "2023-10-05", updated[2].GetFormattedDate())
+		assert.Equal(suite.T(), 3, updated[2].TeamMemberID)
+		assert.Nil(suite.T(), updated[2].OriginalTeamMemberID)
+	}
+}
+
+// TestReassignConflictsKeepsRolesApart tests that the primary of a shift doesn't take over its backup
+func (suite *TimeOffServiceTestSuite) TestReassignConflictsKeepsRolesApart() {
+	ctx := context.Background()
+	bobOff := timeOffPeriod(2, "2023-10-02", "2023-10-02")
+	bobOff.ID = 7
+
+	suite.mockTimeOffRepo.EXPECT().GetByID(ctx, 7).Return(&bobOff, nil)
+	suite.mockScheduleRepo.EXPECT().GetByDateRange(ctx, testMonday, testMonday).Return([]models.ScheduleEntry{
+		historyEntry("2023-10-02", 3),
+		backupEntry("2023-10-02", 2),
+	}, nil)
+	suite.mockTeamRepo.EXPECT().GetActiveMembers(ctx).Return(threeMembers, nil)
+	suite.mockScheduleRepo.EXPECT().GetState(ctx).Return(&models.ScheduleState{}, nil)
+	suite.mockTimeOffRepo.EXPECT().GetByDateRange(ctx, bobOff.StartDate, bobOff.EndDate).Return([]models.TimeOff{bobOff}, nil)
+
+	var updated []models.ScheduleEntry
+	suite.mockScheduleRepo.EXPECT().Update(ctx, mock.Anything).RunAndReturn(func(ctx context.Context, entry *models.ScheduleEntry) error {
+		updated = append(updated, *entry)
+		return nil
+	})
+
+	reassigned, err := suite.service.ReassignConflicts(ctx, 2, 7)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, reassigned)
+	if assert.Len(suite.T(), updated, 1) {
+		// Charlie is next after Bob, but already the primary
+		assert.Equal(suite.T(), 1, updated[0].TeamMemberID)
+		assert.True(suite.T(), updated[0].IsBackup())
+	}
+}
+
+// TestTimeOffBelongsToMember tests that time off can only be changed through its own member
+func (suite *TimeOffServiceTestSuite) TestTimeOffBelongsToMember() {
+	ctx := context.Background()
+	aliceOff := timeOffPeriod(1, "2023-10-02", "2023-10-06")
+	suite.mockTimeOffRepo.EXPECT().GetByID(ctx, 7).Return(&aliceOff, nil)
+
+	err := suite.service.DeleteTimeOff(ctx, 2, 7)
+	assert.ErrorContains(suite.T(), err, "time off with ID 7 not found")
+
+	_, err = suite.service.ReassignConflicts(ctx, 2, 7)
+	assert.ErrorContains(suite.T(), err, "time off with ID 7 not found")
+}
+
+// TestRunTimeOffServiceTestSuite runs the test suite
+func TestRunTimeOffServiceTestSuite(t *testing.T) {
+	suite.Run(t, new(TimeOffServiceTestSuite))
+}
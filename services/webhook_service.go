@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/repositories"
+)
+
+// webhookLogSize is the number of deliveries the delivery log shows
+const webhookLogSize = 200
+
+// WebhookService interface defines the business logic of the webhooks of a team
+type WebhookService interface {
+	GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error)
+	GetSubscriptionByID(ctx context.Context, id int) (*models.WebhookSubscription, error)
+	CreateSubscription(ctx context.Context, form *models.WebhookSubscriptionForm) (*models.WebhookSubscription, error)
+	UpdateSubscription(ctx context.Context, id int, form *models.WebhookSubscriptionForm) (*models.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id int) error
+	GetDeliveries(ctx context.Context) ([]models.WebhookDelivery, error)
+	RetryDelivery(ctx context.Context, id int) error
+}
+
+// webhookService implements WebhookService interface
+type webhookService struct {
+	webhookRepo repositories.WebhookRepository
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo repositories.WebhookRepository) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+	}
+}
+
+// GetSubscriptions retrieves all webhooks of the team
+func (s *webhookService) GetSubscriptions(ctx context.Context) ([]models.WebhookSubscription, error) {
+	return s.webhookRepo.GetSubscriptions(ctx)
+}
+
+// GetSubscriptionByID retrieves a webhook by ID
+func (s *webhookService) GetSubscriptionByID(ctx context.Context, id int) (*models.WebhookSubscription, error) {
+	if id <= 0 {
+		return nil, fmt.Errorf("invalid webhook subscription ID: %d", id)
+	}
+	return s.webhookRepo.GetSubscriptionByID(ctx, id)
+}
+
+// CreateSubscription creates a new webhook, with a generated secret when the form has none
+func (s *webhookService) CreateSubscription(ctx context.Context, form *models.WebhookSubscriptionForm) (*models.WebhookSubscription, error) {
+	subscription, err := newSubscriptionFromForm(form)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Secret == "" {
+		if subscription.Secret, err = randomHex(32); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+	}
+
+	if err := s.webhookRepo.CreateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to create webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// UpdateSubscription updates an existing webhook, keeping its secret when the form has none
+func (s *webhookService) UpdateSubscription(ctx context.Context, id int, form *models.WebhookSubscriptionForm) (*models.WebhookSubscription, error) {
+	existing, err := s.GetSubscriptionByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("webhook subscription not found: %w", err)
+	}
+
+	subscription, err := newSubscriptionFromForm(form)
+	if err != nil {
+		return nil, err
+	}
+	subscription.ID = id
+	if subscription.Secret == "" {
+		subscription.Secret = existing.Secret
+	}
+
+	if err := s.webhookRepo.UpdateSubscription(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update webhook subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a webhook and its deliveries
+func (s *webhookService) DeleteSubscription(ctx context.Context, id int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid webhook subscription ID: %d", id)
+	}
+	return s.webhookRepo.DeleteSubscription(ctx, id)
+}
+
+// GetDeliveries retrieves the latest deliveries of the team, newest first
+func (s *webhookService) GetDeliveries(ctx context.Context) ([]models.WebhookDelivery, error) {
+	return s.webhookRepo.GetDeliveries(ctx, webhookLogSize)
+}
+
+// RetryDelivery queues a failed delivery again, with a fresh set of attempts
+func (s *webhookService) RetryDelivery(ctx context.Context, id int) error {
+	if id <= 0 {
+		return fmt.Errorf("invalid webhook delivery ID: %d", id)
+	}
+
+	delivery, err := s.webhookRepo.GetDeliveryByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if !delivery.IsFailed() {
+		return fmt.Errorf("can only retry failed deliveries")
+	}
+
+	delivery.Status = models.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = timeNow()
+	if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to retry webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+// newSubscriptionFromForm validates the form and creates the webhook it describes
+func newSubscriptionFromForm(form *models.WebhookSubscriptionForm) (*models.WebhookSubscription, error) {
+	if errors := form.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+	}
+
+	return &models.WebhookSubscription{
+		Name:   strings.TrimSpace(form.Name),
+		URL:    strings.TrimSpace(form.URL),
+		Secret: strings.TrimSpace(form.Secret),
+		Events: form.GetEvents(),
+		Active: form.Active,
+	}, nil
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+func TestWebhookServiceCreateSubscription(t *testing.T) {
+	ctx := context.Background()
+	mockWebhookRepo := dbMocks.NewMockWebhookRepository(t)
+	service := NewWebhookService(mockWebhookRepo)
+
+	// Without a secret in the form, one is generated
+	mockWebhookRepo.EXPECT().CreateSubscription(ctx, mock.MatchedBy(func(subscription *models.WebhookSubscription) bool {
+		return subscription.Name == "Paging" && subscription.URL == "https://example.com/hooks" && len(subscription.Secret) == 64 &&
+			subscription.Active && assert.ObjectsAreEqual([]string{models.WebhookEventEntryCreated, models.WebhookEventOverrideCreated}, subscription.Events)
+	})).Return(nil).Once()
+
+	form := &models.WebhookSubscriptionForm{
+		Name:   " Paging ",
+		URL:    "https://example.com/hooks ",
+		Events: []string{models.WebhookEventOverrideCreated, models.WebhookEventEntryCreated},
+		Active: true,
+	}
+	_, err := service.CreateSubscription(ctx, form)
+	assert.NoError(t, err)
+
+	// Invalid forms never reach the repository
+	_, err = service.CreateSubscription(ctx, &models.WebhookSubscriptionForm{Name: "Paging", URL: "https://example.com/hooks", Secret: "short", Events: models.WebhookEvents})
+	assert.EqualError(t, err, "validation failed: Secret must be at least 16 characters")
+}
+
+func TestWebhookServiceUpdateSubscription(t *testing.T) {
+	ctx := context.Background()
+	mockWebhookRepo := dbMocks.NewMockWebhookRepository(t)
+	service := NewWebhookService(mockWebhookRepo)
+	form := &models.WebhookSubscriptionForm{Name: "Paging", URL: "https://example.com/hooks", Events: models.WebhookEvents}
+
+	// An empty secret keeps the current one
+	mockWebhookRepo.EXPECT().GetSubscriptionByID(ctx, 3).Return(&models.WebhookSubscription{ID: 3, Secret: "current-secret-123"}, nil).Times(2)
+	mockWebhookRepo.EXPECT().UpdateSubscription(ctx, mock.MatchedBy(func(subscription *models.WebhookSubscription) bool {
+		return subscription.ID == 3 && subscription.Secret == "current-secret-123" && !subscription.Active
+	})).Return(nil).Once()
+
+	_, err := service.UpdateSubscription(ctx, 3, form)
+	assert.NoError(t, err)
+
+	// A new secret replaces it
+	mockWebhookRepo.EXPECT().UpdateSubscription(ctx, mock.MatchedBy(func(subscription *models.WebhookSubscription) bool {
+		return subscription.Secret == "rotated-secret-456"
+	})).Return(nil).Once()
+
+	form.Secret = "rotated-secret-456"
+	_, err = service.UpdateSubscription(ctx, 3, form)
+	assert.NoError(t, err)
+
+	mockWebhookRepo.EXPECT().GetSubscriptionByID(ctx, 4).Return(nil, errors.New("webhook subscription with ID 4 not found")).Once()
+	_, err = service.UpdateSubscription(ctx, 4, form)
+	assert.EqualError(t, err, "webhook subscription not found: webhook subscription with ID 4 not found")
+}
+
+func TestWebhookServiceRetryDelivery(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := context.Background()
+	mockWebhookRepo := dbMocks.NewMockWebhookRepository(t)
+	service := NewWebhookService(mockWebhookRepo)
+
+	// A failed delivery gets a fresh set of attempts, due right away
+	failed := &models.WebhookDelivery{ID: 7, Status: models.WebhookDeliveryFailed, Attempts: webhookMaxAttempts, NextAttemptAt: testMonday.Add(-time.Hour)}
+	mockWebhookRepo.EXPECT().GetDeliveryByID(ctx, 7).Return(failed, nil).Once()
+	mockWebhookRepo.EXPECT().UpdateDelivery(ctx, mock.MatchedBy(func(delivery *models.WebhookDelivery) bool {
+		return delivery.ID == 7 && delivery.IsPending() && delivery.Attempts == 0 && delivery.NextAttemptAt.Equal(testMonday)
+	})).Return(nil).Once()
+
+	assert.NoError(t, service.RetryDelivery(ctx, 7))
+
+	// Deliveries that are delivered or still pending aren't retried
+	mockWebhookRepo.EXPECT().GetDeliveryByID(ctx, 8).Return(&models.WebhookDelivery{ID: 8, Status: models.WebhookDeliveryDelivered}, nil).Once()
+	assert.EqualError(t, service.RetryDelivery(ctx, 8), "can only retry failed deliveries")
+}
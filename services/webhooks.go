@@ -0,0 +1,293 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/notifications"
+	"github.com/blogem/eod-scheduler/repositories"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+const (
+	webhookPollInterval = 15 * time.Second    // How often the queue is checked for deliveries that came due
+	webhookBatchSize    = 100                 // Deliveries sent per team and run
+	webhookMaxAttempts  = 8                   // Attempts before a delivery fails
+	webhookRetryDelay   = 30 * time.Second    // Delay before the first retry, doubled for every retry after it
+	webhookRetention    = 30 * 24 * time.Hour // How long delivered and failed deliveries stay in the log
+	webhookPruneEvery   = time.Hour           // How often the log is pruned
+)
+
+// WebhookPublisher queues the events of the team in the context for its webhooks
+type WebhookPublisher interface {
+	Publish(ctx context.Context, event string, data any)
+}
+
+// WebhookEnvelope is the JSON body posted to a webhook
+type WebhookEnvelope struct {
+	ID         string                     `json:"id"` // The same for the deliveries of one event to several webhooks
+	Event      string                     `json:"event"`
+	Team       *notifications.WebhookTeam `json:"team,omitempty"`
+	OccurredAt time.Time                  `json:"occurred_at"`
+	Data       any                        `json:"data"`
+}
+
+// webhookOverride is the data of the override events
+type webhookOverride struct {
+	Override *models.ScheduleEntry `json:"override"`
+	Replaced *models.ScheduleEntry `json:"replaced,omitempty"` // The generated entry the override replaced
+	Restored *models.ScheduleEntry `json:"restored,omitempty"` // The generated entry that is back
+}
+
+// webhookGeneration is the data of the generation event
+type webhookGeneration struct {
+	Trigger string                   `json:"trigger"`
+	Result  *models.GenerationResult `json:"result"`
+}
+
+// WebhookSender queues the events for the webhooks subscribed to them and posts them, trying again
+// with a growing delay until a webhook accepts them
+type WebhookSender struct {
+	teamsRepo   repositories.TeamsRepository
+	webhookRepo repositories.WebhookRepository
+	client      *http.Client
+	wake        chan struct{} // Signals queued events, so they go out without waiting for the next poll
+	prunedAt    time.Time     // When the delivery log was last pruned
+}
+
+// NewWebhookSender creates the webhook sender
+func NewWebhookSender(teamsRepo repositories.TeamsRepository, webhookRepo repositories.WebhookRepository) *WebhookSender {
+	return &WebhookSender{
+		teamsRepo:   teamsRepo,
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: 10 * time.Second},
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// Publish queues an event for the active webhooks of the team that subscribe to it. Failing to queue
+// it is logged, the change the event is about stands.
+func (s *WebhookSender) Publish(ctx context.Context, event string, data any) {
+	subscriptions, err := s.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		log.Printf("Failed to get webhooks for %s: %v", event, err)
+		return
+	}
+
+	var subscribed []models.WebhookSubscription
+	for _, subscription := range subscriptions {
+		if subscription.Active && subscription.Subscribes(event) {
+			subscribed = append(subscribed, subscription)
+		}
+	}
+	if len(subscribed) == 0 {
+		return
+	}
+
+	eventID, err := randomHex(16)
+	if err != nil {
+		log.Printf("Failed to create webhook event ID for %s: %v", event, err)
+		return
+	}
+
+	now := timeNow()
+	envelope := WebhookEnvelope{ID: eventID, Event: event, OccurredAt: now, Data: data}
+	if team := teamctx.GetTeam(ctx); team != nil {
+		envelope.Team = &notifications.WebhookTeam{ID: team.ID, Name: team.Name, Slug: team.Slug}
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		log.Printf("Failed to encode webhook event %s: %v", event, err)
+		return
+	}
+
+	deliveries := make([]*models.WebhookDelivery, len(subscribed))
+	for i, subscription := range subscribed {
+		deliveries[i] = &models.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventID,
+			Event:          event,
+			Payload:        string(payload),
+			Status:         models.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+			CreatedAt:      now,
+		}
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		log.Printf("Failed to queue webhook event %s: %v", event, err)
+		return
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Start sends the queued deliveries as they come due, until the context is done
+func (s *WebhookSender) Start(ctx context.Context) {
+	for {
+		timer := time.NewTimer(time.Until(s.Run(ctx)))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-s.wake:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// Run sends the deliveries of every team that are due and prunes the delivery log now and then. It
+// returns when to run again, right away when a team has more deliveries due than fit in a run.
+func (s *WebhookSender) Run(ctx context.Context) time.Time {
+	now := timeNow()
+	next := now.Add(webhookPollInterval)
+
+	teams, err := s.teamsRepo.GetAll(ctx)
+	if err != nil {
+		log.Printf("Webhook sender failed to get the teams: %v", err)
+		return next
+	}
+
+	prune := now.Sub(s.prunedAt) >= webhookPruneEvery
+	for i := range teams {
+		teamCtx := teamctx.SetTeam(ctx, &teams[i])
+		if s.sendDue(teamCtx, now) {
+			next = now
+		}
+
+		if prune {
+			if _, err := s.webhookRepo.DeleteDeliveriesBefore(teamCtx, now.Add(-webhookRetention)); err != nil {
+				log.Printf("Webhook sender failed to prune the deliveries of team %s: %v", teams[i].Slug, err)
+			}
+		}
+	}
+	if prune {
+		s.prunedAt = now
+	}
+
+	return next
+}
+
+// sendDue sends the deliveries of the team in the context that are due, and reports whether more
+// may be due than it sent
+func (s *WebhookSender) sendDue(ctx context.Context, now time.Time) bool {
+	team := teamctx.GetTeam(ctx)
+	deliveries, err := s.webhookRepo.GetDueDeliveries(ctx, now, webhookBatchSize)
+	if err != nil {
+		log.Printf("Webhook sender failed to get the deliveries of team %s: %v", team.Slug, err)
+		return false
+	}
+	if len(deliveries) == 0 {
+		return false
+	}
+
+	subscriptions, err := s.webhookRepo.GetSubscriptions(ctx)
+	if err != nil {
+		log.Printf("Webhook sender failed to get the webhooks of team %s: %v", team.Slug, err)
+		return false
+	}
+	byID := make(map[int]*models.WebhookSubscription, len(subscriptions))
+	for i := range subscriptions {
+		byID[subscriptions[i].ID] = &subscriptions[i]
+	}
+
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		subscription, ok := byID[delivery.SubscriptionID]
+		if !ok {
+			continue
+		}
+
+		s.attempt(ctx, subscription, delivery, now)
+		if err := s.webhookRepo.UpdateDelivery(ctx, delivery); err != nil {
+			log.Printf("Webhook sender failed to record delivery %d of team %s: %v", delivery.ID, team.Slug, err)
+		}
+	}
+
+	return len(deliveries) == webhookBatchSize
+}
+
+// attempt posts a delivery and records the outcome on it. A failed attempt is retried after
+// webhookRetryDelay, doubled for every attempt before it, until webhookMaxAttempts is reached.
+func (s *WebhookSender) attempt(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery, now time.Time) {
+	status, err := s.post(ctx, subscription, delivery)
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.ResponseStatus = status
+	if err == nil {
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = err.Error()
+	if delivery.Attempts >= webhookMaxAttempts {
+		delivery.Status = models.WebhookDeliveryFailed
+		return
+	}
+	delivery.NextAttemptAt = now.Add(webhookRetryDelay << (delivery.Attempts - 1))
+}
+
+// post posts the payload of a delivery, signed with the secret of its webhook, and returns the
+// status of the response, 0 if there was none
+func (s *WebhookSender) post(ctx context.Context, subscription *models.WebhookSubscription, delivery *models.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(timeNow().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "EOD-Scheduler-Webhooks")
+	req.Header.Set("X-EOD-Event", delivery.Event)
+	req.Header.Set("X-EOD-Delivery", delivery.EventID)
+	req.Header.Set("X-EOD-Timestamp", timestamp)
+	req.Header.Set("X-EOD-Signature", SignWebhook(subscription.Secret, timestamp, []byte(delivery.Payload)))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		reason, _ := io.ReadAll(io.LimitReader(resp.Body, 200))
+		return resp.StatusCode, fmt.Errorf("responded with %s: %s", resp.Status, strings.TrimSpace(string(reason)))
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhook returns the X-EOD-Signature of a webhook body, "v1=" followed by the hex HMAC-SHA256
+// of "v1:<timestamp>:<body>" keyed with the secret of the webhook
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte("v1:" + timestamp + ":"))
+	mac.Write(body)
+	return "v1=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// randomHex returns n random bytes as hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
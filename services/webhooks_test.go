@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	"github.com/blogem/eod-scheduler/notifications"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+	"github.com/blogem/eod-scheduler/teamctx"
+)
+
+// publishedEvent is an event published to a webhookRecorder
+type publishedEvent struct {
+	event string
+	data  any
+}
+
+// webhookRecorder is a webhook publisher that keeps the events instead of queueing them
+type webhookRecorder struct {
+	mu        sync.Mutex
+	published []publishedEvent
+}
+
+// Publish records the event
+func (r *webhookRecorder) Publish(ctx context.Context, event string, data any) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.published = append(r.published, publishedEvent{event: event, data: data})
+}
+
+// events returns the recorded events in the order they were published
+func (r *webhookRecorder) events() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []string
+	for _, p := range r.published {
+		events = append(events, p.event)
+	}
+	return events
+}
+
+func TestWebhookSenderPublish(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday }
+
+	ctx := teamctx.SetTeam(context.Background(), &models.Team{ID: 2, Name: "Platform", Slug: "platform"})
+	mockWebhookRepo := dbMocks.NewMockWebhookRepository(t)
+	sender := NewWebhookSender(dbMocks.NewMockTeamsRepository(t), mockWebhookRepo)
+
+	// Only the active webhook that subscribes to the event gets it
+	mockWebhookRepo.EXPECT().GetSubscriptions(ctx).Return([]models.WebhookSubscription{
+		{ID: 1, Events: []string{models.WebhookEventMemberActivated}, Active: true},
+		{ID: 2, Events: []string{models.WebhookEventMemberActivated}, Active: false},
+		{ID: 3, Events: []string{models.WebhookEventEntryCreated}, Active: true},
+	}, nil)
+	var queued []*models.WebhookDelivery
+	mockWebhookRepo.EXPECT().CreateDeliveries(ctx, mock.Anything).RunAndReturn(
+		func(ctx context.Context, deliveries []*models.WebhookDelivery) error {
+			queued = deliveries
+			return nil
+		},
+	).Once()
+
+	sender.Publish(ctx, models.WebhookEventMemberActivated, &models.TeamMember{ID: 4, Name: "Dana", Active: true})
+	sender.Publish(ctx, models.WebhookEventMemberDeactivated, &models.TeamMember{ID: 4, Name: "Dana"})
+
+	if assert.Len(t, queued, 1) {
+		delivery := queued[0]
+		assert.Equal(t, 1, delivery.SubscriptionID)
+		assert.Equal(t, models.WebhookEventMemberActivated, delivery.Event)
+		assert.Equal(t, models.WebhookDeliveryPending, delivery.Status)
+		assert.Equal(t, testMonday, delivery.NextAttemptAt)
+		assert.Len(t, delivery.EventID, 32)
+
+		var envelope struct {
+			WebhookEnvelope
+			Data models.TeamMember `json:"data"`
+		}
+		assert.NoError(t, json.Unmarshal([]byte(delivery.Payload), &envelope))
+		assert.Equal(t, delivery.EventID, envelope.ID)
+		assert.Equal(t, models.WebhookEventMemberActivated, envelope.Event)
+		assert.Equal(t, &notifications.WebhookTeam{ID: 2, Name: "Platform", Slug: "platform"}, envelope.Team)
+		assert.True(t, testMonday.Equal(envelope.OccurredAt))
+		assert.Equal(t, "Dana", envelope.Data.Name)
+	}
+
+	// The sender is woken up to post the delivery right away
+	assert.Len(t, sender.wake, 1)
+}
+
+func TestWebhookSenderRun(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+
+	subscription := models.WebhookSubscription{ID: 1, Name: "Paging", Secret: "0123456789abcdef", Events: models.WebhookEvents, Active: true}
+	statuses := []int{http.StatusInternalServerError, http.StatusOK}
+	var received []*http.Request
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, `{"id":"abc"}`, string(body))
+		assert.Equal(t, SignWebhook(subscription.Secret, r.Header.Get("X-EOD-Timestamp"), body), r.Header.Get("X-EOD-Signature"))
+		received = append(received, r)
+
+		status := statuses[0]
+		statuses = statuses[1:]
+		w.WriteHeader(status)
+		if status != http.StatusOK {
+			w.Write([]byte("boom"))
+		}
+	}))
+	defer server.Close()
+	subscription.URL = server.URL
+
+	ctx := context.Background()
+	onTeam := mock.MatchedBy(func(ctx context.Context) bool { return teamctx.GetTeamID(ctx) == 1 })
+	mockTeamsRepo := dbMocks.NewMockTeamsRepository(t)
+	mockWebhookRepo := dbMocks.NewMockWebhookRepository(t)
+	mockTeamsRepo.EXPECT().GetAll(ctx).Return([]models.Team{{ID: 1, Name: "Default", Slug: "default"}}, nil)
+	mockWebhookRepo.EXPECT().GetSubscriptions(onTeam).Return([]models.WebhookSubscription{subscription}, nil)
+
+	// The queue holds one delivery that is due from the start
+	stored := models.WebhookDelivery{ID: 7, SubscriptionID: 1, EventID: "abc", Event: models.WebhookEventEntryCreated, Payload: `{"id":"abc"}`, Status: models.WebhookDeliveryPending, NextAttemptAt: testMonday}
+	mockWebhookRepo.EXPECT().GetDueDeliveries(onTeam, mock.Anything, webhookBatchSize).RunAndReturn(
+		func(ctx context.Context, now time.Time, limit int) ([]models.WebhookDelivery, error) {
+			if !stored.IsPending() || stored.NextAttemptAt.After(now) {
+				return nil, nil
+			}
+			return []models.WebhookDelivery{stored}, nil
+		},
+	)
+	mockWebhookRepo.EXPECT().UpdateDelivery(onTeam, mock.Anything).RunAndReturn(
+		func(ctx context.Context, delivery *models.WebhookDelivery) error {
+			stored = *delivery
+			return nil
+		},
+	)
+	// The log is pruned on the first run, and not again within the hour
+	mockWebhookRepo.EXPECT().DeleteDeliveriesBefore(onTeam, testMonday.Add(-webhookRetention)).Return(0, nil).Once()
+
+	sender := NewWebhookSender(mockTeamsRepo, mockWebhookRepo)
+	run := func(at time.Duration) time.Time {
+		timeNow = func() time.Time { return testMonday.Add(at) }
+		return sender.Run(ctx)
+	}
+
+	// The first attempt fails and is tried again after the retry delay
+	assert.Equal(t, testMonday.Add(webhookPollInterval), run(0))
+	assert.Equal(t, models.WebhookDeliveryPending, stored.Status)
+	assert.Equal(t, 1, stored.Attempts)
+	assert.Equal(t, http.StatusInternalServerError, stored.ResponseStatus)
+	assert.Equal(t, "responded with 500 Internal Server Error: boom", stored.LastError)
+	assert.Equal(t, testMonday.Add(webhookRetryDelay), stored.NextAttemptAt)
+
+	// Not yet due
+	run(webhookPollInterval)
+	assert.Len(t, received, 1)
+
+	// The retry is delivered
+	run(webhookRetryDelay)
+	assert.Equal(t, models.WebhookDeliveryDelivered, stored.Status)
+	assert.Equal(t, 2, stored.Attempts)
+	assert.Equal(t, http.StatusOK, stored.ResponseStatus)
+	assert.Empty(t, stored.LastError)
+	assert.Equal(t, testMonday.Add(webhookRetryDelay), *stored.DeliveredAt)
+
+	if assert.Len(t, received, 2) {
+		header := received[1].Header
+		assert.Equal(t, "application/json", header.Get("Content-Type"))
+		assert.Equal(t, models.WebhookEventEntryCreated, header.Get("X-EOD-Event"))
+		assert.Equal(t, "abc", header.Get("X-EOD-Delivery"))
+	}
+}
+
+func TestWebhookSenderAttempt(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name           string
+		attempts       int
+		expectedStatus string
+		expectedNext   time.Time
+	}{
+		{
+			name:           "first retry after the retry delay",
+			attempts:       0,
+			expectedStatus: models.WebhookDeliveryPending,
+			expectedNext:   testMonday.Add(30 * time.Second),
+		},
+		{
+			name:           "delay doubles with every attempt",
+			attempts:       3,
+			expectedStatus: models.WebhookDeliveryPending,
+			expectedNext:   testMonday.Add(4 * time.Minute),
+		},
+		{
+			name:           "fails after the last attempt",
+			attempts:       webhookMaxAttempts - 1,
+			expectedStatus: models.WebhookDeliveryFailed,
+			expectedNext:   testMonday,
+		},
+	}
+
+	sender := NewWebhookSender(nil, nil)
+	subscription := &models.WebhookSubscription{URL: server.URL, Secret: "0123456789abcdef"}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delivery := &models.WebhookDelivery{Payload: "{}", Status: models.WebhookDeliveryPending, Attempts: tc.attempts, NextAttemptAt: testMonday}
+
+			sender.attempt(context.Background(), subscription, delivery, testMonday)
+
+			assert.Equal(t, tc.expectedStatus, delivery.Status)
+			assert.Equal(t, tc.attempts+1, delivery.Attempts)
+			assert.Equal(t, tc.expectedNext, delivery.NextAttemptAt)
+			assert.Equal(t, http.StatusGone, delivery.ResponseStatus)
+			assert.Equal(t, "responded with 410 Gone: ", delivery.LastError)
+			assert.Nil(t, delivery.DeliveredAt)
+		})
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	signature := SignWebhook("0123456789abcdef", "1696233600", []byte(`{"event":"entry.created"}`))
+
+	assert.Regexp(t, "^v1=[0-9a-f]{64}$", signature)
+	assert.Equal(t, signature, SignWebhook("0123456789abcdef", "1696233600", []byte(`{"event":"entry.created"}`)))
+	assert.NotEqual(t, signature, SignWebhook("0123456789abcdef", "1696233601", []byte(`{"event":"entry.created"}`)))
+	assert.NotEqual(t, signature, SignWebhook("fedcba9876543210", "1696233600", []byte(`{"event":"entry.created"}`)))
+}
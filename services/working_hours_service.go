@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/blogem/eod-scheduler/models"
 	"github.com/blogem/eod-scheduler/repositories"
@@ -12,10 +14,16 @@ import (
 // WorkingHoursService interface defines working hours business logic
 type WorkingHoursService interface {
 	GetAllWorkingHours(ctx context.Context) ([]models.WorkingHours, error)
-	GetWorkingHoursByDay(ctx context.Context, dayOfWeek int) (*models.WorkingHours, error)
+	GetWorkingHoursOn(ctx context.Context, date time.Time) ([]models.WorkingHours, error)
+	GetWorkingHoursByDay(ctx context.Context, dayOfWeek int) ([]models.WorkingHours, error)
 	GetActiveDays(ctx context.Context) ([]models.WorkingHours, error)
-	UpdateWorkingHours(ctx context.Context, dayOfWeek int, form *models.WorkingHoursForm) (*models.WorkingHours, error)
-	UpdateAllWorkingHours(ctx context.Context, forms map[int]*models.WorkingHoursForm) error
+	GetUpcomingChanges(ctx context.Context) ([]models.WorkingHoursChange, error)
+	UpdateWorkingHours(ctx context.Context, effectiveFrom time.Time, dayOfWeek int, forms []*models.WorkingHoursForm) ([]models.WorkingHours, error)
+	UpdateAllWorkingHours(ctx context.Context, effectiveFrom time.Time, forms map[int][]*models.WorkingHoursForm) error
+	CancelChange(ctx context.Context, effectiveFrom time.Time) error
+	GetUpcomingExceptions(ctx context.Context) ([]models.WorkingHoursException, error)
+	SaveException(ctx context.Context, form *models.WorkingHoursExceptionForm) (*models.WorkingHoursException, error)
+	DeleteException(ctx context.Context, id int) error
 	IsWorkingDay(ctx context.Context, dayOfWeek int) (bool, error)
 	GetDayNames() map[int]string
 }
@@ -32,68 +40,80 @@ func NewWorkingHoursService(workingHoursRepo repositories.WorkingHoursRepository
 	}
 }
 
-// GetAllWorkingHours retrieves all working hours configurations
+// GetAllWorkingHours retrieves the working hours of every version
 func (s *workingHoursService) GetAllWorkingHours(ctx context.Context) ([]models.WorkingHours, error) {
 	return s.workingHoursRepo.GetAll(ctx)
 }
 
-// GetWorkingHoursByDay retrieves working hours for a specific day
-func (s *workingHoursService) GetWorkingHoursByDay(ctx context.Context, dayOfWeek int) (*models.WorkingHours, error) {
+// GetWorkingHoursOn retrieves the active shifts of every day in the versions in effect on the date
+func (s *workingHoursService) GetWorkingHoursOn(ctx context.Context, date time.Time) ([]models.WorkingHours, error) {
+	hours, err := s.workingHoursRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
+	}
+	return models.ShiftsInEffect(hours, date), nil
+}
+
+// GetWorkingHoursByDay retrieves the shifts of a specific day in effect today
+func (s *workingHoursService) GetWorkingHoursByDay(ctx context.Context, dayOfWeek int) ([]models.WorkingHours, error) {
 	if dayOfWeek < 0 || dayOfWeek > 6 {
 		return nil, fmt.Errorf("invalid day of week: %d (must be 0-6)", dayOfWeek)
 	}
-	return s.workingHoursRepo.GetByDay(ctx, dayOfWeek)
+	return s.workingHoursRepo.GetByDay(ctx, dayOfWeek, timeNow())
 }
 
-// GetActiveDays retrieves only active working days
+// GetActiveDays retrieves the shifts of the active working days of every version
 func (s *workingHoursService) GetActiveDays(ctx context.Context) ([]models.WorkingHours, error) {
 	return s.workingHoursRepo.GetActiveDays(ctx)
 }
 
-// UpdateWorkingHours updates working hours for a specific day
-func (s *workingHoursService) UpdateWorkingHours(ctx context.Context, dayOfWeek int, form *models.WorkingHoursForm) (*models.WorkingHours, error) {
-	if dayOfWeek < 0 || dayOfWeek > 6 {
-		return nil, fmt.Errorf("invalid day of week: %d (must be 0-6)", dayOfWeek)
+// GetUpcomingChanges retrieves the versions taking effect after today
+func (s *workingHoursService) GetUpcomingChanges(ctx context.Context) ([]models.WorkingHoursChange, error) {
+	hours, err := s.workingHoursRepo.GetAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working hours: %w", err)
 	}
+	return models.UpcomingChanges(hours, timeNow()), nil
+}
 
-	// Validate form
-	if errors := form.Validate(); len(errors) > 0 {
-		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+// UpdateWorkingHours replaces the shifts of a specific day from the effective date on. Inactive
+// shifts are dropped, a day without active shifts is kept as an inactive day.
+func (s *workingHoursService) UpdateWorkingHours(ctx context.Context, effectiveFrom time.Time, dayOfWeek int, forms []*models.WorkingHoursForm) ([]models.WorkingHours, error) {
+	if dayOfWeek < 0 || dayOfWeek > 6 {
+		return nil, fmt.Errorf("invalid day of week: %d (must be 0-6)", dayOfWeek)
 	}
-
-	// Get existing working hours
-	existing, err := s.workingHoursRepo.GetByDay(ctx, dayOfWeek)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get existing working hours: %w", err)
+	if err := validateEffectiveFrom(effectiveFrom); err != nil {
+		return nil, err
 	}
 
-	// Update fields
-	existing.StartTime = strings.TrimSpace(form.StartTime)
-	existing.EndTime = strings.TrimSpace(form.EndTime)
-	existing.Active = form.Active
-
-	// If deactivating, set times to 00:00
-	if !form.Active {
-		existing.StartTime = "00:00"
-		existing.EndTime = "00:00"
+	// Validate forms
+	if errors := models.ValidateShifts(forms); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
 	}
 
-	if err := s.workingHoursRepo.Update(ctx, existing); err != nil {
+	shifts := shiftsFromForms(dayOfWeek, forms)
+	if err := s.workingHoursRepo.ReplaceDay(ctx, truncateToDate(effectiveFrom), dayOfWeek, shifts); err != nil {
 		return nil, fmt.Errorf("failed to update working hours: %w", err)
 	}
 
-	return existing, nil
+	return shifts, nil
 }
 
-// UpdateAllWorkingHours updates working hours for multiple days
-func (s *workingHoursService) UpdateAllWorkingHours(ctx context.Context, forms map[int]*models.WorkingHoursForm) error {
+// UpdateAllWorkingHours updates the shifts of multiple days from the effective date on. Only the
+// days that differ from the version in effect on that date get a new version, so the other days
+// keep following their own changes.
+func (s *workingHoursService) UpdateAllWorkingHours(ctx context.Context, effectiveFrom time.Time, forms map[int][]*models.WorkingHoursForm) error {
+	if err := validateEffectiveFrom(effectiveFrom); err != nil {
+		return err
+	}
+
 	// Validate all forms first
-	for dayOfWeek, form := range forms {
+	for dayOfWeek, dayForms := range forms {
 		if dayOfWeek < 0 || dayOfWeek > 6 {
 			return fmt.Errorf("invalid day of week: %d (must be 0-6)", dayOfWeek)
 		}
 
-		if errors := form.Validate(); len(errors) > 0 {
+		if errors := models.ValidateShifts(dayForms); len(errors) > 0 {
 			dayName := models.DayNames[dayOfWeek]
 			return fmt.Errorf("validation failed for %s: %s", dayName, strings.Join(errors, ", "))
 		}
@@ -101,10 +121,12 @@ func (s *workingHoursService) UpdateAllWorkingHours(ctx context.Context, forms m
 
 	// Check that at least one day is active
 	hasActiveDay := false
-	for _, form := range forms {
-		if form.Active {
-			hasActiveDay = true
-			break
+	for _, dayForms := range forms {
+		for _, form := range dayForms {
+			if form.Active {
+				hasActiveDay = true
+				break
+			}
 		}
 	}
 
@@ -112,10 +134,19 @@ func (s *workingHoursService) UpdateAllWorkingHours(ctx context.Context, forms m
 		return fmt.Errorf("at least one working day must be active")
 	}
 
-	// Update all working hours
-	for dayOfWeek, form := range forms {
-		_, err := s.UpdateWorkingHours(ctx, dayOfWeek, form)
-		if err != nil {
+	current, err := s.workingHoursRepo.GetAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get working hours: %w", err)
+	}
+
+	// Update the working hours of the changed days
+	for dayOfWeek, dayForms := range forms {
+		inEffect := models.DayVersionOn(current, dayOfWeek, effectiveFrom)
+		if sameShifts(inEffect, shiftsFromForms(dayOfWeek, dayForms)) {
+			continue
+		}
+
+		if _, err := s.UpdateWorkingHours(ctx, effectiveFrom, dayOfWeek, dayForms); err != nil {
 			dayName := models.DayNames[dayOfWeek]
 			return fmt.Errorf("failed to update %s: %w", dayName, err)
 		}
@@ -124,18 +155,172 @@ func (s *workingHoursService) UpdateAllWorkingHours(ctx context.Context, forms m
 	return nil
 }
 
-// IsWorkingDay checks if a specific day is a working day
+// CancelChange deletes an upcoming change, the days keep the versions in effect before it
+func (s *workingHoursService) CancelChange(ctx context.Context, effectiveFrom time.Time) error {
+	if !truncateToDate(effectiveFrom).After(truncateToDate(timeNow())) {
+		return fmt.Errorf("only upcoming changes can be cancelled")
+	}
+
+	if err := s.workingHoursRepo.DeleteChange(ctx, truncateToDate(effectiveFrom)); err != nil {
+		return fmt.Errorf("failed to cancel working hours change: %w", err)
+	}
+	return nil
+}
+
+// GetUpcomingExceptions retrieves the working hours exceptions from today on
+func (s *workingHoursService) GetUpcomingExceptions(ctx context.Context) ([]models.WorkingHoursException, error) {
+	exceptions, err := s.workingHoursRepo.GetExceptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get working hours exceptions: %w", err)
+	}
+
+	today := truncateToDate(timeNow())
+	var upcoming []models.WorkingHoursException
+	for _, exception := range exceptions {
+		if !truncateToDate(exception.Date).Before(today) {
+			upcoming = append(upcoming, exception)
+		}
+	}
+	return upcoming, nil
+}
+
+// SaveException creates the working hours exception of a date, replacing the exception the date
+// already has. Like changes of the working hours, exceptions can't take effect in the past.
+func (s *workingHoursService) SaveException(ctx context.Context, form *models.WorkingHoursExceptionForm) (*models.WorkingHoursException, error) {
+	if errors := form.Validate(); len(errors) > 0 {
+		return nil, fmt.Errorf("validation failed: %s", strings.Join(errors, ", "))
+	}
+
+	date, _ := models.ParseDate(strings.TrimSpace(form.Date))
+	if err := validateEffectiveFrom(date); err != nil {
+		return nil, err
+	}
+
+	exception := &models.WorkingHoursException{
+		Date:   date,
+		Kind:   form.Kind,
+		Reason: strings.TrimSpace(form.Reason),
+	}
+	if form.Kind != models.ExceptionKindNonWorking {
+		exception.StartTime = strings.TrimSpace(form.StartTime)
+		exception.EndTime = strings.TrimSpace(form.EndTime)
+	}
+
+	// An extra working day belongs to one of the shifts, so it rotates with that shift
+	if form.Kind == models.ExceptionKindWorking {
+		exception.Name = strings.TrimSpace(form.Name)
+		hours, err := s.workingHoursRepo.GetAll(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get working hours: %w", err)
+		}
+		if !slices.Contains(shiftNames(hours), exception.Name) {
+			return nil, fmt.Errorf("unknown shift: %s", exception.Name)
+		}
+	}
+
+	if err := s.workingHoursRepo.SaveException(ctx, exception); err != nil {
+		return nil, fmt.Errorf("failed to save working hours exception: %w", err)
+	}
+	return exception, nil
+}
+
+// DeleteException deletes an upcoming working hours exception
+func (s *workingHoursService) DeleteException(ctx context.Context, id int) error {
+	exceptions, err := s.GetUpcomingExceptions(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, exception := range exceptions {
+		if exception.ID == id {
+			if err := s.workingHoursRepo.DeleteException(ctx, id); err != nil {
+				return fmt.Errorf("failed to delete working hours exception: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("no upcoming working hours exception with ID %d", id)
+}
+
+// IsWorkingDay checks if a specific day has an active shift today
 func (s *workingHoursService) IsWorkingDay(ctx context.Context, dayOfWeek int) (bool, error) {
 	if dayOfWeek < 0 || dayOfWeek > 6 {
 		return false, fmt.Errorf("invalid day of week: %d (must be 0-6)", dayOfWeek)
 	}
 
-	workingHours, err := s.workingHoursRepo.GetByDay(ctx, dayOfWeek)
+	shifts, err := s.workingHoursRepo.GetByDay(ctx, dayOfWeek, timeNow())
 	if err != nil {
 		return false, fmt.Errorf("failed to get working hours: %w", err)
 	}
 
-	return workingHours.Active, nil
+	for _, shift := range shifts {
+		if shift.Active {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// validateEffectiveFrom checks that a change doesn't take effect in the past, which would change
+// the rotation of schedules that were already published
+func validateEffectiveFrom(effectiveFrom time.Time) error {
+	if truncateToDate(effectiveFrom).Before(truncateToDate(timeNow())) {
+		return fmt.Errorf("changes can't take effect before today")
+	}
+	return nil
+}
+
+// shiftsFromForms converts the forms of a day to its shifts. Inactive shifts are dropped, a day
+// without active shifts keeps a single inactive row with its times set to 00:00.
+func shiftsFromForms(dayOfWeek int, forms []*models.WorkingHoursForm) []models.WorkingHours {
+	var shifts []models.WorkingHours
+	for _, form := range forms {
+		if !form.Active {
+			continue
+		}
+		shifts = append(shifts, models.WorkingHours{
+			DayOfWeek: dayOfWeek,
+			Name:      strings.TrimSpace(form.Name),
+			StartTime: strings.TrimSpace(form.StartTime),
+			EndTime:   strings.TrimSpace(form.EndTime),
+			Active:    true,
+		})
+	}
+
+	if len(shifts) == 0 {
+		shifts = append(shifts, models.WorkingHours{
+			DayOfWeek: dayOfWeek,
+			StartTime: "00:00",
+			EndTime:   "00:00",
+			Active:    false,
+		})
+	}
+	return shifts
+}
+
+// sameShifts checks if a version of a day has the given shifts, ignoring their order
+func sameShifts(version, shifts []models.WorkingHours) bool {
+	if len(version) != len(shifts) {
+		return false
+	}
+
+	key := func(shift models.WorkingHours) string {
+		if !shift.Active {
+			return "off"
+		}
+		return shift.Name + "|" + shift.StartTime + "|" + shift.EndTime
+	}
+	remaining := make(map[string]int)
+	for _, shift := range version {
+		remaining[key(shift)]++
+	}
+	for _, shift := range shifts {
+		if remaining[key(shift)] == 0 {
+			return false
+		}
+		remaining[key(shift)]--
+	}
+	return true
 }
 
 // GetDayNames returns the mapping of day numbers to names
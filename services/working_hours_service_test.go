@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"github.com/blogem/eod-scheduler/models"
+	dbMocks "github.com/blogem/eod-scheduler/repositories/mocks"
+)
+
+// weekForms builds the forms of a whole week with the given days off
+func weekForms(off ...int) map[int][]*models.WorkingHoursForm {
+	forms := make(map[int][]*models.WorkingHoursForm)
+	for day := 0; day < 7; day++ {
+		forms[day] = []*models.WorkingHoursForm{{DayOfWeek: day, StartTime: "09:00", EndTime: "17:00", Active: day < 5}}
+	}
+	for _, day := range off {
+		forms[day][0].Active = false
+	}
+	return forms
+}
+
+// TestUpdateAllWorkingHours tests that saving the working hours adds a version for the changed
+// days only, from the effective date on
+func TestUpdateAllWorkingHours(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday.Add(10 * time.Hour) }
+
+	ctx := context.Background()
+	mockWorkingRepo := dbMocks.NewMockWorkingHoursRepository(t)
+	service := NewWorkingHoursService(mockWorkingRepo)
+
+	current := append(weekdaysMonToFri(),
+		models.WorkingHours{DayOfWeek: 5, StartTime: "00:00", EndTime: "00:00"},
+		models.WorkingHours{DayOfWeek: 6, StartTime: "00:00", EndTime: "00:00"},
+	)
+	effectiveFrom := testMonday.AddDate(0, 0, 14)
+
+	// Friday is off from the effective date on, the other days keep their version
+	mockWorkingRepo.EXPECT().GetAll(ctx).Return(current, nil).Once()
+	mockWorkingRepo.EXPECT().ReplaceDay(ctx, effectiveFrom, 4, mock.MatchedBy(func(shifts []models.WorkingHours) bool {
+		return len(shifts) == 1 && !shifts[0].Active
+	})).Return(nil).Once()
+	assert.NoError(t, service.UpdateAllWorkingHours(ctx, effectiveFrom, weekForms(4)))
+
+	// Nothing changes when the version in effect on the date already has the hours
+	changed := append(current, models.WorkingHours{DayOfWeek: 4, StartTime: "00:00", EndTime: "00:00", EffectiveFrom: effectiveFrom})
+	mockWorkingRepo.EXPECT().GetAll(ctx).Return(changed, nil).Once()
+	assert.NoError(t, service.UpdateAllWorkingHours(ctx, effectiveFrom.AddDate(0, 0, 7), weekForms(4)))
+
+	// Changes can take effect today, but not in the past
+	mockWorkingRepo.EXPECT().GetAll(ctx).Return(current, nil).Once()
+	mockWorkingRepo.EXPECT().ReplaceDay(ctx, testMonday, 4, mock.Anything).Return(nil).Once()
+	assert.NoError(t, service.UpdateAllWorkingHours(ctx, testMonday, weekForms(4)))
+
+	err := service.UpdateAllWorkingHours(ctx, testMonday.AddDate(0, 0, -1), weekForms(4))
+	assert.EqualError(t, err, "changes can't take effect before today")
+
+	// Only upcoming changes can be cancelled
+	mockWorkingRepo.EXPECT().DeleteChange(ctx, effectiveFrom).Return(nil).Once()
+	assert.NoError(t, service.CancelChange(ctx, effectiveFrom))
+	assert.EqualError(t, service.CancelChange(ctx, testMonday), "only upcoming changes can be cancelled")
+}
+
+// TestWorkingHoursExceptions tests that exceptions are validated, that an extra working day needs
+// an existing shift, and that only upcoming exceptions change
+func TestWorkingHoursExceptions(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() { timeNow = originalTimeNow }()
+	timeNow = func() time.Time { return testMonday.Add(10 * time.Hour) }
+
+	ctx := context.Background()
+	mockWorkingRepo := dbMocks.NewMockWorkingHoursRepository(t)
+	service := NewWorkingHoursService(mockWorkingRepo)
+
+	// Exceptions without hours don't keep the hours of the form
+	mockWorkingRepo.EXPECT().SaveException(ctx, mock.MatchedBy(func(exception *models.WorkingHoursException) bool {
+		return exception.Kind == models.ExceptionKindNonWorking && exception.StartTime == "" && exception.Date.Equal(testMonday)
+	})).Return(nil).Once()
+	_, err := service.SaveException(ctx, &models.WorkingHoursExceptionForm{Date: "2023-10-02", Kind: models.ExceptionKindNonWorking, StartTime: "09:00", EndTime: "13:00"})
+	assert.NoError(t, err)
+
+	_, err = service.SaveException(ctx, &models.WorkingHoursExceptionForm{Date: "2023-10-01", Kind: models.ExceptionKindNonWorking})
+	assert.EqualError(t, err, "changes can't take effect before today")
+
+	_, err = service.SaveException(ctx, &models.WorkingHoursExceptionForm{Date: "2023-10-03", Kind: models.ExceptionKindHours, StartTime: "09:00"})
+	assert.ErrorContains(t, err, "validation failed")
+
+	// An extra working day rotates with one of the shifts
+	mockWorkingRepo.EXPECT().GetAll(ctx).Return(morningAndAfternoon(), nil).Twice()
+	mockWorkingRepo.EXPECT().SaveException(ctx, mock.MatchedBy(func(exception *models.WorkingHoursException) bool {
+		return exception.Name == "Morning" && exception.StartTime == "10:00"
+	})).Return(nil).Once()
+	_, err = service.SaveException(ctx, &models.WorkingHoursExceptionForm{Date: "2023-10-07", Kind: models.ExceptionKindWorking, Name: "Morning", StartTime: "10:00", EndTime: "14:00"})
+	assert.NoError(t, err)
+
+	_, err = service.SaveException(ctx, &models.WorkingHoursExceptionForm{Date: "2023-10-07", Kind: models.ExceptionKindWorking, StartTime: "10:00", EndTime: "14:00"})
+	assert.EqualError(t, err, "unknown shift: ")
+
+	// Past exceptions stay, they shaped the rotation up to today
+	mockWorkingRepo.EXPECT().GetExceptions(ctx).Return([]models.WorkingHoursException{
+		{ID: 1, Date: testMonday.AddDate(0, 0, -3), Kind: models.ExceptionKindNonWorking},
+		{ID: 2, Date: testMonday, Kind: models.ExceptionKindNonWorking},
+	}, nil)
+	upcoming, err := service.GetUpcomingExceptions(ctx)
+	assert.NoError(t, err)
+	assert.Len(t, upcoming, 1)
+
+	mockWorkingRepo.EXPECT().DeleteException(ctx, 2).Return(nil).Once()
+	assert.NoError(t, service.DeleteException(ctx, 2))
+	assert.EqualError(t, service.DeleteException(ctx, 1), "no upcoming working hours exception with ID 1")
+}
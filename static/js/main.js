@@ -45,6 +45,14 @@ document.addEventListener('DOMContentLoaded', function () {
         }
     });
 
+    // Switch to the pages of the team chosen in the header
+    const teamSwitcher = document.querySelector('.team-switcher');
+    if (teamSwitcher) {
+        teamSwitcher.addEventListener('change', function () {
+            window.location.href = this.value;
+        });
+    }
+
     // Add smooth scrolling for anchor links
     const anchorLinks = document.querySelectorAll('a[href^="#"]');
     anchorLinks.forEach(link => {
@@ -0,0 +1,43 @@
+package teamctx
+
+import (
+	"context"
+
+	"github.com/blogem/eod-scheduler/models"
+)
+
+// Context key type
+type contextKey string
+
+const teamKey contextKey = "team"
+const teamsKey contextKey = "teams"
+
+// SetTeam adds the team a request works on to the context
+func SetTeam(ctx context.Context, team *models.Team) context.Context {
+	return context.WithValue(ctx, teamKey, team)
+}
+
+// GetTeam retrieves the team a request works on from the context, or nil if none was set
+func GetTeam(ctx context.Context) *models.Team {
+	team, _ := ctx.Value(teamKey).(*models.Team)
+	return team
+}
+
+// GetTeamID retrieves the ID of the team a request works on, defaulting to the default team
+func GetTeamID(ctx context.Context) int {
+	if team := GetTeam(ctx); team != nil {
+		return team.ID
+	}
+	return models.DefaultTeamID
+}
+
+// SetTeams adds all teams, for the team switcher, to the context
+func SetTeams(ctx context.Context, teams []models.Team) context.Context {
+	return context.WithValue(ctx, teamsKey, teams)
+}
+
+// GetTeams retrieves all teams from the context
+func GetTeams(ctx context.Context) []models.Team {
+	teams, _ := ctx.Value(teamsKey).([]models.Team)
+	return teams
+}